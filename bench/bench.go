@@ -54,6 +54,12 @@ type CommonConfig struct {
 	// before invoking the benchmark.
 	RateLimiter *rate.Limiter
 
+	// InjectErrors, when non-empty, is an errorfs DSL expression (see
+	// errorfs.NewParser) used to inject errors, latency, stalls and hangs into
+	// the DB's filesystem operations. Disk slowness events are logged when it
+	// is set. Any hangs are released when the DB is closed.
+	InjectErrors string
+
 	// Logger, when non-nil, overrides the default Pebble logger. The in-process
 	// benchmarks set this to a noop logger so Pebble's internal log messages
 	// don't pollute `go test -v` output.
@@ -14,6 +14,7 @@ import (
 	"github.com/cockroachdb/pebble/objstorage/remote"
 	"github.com/cockroachdb/pebble/sstable"
 	"github.com/cockroachdb/pebble/vfs"
+	"github.com/cockroachdb/pebble/vfs/errorfs"
 )
 
 // DB specifies the minimal interfaces that need to be implemented by the
@@ -55,6 +56,8 @@ type Batch interface {
 type pebbleDB struct {
 	d       *pebble.DB
 	ballast []byte
+	// inj is the injector wrapping the DB's filesystem, if any.
+	inj errorfs.Injector
 }
 
 // NewPebbleDB opens a Pebble DB at the given directory using settings derived
@@ -120,6 +123,26 @@ func NewPebbleDB(dir string, cfg *CommonConfig) DB {
 		opts.EventListener.WALDeleted = nil
 	}
 
+	var inj errorfs.Injector
+	if cfg.InjectErrors != "" {
+		var err error
+		if inj, err = errorfs.ParseDSL(cfg.InjectErrors); err != nil {
+			log.Fatal(err)
+		}
+		opts.FS = errorfs.Wrap(opts.FS, inj)
+		// Wrap the injecting filesystem with disk-health checking so that
+		// injected latency surfaces as disk slowness events.
+		opts.WithFSDefaults()
+		if !cfg.Verbose {
+			// The verbose event listener already logs disk slowness events.
+			opts.AddEventListener(pebble.EventListener{
+				DiskSlow: func(info pebble.DiskSlowInfo) {
+					opts.Logger.Infof("%s", info)
+				},
+			})
+		}
+	}
+
 	if cfg.PathToLocalSharedStorage != "" {
 		opts.RemoteStorage = remote.MakeSimpleFactory(map[remote.Locator]remote.Storage{
 			// Store all shared objects on local disk, for convenience.
@@ -140,7 +163,7 @@ func NewPebbleDB(dir string, cfg *CommonConfig) DB {
 			log.Fatal(err)
 		}
 	}
-	db := pebbleDB{d: p}
+	db := pebbleDB{d: p, inj: inj}
 	if cfg.Ballast > 0 {
 		db.ballast = make([]byte, cfg.Ballast)
 	}
@@ -189,5 +212,9 @@ func (p pebbleDB) Metrics() *pebble.Metrics {
 }
 
 func (p pebbleDB) Close() error {
+	if p.inj != nil {
+		// Don't let hung operations prevent the DB from closing.
+		errorfs.ReleaseHangs(p.inj)
+	}
 	return p.d.Close()
 }
@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"fmt"
 	"log"
+	"math/rand/v2"
 	"os"
 	"path"
 	"sync"
@@ -124,6 +125,14 @@ func FsBenchmarks(fs vfs.FS) map[string]FsBenchmark {
 			"delete_small_dir_2MiB", "Prepopulate directory with 1k 1MiB files, measure delete peformance of 2MiB files",
 			1e3, 1<<20, 2<<20,
 		),
+		"read_random_4KiB": env.readRandomBench(
+			"read_random_4KiB", "Prepopulate a 1GiB file, measure random 4KiB reads.",
+			1<<30, 4<<10, 1,
+		),
+		"read_random_4KiB_parallel_32": env.readRandomBench(
+			"read_random_4KiB_parallel_32", "Prepopulate a 1GiB file, measure random 4KiB reads issued by 32 concurrent readers.",
+			1<<30, 4<<10, 32,
+		),
 	}
 }
 
@@ -522,6 +531,79 @@ func (e *fsEnv) diskUsageBench(
 	return FsBenchmark{createBench, benchName, benchDescription}
 }
 
+func (e *fsEnv) readRandomBench(
+	benchName string, benchDescription string, fileSize int64, readSize int64, concurrency int,
+) FsBenchmark {
+	if readSize > fileSize {
+		log.Fatalln("Read size is greater than file size.")
+	}
+
+	createBench := func(dirpath string) *fsBench {
+		bench := &fsBench{env: e}
+		e.mkDir(dirpath)
+		fh := e.openDir(dirpath)
+
+		bench.dir = fh
+		bench.dirName = dirpath
+		bench.reg = newHistogramRegistry()
+		bench.numOps = 0
+		bench.name = benchName
+		bench.description = benchDescription
+
+		filepath := path.Join(dirpath, "temp_read")
+		wfh := e.createFile(filepath)
+		e.writeToFile(wfh, fileSize)
+		e.syncFile(wfh)
+		e.closeFile(wfh)
+
+		var benchData struct {
+			done atomic.Bool
+			fh   vfs.File
+		}
+		var err error
+		benchData.fh, err = e.fs.Open(filepath, vfs.RandomReadsOption)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		bufs := make([][]byte, concurrency)
+		for i := range bufs {
+			bufs[i] = make([]byte, readSize)
+		}
+
+		// Each run issues one read from each of the concurrent readers.
+		bench.run = func(hist *namedHistogram) bool {
+			if benchData.done.Load() {
+				return false
+			}
+			var wg sync.WaitGroup
+			for i := range bufs {
+				wg.Go(func() {
+					off := rand.Int64N(fileSize - readSize + 1)
+					start := time.Now()
+					if _, err := benchData.fh.ReadAt(bufs[i], off); err != nil {
+						log.Fatalln(err)
+					}
+					hist.Record(time.Since(start))
+				})
+			}
+			wg.Wait()
+			return true
+		}
+
+		bench.stop = func() { benchData.done.Store(true) }
+
+		bench.clean = func() {
+			e.closeFile(benchData.fh)
+			e.removeAllFiles(dirpath)
+			e.closeFile(bench.dir)
+		}
+
+		return bench
+	}
+
+	return FsBenchmark{createBench, benchName, benchDescription}
+}
+
 // RunFsBench runs the file system benchmark named in cfg.BenchName.
 func RunFsBench(dir string, common *CommonConfig, cfg *FsBenchConfig) error {
 	fs := cfg.FS
@@ -72,6 +72,8 @@ func (c *pickedBlobFileCompaction) ConstructCompaction(
 			// TODO(jackson): Enable shared storage for blob files.
 			PreferSharedStorage: false,
 			WriteCategory:       getDiskWriteCategoryForCompaction(d.opts, compactionKindBlobFileRewrite),
+			// Rewritten blob files remain on the tier of the input.
+			Tier: d.objectTier(base.FileTypeBlob, c.file.Physical.FileNum),
 		},
 		highPriority: c.highPriority,
 	}
@@ -324,7 +326,11 @@ func (d *DB) runBlobFileRewriteLocked(
 	}
 
 	// Create a new file for the rewritten blob file.
-	writable, objMeta, err := d.newCompactionOutputBlob(jobID, compactionKindBlobFileRewrite, -1, &c.bytesWritten, c.objCreateOpts)
+	createOpts := c.objCreateOpts
+	if d.opts.Local.DirectIOForCompactions {
+		createOpts.DirectIO = &bufferPool
+	}
+	writable, objMeta, err := d.newCompactionOutputBlob(jobID, compactionKindBlobFileRewrite, -1, &c.bytesWritten, createOpts)
 	if err != nil {
 		return objstorage.ObjectMetadata{}, nil, err
 	}
@@ -470,7 +470,8 @@ func (d *DB) Checkpoint(
 // that existed on the original database but no longer apply to the checkpointed
 // database. For example, the entire [WAL Failover] stanza is commented out
 // because Checkpoint will copy all WAL segment files from both the primary and
-// secondary WAL directories into the checkpoint.
+// secondary WAL directories into the checkpoint. Similarly, the WALs held by an
+// ExternalWAL are copied into log files in the checkpoint.
 func copyCheckpointOptions(fs vfs.FS, srcPath, dstPath string) error {
 	var buf bytes.Buffer
 	f, err := fs.Open(srcPath)
@@ -483,17 +484,19 @@ func copyCheckpointOptions(fs vfs.FS, srcPath, dstPath string) error {
 		return err
 	}
 	// Copy the OPTIONS file verbatim, but commenting out the [WAL Failover]
-	// section.
+	// and [WAL Replication] sections, and the external_wal option, since the
+	// checkpoint's WALs are all in the checkpoint directory.
 	err = parseOptions(string(b), parseOptionsFuncs{
 		visitNewSection: func(startOff, endOff int, section string) error {
-			if section == "WAL Failover" {
+			if section == "WAL Failover" || section == "WAL Replication" {
 				buf.WriteString("# ")
 			}
 			buf.Write(b[startOff:endOff])
 			return nil
 		},
 		visitKeyValue: func(startOff, endOff int, section, key, value string) error {
-			if section == "WAL Failover" {
+			if section == "WAL Failover" || section == "WAL Replication" ||
+				(section == "Options" && key == "external_wal") {
 				buf.WriteString("# ")
 			}
 			buf.Write(b[startOff:endOff])
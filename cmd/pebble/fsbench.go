@@ -5,6 +5,8 @@
 package main
 
 import (
+	"fmt"
+
 	"github.com/cockroachdb/pebble/bench"
 	"github.com/cockroachdb/pebble/vfs"
 	"github.com/spf13/cobra"
@@ -15,6 +17,8 @@ var fsBenchConfig = bench.FsBenchConfig{
 	FS:       vfs.Default,
 }
 
+var fsBenchIOUring bool
+
 var fsBenchCmd = &cobra.Command{
 	Use:   "fs <dir>",
 	Short: "Run file system benchmarks.",
@@ -28,6 +32,10 @@ or running out of memory.
 
 The --num-times flag can be used to run the entire benchmark, more than
 once. If the flag isn't provided, then the benchmark is only run once.
+
+The --io-uring flag runs the benchmark against the io_uring backed file
+system (Linux only), falling back to the default file system if io_uring
+is not supported.
 `,
 	Args: cobra.ExactArgs(1),
 	RunE: runFsBench,
@@ -47,10 +55,22 @@ func init() {
 		&fsBenchConfig.NumTimes, "num-times", 1,
 		"Number of times each benchmark should be run.")
 
+	fsBenchCmd.Flags().BoolVar(
+		&fsBenchIOUring, "io-uring", false,
+		"Use the io_uring backed file system.")
+
 	fsBenchCmd.AddCommand(listFsBench)
 }
 
 func runFsBench(_ *cobra.Command, args []string) error {
 	fsBenchConfig.Verbose = commonCfg.Verbose
+	if fsBenchIOUring {
+		fs, closer := vfs.NewIOUringFS(vfs.IOUringOptions{})
+		defer func() { _ = closer.Close() }()
+		if fs == vfs.Default {
+			fmt.Println("io_uring is not supported; using the default file system.")
+		}
+		fsBenchConfig.FS = fs
+	}
 	return bench.RunFsBench(args[0], &commonCfg, &fsBenchConfig)
 }
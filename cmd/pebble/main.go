@@ -92,6 +92,12 @@ func main() {
 		cmd.Flags().Int64Var(
 			&commonCfg.SecondaryCacheSize, "secondary-cache", 0, "secondary cache size in bytes")
 	}
+	for _, cmd := range []*cobra.Command{scanCmd, syncCmd, tombstoneCmd, writeBenchCmd, ycsbCmd} {
+		cmd.Flags().StringVar(
+			&commonCfg.InjectErrors, "inject-errors", "",
+			"errorfs DSL expression injecting errors, latency, stalls or hangs into filesystem operations (eg, "+
+				`'(Stall "10s" "5s" (BaseMatch "*.log"))')`)
+	}
 	for _, cmd := range []*cobra.Command{scanCmd, syncCmd, tombstoneCmd, ycsbCmd} {
 		cmd.Flags().Int64Var(
 			&commonCfg.CacheSize, "cache", 1<<30, "cache size")
@@ -58,6 +58,7 @@ var Comparer = base.Comparer{
 	CompareRangeSuffixes: CompareRangeSuffixes,
 	Compare:              Compare,
 	Equal:                Equal,
+	BytewisePrefixOrder:  true,
 
 	AbbreviatedKey: func(k []byte) uint64 {
 		key, ok := getKeyPartFromEngineKey(k)
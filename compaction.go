@@ -2693,6 +2693,11 @@ func (d *DB) runCompaction(
 	if c.cancel.Load() {
 		return ve, stats, outputBlobs, ErrCancelledCompaction
 	}
+	if c.isDownload && d.opts.SigningKeys != nil {
+		if err := d.verifyDownloadSignatures(c); err != nil {
+			return ve, stats, outputBlobs, err
+		}
+	}
 	switch c.kind {
 	case compactionKindDeleteOnly:
 		return d.runDeleteOnlyCompaction(c)
@@ -2790,6 +2795,11 @@ func (d *DB) compactAndWrite(
 	// translate to 4.5 MiB per compaction.
 	c.iterationState.bufferPool.Init(18+suggestedCacheReaders*2, block.ForCompaction)
 	defer c.iterationState.bufferPool.Release()
+	if d.opts.Local.DirectIOForCompactions && c.kind != compactionKindFlush {
+		// Compaction outputs are written with direct I/O, using buffers from
+		// the same pool.
+		c.objCreateOpts.DirectIO = &c.iterationState.bufferPool
+	}
 	blockReadEnv := block.ReadEnv{
 		BufferPool: &c.iterationState.bufferPool,
 		Stats:      &c.metrics.internalIterStats,
@@ -2861,6 +2871,12 @@ func (d *DB) compactAndWrite(
 	var spanPolicySet bool
 	var spanPolicy base.SpanPolicy
 
+	var filterSizing *tableFilterSizing
+	if d.opts.TableFilterMemoryBudget != 0 && c.kind != compactionKindFlush {
+		fs := makeTableFilterSizing(c.inputs, d.fileCache.filterMetricsTracker())
+		filterSizing = &fs
+	}
+
 	valueSeparation := c.getValueSeparation(jobID, c)
 	for runner.MoreDataToWrite() {
 		if c.cancel.Load() {
@@ -2886,21 +2902,36 @@ func (d *DB) compactAndWrite(
 		if spanPolicy.PreferFastCompression && writerOpts.Compression != block.NoCompression {
 			writerOpts.Compression = block.FastestCompression
 		}
+		writerOpts.FilterPolicy = spanPolicy.AdjustTableFilterPolicy(
+			writerOpts.FilterPolicy, c.eventualOutputLevel == numLevels-1)
+		if filterSizing != nil {
+			writerOpts.FilterPolicy = filterSizing.adaptPolicy(writerOpts.FilterPolicy, d.opts.TableFilterMemoryBudget)
+		}
 		vSep := valueSeparation
 		if spanPolicy.ValueStoragePolicy.DisableBlobSeparation {
 			vSep = valsep.NeverSeparateValues{}
-		} else if spanPolicy.ValueStoragePolicy.ContainsOverrides() {
-			vSep.SetNextOutputConfig(valsep.ValueSeparationOutputConfig{
+		} else if tiered := d.opts.Tiering.CreateColdOnShared && spanPolicy.TieringPolicy.IsSet(); tiered ||
+			spanPolicy.ValueStoragePolicy.ContainsOverrides() {
+			outputConfig := valsep.ValueSeparationOutputConfig{
 				MinimumSize:                    spanPolicy.ValueStoragePolicy.OverrideBlobSeparationMinimumSize,
 				DisableValueSeparationBySuffix: spanPolicy.ValueStoragePolicy.DisableSeparationBySuffix,
 				MinimumMVCCGarbageSize:         spanPolicy.ValueStoragePolicy.MinimumMVCCGarbageSize,
-			})
+			}
+			if tiered {
+				outputConfig.TieringPolicy = spanPolicy.TieringPolicy
+				outputConfig.ColdThreshold = coldThreshold(d.opts.Tiering.NowFn(), spanPolicy.TieringPolicy.TieringPolicy)
+			}
+			vSep.SetNextOutputConfig(outputConfig)
 		}
-		objMeta, tw, err := d.newCompactionOutputTable(jobID, c, writerOpts)
+		var tiering compact.TableTiering
+		if tierFn := d.tableTierFunc(c.eventualOutputLevel, spanPolicy.TieringPolicy); tierFn != nil {
+			tiering = compact.TableTiering{Tier: runner.FirstKeyTier(tierFn), TierFn: tierFn}
+		}
+		objMeta, tw, err := d.newCompactionOutputTable(jobID, c, writerOpts, tiering.Tier)
 		if err != nil {
 			return runner.Finish().WithError(err)
 		}
-		runner.WriteTable(objMeta, tw, spanPolicy.KeyRange.End, vSep)
+		runner.WriteTable(objMeta, tw, spanPolicy.KeyRange.End, vSep, tiering)
 	}
 	result = runner.Finish()
 	if result.Err == nil {
@@ -3046,10 +3077,17 @@ func (c *tableCompaction) makeVersionEdit(result compact.Result) (*manifest.Vers
 // newCompactionOutputTable creates an object for a new table produced by a
 // compaction or flush.
 func (d *DB) newCompactionOutputTable(
-	jobID JobID, c *tableCompaction, writerOpts sstable.WriterOptions,
+	jobID JobID, c *tableCompaction, writerOpts sstable.WriterOptions, tier base.StorageTier,
 ) (objstorage.ObjectMetadata, sstable.RawWriter, error) {
+	createOpts := c.objCreateOpts
+	if tier == base.ColdTier {
+		// Cold tables are created on the cold tier's shared storage rather than
+		// on the CreateOnSharedLocator.
+		createOpts.Tier = base.ColdTier
+		createOpts.PreferSharedStorage = false
+	}
 	writable, objMeta, err := d.newCompactionOutputObj(
-		base.FileTypeTable, c.kind, c.outputLevel.level, &c.metrics.bytesWritten, c.objCreateOpts)
+		base.FileTypeTable, c.kind, c.outputLevel.level, &c.metrics.bytesWritten, createOpts)
 	if err != nil {
 		return objstorage.ObjectMetadata{}, nil, err
 	}
@@ -3065,6 +3103,12 @@ func (d *DB) newCompactionOutputTable(
 			FileNum:     objMeta.DiskFileNum,
 		},
 	})
+	if g, ok := c.grantHandle.(CompactionCompressionGrant); ok {
+		writerOpts.CompressionWorkers = max(g.CompressionWorkers(), 0)
+	}
+	// The compression goroutines are not reported to the CPUMeasurer; bound
+	// their CPU consumption.
+	writerOpts.CompressionWorkers = min(writerOpts.CompressionWorkers, MaxCompactionCompressionWorkers)
 	tw := sstable.NewRawWriterWithCPUMeasurer(writable, writerOpts, c.grantHandle)
 	return objMeta, tw, nil
 }
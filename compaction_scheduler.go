@@ -15,6 +15,11 @@ import (
 type CompactionGrantHandle = base.CompactionGrantHandle
 type CompactionGrantHandleStats = base.CompactionGrantHandleStats
 type CompactionGoroutineKind = base.CompactionGoroutineKind
+type CompactionCompressionGrant = base.CompactionCompressionGrant
+
+// MaxCompactionCompressionWorkers is the maximum number of compression
+// goroutines used by a compaction (see CompactionCompressionGrant).
+const MaxCompactionCompressionWorkers = base.MaxCompactionCompressionWorkers
 
 const (
 	CompactionGoroutinePrimary           = base.CompactionGoroutinePrimary
@@ -5,14 +5,19 @@
 package pebble
 
 import (
+	"context"
 	"fmt"
 	"slices"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/cockroachdb/crlib/testutils/leaktest"
 	"github.com/cockroachdb/datadriven"
+	"github.com/cockroachdb/pebble/internal/testutils"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/stretchr/testify/require"
 )
 
 type testTimeSource struct {
@@ -168,3 +173,75 @@ func TestConcurrencyLimitScheduler(t *testing.T) {
 			}
 		})
 }
+
+// compressionGrantScheduler wraps a ConcurrencyLimitScheduler, granting
+// handles that implement CompactionCompressionGrant.
+type compressionGrantScheduler struct {
+	*ConcurrencyLimitScheduler
+	workers int
+	calls   atomic.Int32
+}
+
+func (s *compressionGrantScheduler) TrySchedule() (bool, CompactionGrantHandle) {
+	ok, h := s.ConcurrencyLimitScheduler.TrySchedule()
+	if ok {
+		h = compressionGrantHandle{CompactionGrantHandle: h, s: s}
+	}
+	return ok, h
+}
+
+type compressionGrantHandle struct {
+	CompactionGrantHandle
+	s *compressionGrantScheduler
+}
+
+var _ CompactionCompressionGrant = compressionGrantHandle{}
+
+func (h compressionGrantHandle) CompressionWorkers() int {
+	h.s.calls.Add(1)
+	return h.s.workers
+}
+
+func TestCompactionCompressionGrant(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	sched := &compressionGrantScheduler{
+		ConcurrencyLimitScheduler: NewConcurrencyLimitSchedulerWithNoPeriodicGrantingForTest(),
+		workers:                   2,
+	}
+	opts := &Options{
+		FS:     vfs.NewMem(),
+		Logger: testutils.Logger{T: t},
+		// Flushes don't use the CompactionScheduler.
+		CompressionWorkers: 3,
+	}
+	opts.CompactionScheduler = func() CompactionScheduler { return sched }
+	for i := range opts.Levels {
+		opts.Levels[i].BlockSize = 512
+	}
+	d, err := Open("", opts)
+	require.NoError(t, err)
+
+	const n = 5000
+	key := func(i int) []byte { return fmt.Appendf(nil, "key-%05d", i) }
+	for round := range 3 {
+		for i := round; i < n; i += 3 {
+			require.NoError(t, d.Set(key(i), key(i), nil))
+		}
+		require.NoError(t, d.Flush())
+	}
+	require.NoError(t, d.Compact(context.Background(), key(0), key(n), false /* parallelize */))
+	require.Greater(t, sched.calls.Load(), int32(0))
+
+	iter, err := d.NewIter(nil)
+	require.NoError(t, err)
+	i := 0
+	for valid := iter.First(); valid; valid = iter.Next() {
+		require.Equal(t, key(i), iter.Key())
+		require.Equal(t, key(i), iter.Value())
+		i++
+	}
+	require.NoError(t, iter.Close())
+	require.Equal(t, n, i)
+	require.NoError(t, d.Close())
+}
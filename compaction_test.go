@@ -41,6 +41,7 @@ import (
 	"github.com/cockroachdb/pebble/sstable"
 	"github.com/cockroachdb/pebble/sstable/blob"
 	"github.com/cockroachdb/pebble/sstable/block"
+	"github.com/cockroachdb/pebble/sstable/tablefilters/bloom"
 	"github.com/cockroachdb/pebble/vfs"
 	"github.com/cockroachdb/pebble/vfs/errorfs"
 	"github.com/stretchr/testify/require"
@@ -3697,3 +3698,159 @@ func TestTombstoneDensityCompactionMoveOptimization_InvalidStats(t *testing.T) {
 	pc := vs.picker.pickAutoNonScore(compactionEnv{diskAvailBytes: 1 << 30})
 	require.Nil(t, pc, "no compaction should be picked if stats are missing or invalid")
 }
+
+func TestCompactionDirectIO(t *testing.T) {
+	opts := &Options{
+		FS:                          vfs.Default,
+		DisableAutomaticCompactions: true,
+	}
+	opts.Local.DirectIOForCompactions = true
+	d, err := Open(t.TempDir(), opts)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, d.Close()) }()
+
+	rng := rand.New(rand.NewPCG(0, 0))
+	val := make([]byte, 100)
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 10000; j++ {
+			for k := range val {
+				val[k] = byte(rng.Uint32())
+			}
+			require.NoError(t, d.Set([]byte(fmt.Sprintf("key%05d", j)), val, nil))
+		}
+		require.NoError(t, d.Flush())
+	}
+	// Flushes don't use direct I/O.
+	require.Zero(t, d.Metrics().DirectIO)
+	require.NoError(t, d.Compact(context.Background(), []byte("a"), []byte("z"), false))
+
+	m := d.Metrics().DirectIO
+	if m.Fallbacks > 0 {
+		t.Skip("direct I/O is not supported")
+	}
+	require.NotZero(t, m.BytesRead)
+	require.NotZero(t, m.BytesWritten)
+	// Wait for the compaction inputs to be deleted.
+	require.Eventually(t, func() bool {
+		return len(d.objProvider.List()) == 1
+	}, 10*time.Second, time.Millisecond)
+	// The compaction output was written with direct I/O, so only the parts read
+	// when the table was opened are in the page cache.
+	resident, size, err := d.PageCacheResidency()
+	require.NoError(t, err)
+	require.Less(t, 2*resident, size)
+}
+
+func TestCompactionSpanPolicyTableFilter(t *testing.T) {
+	opts := &Options{
+		FS:                          vfs.NewMem(),
+		DisableAutomaticCompactions: true,
+		SpanPolicyFunc: MakeStaticSpanPolicyFunc(
+			base.DefaultComparer.Compare,
+			SpanPolicy{
+				KeyRange:          KeyRange{Start: []byte("b"), End: []byte("c")},
+				TableFilterPolicy: base.NoFilterPolicy,
+			},
+			SpanPolicy{
+				KeyRange:               KeyRange{Start: []byte("c"), End: []byte("d")},
+				OptimizeFiltersForHits: true,
+			},
+		),
+	}
+	opts.ApplyTableFilterPolicy(func() DBTableFilterPolicy { return DBTableFilterPolicyUniform })
+	d, err := Open("", opts)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, d.Close()) }()
+
+	// filterFamilies returns the filter family of the table in each level that
+	// contains the given key.
+	filterFamilies := func(key string) map[int]string {
+		tables, err := d.SSTables(WithProperties())
+		require.NoError(t, err)
+		res := make(map[int]string)
+		for level := range tables {
+			for _, tbl := range tables[level] {
+				if tbl.Smallest.UserKey[0] == key[0] {
+					res[level] = tbl.Properties.FilterFamily
+				}
+			}
+		}
+		return res
+	}
+
+	for i := 0; i < 2; i++ {
+		for _, prefix := range []string{"a", "b", "c"} {
+			for j := 0; j < 10; j++ {
+				require.NoError(t, d.Set([]byte(fmt.Sprintf("%s%03d", prefix, 2*j+i)), nil, nil))
+			}
+		}
+		require.NoError(t, d.Flush())
+	}
+	bloomFamily := string(bloom.Family)
+	require.Equal(t, map[int]string{0: bloomFamily}, filterFamilies("a"))
+	require.Equal(t, map[int]string{0: ""}, filterFamilies("b"))
+	require.Equal(t, map[int]string{0: bloomFamily}, filterFamilies("c"))
+
+	// Compacting into the bottommost level drops the filters for the span that
+	// optimizes filters for hits.
+	require.NoError(t, d.Compact(context.Background(), []byte("a"), []byte("z"), false))
+	require.Equal(t, map[int]string{6: bloomFamily}, filterFamilies("a"))
+	require.Equal(t, map[int]string{6: ""}, filterFamilies("b"))
+	require.Equal(t, map[int]string{6: ""}, filterFamilies("c"))
+
+	// Filter checks are attributed to the level of the table.
+	iter, err := d.NewIter(&IterOptions{UseL6Filters: true})
+	require.NoError(t, err)
+	for _, k := range []string{"a005x", "b005x", "c005x"} {
+		require.False(t, iter.SeekPrefixGE([]byte(k)))
+	}
+	require.NoError(t, iter.Close())
+	m := d.Metrics().Filter
+	require.Equal(t, int64(1), m.Hits)
+	require.Equal(t, int64(1), m.ByLevel[6].Hits)
+}
+
+func TestCompactionRateLimiting(t *testing.T) {
+	limiter := vfs.NewRateLimiter()
+	opts := &Options{
+		FS:                          vfs.NewMem(),
+		DisableAutomaticCompactions: true,
+		RateLimiter:                 limiter,
+	}
+	d, err := Open("", opts)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, d.Close()) }()
+
+	rng := rand.New(rand.NewPCG(0, 0))
+	val := make([]byte, 100)
+	writeAndFlush := func() {
+		for j := 0; j < 1000; j++ {
+			for k := range val {
+				val[k] = byte(rng.Uint32())
+			}
+			require.NoError(t, d.Set([]byte(fmt.Sprintf("key%05d", j)), val, nil))
+		}
+		require.NoError(t, d.Flush())
+	}
+	compact := func() {
+		require.NoError(t, d.Compact(context.Background(), []byte("a"), []byte("z"), false))
+	}
+	compactionReads := vfs.ReadCategory(categoryCompaction.String())
+
+	// Limit compaction writes to less than the size of the compaction output.
+	writeAndFlush()
+	writeAndFlush()
+	limiter.SetWriteLimit("pebble-compaction", 64<<10)
+	compact()
+	require.NotZero(t, limiter.ThrottledWrites("pebble-compaction"))
+	require.Zero(t, limiter.ThrottledWrites(vfs.WriteCategoryWAL))
+	require.Zero(t, limiter.ThrottledWrites("pebble-memtable-flush"))
+	require.Zero(t, limiter.ThrottledReads(compactionReads))
+
+	// Limit compaction reads to less than the size of the compaction inputs.
+	limiter.SetWriteLimit("pebble-compaction", 0)
+	limiter.SetReadLimit(compactionReads, 128<<10)
+	writeAndFlush()
+	compact()
+	require.NotZero(t, limiter.ThrottledReads(compactionReads))
+}
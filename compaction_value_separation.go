@@ -53,6 +53,15 @@ func (d *DB) determineCompactionValueSeparation(
 	}
 
 	// This compaction should write values to new blob files.
+	var newColdBlobObject func() (objstorage.Writable, objstorage.ObjectMetadata, error)
+	if d.opts.Tiering.CreateColdOnShared {
+		coldCreateOpts := c.objCreateOpts
+		coldCreateOpts.Tier = base.ColdTier
+		newColdBlobObject = func() (objstorage.Writable, objstorage.ObjectMetadata, error) {
+			return d.newCompactionOutputBlob(
+				jobID, c.kind, c.outputLevel.level, &c.metrics.bytesWritten, coldCreateOpts)
+		}
+	}
 	return valsep.NewWriteNewBlobFiles(
 		d.opts.Comparer,
 		func() (objstorage.Writable, objstorage.ObjectMetadata, error) {
@@ -65,6 +74,7 @@ func (d *DB) determineCompactionValueSeparation(
 		valsep.WriteNewBlobFilesOptions{
 			InputBlobPhysicalFiles: blobFileSet,
 			ShortAttrExtractor:     d.opts.ShortAttributeExtractor,
+			NewColdBlobObject:      newColdBlobObject,
 			InvalidValueCallback: func(userKey []byte, value []byte, err error) {
 				// The value may not be safe, so it will be redacted when redaction
 				// is enabled.
@@ -18,6 +18,7 @@ import (
 
 	"github.com/cockroachdb/crlib/crtime"
 	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/errors/oserror"
 	"github.com/cockroachdb/pebble/internal/arenaskl"
 	"github.com/cockroachdb/pebble/internal/base"
 	"github.com/cockroachdb/pebble/internal/bytesprofile"
@@ -40,6 +41,7 @@ import (
 	"github.com/cockroachdb/pebble/sstable"
 	"github.com/cockroachdb/pebble/sstable/blob"
 	"github.com/cockroachdb/pebble/sstable/block"
+	"github.com/cockroachdb/pebble/vfs"
 	"github.com/cockroachdb/pebble/vfs/atomicfs"
 	"github.com/cockroachdb/pebble/wal"
 	"github.com/cockroachdb/tokenbucket"
@@ -297,6 +299,15 @@ type DB struct {
 
 	dirs *resolvedDirs
 
+	// replica is set when the DB was opened with Options.RemoteReplica.
+	replica *remoteReplica
+	// replicaSnapshots holds the snapshots published by PublishReplicaSnapshot
+	// that haven't been released.
+	replicaSnapshots struct {
+		sync.Mutex
+		published []*ReplicaSnapshot
+	}
+
 	fileCache            *fileCacheHandle
 	newIters             tableNewIters
 	tableNewRangeKeyIter keyspanimpl.TableNewSpanIter
@@ -546,6 +557,7 @@ type DB struct {
 
 	tableDiskUsageAnnotator    manifest.TableAnnotator[TableUsageByPlacement]
 	blobFileDiskUsageAnnotator manifest.BlobFileAnnotator[metrics.CountAndSizeByPlacement]
+	blobFileTierAnnotator      manifest.BlobFileAnnotator[metrics.CountAndSizeByTier]
 
 	// problemSpans keeps track of spans of keys within LSM levels where
 	// compactions have failed; used to avoid retrying these compactions too
@@ -1825,6 +1837,9 @@ func (d *DB) Close() error {
 	if err := d.closed.Load(); err != nil {
 		panic(err)
 	}
+	if d.replica != nil {
+		d.replica.stop()
+	}
 	d.compactionSchedulers.Wait()
 	// Compactions can be asynchronously started by the CompactionScheduler
 	// calling d.Schedule. When this Unregister returns, we know that the
@@ -1956,6 +1971,7 @@ func (d *DB) Close() error {
 
 	err = firstError(err, d.fileCache.Close())
 
+	d.releaseReplicaSnapshots(nil, true /* all */)
 	err = firstError(err, d.objProvider.Close())
 
 	// If the options include a closer to 'close' the filesystem, close it.
@@ -2310,6 +2326,7 @@ func (d *DB) Metrics() *Metrics {
 	d.mu.versions.logLock()
 	metrics.private.manifestFileSize = uint64(d.mu.versions.manifest.Size())
 	backingStats := d.mu.versions.latest.virtualBackings.Stats()
+	backingsByTier := d.virtualBackingsByTier()
 	blobStats, _ := d.mu.versions.latest.blobFiles.Stats()
 	d.mu.versions.logUnlock()
 
@@ -2334,6 +2351,8 @@ func (d *DB) Metrics() *Metrics {
 	metrics.Table.Physical.Live.Shared = tableDiskUsage.Shared.Physical
 	metrics.Table.Physical.Live.External = tableDiskUsage.External.Physical
 	metrics.Table.Physical.Live.Accumulate(backingStats)
+	metrics.Table.Physical.LiveByTier = tableDiskUsage.PhysicalByTier
+	metrics.Table.Physical.LiveByTier.Accumulate(backingsByTier)
 
 	// TODO(jackson): Consider making these metrics optional.
 	aggProps := tablePropsAnnotator.MultiLevelAnnotation(vers.Levels[:])
@@ -2351,6 +2370,7 @@ func (d *DB) Metrics() *Metrics {
 	}
 
 	metrics.BlobFiles.Live = d.blobFileDiskUsageAnnotator.Annotation(&vers.BlobFiles)
+	metrics.BlobFiles.LiveByTier = d.blobFileTierAnnotator.Annotation(&vers.BlobFiles)
 
 	metrics.BlobFiles.ValueSize = blobStats.ValueSize
 	metrics.BlobFiles.ReferencedValueSize = blobStats.ReferencedValueSize
@@ -2370,6 +2390,7 @@ func (d *DB) Metrics() *Metrics {
 	metrics.DeletePacer = deletePacerMetrics
 
 	metrics.SecondaryCacheMetrics = d.objProvider.Metrics()
+	metrics.DirectIO = d.objProvider.DirectIOMetrics()
 
 	metrics.Uptime = d.opts.private.timeNow().Sub(d.openedAt)
 
@@ -2378,6 +2399,40 @@ func (d *DB) Metrics() *Metrics {
 	return metrics
 }
 
+// PageCacheResidency returns the number of bytes of the DB's local sstables
+// and blob files that are resident in the OS page cache, along with the total
+// size of these files. It can be used to compare page cache usage with and
+// without Options.Local.DirectIOForCompactions. Objects on remote storage or
+// on the cold tier are not included.
+//
+// It returns vfs.ErrDirectIOUnsupported if the file system does not support
+// querying page cache residency.
+func (d *DB) PageCacheResidency() (resident, size int64, err error) {
+	if err := d.closed.Load(); err != nil {
+		panic(err)
+	}
+	for _, meta := range d.objProvider.List() {
+		if meta.IsRemote() || meta.Local.Tier != base.HotTier {
+			continue
+		}
+		f, err := d.opts.FS.Open(d.objProvider.Path(meta))
+		if oserror.IsNotExist(err) {
+			// The object was deleted concurrently.
+			continue
+		} else if err != nil {
+			return 0, 0, err
+		}
+		r, s, err := vfs.PageCacheResidency(f)
+		err = firstError(err, f.Close())
+		if err != nil {
+			return 0, 0, err
+		}
+		resident += r
+		size += s
+	}
+	return resident, size, nil
+}
+
 // sstablesOptions hold the optional parameters to retrieve TableInfo for all sstables.
 type sstablesOptions struct {
 	// set to true will return the sstable properties in TableInfo
@@ -65,6 +65,10 @@ func (d *DB) EstimateDiskUsageByBackingType(
 // tables overlapping the bounds, the usage is a best-effort estimation).
 type TableUsageByPlacement struct {
 	metrics.ByPlacement[TableDiskUsage]
+
+	// PhysicalByTier contains the count and total size of physical tables in
+	// the set, broken down by storage tier.
+	PhysicalByTier metrics.CountAndSizeByTier
 }
 
 // Accumulate adds the rhs counts and sizes to the receiver.
@@ -72,6 +76,7 @@ func (u *TableUsageByPlacement) Accumulate(rhs TableUsageByPlacement) {
 	u.Local.Accumulate(rhs.Local)
 	u.Shared.Accumulate(rhs.Shared)
 	u.External.Accumulate(rhs.External)
+	u.PhysicalByTier.Accumulate(rhs.PhysicalByTier)
 }
 
 // TableDiskUsage contains space usage information for a set of sstables.
@@ -114,6 +119,9 @@ func (d *DB) singleTableDiskUsage(
 	placement := objstorage.Placement(d.objProvider, base.FileTypeTable, fileNum)
 	var res TableUsageByPlacement
 	res.Set(placement, u)
+	if !isVirtual {
+		res.PhysicalByTier.Ptr(d.objectTier(base.FileTypeTable, fileNum)).Inc(fileSize)
+	}
 	return res
 }
 
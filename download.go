@@ -313,6 +313,35 @@ func (d *DB) tryLaunchDownloadForFile(
 	return doneCh, true
 }
 
+// verifyDownloadSignatures authenticates the external sstables that are
+// inputs of the given download compaction using Options.SigningKeys, reading
+// each sstable in its entirety. External sstables are authenticated when they
+// are ingested, but the external objects can change before they are
+// downloaded.
+//
+// d.mu must be held when calling this method. The mutex will be released when
+// doing IO.
+func (d *DB) verifyDownloadSignatures(c *tableCompaction) error {
+	var external []base.DiskFileNum
+	for i := range c.inputs {
+		for f := range c.inputs[i].files.All() {
+			objMeta, err := d.objProvider.Lookup(base.FileTypeTable, f.TableBacking.DiskFileNum)
+			if err != nil {
+				return err
+			}
+			if objMeta.IsExternal() {
+				external = append(external, objMeta.DiskFileNum)
+			}
+		}
+	}
+	if len(external) == 0 {
+		return nil
+	}
+	d.mu.Unlock()
+	defer d.mu.Lock()
+	return verifyTableSignatures(c.ctx, d.objProvider, d.opts.SigningKeys, external)
+}
+
 type launchDownloadResult int8
 
 const (
@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/pebble/objstorage"
 	"github.com/cockroachdb/pebble/objstorage/remote"
 	"github.com/cockroachdb/pebble/vfs"
+	"github.com/cockroachdb/pebble/wal"
 	"github.com/cockroachdb/redact"
 )
 
@@ -523,6 +524,54 @@ func (i DownloadInfo) SafeFormat(w redact.SafePrinter, _ rune) {
 	}
 }
 
+// WarmSpansInfo contains the info for a DB.WarmSpans() event.
+type WarmSpansInfo struct {
+	// JobID is the ID of the warming job.
+	JobID int
+
+	Spans []KeyRange
+
+	// TablesTotal is the number of remote tables that overlap the spans.
+	TablesTotal int
+	// TablesWarmed is the number of remote tables that were processed so far.
+	TablesWarmed int
+	// BytesFetched is the number of bytes read from remote storage so far.
+	BytesFetched int64
+
+	// Duration is the time since the operation was started.
+	Duration time.Duration
+	Done     bool
+	Err      error
+}
+
+func (i WarmSpansInfo) String() string {
+	return redact.StringWithoutMarkers(i)
+}
+
+// SafeFormat implements redact.SafeFormatter.
+func (i WarmSpansInfo) SafeFormat(w redact.SafePrinter, _ rune) {
+	switch {
+	case i.Err != nil:
+		w.Printf("[JOB %d] warming error after %.1fs (%d/%d tables, fetched %s): %s",
+			redact.Safe(i.JobID), redact.Safe(i.Duration.Seconds()), redact.Safe(i.TablesWarmed),
+			redact.Safe(i.TablesTotal), redact.Safe(humanize.Bytes.Int64(i.BytesFetched)), i.Err)
+
+	case i.Done:
+		w.Printf("[JOB %d] warming finished in %.1fs (%d tables, fetched %s)",
+			redact.Safe(i.JobID), redact.Safe(i.Duration.Seconds()), redact.Safe(i.TablesWarmed),
+			redact.Safe(humanize.Bytes.Int64(i.BytesFetched)))
+
+	case i.TablesWarmed == 0:
+		w.Printf("[JOB %d] starting warming of %d remote tables for %d spans",
+			redact.Safe(i.JobID), redact.Safe(i.TablesTotal), redact.Safe(len(i.Spans)))
+
+	default:
+		w.Printf("[JOB %d] warmed %d/%d tables (fetched %s)",
+			redact.Safe(i.JobID), redact.Safe(i.TablesWarmed), redact.Safe(i.TablesTotal),
+			redact.Safe(humanize.Bytes.Int64(i.BytesFetched)))
+	}
+}
+
 // ManifestCreateInfo contains info about a manifest creation event.
 type ManifestCreateInfo struct {
 	// JobID is the ID of the job the caused the manifest to be created.
@@ -769,6 +818,57 @@ func (i WALDeleteInfo) SafeFormat(w redact.SafePrinter, _ rune) {
 	w.Printf("[JOB %d] WAL deleted %s", redact.Safe(i.JobID), i.FileNum)
 }
 
+// WALRecoveryStopInfo contains the info for a WAL recovery stopped event,
+// which is emitted by Open when Options.WALRecoveryTarget is set.
+type WALRecoveryStopInfo struct {
+	// JobID is the ID of the job that replayed the WALs.
+	JobID int
+	// TargetSeqNum is Options.WALRecoveryTarget.SeqNum.
+	TargetSeqNum base.SeqNum
+	// LastAppliedWAL, LastAppliedOffset and LastAppliedSeqNum identify the
+	// last batch that was applied: its WAL, its offset within the WAL, and the
+	// largest sequence number it contains. They are zero if no batch was
+	// applied.
+	LastAppliedWAL    base.DiskFileNum
+	LastAppliedOffset wal.Offset
+	LastAppliedSeqNum base.SeqNum
+	// Stopped is true if replay stopped before the end of the WALs, at a batch
+	// containing a sequence number greater than TargetSeqNum. StopWAL,
+	// StopOffset and StopSeqNum identify that batch: its WAL, its offset within
+	// the WAL, and its smallest sequence number.
+	Stopped    bool
+	StopWAL    base.DiskFileNum
+	StopOffset wal.Offset
+	StopSeqNum base.SeqNum
+	// ArchivedWALs are the WALs, starting with StopWAL, that were not fully
+	// replayed and were copied into ArchiveDir.
+	ArchiveDir   string
+	ArchivedWALs []base.DiskFileNum
+}
+
+func (i WALRecoveryStopInfo) String() string {
+	return redact.StringWithoutMarkers(i)
+}
+
+// SafeFormat implements redact.SafeFormatter.
+func (i WALRecoveryStopInfo) SafeFormat(w redact.SafePrinter, _ rune) {
+	w.Printf("[JOB %d] WAL recovery to seqnum %s", redact.Safe(i.JobID), i.TargetSeqNum)
+	if i.LastAppliedSeqNum != 0 {
+		w.Printf(": last applied batch in WAL %s at %s, up to seqnum %s",
+			i.LastAppliedWAL, i.LastAppliedOffset, i.LastAppliedSeqNum)
+	} else {
+		w.Printf(": no batches applied")
+	}
+	if !i.Stopped {
+		w.Printf("; reached the end of the WALs")
+		return
+	}
+	w.Printf("; stopped in WAL %s at %s, at seqnum %s", i.StopWAL, i.StopOffset, i.StopSeqNum)
+	if len(i.ArchivedWALs) > 0 {
+		w.Printf("; archived WALs %s to %s", i.ArchivedWALs, i.ArchiveDir)
+	}
+}
+
 // WriteStallBeginInfo contains the info for a write stall begin event.
 type WriteStallBeginInfo struct {
 	Reason string
@@ -1017,6 +1117,16 @@ type EventListener struct {
 	// DownloadEnd is invoked when a db.Download operation completes.
 	DownloadEnd func(DownloadInfo)
 
+	// WarmSpansBegin is invoked when a db.WarmSpans operation starts.
+	WarmSpansBegin func(WarmSpansInfo)
+
+	// WarmSpansProgress is invoked by a db.WarmSpans operation after each
+	// remote table is warmed.
+	WarmSpansProgress func(WarmSpansInfo)
+
+	// WarmSpansEnd is invoked when a db.WarmSpans operation completes.
+	WarmSpansEnd func(WarmSpansInfo)
+
 	// FormatUpgrade is invoked after the database's FormatMajorVersion
 	// is upgraded.
 	FormatUpgrade func(FormatMajorVersion)
@@ -1050,6 +1160,10 @@ type EventListener struct {
 	// WALDeleted is invoked after a WAL has been deleted.
 	WALDeleted func(WALDeleteInfo)
 
+	// WALRecoveryStopped is invoked by Open when Options.WALRecoveryTarget is
+	// set, after the WALs have been replayed up to the target.
+	WALRecoveryStopped func(WALRecoveryStopInfo)
+
 	// WriteStallBegin is invoked when writes are intentionally delayed.
 	WriteStallBegin func(WriteStallBeginInfo)
 
@@ -1120,6 +1234,15 @@ func (l *EventListener) EnsureDefaults(logger Logger) {
 	if l.DownloadEnd == nil {
 		l.DownloadEnd = func(info DownloadInfo) {}
 	}
+	if l.WarmSpansBegin == nil {
+		l.WarmSpansBegin = func(info WarmSpansInfo) {}
+	}
+	if l.WarmSpansProgress == nil {
+		l.WarmSpansProgress = func(info WarmSpansInfo) {}
+	}
+	if l.WarmSpansEnd == nil {
+		l.WarmSpansEnd = func(info WarmSpansInfo) {}
+	}
 	if l.FormatUpgrade == nil {
 		l.FormatUpgrade = func(v FormatMajorVersion) {}
 	}
@@ -1150,6 +1273,9 @@ func (l *EventListener) EnsureDefaults(logger Logger) {
 	if l.WALDeleted == nil {
 		l.WALDeleted = func(info WALDeleteInfo) {}
 	}
+	if l.WALRecoveryStopped == nil {
+		l.WALRecoveryStopped = func(info WALRecoveryStopInfo) {}
+	}
 	if l.WriteStallBegin == nil {
 		l.WriteStallBegin = func(info WriteStallBeginInfo) {}
 	}
@@ -1214,6 +1340,15 @@ func MakeLoggingEventListener(logger Logger) EventListener {
 		DownloadEnd: func(info DownloadInfo) {
 			logger.Infof("%s", info)
 		},
+		WarmSpansBegin: func(info WarmSpansInfo) {
+			logger.Infof("%s", info)
+		},
+		WarmSpansProgress: func(info WarmSpansInfo) {
+			logger.Infof("%s", info)
+		},
+		WarmSpansEnd: func(info WarmSpansInfo) {
+			logger.Infof("%s", info)
+		},
 		FormatUpgrade: func(v FormatMajorVersion) {
 			logger.Infof("upgraded to format version: %s", v)
 		},
@@ -1244,6 +1379,9 @@ func MakeLoggingEventListener(logger Logger) EventListener {
 		WALDeleted: func(info WALDeleteInfo) {
 			logger.Infof("%s", info)
 		},
+		WALRecoveryStopped: func(info WALRecoveryStopInfo) {
+			logger.Infof("%s", info)
+		},
 		WriteStallBegin: func(info WriteStallBeginInfo) {
 			logger.Infof("%s", info)
 		},
@@ -1316,6 +1454,18 @@ func TeeEventListener(a, b EventListener) EventListener {
 			a.DownloadEnd(info)
 			b.DownloadEnd(info)
 		},
+		WarmSpansBegin: func(info WarmSpansInfo) {
+			a.WarmSpansBegin(info)
+			b.WarmSpansBegin(info)
+		},
+		WarmSpansProgress: func(info WarmSpansInfo) {
+			a.WarmSpansProgress(info)
+			b.WarmSpansProgress(info)
+		},
+		WarmSpansEnd: func(info WarmSpansInfo) {
+			a.WarmSpansEnd(info)
+			b.WarmSpansEnd(info)
+		},
 		FormatUpgrade: func(v FormatMajorVersion) {
 			a.FormatUpgrade(v)
 			b.FormatUpgrade(v)
@@ -1356,6 +1506,10 @@ func TeeEventListener(a, b EventListener) EventListener {
 			a.WALDeleted(info)
 			b.WALDeleted(info)
 		},
+		WALRecoveryStopped: func(info WALRecoveryStopInfo) {
+			a.WALRecoveryStopped(info)
+			b.WALRecoveryStopped(info)
+		},
 		WriteStallBegin: func(info WriteStallBeginInfo) {
 			a.WriteStallBegin(info)
 			b.WriteStallBegin(info)
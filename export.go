@@ -0,0 +1,428 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package pebble
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble/objstorage"
+	"github.com/cockroachdb/pebble/objstorage/objstorageprovider"
+	"github.com/cockroachdb/pebble/objstorage/remote"
+	"github.com/cockroachdb/pebble/sstable/blob"
+	"github.com/cockroachdb/pebble/sstable/block"
+	"github.com/cockroachdb/pebble/valsep"
+	"github.com/cockroachdb/pebble/vfs"
+)
+
+// ExportDestination describes where DB.Export writes its files. Exactly one of
+// FS and Storage must be set.
+type ExportDestination struct {
+	// FS and Dir specify a local directory to write files to. The directory
+	// must exist.
+	FS  vfs.FS
+	Dir string
+	// Storage specifies a remote storage to write files to. Files are written
+	// as objects named after ExportOptions.NamePrefix.
+	Storage remote.Storage
+}
+
+// ExportOptions configures DB.Export.
+type ExportOptions struct {
+	// AllSuffixes, if true, exports every visible user key in the span,
+	// including all the keys that share a prefix (as defined by
+	// Comparer.Split), e.g. all the MVCC versions of a key. Otherwise only the
+	// first key for each prefix, i.e. the newest MVCC version, is exported.
+	//
+	// Either way, only the visible state of the span is exported: shadowed
+	// internal versions of a user key, point tombstones and range deletions
+	// are not (see DB.Export).
+	AllSuffixes bool
+	// TargetFileSize is the size at which an exported sstable is finished and a
+	// new one started. Files are only split between prefixes, so a file may
+	// exceed this size. Defaults to Options.TargetFileSizes[0].
+	TargetFileSize int64
+	// FormatMajorVersion is the format major version of the store that will
+	// ingest the exported files; it bounds the sstable and blob file formats.
+	// Defaults to the format major version of the exporting DB.
+	FormatMajorVersion FormatMajorVersion
+	// BlobValueMinSize, if positive, causes values of at least this size to be
+	// written to blob files accompanying each sstable. Blob files are only
+	// supported when exporting to a vfs.FS.
+	BlobValueMinSize int
+	// NamePrefix is prepended to the names of the exported files. Defaults to
+	// "export".
+	NamePrefix string
+}
+
+func (o *ExportOptions) ensureDefaults(d *DB) {
+	if o.TargetFileSize <= 0 {
+		o.TargetFileSize = d.opts.TargetFileSizes[0]
+	}
+	if o.FormatMajorVersion == FormatDefault {
+		o.FormatMajorVersion = d.FormatMajorVersion()
+	}
+	if o.NamePrefix == "" {
+		o.NamePrefix = "export"
+	}
+}
+
+// ExportManifest describes the files written by DB.Export.
+type ExportManifest struct {
+	// Files are ordered by key and have non-overlapping bounds that together
+	// tile the exported span.
+	Files []ExportedFile
+}
+
+// ExportedFile describes a single sstable written by DB.Export.
+type ExportedFile struct {
+	// Name is the path of the sstable (for a vfs.FS destination) or the name
+	// of the object (for a remote.Storage destination).
+	Name string
+	// BlobNames are the blob files referenced by the sstable, in the order
+	// expected by LocalSST.BlobPaths.
+	BlobNames []string
+	// StartKey and EndKey are the bounds [StartKey, EndKey) of the file. They
+	// never have suffixes.
+	StartKey, EndKey []byte
+	// HasPointKey and HasRangeKey indicate the kinds of keys in the file.
+	HasPointKey, HasRangeKey bool
+	// Size is the total size of the sstable and its blob files.
+	Size uint64
+}
+
+// LocalSSTables returns the exported files in the form accepted by
+// DB.IngestAndExciseWithBlobs. It is only meaningful for files exported to a
+// vfs.FS.
+func (m *ExportManifest) LocalSSTables() LocalSSTables {
+	ssts := make(LocalSSTables, len(m.Files))
+	for i := range m.Files {
+		ssts[i] = LocalSST{Path: m.Files[i].Name, BlobPaths: m.Files[i].BlobNames}
+	}
+	return ssts
+}
+
+// ExternalFiles returns the exported files in the form accepted by
+// DB.IngestExternalFiles, given the locator under which the destination
+// remote.Storage is registered in the ingesting store.
+func (m *ExportManifest) ExternalFiles(locator remote.Locator) []ExternalFile {
+	files := make([]ExternalFile, len(m.Files))
+	for i, f := range m.Files {
+		files[i] = ExternalFile{
+			Locator:     locator,
+			ObjName:     f.Name,
+			Size:        f.Size,
+			StartKey:    f.StartKey,
+			EndKey:      f.EndKey,
+			HasPointKey: f.HasPointKey,
+			HasRangeKey: f.HasRangeKey,
+		}
+	}
+	return files
+}
+
+// Export writes the keys in span, as of a consistent point in time, to
+// standalone sstables at dest that another store can ingest. The returned
+// manifest describes the files.
+//
+// The files contain the visible state of the span: merge operands are
+// resolved, and deleted keys (including keys covered by range deletions) are
+// omitted rather than exported as tombstones. Range deletions are not exported
+// either, so ingesting the files does not delete keys that the ingesting store
+// already has in the span, unless the ingestion excises it. Range keys are
+// exported truncated to each file's bounds. All keys are written with a zero
+// sequence number.
+//
+// The bounds of span must not have suffixes. If Export returns an error, any
+// files it created are removed.
+func (d *DB) Export(
+	ctx context.Context, span KeyRange, dest ExportDestination, opts ExportOptions,
+) (*ExportManifest, error) {
+	if err := d.closed.Load(); err != nil {
+		panic(err)
+	}
+	opts.ensureDefaults(d)
+	if (dest.FS == nil) == (dest.Storage == nil) {
+		return nil, errors.New("pebble: export requires exactly one of FS and Storage")
+	}
+	if opts.BlobValueMinSize > 0 {
+		if dest.Storage != nil {
+			return nil, errors.New("pebble: exporting blob files to remote storage is not supported")
+		}
+		if opts.FormatMajorVersion < FormatIngestBlobFiles {
+			return nil, errors.Newf("pebble: exporting blob files requires format major version %s", FormatIngestBlobFiles)
+		}
+	}
+	cmp, split := d.opts.Comparer.Compare, d.opts.Comparer.Split
+	if !span.Valid() || cmp(span.Start, span.End) >= 0 {
+		return nil, errors.New("pebble: invalid export span")
+	}
+	if split(span.Start) != len(span.Start) || split(span.End) != len(span.End) {
+		return nil, errors.New("pebble: export span bounds must not have suffixes")
+	}
+
+	iter, err := d.NewIterWithContext(ctx, &IterOptions{
+		LowerBound: span.Start,
+		UpperBound: span.End,
+		KeyTypes:   IterKeyTypePointsAndRanges,
+	})
+	if err != nil {
+		return nil, err
+	}
+	e := &exporter{
+		d:         d,
+		dest:      dest,
+		opts:      opts,
+		fileStart: slices.Clone(span.Start),
+	}
+	err = e.run(ctx, iter, span.End)
+	err = errors.CombineErrors(err, iter.Close())
+	if err != nil {
+		e.abort()
+		return nil, err
+	}
+	return &e.manifest, nil
+}
+
+// exportRangeKeySpan is a fragmented range key span buffered for the current
+// export file.
+type exportRangeKeySpan struct {
+	start, end []byte
+	keys       []RangeKeyData
+}
+
+type exporter struct {
+	d    *DB
+	dest ExportDestination
+	opts ExportOptions
+
+	manifest ExportManifest
+	// created contains the names of all files created so far, so they can be
+	// removed on error.
+	created []string
+
+	// w is the writer for the current file; nil if no file is open.
+	w         *valsep.SSTBlobWriter
+	name      string
+	blobNames []string
+	fileStart []byte
+	rangeKeys []exportRangeKeySpan
+	// blobValueBytes is the size of the values in the current file that were
+	// (likely) separated into blob files.
+	blobValueBytes int64
+}
+
+func (e *exporter) run(ctx context.Context, iter *Iterator, spanEnd []byte) error {
+	split, equal := e.d.opts.Comparer.Split, e.d.opts.Comparer.Equal
+	var lastPrefix, lastPointPrefix []byte
+	for valid := iter.First(); valid; valid = iter.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		key := iter.Key()
+		prefix := key[:split(key)]
+		if lastPrefix != nil && !equal(prefix, lastPrefix) && e.w != nil &&
+			e.estimatedSize() >= e.opts.TargetFileSize {
+			if err := e.finishFile(slices.Clone(prefix)); err != nil {
+				return err
+			}
+		}
+		lastPrefix = append(lastPrefix[:0], prefix...)
+
+		hasPoint, hasRange := iter.HasPointAndRange()
+		if hasRange && iter.RangeKeyChanged() {
+			start, end := iter.RangeBounds()
+			s := exportRangeKeySpan{start: slices.Clone(start), end: slices.Clone(end)}
+			for _, k := range iter.RangeKeys() {
+				s.keys = append(s.keys, RangeKeyData{Suffix: slices.Clone(k.Suffix), Value: slices.Clone(k.Value)})
+			}
+			e.rangeKeys = append(e.rangeKeys, s)
+		}
+		if !hasPoint {
+			continue
+		}
+		if !e.opts.AllSuffixes && lastPointPrefix != nil && equal(prefix, lastPointPrefix) {
+			continue
+		}
+		lastPointPrefix = append(lastPointPrefix[:0], prefix...)
+		value, err := iter.ValueAndErr()
+		if err != nil {
+			return err
+		}
+		if err := e.ensureWriter(); err != nil {
+			return err
+		}
+		if err := e.w.Set(key, value); err != nil {
+			return err
+		}
+		if e.opts.BlobValueMinSize > 0 && len(value) >= e.opts.BlobValueMinSize {
+			e.blobValueBytes += int64(len(value))
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	if e.w != nil || len(e.rangeKeys) > 0 {
+		if err := e.finishFile(slices.Clone(spanEnd)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// estimatedSize returns the estimated size of the current file, including its
+// blob files.
+func (e *exporter) estimatedSize() int64 {
+	return int64(e.w.SSTWriter.Raw().EstimatedSize()) + e.blobValueBytes
+}
+
+func (e *exporter) create(name string) (objstorage.Writable, error) {
+	if e.dest.FS != nil {
+		f, err := e.dest.FS.Create(name, vfs.WriteCategoryUnspecified)
+		if err != nil {
+			return nil, err
+		}
+		e.created = append(e.created, name)
+		return objstorageprovider.NewFileWritable(f), nil
+	}
+	obj, err := e.dest.Storage.CreateObject(name)
+	if err != nil {
+		return nil, err
+	}
+	e.created = append(e.created, name)
+	return objstorageprovider.NewRemoteWritable(obj), nil
+}
+
+func (e *exporter) fileName(suffix string) string {
+	name := fmt.Sprintf("%s-%06d%s", e.opts.NamePrefix, len(e.manifest.Files)+1, suffix)
+	if e.dest.FS != nil {
+		name = e.dest.FS.PathJoin(e.dest.Dir, name)
+	}
+	return name
+}
+
+func (e *exporter) ensureWriter() error {
+	if e.w != nil {
+		return nil
+	}
+	e.name = e.fileName(".sst")
+	writable, err := e.create(e.name)
+	if err != nil {
+		return err
+	}
+	fmv := e.opts.FormatMajorVersion
+	lo := &e.d.opts.Levels[numLevels-1]
+	e.blobNames = nil
+	e.blobValueBytes = 0
+	sstWriterOpts := e.d.opts.MakeWriterOptions(numLevels-1, fmv.MaxTableFormat())
+	if fmv < FormatSignedTables {
+		sstWriterOpts.SigningKeys = nil
+	}
+	e.w = valsep.NewSSTBlobWriter(writable, valsep.SSTBlobWriterOptions{
+		SSTWriterOpts: sstWriterOpts,
+		BlobWriterOpts: blob.FileWriterOptions{
+			Format:       fmv.MaxBlobFileFormat(),
+			Compression:  lo.Compression(),
+			ChecksumType: block.ChecksumTypeCRC32c,
+			FlushGovernor: block.MakeFlushGovernor(
+				lo.BlockSize,
+				lo.BlockSizeThreshold,
+				0, /* sizeClassAwareThreshold */
+				e.d.opts.AllocatorSizeClasses,
+			),
+		},
+		BlobFilesDisabled:                 e.opts.BlobValueMinSize <= 0,
+		ValueSeparationMinSize:            e.opts.BlobValueMinSize,
+		MVCCGarbageValueSeparationMinSize: e.opts.BlobValueMinSize,
+		NewBlobFileFn: func() (objstorage.Writable, error) {
+			name := e.fileName(fmt.Sprintf("-%d.blob", len(e.blobNames)))
+			w, err := e.create(name)
+			if err != nil {
+				return nil, err
+			}
+			e.blobNames = append(e.blobNames, name)
+			return w, nil
+		},
+	})
+	return nil
+}
+
+// finishFile writes the buffered range keys that start before end, truncated
+// to end, and finishes the current file with the bounds [fileStart, end).
+// Range keys extending past end are carried over to the next file.
+func (e *exporter) finishFile(end []byte) error {
+	if err := e.ensureWriter(); err != nil {
+		return err
+	}
+	cmp := e.d.opts.Comparer.Compare
+	var carried []exportRangeKeySpan
+	for _, s := range e.rangeKeys {
+		if cmp(s.start, end) >= 0 {
+			carried = append(carried, s)
+			continue
+		}
+		spanEnd := s.end
+		if cmp(spanEnd, end) > 0 {
+			spanEnd = end
+			carried = append(carried, exportRangeKeySpan{start: end, end: s.end, keys: s.keys})
+		}
+		for _, k := range s.keys {
+			if err := e.w.SSTWriter.RangeKeySet(s.start, spanEnd, k.Suffix, k.Value); err != nil {
+				return err
+			}
+		}
+	}
+	e.rangeKeys = carried
+
+	w := e.w
+	e.w = nil
+	if err := w.Close(); err != nil {
+		return err
+	}
+	meta, err := w.SSTWriter.Raw().Metadata()
+	if err != nil {
+		return err
+	}
+	blobMetas, err := w.BlobWriterMetas()
+	if err != nil {
+		return err
+	}
+	f := ExportedFile{
+		Name:        e.name,
+		BlobNames:   e.blobNames,
+		StartKey:    e.fileStart,
+		EndKey:      end,
+		HasPointKey: meta.HasPointKeys,
+		HasRangeKey: meta.HasRangeKeys,
+		Size:        meta.Size,
+	}
+	for _, bm := range blobMetas {
+		f.Size += bm.FileLen
+	}
+	e.manifest.Files = append(e.manifest.Files, f)
+	e.fileStart = end
+	return nil
+}
+
+// abort removes all files created by the export.
+func (e *exporter) abort() {
+	if e.w != nil {
+		_ = e.w.Close()
+		e.w = nil
+	}
+	for _, name := range e.created {
+		var err error
+		if e.dest.FS != nil {
+			err = e.dest.FS.Remove(name)
+		} else {
+			err = e.dest.Storage.Delete(name)
+		}
+		if err != nil {
+			e.d.opts.Logger.Errorf("export: failed to remove %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package pebble
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/pebble/internal/testkeys"
+	"github.com/cockroachdb/pebble/internal/testutils"
+	"github.com/cockroachdb/pebble/objstorage/remote"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport(t *testing.T) {
+	ctx := context.Background()
+	newOpts := func() *Options {
+		return &Options{
+			FS:                 vfs.NewMem(),
+			Comparer:           testkeys.Comparer,
+			FormatMajorVersion: FormatNewest,
+			Logger:             testutils.Logger{T: t},
+		}
+	}
+	src, err := Open("", newOpts())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, src.Close()) }()
+
+	for i := 0; i < 200; i++ {
+		for ts := 1; ts <= 3; ts++ {
+			key := fmt.Sprintf("k%03d@%d", i, ts)
+			require.NoError(t, src.Set([]byte(key), []byte(strings.Repeat(key, 20)), nil))
+		}
+	}
+	require.NoError(t, src.Flush())
+	require.NoError(t, src.DeleteRange([]byte("k010"), []byte("k020"), nil))
+	require.NoError(t, src.RangeKeySet([]byte("k050"), []byte("k150"), []byte("@9"), []byte("rk"), nil))
+	// Keys outside of the exported span.
+	require.NoError(t, src.Set([]byte("z@1"), []byte("z"), nil))
+
+	span := KeyRange{Start: []byte("k"), End: []byte("l")}
+	dump := func(d *DB, allSuffixes bool) string {
+		iter, err := d.NewIter(&IterOptions{KeyTypes: IterKeyTypePointsAndRanges})
+		require.NoError(t, err)
+		var buf strings.Builder
+		var lastPrefix []byte
+		for valid := iter.First(); valid; valid = iter.Next() {
+			key := iter.Key()
+			if !span.Contains(testkeys.Comparer.Compare, MakeInternalKey(key, 0, InternalKeyKindSet)) {
+				continue
+			}
+			if hasPoint, _ := iter.HasPointAndRange(); hasPoint {
+				prefix := key[:testkeys.Comparer.Split(key)]
+				if allSuffixes || string(prefix) != string(lastPrefix) {
+					fmt.Fprintf(&buf, "%s=%d\n", key, len(iter.Value()))
+				}
+				lastPrefix = append(lastPrefix[:0], prefix...)
+			}
+			if iter.RangeKeyChanged() {
+				start, end := iter.RangeBounds()
+				fmt.Fprintf(&buf, "[%s,%s)=%v\n", start, end, iter.RangeKeys())
+			}
+		}
+		require.NoError(t, iter.Close())
+		return buf.String()
+	}
+
+	t.Run("local", func(t *testing.T) {
+		fs := vfs.NewMem()
+		require.NoError(t, fs.MkdirAll("export", 0755))
+		m, err := src.Export(ctx, span, ExportDestination{FS: fs, Dir: "export"}, ExportOptions{
+			TargetFileSize:   4 << 10,
+			BlobValueMinSize: 50,
+		})
+		require.NoError(t, err)
+		require.Greater(t, len(m.Files), 1)
+		require.Equal(t, "k", string(m.Files[0].StartKey))
+		require.Equal(t, "l", string(m.Files[len(m.Files)-1].EndKey))
+		for i := 1; i < len(m.Files); i++ {
+			require.Equal(t, m.Files[i-1].EndKey, m.Files[i].StartKey)
+		}
+		require.NotEmpty(t, m.Files[0].BlobNames)
+
+		opts := newOpts()
+		opts.FS = fs
+		dst, err := Open("", opts)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, dst.Close()) }()
+		_, err = dst.IngestAndExciseWithBlobs(ctx, m.LocalSSTables(), nil /* shared */, nil /* external */, KeyRange{})
+		require.NoError(t, err)
+		require.Equal(t, dump(src, false /* allSuffixes */), dump(dst, true /* allSuffixes */))
+	})
+
+	t.Run("remote", func(t *testing.T) {
+		storage := remote.NewInMem()
+		m, err := src.Export(ctx, span, ExportDestination{Storage: storage}, ExportOptions{
+			AllSuffixes:    true,
+			TargetFileSize: 8 << 10,
+			NamePrefix:     "backup",
+		})
+		require.NoError(t, err)
+		require.Greater(t, len(m.Files), 1)
+
+		opts := newOpts()
+		opts.RemoteStorage = remote.MakeSimpleFactory(map[remote.Locator]remote.Storage{
+			remote.MakeLocator("backups"): storage,
+		})
+		dst, err := Open("", opts)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, dst.Close()) }()
+		_, err = dst.IngestExternalFiles(ctx, m.ExternalFiles(remote.MakeLocator("backups")))
+		require.NoError(t, err)
+		require.Equal(t, dump(src, true /* allSuffixes */), dump(dst, true /* allSuffixes */))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		storage := remote.NewInMem()
+		_, err := src.Export(ctx, span, ExportDestination{}, ExportOptions{})
+		require.Error(t, err)
+		_, err = src.Export(ctx, KeyRange{Start: []byte("k@1"), End: []byte("l")}, ExportDestination{Storage: storage}, ExportOptions{})
+		require.Error(t, err)
+		_, err = src.Export(ctx, span, ExportDestination{Storage: storage}, ExportOptions{BlobValueMinSize: 1})
+		require.Error(t, err)
+
+		// A canceled export leaves no files behind.
+		cctx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err = src.Export(cctx, span, ExportDestination{Storage: storage}, ExportOptions{})
+		require.ErrorIs(t, err, context.Canceled)
+		names, err := storage.List("", "")
+		require.NoError(t, err)
+		require.Empty(t, names)
+	})
+}
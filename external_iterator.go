@@ -184,6 +184,8 @@ func createExternalPointIter(
 				FilterBlockSizeLimit: sstable.NeverUseFilterBlock,
 				Env:                  readEnv,
 				ReaderProvider:       sstable.MakeTrivialReaderProvider(r),
+				PointKeyPredicate:    it.opts.PointKeyPredicate,
+				ValueProjection:      it.opts.ValueProjection,
 			})
 			if err == nil {
 				rangeDelIter, err = r.NewRawRangeDelIter(ctx, sstable.FragmentIterTransforms{
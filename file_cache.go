@@ -287,7 +287,8 @@ func (h *fileCacheHandle) findOrCreateBlob(
 	return valRef, err
 }
 
-// Evict the given file from the file cache and the block cache.
+// Evict the given file from the file cache and the block cache. It must only be
+// called once the file is obsolete.
 func (h *fileCacheHandle) Evict(fileNum base.DiskFileNum, fileType base.FileType) {
 	defer func() {
 		if p := recover(); p != nil {
@@ -298,6 +299,12 @@ func (h *fileCacheHandle) Evict(fileNum base.DiskFileNum, fileType base.FileType
 	h.blockCacheHandle.EvictFile(fileNum)
 }
 
+// filterMetricsTracker returns the tracker for the filter metrics of the
+// sstables opened through this handle.
+func (h *fileCacheHandle) filterMetricsTracker() *sstable.FilterMetricsTracker {
+	return h.readerOpts.FilterMetricsTracker
+}
+
 func (h *fileCacheHandle) SSTStatsCollector() *block.CategoryStatsCollector {
 	return &h.sstStatsCollector
 }
@@ -709,6 +716,17 @@ func (h *fileCacheHandle) newPointIter(
 		}
 	}
 
+	if opts != nil && opts.useRangeFilter && !internalOpts.compaction && !file.SyntheticPrefixAndSuffix.HasPrefix() {
+		mayContain, err := r.MayContainRange(ctx, internalOpts.readEnv, filterBlockSizeLimit, opts.LowerBound, opts.UpperBound)
+		if err != nil {
+			return nil, err
+		} else if !mayContain {
+			// The range filter guarantees that no point keys within the table
+			// fall within the bounds.
+			return nil, nil
+		}
+	}
+
 	if v.isShared && file.SyntheticSeqNum() != 0 {
 		// The table is shared and ingested.
 		hideObsoletePoints = true
@@ -746,6 +764,8 @@ func (h *fileCacheHandle) newPointIter(
 				References:   blobReferences,
 			},
 			MaximumSuffixProperty: opts.GetMaximumSuffixProperty(),
+			PointKeyPredicate:     opts.GetPointKeyPredicate(),
+			ValueProjection:       opts.GetValueProjection(),
 		})
 	}
 	if err != nil {
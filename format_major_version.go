@@ -275,6 +275,40 @@ const (
 	// Experimental versions, which are excluded by FormatNewest (but can be used
 	// in tests) can be defined here.
 
+	// FormatTableFormatV9 is a format major version enabling the sstable table
+	// format TableFormatPebblev9, which supports tiering metadata and
+	// partitioned table filters (see Options.PartitionTableFilters).
+	//
+	// Experimental.
+	FormatTableFormatV9
+
+	// FormatValueColumns is a format major version enabling the sstable table
+	// format TableFormatPebblev10, which can store structured values in value
+	// columns (see Options.ValueSchema).
+	//
+	// Experimental.
+	FormatValueColumns
+
+	// FormatRowBlockColumnarIndex is a format major version enabling the
+	// sstable table format TableFormatPebblev4ColumnarIndex, which uses
+	// columnar index blocks with row-based data blocks, for ingestion.
+	//
+	// This version doesn't change the sstables that the DB writes: it follows
+	// FormatColumnarBlocks, so the DB writes columnar sstables (with columnar
+	// index blocks) at this version, and a DB at an earlier version, which
+	// writes row-based sstables, can't use the format. It only allows ingesting
+	// sstables written in the format by sstable writers outside the DB.
+	//
+	// Experimental.
+	FormatRowBlockColumnarIndex
+
+	// FormatSignedTables is a format major version enabling signed sstables
+	// (see Options.SigningKeys). Signed sstables use a distinct footer magic
+	// number which older versions of Pebble can't read.
+	//
+	// Experimental.
+	FormatSignedTables
+
 	// -- Add experimental versions here --
 
 	// internalFormatNewest is the most recent, possibly experimental format major
@@ -307,6 +341,10 @@ func (v FormatMajorVersion) resolveDefault() FormatMajorVersion {
 func (v FormatMajorVersion) MaxTableFormat() sstable.TableFormat {
 	v = v.resolveDefault()
 	switch {
+	case v >= FormatValueColumns:
+		return sstable.TableFormatPebblev10
+	case v >= FormatTableFormatV9:
+		return sstable.TableFormatPebblev9
 	case v >= formatFooterAttributes:
 		return sstable.TableFormatPebblev7
 	case v >= FormatTableFormatV6:
@@ -334,6 +372,17 @@ func (v FormatMajorVersion) MinTableFormat() sstable.TableFormat {
 	return sstable.TableFormatPebblev1
 }
 
+// SupportsTableFormat returns true if sstables with the given
+// sstable.TableFormat can be used (e.g. ingested) at this FormatMajorVersion.
+func (v FormatMajorVersion) SupportsTableFormat(f sstable.TableFormat) bool {
+	if f == sstable.TableFormatPebblev4ColumnarIndex {
+		// TableFormatPebblev4ColumnarIndex is ordered among the row-based
+		// formats, but it was introduced after all of them.
+		return v.resolveDefault() >= FormatRowBlockColumnarIndex
+	}
+	return f >= v.MinTableFormat() && f <= v.MaxTableFormat()
+}
+
 // MaxBlobFileFormat returns the maximum blob.FileFormat that can be used at
 // this FormatMajorVersion. It can only be used on versions that support value
 // separation.
@@ -421,6 +470,18 @@ var formatMajorVersionMigrations = map[FormatMajorVersion]func(*DB) error{
 		}
 		return d.finalizeFormatVersUpgrade(FormatRowblkMarkedForCompaction)
 	},
+	FormatTableFormatV9: func(d *DB) error {
+		return d.finalizeFormatVersUpgrade(FormatTableFormatV9)
+	},
+	FormatValueColumns: func(d *DB) error {
+		return d.finalizeFormatVersUpgrade(FormatValueColumns)
+	},
+	FormatRowBlockColumnarIndex: func(d *DB) error {
+		return d.finalizeFormatVersUpgrade(FormatRowBlockColumnarIndex)
+	},
+	FormatSignedTables: func(d *DB) error {
+		return d.finalizeFormatVersUpgrade(FormatSignedTables)
+	},
 }
 
 const formatVersionMarkerName = `format-version`
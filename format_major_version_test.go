@@ -10,9 +10,12 @@ import (
 	"testing"
 
 	"github.com/cockroachdb/crlib/testutils/leaktest"
+	"github.com/cockroachdb/pebble/internal/base"
 	"github.com/cockroachdb/pebble/internal/testutils"
+	"github.com/cockroachdb/pebble/objstorage"
 	"github.com/cockroachdb/pebble/sstable"
 	"github.com/cockroachdb/pebble/sstable/blob"
+	"github.com/cockroachdb/pebble/sstable/tablefilters/bloom"
 	"github.com/cockroachdb/pebble/vfs"
 	"github.com/cockroachdb/pebble/vfs/atomicfs"
 	"github.com/stretchr/testify/require"
@@ -42,11 +45,15 @@ func TestFormatMajorVersionStableValues(t *testing.T) {
 	require.Equal(t, FormatMarkForCompactionInVersionEdit, FormatMajorVersion(28))
 	require.Equal(t, FormatIngestBlobFiles, FormatMajorVersion(29))
 	require.Equal(t, FormatRowblkMarkedForCompaction, FormatMajorVersion(30))
+	require.Equal(t, FormatTableFormatV9, FormatMajorVersion(31))
+	require.Equal(t, FormatValueColumns, FormatMajorVersion(32))
+	require.Equal(t, FormatRowBlockColumnarIndex, FormatMajorVersion(33))
+	require.Equal(t, FormatSignedTables, FormatMajorVersion(34))
 
 	// When we add a new version, we should add a check for the new version above
 	// in addition to updating the expected values below.
 	require.Equal(t, FormatNewest, FormatMajorVersion(30))
-	require.Equal(t, internalFormatNewest, FormatMajorVersion(30))
+	require.Equal(t, internalFormatNewest, FormatMajorVersion(34))
 }
 
 func TestFormatMajorVersion_MigrationDefined(t *testing.T) {
@@ -243,6 +250,10 @@ func TestFormatMajorVersions_TableFormat(t *testing.T) {
 		FormatMarkForCompactionInVersionEdit:        {sstable.TableFormatPebblev1, sstable.TableFormatPebblev7},
 		FormatIngestBlobFiles:                       {sstable.TableFormatPebblev1, sstable.TableFormatPebblev7},
 		FormatRowblkMarkedForCompaction:             {sstable.TableFormatPebblev1, sstable.TableFormatPebblev7},
+		FormatTableFormatV9:                         {sstable.TableFormatPebblev1, sstable.TableFormatPebblev9},
+		FormatValueColumns:                          {sstable.TableFormatPebblev1, sstable.TableFormatPebblev10},
+		FormatRowBlockColumnarIndex:                 {sstable.TableFormatPebblev1, sstable.TableFormatPebblev10},
+		FormatSignedTables:                          {sstable.TableFormatPebblev1, sstable.TableFormatPebblev10},
 	}
 
 	// Valid versions.
@@ -258,6 +269,30 @@ func TestFormatMajorVersions_TableFormat(t *testing.T) {
 	require.Panics(t, func() { _ = fmv.MinTableFormat() })
 }
 
+func TestFormatMajorVersions_SupportsTableFormat(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testCases := []struct {
+		fmv  FormatMajorVersion
+		tf   sstable.TableFormat
+		want bool
+	}{
+		{FormatMinSupported, sstable.TableFormatLevelDB, false},
+		{FormatMinSupported, sstable.TableFormatPebblev1, true},
+		{FormatMinSupported, sstable.TableFormatPebblev4, false},
+		{FormatNewest, sstable.TableFormatPebblev4, true},
+		{FormatNewest, sstable.TableFormatPebblev7, true},
+		{FormatNewest, sstable.TableFormatPebblev9, false},
+		{FormatNewest, sstable.TableFormatPebblev4ColumnarIndex, false},
+		{FormatValueColumns, sstable.TableFormatPebblev10, true},
+		{FormatValueColumns, sstable.TableFormatPebblev4ColumnarIndex, false},
+		{FormatRowBlockColumnarIndex, sstable.TableFormatPebblev4ColumnarIndex, true},
+		{FormatRowBlockColumnarIndex, sstable.TableFormatPebblev10, true},
+	}
+	for _, tc := range testCases {
+		require.Equalf(t, tc.want, tc.fmv.SupportsTableFormat(tc.tf), "%s %s", tc.fmv, tc.tf)
+	}
+}
+
 func TestFormatMajorVersions_BlobFileFormat(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	// NB: This test is intended to validate the mapping between every
@@ -273,6 +308,10 @@ func TestFormatMajorVersions_BlobFileFormat(t *testing.T) {
 		FormatMarkForCompactionInVersionEdit: blob.FileFormatV2,
 		FormatIngestBlobFiles:                blob.FileFormatV2,
 		FormatRowblkMarkedForCompaction:      blob.FileFormatV2,
+		FormatTableFormatV9:                  blob.FileFormatV2,
+		FormatValueColumns:                   blob.FileFormatV2,
+		FormatRowBlockColumnarIndex:          blob.FileFormatV2,
+		FormatSignedTables:                   blob.FileFormatV2,
 	}
 
 	// Valid versions.
@@ -313,6 +352,22 @@ func TestFormatMajorVersions_MaxTableFormat(t *testing.T) {
 			fmv:  FormatNewest,
 			want: sstable.TableFormatPebblev7,
 		},
+		{
+			fmv:  FormatTableFormatV9,
+			want: sstable.TableFormatPebblev9,
+		},
+		{
+			fmv:  FormatValueColumns,
+			want: sstable.TableFormatPebblev10,
+		},
+		{
+			fmv:  FormatRowBlockColumnarIndex,
+			want: sstable.TableFormatPebblev10,
+		},
+		{
+			fmv:  FormatSignedTables,
+			want: sstable.TableFormatPebblev10,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.fmv.String(), func(t *testing.T) {
@@ -327,3 +382,48 @@ func TestFormatMajorVersions_MaxTableFormat(t *testing.T) {
 		})
 	}
 }
+
+// TestPartitionTableFilters tests that table filters are only partitioned once
+// the format major version supports it.
+func TestPartitionTableFilters(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	for _, fmv := range []FormatMajorVersion{FormatNewest, FormatTableFormatV9} {
+		t.Run(fmv.String(), func(t *testing.T) {
+			opts := &Options{
+				FS:                    vfs.NewMem(),
+				FormatMajorVersion:    fmv,
+				PartitionTableFilters: true,
+			}
+			opts.ApplyTableFilterPolicy(func() DBTableFilterPolicy {
+				return UniformDBTableFilterPolicy(bloom.FilterPolicy(10))
+			})
+			for i := range opts.Levels {
+				opts.Levels[i].IndexBlockSize = 128
+			}
+			opts.Levels[0].BlockSize = 64
+			d, err := Open("", opts)
+			require.NoError(t, err)
+			defer func() { require.NoError(t, d.Close()) }()
+
+			for i := 0; i < 100; i++ {
+				require.NoError(t, d.Set(fmt.Appendf(nil, "key%03d", i), nil, nil))
+			}
+			require.NoError(t, d.Flush())
+
+			tables, err := d.SSTables()
+			require.NoError(t, err)
+			require.Len(t, tables[0], 1)
+			f, err := opts.FS.Open(base.MakeFilepath(opts.FS, "", base.FileTypeTable, tables[0][0].BackingSSTNum))
+			require.NoError(t, err)
+			readable, err := objstorage.NewSimpleReadable(f)
+			require.NoError(t, err)
+			r, err := sstable.NewReader(context.Background(), readable, opts.MakeReaderOptions())
+			require.NoError(t, err)
+			defer func() { require.NoError(t, r.Close()) }()
+			l, err := r.Layout()
+			require.NoError(t, err)
+			require.Greater(t, len(l.Index), 1)
+			require.Equal(t, fmv >= FormatTableFormatV9, len(l.FilterPartitions) > 0)
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/pebble/internal/overlap"
 	"github.com/cockroachdb/pebble/internal/sstableinternal"
 	"github.com/cockroachdb/pebble/objstorage"
+	"github.com/cockroachdb/pebble/objstorage/objstorageprovider"
 	"github.com/cockroachdb/pebble/objstorage/remote"
 	"github.com/cockroachdb/pebble/sstable"
 	"github.com/cockroachdb/pebble/sstable/blob"
@@ -199,6 +200,62 @@ func ingestLoad1External(
 	return meta, nil
 }
 
+// ingestVerifyExternalSignatures authenticates the external sstables using
+// Options.SigningKeys, reading each external object in its entirety. Local
+// sstables are authenticated by ingestLoad1.
+func (d *DB) ingestVerifyExternalSignatures(ctx context.Context, external []ExternalFile) error {
+	type objKey struct {
+		locator remote.Locator
+		objName string
+	}
+	// An object can be referenced by multiple external files; authenticate it
+	// once.
+	verified := make(map[objKey]struct{}, len(external))
+	for i := range external {
+		k := objKey{locator: external[i].Locator, objName: external[i].ObjName}
+		if _, ok := verified[k]; ok {
+			continue
+		}
+		storage, err := d.objProvider.RemoteStorage(k.locator)
+		if err != nil {
+			return err
+		}
+		objReader, size, err := storage.ReadObject(ctx, k.objName)
+		if err != nil {
+			return err
+		}
+		readable := objstorageprovider.NewRemoteReadable(objReader, size, storage.IsNotExistError)
+		err = sstable.VerifySignature(ctx, readable, d.opts.SigningKeys)
+		if err = errors.CombineErrors(err, readable.Close()); err != nil {
+			return errors.Wrapf(err, "pebble: authenticating external file %q", k.objName)
+		}
+		verified[k] = struct{}{}
+	}
+	return nil
+}
+
+// verifyTableSignatures authenticates the given sstables, which must be known
+// to the provider, using the signing keys, reading each sstable in its
+// entirety.
+func verifyTableSignatures(
+	ctx context.Context,
+	provider objstorage.Provider,
+	keys sstable.SigningKeyProvider,
+	fileNums []base.DiskFileNum,
+) error {
+	for _, fileNum := range fileNums {
+		readable, err := provider.OpenForReading(ctx, base.FileTypeTable, fileNum, objstorage.OpenOptions{MustExist: true})
+		if err != nil {
+			return err
+		}
+		err = sstable.VerifySignature(ctx, readable, keys)
+		if err = errors.CombineErrors(err, readable.Close()); err != nil {
+			return errors.Wrapf(err, "pebble: authenticating sstable %s", fileNum)
+		}
+	}
+	return nil
+}
+
 type rangeKeyIngestValidator struct {
 	// lastRangeKey is the last range key seen in the previous file.
 	lastRangeKey keyspan.Span
@@ -299,13 +356,21 @@ func ingestLoad1(
 	if err != nil {
 		return ingestLocalResult{}, err
 	}
-	if tf < fmv.MinTableFormat() || tf > fmv.MaxTableFormat() {
+	if !fmv.SupportsTableFormat(tf) {
 		return ingestLocalResult{}, errors.Newf(
-			"pebble: table format %s is not within range supported at DB format major version %d, (%s,%s)",
+			"pebble: table format %s is not supported at DB format major version %d, (%s,%s)",
 			tf, fmv, fmv.MinTableFormat(), fmv.MaxTableFormat(),
 		)
 	}
 
+	// Authenticate the entire table before ingesting it. Ingested tables are
+	// only required to be signed once the DB signs its own tables.
+	if opts.SigningKeys != nil && fmv >= FormatSignedTables {
+		if err := sstable.VerifySignature(ctx, readable, opts.SigningKeys); err != nil {
+			return ingestLocalResult{}, errors.Wrap(err, "pebble: authenticating ingested table")
+		}
+	}
+
 	props, err := r.ReadPropertiesBlock(ctx, nil /* buffer pool */)
 	if err != nil {
 		return ingestLocalResult{}, err
@@ -920,7 +985,7 @@ func ingestLinkLocal(
 //
 // ingestUnprotectExternalBackings() must be called after this function (even in
 // error cases).
-func (d *DB) ingestAttachRemote(jobID JobID, lr ingestLoadResult) error {
+func (d *DB) ingestAttachRemote(ctx context.Context, jobID JobID, lr ingestLoadResult) error {
 	remoteObjs := make([]objstorage.RemoteObjectToAttach, 0, len(lr.shared)+len(lr.external))
 	for i := range lr.shared {
 		backing, err := lr.shared[i].shared.Backing.Get()
@@ -1002,6 +1067,23 @@ func (d *DB) ingestAttachRemote(jobID JobID, lr ingestLoadResult) error {
 		}
 	}
 
+	// Authenticate the shared sstables. The external sstables were
+	// authenticated by ingestVerifyExternalSignatures.
+	if d.opts.SigningKeys != nil && d.FormatMajorVersion() >= FormatSignedTables && len(lr.shared) > 0 {
+		fileNums := make([]base.DiskFileNum, len(lr.shared))
+		for i := range lr.shared {
+			fileNums[i] = remoteObjMetas[i].DiskFileNum
+		}
+		if err := verifyTableSignatures(ctx, d.objProvider, d.opts.SigningKeys, fileNums); err != nil {
+			for i := range remoteObjMetas {
+				if err2 := d.objProvider.Remove(base.FileTypeTable, remoteObjMetas[i].DiskFileNum); err2 != nil {
+					d.opts.Logger.Errorf("ingest cleanup failed: %v", err2)
+				}
+			}
+			return err
+		}
+	}
+
 	if d.opts.EventListener.TableCreated != nil {
 		for i := range remoteObjMetas {
 			d.opts.EventListener.TableCreated(TableCreateInfo{
@@ -1758,6 +1840,11 @@ func (d *DB) ingest(ctx context.Context, args ingestArgs) (IngestOperationStats,
 			}
 		}
 	}
+	if d.opts.SigningKeys != nil && d.FormatMajorVersion() >= FormatSignedTables {
+		if err := d.ingestVerifyExternalSignatures(ctx, external); err != nil {
+			return IngestOperationStats{}, err
+		}
+	}
 	jobID := d.newJobID()
 
 	// Load the metadata for all the files being ingested. This step detects
@@ -1787,7 +1874,7 @@ func (d *DB) ingest(ctx context.Context, args ingestArgs) (IngestOperationStats,
 		return IngestOperationStats{}, err
 	}
 
-	err = d.ingestAttachRemote(jobID, loadResult)
+	err = d.ingestAttachRemote(ctx, jobID, loadResult)
 	defer d.ingestUnprotectExternalBackings(loadResult)
 	if err != nil {
 		if err2 := ingestCleanup(d.objProvider, loadResult.local, nil); err2 != nil {
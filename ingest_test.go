@@ -2500,6 +2500,190 @@ func TestIngestIdempotence(t *testing.T) {
 	require.NoError(t, d.Close())
 }
 
+func TestIngestSignedTables(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	keys := &sstable.StaticSigningKeys{
+		SigningKeyID: 1,
+		Keys:         map[uint32][]byte{1: []byte("key")},
+	}
+	mem := vfs.NewMem()
+	remoteStorage := remote.NewInMem()
+	opts := &Options{
+		FS:                 mem,
+		FormatMajorVersion: FormatNewest,
+		Logger:             testutils.Logger{T: t},
+		SigningKeys:        keys,
+	}
+	opts.RemoteStorage = remote.MakeSimpleFactory(map[remote.Locator]remote.Storage{
+		remote.MakeLocator("external-locator"): remoteStorage,
+	})
+	// A DB below FormatSignedTables can be opened with signing keys, including
+	// read-only. It doesn't sign the tables that it writes, nor does it require
+	// ingested tables to be signed.
+	old, err := Open("old", opts)
+	require.NoError(t, err)
+	require.Nil(t, old.makeWriterOptions(0).SigningKeys)
+	writerOpts := old.opts.MakeWriterOptions(0, old.TableFormat())
+	writerOpts.SigningKeys = nil
+	f, err := mem.Create("old-unsigned.sst", vfs.WriteCategoryUnspecified)
+	require.NoError(t, err)
+	sw := sstable.NewWriter(objstorageprovider.NewFileWritable(f), writerOpts)
+	require.NoError(t, sw.Set([]byte("a"), []byte("value")))
+	require.NoError(t, sw.Close())
+	require.NoError(t, old.Ingest(ctx, []string{"old-unsigned.sst"}))
+	require.NoError(t, old.Close())
+	readOnlyOpts := opts.Clone()
+	readOnlyOpts.ReadOnly = true
+	old, err = Open("old", readOnlyOpts)
+	require.NoError(t, err)
+	require.NoError(t, old.Close())
+
+	opts.FormatMajorVersion = FormatSignedTables
+	d, err := Open("", opts)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, d.Close()) }()
+	require.GreaterOrEqual(t, d.TableFormat(), sstable.TableFormatPebblev6)
+
+	writeTable := func(signed bool, key string) []byte {
+		writerOpts := d.opts.MakeWriterOptions(0, d.TableFormat())
+		if !signed {
+			writerOpts.SigningKeys = nil
+		}
+		f := &objstorage.MemObj{}
+		w := sstable.NewWriter(f, writerOpts)
+		require.NoError(t, w.Set([]byte(key), []byte("value")))
+		require.NoError(t, w.Close())
+		return f.Data()
+	}
+	ingestLocal := func(name string, data []byte) error {
+		f, err := mem.Create(name, vfs.WriteCategoryUnspecified)
+		require.NoError(t, err)
+		_, err = f.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		return d.Ingest(ctx, []string{name})
+	}
+	ingestExternal := func(name string, data []byte, key string) error {
+		w, err := remoteStorage.CreateObject(name)
+		require.NoError(t, err)
+		_, err = w.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		_, err = d.IngestExternalFiles(ctx, []ExternalFile{{
+			Locator:           remote.MakeLocator("external-locator"),
+			ObjName:           name,
+			Size:              uint64(len(data)),
+			StartKey:          []byte(key),
+			EndKey:            []byte(key),
+			EndKeyIsInclusive: true,
+			HasPointKey:       true,
+		}})
+		return err
+	}
+
+	require.NoError(t, ingestLocal("signed.sst", writeTable(true, "a")))
+	require.ErrorIs(t, ingestLocal("unsigned.sst", writeTable(false, "b")), sstable.ErrUnsignedTable)
+	tampered := writeTable(true, "c")
+	tampered[0] ^= 0xff
+	require.Error(t, ingestLocal("tampered.sst", tampered))
+
+	require.NoError(t, ingestExternal("signed-external.sst", writeTable(true, "d"), "d"))
+	require.ErrorIs(t, ingestExternal("unsigned-external.sst", writeTable(false, "e"), "e"), sstable.ErrUnsignedTable)
+
+	// Tables written by the DB are signed too.
+	require.NoError(t, d.Set([]byte("f"), []byte("value"), nil))
+	require.NoError(t, d.Flush())
+	for _, k := range []string{"a", "d", "f"} {
+		v, closer, err := d.Get([]byte(k))
+		require.NoError(t, err)
+		require.Equal(t, "value", string(v))
+		require.NoError(t, closer.Close())
+	}
+	for _, k := range []string{"b", "c", "e"} {
+		_, _, err := d.Get([]byte(k))
+		require.ErrorIs(t, err, ErrNotFound)
+	}
+
+	// External objects are authenticated again when they are downloaded, since
+	// they can change after they are ingested.
+	require.NoError(t, ingestExternal("replaced-external.sst", writeTable(true, "g"), "g"))
+	require.NoError(t, remoteStorage.Delete("replaced-external.sst"))
+	w, err := remoteStorage.CreateObject("replaced-external.sst")
+	require.NoError(t, err)
+	_, err = w.Write(writeTable(false, "g"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	for _, viaBackingFileDownload := range []bool{false, true} {
+		err = d.Download(ctx, []DownloadSpan{{
+			StartKey:               []byte("g"),
+			EndKey:                 []byte("h"),
+			ViaBackingFileDownload: viaBackingFileDownload,
+		}})
+		require.ErrorIs(t, err, sstable.ErrUnsignedTable)
+	}
+	require.NoError(t, d.Download(ctx, []DownloadSpan{{StartKey: []byte("d"), EndKey: []byte("e")}}))
+	tables, err := d.SSTables()
+	require.NoError(t, err)
+	for _, level := range tables {
+		for _, info := range level {
+			if info.Virtual {
+				continue
+			}
+			f, err := mem.Open(base.MakeFilepath(mem, "", base.FileTypeTable, info.BackingSSTNum))
+			require.NoError(t, err)
+			readable, err := objstorageprovider.NewFileReadable(f, mem, objstorageprovider.NewReadaheadConfig(), "")
+			require.NoError(t, err)
+			require.NoError(t, sstable.VerifySignature(ctx, readable, keys))
+			require.NoError(t, readable.Close())
+		}
+	}
+}
+
+// TestIngestRowBlockColumnarIndex tests that sstables with the
+// TableFormatPebblev4ColumnarIndex table format are only ingested at format
+// major versions that support them.
+func TestIngestRowBlockColumnarIndex(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	for _, fmv := range []FormatMajorVersion{FormatValueColumns, FormatRowBlockColumnarIndex} {
+		t.Run(fmv.String(), func(t *testing.T) {
+			mem := vfs.NewMem()
+			d, err := Open("", &Options{
+				FS:                 mem,
+				FormatMajorVersion: fmv,
+				Logger:             testutils.Logger{T: t},
+			})
+			require.NoError(t, err)
+			defer func() { require.NoError(t, d.Close()) }()
+
+			f, err := mem.Create("ext", vfs.WriteCategoryUnspecified)
+			require.NoError(t, err)
+			writerOpts := d.opts.MakeWriterOptions(0, sstable.TableFormatPebblev4ColumnarIndex)
+			w := sstable.NewWriter(objstorageprovider.NewFileWritable(f), writerOpts)
+			for i := 0; i < 100; i++ {
+				require.NoError(t, w.Set([]byte(fmt.Sprintf("k%03d", i)), []byte("value")))
+			}
+			require.NoError(t, w.Close())
+
+			err = d.Ingest(context.Background(), []string{"ext"})
+			if fmv < FormatRowBlockColumnarIndex {
+				require.ErrorContains(t, err, "is not supported at DB format major version")
+				return
+			}
+			require.NoError(t, err)
+			iter, err := d.NewIter(nil)
+			require.NoError(t, err)
+			n := 0
+			for valid := iter.First(); valid; valid = iter.Next() {
+				require.Equal(t, fmt.Sprintf("k%03d", n), string(iter.Key()))
+				n++
+			}
+			require.NoError(t, iter.Close())
+			require.Equal(t, 100, n)
+		})
+	}
+}
+
 func TestIngestCompact(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	mem := vfs.NewMem()
@@ -33,6 +33,31 @@ type CompactionGrantHandle interface {
 	Done()
 }
 
+// CompactionCompressionGrant may optionally be implemented by a
+// CompactionGrantHandle to configure the number of goroutines used to compress
+// the data blocks of the sstables written by the compaction, typically based on
+// the CPU granted to the compaction.
+type CompactionCompressionGrant interface {
+	// CompressionWorkers returns the number of compression goroutines to use
+	// for the next sstable written by the compaction (see
+	// sstable.WriterOptions.CompressionWorkers). It is called before each
+	// output sstable is created, so the result may change over the course of
+	// the compaction. The result is capped at MaxCompactionCompressionWorkers.
+	//
+	// The compression goroutines are not reported to CPUMeasurer.MeasureCPU,
+	// which measures a single goroutine of each kind. Each of them can consume
+	// up to one CPU while the compaction writes an sstable, and a compaction
+	// writes one sstable at a time, so an implementation returning n should
+	// account for up to n CPUs on top of the CPU measured for the compaction.
+	CompressionWorkers() int
+}
+
+// MaxCompactionCompressionWorkers is the maximum number of compression
+// goroutines used by a compaction, which bounds the CPU consumed by
+// compression beyond what is reported to CPUMeasurer.MeasureCPU (see
+// CompactionCompressionGrant).
+const MaxCompactionCompressionWorkers = 4
+
 // CompactionGoroutineKind identifies the kind of compaction goroutine.
 type CompactionGoroutineKind uint8
 
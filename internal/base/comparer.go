@@ -232,6 +232,14 @@ type Comparer struct {
 	Compare Compare
 	// Equal defaults to using Compare() == 0 if it is not specified.
 	Equal Equal
+
+	// BytewisePrefixOrder declares that Compare orders prefixes (see Split)
+	// bytewise, as described in the contract of Compare. Range filters (see
+	// TableRangeFilterDecoder) answer queries over the bytewise order of
+	// prefixes and would return false negatives if the ordering differed, so
+	// they are only used if it is set. EnsureDefaults sets it if Compare is not
+	// specified.
+	BytewisePrefixOrder bool
 	// FormatKey defaults to the DefaultFormatter if it is not specified.
 	FormatKey FormatKey
 
@@ -268,6 +276,11 @@ func (c *Comparer) EnsureDefaults() *Comparer {
 	n := &Comparer{}
 	*n = *c
 
+	if n.Compare == nil {
+		// Both bytes.Compare and the default implementation order prefixes
+		// bytewise.
+		n.BytewisePrefixOrder = true
+	}
 	if n.Split == nil {
 		n.Split = DefaultSplit
 	}
@@ -317,6 +330,7 @@ var DefaultComparer = &Comparer{
 	CompareRangeSuffixes: bytes.Compare,
 	Compare:              bytes.Compare,
 	Equal:                bytes.Equal,
+	BytewisePrefixOrder:  true,
 
 	AbbreviatedKey: func(key []byte) uint64 {
 		if len(key) >= 8 {
@@ -473,6 +473,11 @@ type InternalIteratorStats struct {
 	// can be useful for discovering instances of
 	// https://github.com/cockroachdb/pebble/issues/1070.
 	PointsCoveredByRangeTombstones uint64
+	// Points that were skipped in sstable iterators because they did not match
+	// the iterator's PointKeyPredicate. Other versions of their user keys that
+	// are skipped along with them without being evaluated are not counted.
+	// These points are not included in PointCount.
+	PointsFilteredByPredicate uint64
 
 	// Stats related to points in value blocks encountered during iteration.
 	// These are useful to understand outliers, since typical user facing
@@ -512,6 +517,7 @@ func (s *InternalIteratorStats) Merge(from InternalIteratorStats) {
 	s.ValueBytes += from.ValueBytes
 	s.PointCount += from.PointCount
 	s.PointsCoveredByRangeTombstones += from.PointsCoveredByRangeTombstones
+	s.PointsFilteredByPredicate += from.PointsFilteredByPredicate
 	s.SeparatedPointValue.Count += from.SeparatedPointValue.Count
 	s.SeparatedPointValue.CountFetched += from.SeparatedPointValue.CountFetched
 	s.SeparatedPointValue.ReaderCacheMisses += from.SeparatedPointValue.ReaderCacheMisses
@@ -548,6 +554,9 @@ func (s *InternalIteratorStats) SafeFormat(p redact.SafePrinter, verb rune) {
 	if s.PointsCoveredByRangeTombstones != 0 {
 		p.Printf("(%s tombstoned)", humanize.Count.Uint64(s.PointsCoveredByRangeTombstones))
 	}
+	if s.PointsFilteredByPredicate != 0 {
+		p.Printf("(%s filtered)", humanize.Count.Uint64(s.PointsFilteredByPredicate))
+	}
 	p.Printf(" (%s keys, %s values)",
 		humanize.Bytes.Uint64(s.KeyBytes),
 		humanize.Bytes.Uint64(s.ValueBytes),
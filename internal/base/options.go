@@ -67,6 +67,19 @@ type TableFilterDecoder interface {
 	MayContain(filter, key []byte) bool
 }
 
+// TableRangeFilterDecoder is implemented by the TableFilterDecoders of filter
+// families that can also answer range queries. Such filters let an iterator
+// with narrow bounds skip tables that contain no keys within the bounds.
+type TableRangeFilterDecoder interface {
+	TableFilterDecoder
+
+	// MayContainRange returns whether the encoded filter may contain a key k
+	// with lo <= k <= hi, where keys are compared bytewise. False positives are
+	// possible, where it returns true when no key in the original set falls
+	// within the range.
+	MayContainRange(filter, lo, hi []byte) bool
+}
+
 // NoFilterPolicy implements the "none" filter policy.
 var NoFilterPolicy TableFilterPolicy = noFilter{}
 
@@ -90,3 +103,41 @@ type BlockPropertyFilter interface {
 	// replacement.
 	SyntheticSuffixIntersects(prop []byte, suffix []byte) (bool, error)
 }
+
+// PointKeyPredicate is a predicate on point keys that is evaluated by sstable
+// iterators, allowing point keys that don't match to be skipped before they
+// are surfaced to the merging iterator. It is passed the suffix of the user
+// key (as determined by Comparer.Split) and a summary of the value. It returns
+// false if the point key should be skipped.
+//
+// The predicate is only evaluated for SET and SETWITHDEL keys, and only by
+// iterators over sstables with columnar data blocks. Like BlockPropertyFilters,
+// it is best-effort: point keys that don't match may still be surfaced by the
+// iterator, and callers that require exact filtering must still apply the
+// predicate to the returned keys.
+//
+// The predicate must return the same result for all the point keys with the
+// same user key (e.g. by only depending on the suffix in keyspaces in which
+// each user key is written once, such as MVCC keys that embed a timestamp in
+// the suffix). An sstable iterator skips the other versions of a skipped key's
+// user key within the same data block, but it cannot prevent versions in
+// other blocks or sstables from surfacing: if the predicate
+// rejected a key but accepted an older version that it shadows, iteration
+// could return the stale older version.
+//
+// The predicate must be a pure function and must be safe for concurrent use.
+type PointKeyPredicate func(suffix []byte, value PointValueSummary) bool
+
+// PointValueSummary summarizes the value of a point key evaluated by a
+// PointKeyPredicate.
+type PointValueSummary struct {
+	// InPlace is true if the value is stored in place in the data block, in
+	// which case Value is the value.
+	InPlace bool
+	// Value is the value, if InPlace is true. It is only valid for the duration
+	// of the predicate invocation.
+	Value []byte
+	// ShortAttribute is the value's short attribute, if InPlace is false (the
+	// value is stored in a value block or blob file).
+	ShortAttribute ShortAttribute
+}
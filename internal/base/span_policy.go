@@ -56,6 +56,18 @@ type SpanPolicy struct {
 	// in its history, for error checking the aforementioned invariant.
 	TieringPolicy TieringPolicyAndExtractor
 
+	// TableFilterPolicy, if set, overrides the level's table filter policy for
+	// sstables written for keys in this span. NoFilterPolicy disables table
+	// filters for the span.
+	TableFilterPolicy TableFilterPolicy
+
+	// OptimizeFiltersForHits disables table filters for sstables written to the
+	// bottommost level for keys in this span. Filters in the bottommost level
+	// account for most of the filter memory, but they only avoid data block
+	// reads for keys that don't exist; this is worthwhile when lookups in the
+	// span are expected to find their keys.
+	OptimizeFiltersForHits bool
+
 	// NOTE: update the IsDefault() method if you add new fields to this struct.
 }
 
@@ -64,7 +76,23 @@ type SpanPolicy struct {
 func (p *SpanPolicy) IsDefault() bool {
 	return !p.PreferFastCompression &&
 		!p.ValueStoragePolicy.IsSet() &&
-		!p.TieringPolicy.IsSet()
+		!p.TieringPolicy.IsSet() &&
+		p.TableFilterPolicy == nil &&
+		!p.OptimizeFiltersForHits
+}
+
+// AdjustTableFilterPolicy returns the table filter policy to use for an sstable
+// in this span, given the policy configured for the sstable's level.
+func (p *SpanPolicy) AdjustTableFilterPolicy(
+	levelPolicy TableFilterPolicy, bottommostLevel bool,
+) TableFilterPolicy {
+	if p.OptimizeFiltersForHits && bottommostLevel {
+		return NoFilterPolicy
+	}
+	if p.TableFilterPolicy != nil {
+		return p.TableFilterPolicy
+	}
+	return levelPolicy
 }
 
 // StillCovers takes a key that is no smaller than the span policy start key (if
@@ -101,6 +129,12 @@ func (p SpanPolicy) String() string {
 	if p.TieringPolicy.IsSet() {
 		policy = crstrings.WithSep(policy, ",", p.TieringPolicy.String())
 	}
+	if p.TableFilterPolicy != nil {
+		policy = crstrings.WithSep(policy, ",", "filter="+p.TableFilterPolicy.Name())
+	}
+	if p.OptimizeFiltersForHits {
+		policy = crstrings.WithSep(policy, ",", "optimize-filters-for-hits")
+	}
 	if policy == "" {
 		policy = "default"
 	}
@@ -173,6 +173,44 @@ func (r *Runner) FirstKey() []byte {
 	return firstKey
 }
 
+// TierFunc returns the storage tier that a point key-value pair belongs to,
+// given its metadata. It returns ok=false if the KV can be stored in a table of
+// either tier (e.g. a tombstone).
+type TierFunc func(kv *base.InternalKV, meta base.KVMeta) (_ base.StorageTier, ok bool)
+
+// TableTiering configures the storage tier of an output table. The zero value
+// disables tiering.
+type TableTiering struct {
+	// Tier is the storage tier of the output table.
+	Tier base.StorageTier
+	// TierFn, if set, is used to finish the table before the first point key
+	// that belongs to a different tier, so that every output table holds the
+	// KVs of a single tier. User keys are never split between tables.
+	TierFn TierFunc
+}
+
+// FirstKeyTier returns the storage tier of the first point key that will be
+// written, according to tierFn. If there is no such key, or the key can be
+// stored in either tier, FirstKeyTier returns the hot tier.
+//
+// FirstKeyTier can only be called right after MoreDataToWrite() was called and
+// returned true.
+func (r *Runner) FirstKeyTier(tierFn TierFunc) base.StorageTier {
+	if r.kv == nil {
+		return base.HotTier
+	}
+	switch r.kv.K.Kind() {
+	case base.InternalKeyKindRangeDelete, base.InternalKeyKindRangeKeySet,
+		base.InternalKeyKindRangeKeyUnset, base.InternalKeyKindRangeKeyDelete:
+		// The tier is determined by the first point key, which follows the span.
+		return base.HotTier
+	}
+	if tier, ok := tierFn(r.kv, r.iter.GetCurrentMeta()); ok {
+		return tier
+	}
+	return base.HotTier
+}
+
 // WriteTable writes a new output table. This table will be part of
 // Result.Tables. Should only be called if MoreDataToWrite() returned true.
 //
@@ -185,6 +223,7 @@ func (r *Runner) WriteTable(
 	tw sstable.RawWriter,
 	limitKey []byte,
 	valueSeparation valsep.ValueSeparation,
+	tiering TableTiering,
 ) {
 	if r.err != nil {
 		panic(errors.AssertionFailedf("error already encountered"))
@@ -198,7 +237,7 @@ func (r *Runner) WriteTable(
 		CreationTime: time.Now(),
 		ObjMeta:      objMeta,
 	})
-	splitKey, err := r.writeKeysToTable(tw, limitKey, valueSeparation)
+	splitKey, err := r.writeKeysToTable(tw, limitKey, valueSeparation, tiering)
 
 	// Inform the value separation policy that the table is finished.
 	valSepMeta, valSepErr := valueSeparation.FinishOutput()
@@ -243,7 +282,10 @@ func (r *Runner) WriteTable(
 }
 
 func (r *Runner) writeKeysToTable(
-	tw sstable.RawWriter, limitKey []byte, valueSeparation valsep.ValueSeparation,
+	tw sstable.RawWriter,
+	limitKey []byte,
+	valueSeparation valsep.ValueSeparation,
+	tiering TableTiering,
 ) (splitKey []byte, _ error) {
 	const updateGrantHandleEveryNKeys = 128
 	firstKey := r.FirstKey()
@@ -296,6 +338,13 @@ func (r *Runner) writeKeysToTable(
 			continue
 		}
 
+		if tiering.TierFn != nil {
+			if tier, ok := tiering.TierFn(kv, r.iter.GetCurrentMeta()); ok && tier != tiering.Tier &&
+				splitter.SplitBefore(kv.K.UserKey, equalPrev) == SplitNow {
+				break
+			}
+		}
+
 		valueLen := kv.V.Len()
 		isLikelyMVCCGarbage := valueLen > valueSeparation.OutputConfig().MinimumMVCCGarbageSize &&
 			tw.IsLikelyMVCCGarbage(kv.K.UserKey, kv.K.Kind())
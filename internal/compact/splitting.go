@@ -268,6 +268,23 @@ func (s *OutputSplitter) ShouldSplitBefore(
 	return NoSplit
 }
 
+// SplitBefore forces a split before the next key, regardless of the output
+// size. It is used when the next key must not be written to the current output
+// (e.g. because it belongs to a different storage tier). It must be called
+// after ShouldSplitBefore returned NoSplit for the same key.
+//
+// SplitBefore returns NoSplit if splitting before the key would split a user
+// key between outputs or produce an empty output.
+func (s *OutputSplitter) SplitBefore(
+	nextUserKey []byte, equalPrevFn func([]byte) bool,
+) ShouldSplit {
+	if s.cmp(nextUserKey, s.startKey) <= 0 || equalPrevFn(nextUserKey) {
+		return NoSplit
+	}
+	s.splitKey = slices.Clone(nextUserKey)
+	return SplitNow
+}
+
 // SplitKey returns the suggested split key - the first key at which the next
 // output file should start.
 //
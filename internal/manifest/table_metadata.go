@@ -441,6 +441,8 @@ type TableBackingProperties struct {
 	NumRangeKeySets uint64
 	// Total size of value blocks and value index block.
 	ValueBlocksSize uint64
+	// Total size of the table filter blocks.
+	FilterSize uint64
 
 	// TombstoneDenseBlocksRatio is the fraction of data blocks in this table that
 	// are tombstone-dense. See sstableCommonProperties.NumTombstoneDenseBlocks for
@@ -491,6 +493,7 @@ func (b *TableBacking) PopulateProperties(props *sstable.Properties) *TableBacki
 		NumRangeKeyDels:                 props.NumRangeKeyDels,
 		NumRangeKeySets:                 props.NumRangeKeySets,
 		ValueBlocksSize:                 props.ValueBlocksSize,
+		FilterSize:                      props.FilterSize,
 		ValueSeparationMinSize:          props.ValueSeparationMinSize,
 		ValueSeparationBySuffixDisabled: props.ValueSeparationBySuffixDisabled,
 	}
@@ -94,3 +94,12 @@ func (l *Limiter) SetRate(r float64) {
 	l.mu.tb.UpdateConfig(tokenbucket.TokensPerSecond(r), tokenbucket.Tokens(l.mu.burst))
 	l.mu.rate = r
 }
+
+// SetRateAndBurst updates the rate limit and the burst.
+func (l *Limiter) SetRateAndBurst(r float64, b float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.mu.tb.UpdateConfig(tokenbucket.TokensPerSecond(r), tokenbucket.Tokens(b))
+	l.mu.rate = r
+	l.mu.burst = b
+}
@@ -59,6 +59,7 @@ var Comparer = &base.Comparer{
 	CompareRangeSuffixes: compareSuffixes,
 	Compare:              compare,
 	Equal:                func(a, b []byte) bool { return compare(a, b) == 0 },
+	BytewisePrefixOrder:  true,
 	AbbreviatedKey: func(k []byte) uint64 {
 		return base.DefaultComparer.AbbreviatedKey(k[:split(k)])
 	},
@@ -2811,7 +2811,10 @@ func (i *Iterator) SetOptions(o *IterOptions) {
 		// filter.
 		o.RangeKeyMasking.Filter == nil && i.opts.RangeKeyMasking.Filter == nil &&
 		// We can only guarantee SkipPoint has not changed if it is not set.
-		o.SkipPoint == nil && i.opts.SkipPoint == nil
+		o.SkipPoint == nil && i.opts.SkipPoint == nil &&
+		// Likewise for PointKeyPredicate.
+		o.PointKeyPredicate == nil && i.opts.PointKeyPredicate == nil &&
+		o.ValueProjection == i.opts.ValueProjection
 
 	reuseRangeKey := i.rangeKey != nil &&
 		i.err == nil &&
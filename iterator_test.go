@@ -11,6 +11,7 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"math"
 	"math/rand/v2"
 	"runtime"
 	"slices"
@@ -36,6 +37,8 @@ import (
 	"github.com/cockroachdb/pebble/objstorage/objstorageprovider"
 	"github.com/cockroachdb/pebble/sstable"
 	"github.com/cockroachdb/pebble/sstable/block/blockkind"
+	"github.com/cockroachdb/pebble/sstable/colblk"
+	"github.com/cockroachdb/pebble/sstable/tablefilters/rangefilter"
 	"github.com/cockroachdb/pebble/vfs"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/sync/errgroup"
@@ -2727,6 +2730,415 @@ func TestIteratorSeekPrefixGERandomized(t *testing.T) {
 	}
 }
 
+// TestIteratorRangeFilter tests that iterators with narrow bounds use range
+// filters to skip tables, and that the results remain correct when the bounds
+// change.
+func TestIteratorRangeFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	seed := *seed
+	if seed == 0 {
+		seed = 1
+	}
+	t.Logf("seed: %d", seed)
+	rng := rand.New(rand.NewPCG(seed, seed))
+
+	opts := &Options{
+		FS:       vfs.NewMem(),
+		Comparer: testkeys.Comparer,
+	}
+	opts.ApplyTableFilterPolicy(func() DBTableFilterPolicy {
+		return UniformDBTableFilterPolicy(rangefilter.FilterPolicy(16))
+	})
+	d, err := Open("", opts)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, d.Close()) }()
+
+	// Write the keys with an even index, in a few tables.
+	const numKeys = 2000
+	key := func(i int) []byte { return []byte(fmt.Sprintf("k%05d", i)) }
+	for i := 0; i < numKeys; i += 2 {
+		require.NoError(t, d.Set(key(i), nil, nil))
+		if i%500 == 498 {
+			require.NoError(t, d.Flush())
+		}
+	}
+	require.NoError(t, d.Flush())
+
+	// An iterator over a gap between keys skips the table. Note that the
+	// upper bound must not be a key in the table, as the range filter is
+	// queried with the prefix of the upper bound.
+	before := d.Metrics().Filter
+	iter, err := d.NewIter(&IterOptions{
+		LowerBound:   key(101),
+		UpperBound:   append(key(101), 'z'),
+		UseL6Filters: true,
+	})
+	require.NoError(t, err)
+	require.False(t, iter.First())
+	after := d.Metrics().Filter
+	require.Less(t, before.RangeHits, after.RangeHits)
+	// Range filter checks are not counted as point filter checks.
+	require.Equal(t, before.Hits, after.Hits)
+	require.Equal(t, before.Misses, after.Misses)
+
+	// Changing the bounds must take effect.
+	iter.SetBounds(key(100), key(101))
+	require.True(t, iter.First())
+	require.Equal(t, key(100), iter.Key())
+	require.False(t, iter.Next())
+
+	// Check random narrow bounds against the expected results.
+	for range 1000 {
+		lo := rng.IntN(numKeys)
+		hi := lo + 1 + rng.IntN(4)
+		iter.SetBounds(key(lo), key(hi))
+		var expected, actual []string
+		for i := lo + lo%2; i < hi && i < numKeys; i += 2 {
+			expected = append(expected, string(key(i)))
+		}
+		if rng.IntN(2) == 0 {
+			for valid := iter.First(); valid; valid = iter.Next() {
+				actual = append(actual, string(iter.Key()))
+			}
+		} else {
+			for valid := iter.Last(); valid; valid = iter.Prev() {
+				actual = append([]string{string(iter.Key())}, actual...)
+			}
+		}
+		require.Equal(t, expected, actual, "bounds [%d, %d)", lo, hi)
+	}
+	require.NoError(t, iter.Close())
+}
+
+// TestIteratorRangeFilterNonBytewisePrefixOrder tests that range filters are
+// not used if the Comparer does not declare BytewisePrefixOrder.
+func TestIteratorRangeFilterNonBytewisePrefixOrder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	cmp := *testkeys.Comparer
+	cmp.BytewisePrefixOrder = false
+	opts := &Options{
+		FS:       vfs.NewMem(),
+		Comparer: &cmp,
+	}
+	opts.ApplyTableFilterPolicy(func() DBTableFilterPolicy {
+		return UniformDBTableFilterPolicy(rangefilter.FilterPolicy(16))
+	})
+	d, err := Open("", opts)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, d.Close()) }()
+
+	require.NoError(t, d.Set([]byte("a"), nil, nil))
+	require.NoError(t, d.Set([]byte("c"), nil, nil))
+	require.NoError(t, d.Flush())
+
+	iter, err := d.NewIter(&IterOptions{
+		LowerBound:   []byte("b"),
+		UpperBound:   []byte("bz"),
+		UseL6Filters: true,
+	})
+	require.NoError(t, err)
+	require.False(t, iter.First())
+	require.NoError(t, iter.Close())
+	m := d.Metrics().Filter
+	require.Zero(t, m.RangeHits)
+	require.Zero(t, m.RangeMisses)
+}
+
+func TestIteratorPointKeyPredicate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	d, err := Open("", &Options{
+		FS:                 vfs.NewMem(),
+		Comparer:           testkeys.Comparer,
+		FormatMajorVersion: internalFormatNewest,
+		// Keep the sstables separate.
+		DisableAutomaticCompactions: true,
+	})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, d.Close()) }()
+
+	// Write keys with distinct prefixes (so that all values are stored in
+	// place); every third key has a value that doesn't match the predicate.
+	const numKeys = 300
+	key := func(i int) []byte { return fmt.Appendf(nil, "k%03d@%d", i, i%5+1) }
+	value := func(i int) []byte {
+		if i%3 == 0 {
+			return []byte("skip")
+		}
+		return []byte("keep")
+	}
+	for i := 0; i < numKeys; i++ {
+		require.NoError(t, d.Set(key(i), value(i), nil))
+	}
+	require.NoError(t, d.Flush())
+	// Keys in the memtable are not filtered.
+	require.NoError(t, d.Set([]byte("z@1"), []byte("skip"), nil))
+
+	var suffixes []string
+	iter, err := d.NewIter(&IterOptions{
+		PointKeyPredicate: func(suffix []byte, v PointValueSummary) bool {
+			suffixes = append(suffixes, string(suffix))
+			return !v.InPlace || string(v.Value) != "skip"
+		},
+	})
+	require.NoError(t, err)
+	var keys []string
+	for valid := iter.First(); valid; valid = iter.Next() {
+		keys = append(keys, string(iter.Key()))
+	}
+	var expected []string
+	for i := 0; i < numKeys; i++ {
+		if i%3 != 0 {
+			expected = append(expected, string(key(i)))
+		}
+	}
+	expected = append(expected, "z@1")
+	require.Equal(t, expected, keys)
+	require.Contains(t, suffixes, "@5")
+	stats := iter.Stats()
+	require.Equal(t, uint64(numKeys/3), stats.InternalStats.PointsFilteredByPredicate)
+	require.NoError(t, iter.Close())
+
+	// Skipping a key must not expose the older versions that it shadows. Use
+	// snapshots to keep both versions of the key in the flushed sstable, and to
+	// read it at a sequence number below the sstable's largest sequence number
+	// (so that the shadowed version isn't hidden as an obsolete point).
+	require.NoError(t, d.Set([]byte("y@1"), []byte("keep"), nil))
+	snap := d.NewSnapshot()
+	defer func() { require.NoError(t, snap.Close()) }()
+	require.NoError(t, d.Set([]byte("y@1"), []byte("skip"), nil))
+	snap2 := d.NewSnapshot()
+	defer func() { require.NoError(t, snap2.Close()) }()
+	require.NoError(t, d.Set([]byte("x@1"), []byte("keep"), nil))
+	require.NoError(t, d.Flush())
+	iter, err = snap2.NewIter(&IterOptions{
+		LowerBound: []byte("y"),
+		UpperBound: []byte("z"),
+		PointKeyPredicate: func(suffix []byte, v PointValueSummary) bool {
+			return !v.InPlace || string(v.Value) != "skip"
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, iter.First())
+	require.False(t, iter.Last())
+	require.NoError(t, iter.Close())
+
+	// Skipping a key must not skip other user keys with the same prefix.
+	require.NoError(t, d.Set([]byte("w@3"), []byte("skip"), nil))
+	require.NoError(t, d.Set([]byte("w@1"), []byte("keep"), nil))
+	require.NoError(t, d.Flush())
+	iter, err = d.NewIter(&IterOptions{
+		LowerBound: []byte("w"),
+		UpperBound: []byte("x"),
+		PointKeyPredicate: func(suffix []byte, v PointValueSummary) bool {
+			return !v.InPlace || string(v.Value) != "skip"
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, iter.First())
+	require.Equal(t, "w@1", string(iter.Key()))
+	require.False(t, iter.Next())
+	require.True(t, iter.Last())
+	require.Equal(t, "w@1", string(iter.Key()))
+	require.False(t, iter.Prev())
+	require.NoError(t, iter.Close())
+
+	// A predicate that returns the same result for all the versions of a user
+	// key doesn't expose the versions in older sstables that a skipped key
+	// shadows.
+	require.NoError(t, d.Set([]byte("v@7"), []byte("old"), nil))
+	require.NoError(t, d.Set([]byte("v@2"), []byte("old"), nil))
+	require.NoError(t, d.Flush())
+	require.NoError(t, d.Set([]byte("v@7"), []byte("new"), nil))
+	require.NoError(t, d.Set([]byte("v@2"), []byte("new"), nil))
+	require.NoError(t, d.Flush())
+	iter, err = d.NewIter(&IterOptions{
+		LowerBound: []byte("v"),
+		UpperBound: []byte("w"),
+		PointKeyPredicate: func(suffix []byte, v PointValueSummary) bool {
+			return string(suffix) != "@7"
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, iter.First())
+	require.Equal(t, "v@2", string(iter.Key()))
+	require.Equal(t, "new", string(iter.Value()))
+	require.False(t, iter.Next())
+	require.True(t, iter.Last())
+	require.Equal(t, "v@2", string(iter.Key()))
+	require.Equal(t, "new", string(iter.Value()))
+	require.False(t, iter.Prev())
+	stats = iter.Stats()
+	require.Less(t, uint64(0), stats.InternalStats.PointsFilteredByPredicate)
+	require.NoError(t, iter.Close())
+}
+
+func TestIteratorValueProjection(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	// countNameSchema decomposes values of the form "<count>:<name>".
+	countNameSchema := &ValueSchema{
+		Name:        "count-name",
+		ColumnTypes: []colblk.DataType{colblk.DataTypeUint, colblk.DataTypeBytes},
+		Decompose: func(value []byte, fields []colblk.ValueField) bool {
+			count, name, ok := bytes.Cut(value, []byte(":"))
+			if !ok {
+				return false
+			}
+			n, err := strconv.ParseUint(string(count), 10, 64)
+			if err != nil || strconv.FormatUint(n, 10) != string(count) {
+				return false
+			}
+			fields[0].Uint = n
+			fields[1].Bytes = name
+			return true
+		},
+		Compose: func(dst []byte, fields []colblk.ValueField) []byte {
+			dst = strconv.AppendUint(dst, fields[0].Uint, 10)
+			dst = append(dst, ':')
+			return append(dst, fields[1].Bytes...)
+		},
+	}
+	opts := &Options{
+		FS:                 vfs.NewMem(),
+		Comparer:           testkeys.Comparer,
+		FormatMajorVersion: FormatValueColumns,
+		ValueSchema:        countNameSchema.Name,
+		ValueSchemas:       sstable.MakeValueSchemas(countNameSchema),
+	}
+	d, err := Open("", opts)
+	require.NoError(t, err)
+
+	const numKeys = 100
+	key := func(i int) []byte { return fmt.Appendf(nil, "k%03d", i) }
+	value := func(i int) string {
+		if i%10 == 0 {
+			// Some values don't conform to the schema.
+			return fmt.Sprintf("opaque-%d", i)
+		}
+		return fmt.Sprintf("%d:name-%d", i*7, i)
+	}
+	for i := 0; i < numKeys; i++ {
+		require.NoError(t, d.Set(key(i), []byte(value(i)), nil))
+	}
+	require.NoError(t, d.Flush())
+	// Values in the memtable are not projected.
+	require.NoError(t, d.Set([]byte("z"), []byte("5:memtable"), nil))
+
+	check := func(d *DB) {
+		iter, err := d.NewIter(&IterOptions{ValueProjection: colblk.ProjectValueColumns(1)})
+		require.NoError(t, err)
+		i := 0
+		for valid := iter.First(); valid && i < numKeys; valid = iter.Next() {
+			require.Equal(t, string(key(i)), string(iter.Key()))
+			expected := value(i)
+			if i%10 != 0 {
+				expected = fmt.Sprintf("0:name-%d", i)
+			}
+			require.Equal(t, expected, string(iter.Value()))
+			i++
+		}
+		require.Equal(t, numKeys, i)
+		require.True(t, iter.Valid())
+		require.Equal(t, "5:memtable", string(iter.Value()))
+		require.False(t, iter.Next())
+
+		// Without a projection, the full values are returned.
+		iter.SetOptions(&IterOptions{})
+		i = 0
+		for valid := iter.First(); valid && i < numKeys; valid = iter.Next() {
+			require.Equal(t, value(i), string(iter.Value()))
+			i++
+		}
+		require.Equal(t, numKeys, i)
+		require.NoError(t, iter.Close())
+	}
+	check(d)
+	require.NoError(t, d.Close())
+
+	// The value schema must be provided when reopening the store.
+	opts.ValueSchema = ""
+	opts.ValueSchemas = nil
+	d, err = Open("", opts)
+	require.NoError(t, err)
+	_, _, err = d.Get(key(1))
+	require.ErrorContains(t, err, `unknown value schema "count-name"`)
+	require.NoError(t, d.Close())
+
+	opts.ValueSchemas = sstable.MakeValueSchemas(countNameSchema)
+	d, err = Open("", opts)
+	require.NoError(t, err)
+	check(d)
+	require.NoError(t, d.Close())
+}
+
+func TestIteratorZoneMapFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	opts := &Options{
+		FS:                 vfs.NewMem(),
+		Comparer:           testkeys.Comparer,
+		FormatMajorVersion: internalFormatNewest,
+		BlockPropertyCollectors: []func() BlockPropertyCollector{
+			func() BlockPropertyCollector {
+				return sstable.NewZoneMapCollector(sstable.ZoneMapConfig{
+					Name: "price",
+					Type: sstable.ZoneMapUint64,
+					Extract: func(key InternalKey, value []byte) ([]byte, sstable.ZoneMapFieldPresence, error) {
+						if key.Kind() == InternalKeyKindDelete {
+							// Deletions have no price.
+							return nil, sstable.ZoneMapFieldAbsent, nil
+						}
+						if value == nil {
+							return nil, sstable.ZoneMapFieldUnknown, nil
+						}
+						n, err := strconv.ParseUint(string(value), 10, 64)
+						if err != nil {
+							return nil, sstable.ZoneMapFieldAbsent, nil
+						}
+						return sstable.EncodeZoneMapUint64(n), sstable.ZoneMapFieldPresent, nil
+					},
+				})
+			},
+		},
+	}
+	opts.Levels[0].BlockSize = 64
+	d, err := Open("", opts)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, d.Close()) }()
+
+	// The value of key kNNN is 10*NNN.
+	const numKeys = 500
+	for i := 0; i < numKeys; i++ {
+		require.NoError(t, d.Set(fmt.Appendf(nil, "k%03d", i), strconv.AppendInt(nil, int64(10*i), 10), nil))
+	}
+	require.NoError(t, d.Flush())
+
+	scan := func(filter BlockPropertyFilter) (keys []string) {
+		iter, err := d.NewIter(&IterOptions{PointKeyFilters: []BlockPropertyFilter{filter}})
+		require.NoError(t, err)
+		for valid := iter.First(); valid; valid = iter.Next() {
+			keys = append(keys, string(iter.Key()))
+		}
+		require.NoError(t, iter.Close())
+		return keys
+	}
+	// The blocks that contain no prices within [1000, 1100) are skipped; the
+	// keys with matching prices are all surfaced.
+	keys := scan(sstable.NewUint64ZoneMapFilter("price", 1000, 1100))
+	require.Less(t, len(keys), numKeys/10)
+	for i := 100; i < 110; i++ {
+		require.Contains(t, keys, fmt.Sprintf("k%03d", i))
+	}
+	// The table-level zone map excludes the whole table.
+	require.Empty(t, scan(sstable.NewUint64ZoneMapFilter("price", 10*numKeys, math.MaxUint64)))
+
+	// A table that only holds a deletion is not excluded, so the deleted key
+	// is not surfaced.
+	require.NoError(t, d.Delete([]byte("k105"), nil))
+	require.NoError(t, d.Flush())
+	keys = scan(sstable.NewUint64ZoneMapFilter("price", 1000, 1100))
+	require.NotContains(t, keys, "k105")
+	require.Contains(t, keys, "k104")
+}
+
 // BenchmarkIterator_RangeKeyMasking benchmarks a scan through a keyspace with
 // 10,000 random suffixed point keys, and three range keys covering most of the
 // keyspace. It varies the suffix of the range keys in subbenchmarks to exercise
@@ -69,9 +69,13 @@ type levelIter struct {
 	iter internalIterator
 	// iterFile holds the current file. It is always equal to l.files.Current().
 	iterFile *manifest.TableMetadata
-	newIters tableNewIters
-	files    manifest.LevelIterator
-	err      error
+	// iterRangeFiltered is set if the point iterator for iterFile was elided
+	// because the table's range filter excluded the iteration bounds. The
+	// file must be reopened if the bounds change.
+	iterRangeFiltered bool
+	newIters          tableNewIters
+	files             manifest.LevelIterator
+	err               error
 
 	// interleaveRangeDels is set to true if the levelIter is configured to
 	// interleave range deletions among point keys, using li.interleaving.
@@ -158,6 +162,8 @@ func (l *levelIter) init(
 		l.tableOpts.PointKeyFilters = l.filtersBuf[:0:1]
 	}
 	l.tableOpts.MaximumSuffixProperty = opts.MaximumSuffixProperty
+	l.tableOpts.PointKeyPredicate = opts.PointKeyPredicate
+	l.tableOpts.ValueProjection = opts.ValueProjection
 	l.tableOpts.UseL6Filters = opts.UseL6Filters
 	l.tableOpts.Category = opts.Category
 	l.tableOpts.layer = l.layer
@@ -573,6 +579,12 @@ func (l *levelIter) loadFile(file *manifest.TableMetadata, dir int) loadFileRetu
 			return noFileLoaded
 		}
 
+		// If both bounds fall within the file, the iteration only covers a
+		// fraction of the file and the table's range filter may be able to
+		// prove that no keys fall within the bounds.
+		l.tableOpts.useRangeFilter = l.prefix == nil &&
+			l.tableOpts.LowerBound != nil && l.tableOpts.UpperBound != nil
+
 		iterKinds := iterPointKeys
 		if l.interleaveRangeDels {
 			iterKinds |= iterRangeDeletions
@@ -586,6 +598,7 @@ func (l *levelIter) loadFile(file *manifest.TableMetadata, dir int) loadFileRetu
 			}
 			return noFileLoaded
 		}
+		l.iterRangeFiltered = l.tableOpts.useRangeFilter && iters.point == nil
 		l.iter = iters.Point()
 		if l.interleaveRangeDels && iters.rangeDeletion != nil {
 			// If this file has range deletions, interleave the bounds of the
@@ -1127,6 +1140,7 @@ func (l *levelIter) Close() error {
 		l.err = l.iter.Close()
 		l.iter = nil
 	}
+	l.iterRangeFiltered = false
 	if l.rangeDelIterSetter != nil {
 		l.rangeDelIterSetter.setRangeDelIter(nil)
 	}
@@ -1140,6 +1154,12 @@ func (l *levelIter) SetBounds(lower, upper []byte) {
 	if l.iter == nil {
 		return
 	}
+	if l.iterRangeFiltered {
+		// The range filter only excluded the file for the previous bounds.
+		// Close() will set levelIter.err if an error occurs.
+		_ = l.Close()
+		return
+	}
 
 	// Update tableOpts.{Lower,Upper}Bound in case the new boundaries fall within
 	// the boundaries of the current table.
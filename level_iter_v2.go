@@ -215,6 +215,8 @@ func (l *levelIterV2) init(
 	}
 	// TODO(radu): investigate maximum suffix property.
 	l.tableOpts.MaximumSuffixProperty = nil // opts.MaximumSuffixProperty
+	l.tableOpts.PointKeyPredicate = opts.PointKeyPredicate
+	l.tableOpts.ValueProjection = opts.ValueProjection
 	l.tableOpts.UseL6Filters = opts.UseL6Filters
 	l.tableOpts.Category = opts.Category
 	l.tableOpts.layer = l.layer
@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/pebble/sstable/tablefilters"
 	"github.com/cockroachdb/pebble/sstable/tablefilters/binaryfuse"
 	"github.com/cockroachdb/pebble/sstable/tablefilters/bloom"
+	"github.com/cockroachdb/pebble/sstable/tablefilters/rangefilter"
 	"github.com/cockroachdb/pebble/vfs"
 	"github.com/cockroachdb/pebble/wal"
 )
@@ -43,7 +44,7 @@ const (
 	// the metamorphic tests should use. This may be greater than
 	// pebble.FormatNewest when some format major versions are marked as
 	// experimental.
-	newestFormatMajorVersionToTest = pebble.FormatNewest
+	newestFormatMajorVersionToTest = pebble.FormatSignedTables
 )
 
 func parseOptions(
@@ -149,6 +150,12 @@ func parseOptions(
 					panic(err)
 				}
 				opts.ioLatencySeed = v
+			case "TestOptions.crash_states_seed":
+				v, err := strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					panic(err)
+				}
+				opts.crashStatesSeed = v
 			case "TestOptions.ingest_split":
 				// TODO(radu): this should be on by default.
 				opts.ingestSplit = true
@@ -255,6 +262,9 @@ func optionsToString(opts *TestOptions) string {
 		fmt.Fprintf(&buf, "  io_latency_probability=%.10f\n", opts.ioLatencyProbability)
 		fmt.Fprintf(&buf, "  io_latency_seed=%d\n", opts.ioLatencySeed)
 	}
+	if opts.crashStatesSeed != 0 {
+		fmt.Fprintf(&buf, "  crash_states_seed=%d\n", opts.crashStatesSeed)
+	}
 	if opts.useSharedReplicate {
 		fmt.Fprintf(&buf, "  use_shared_replicate=%v\n", opts.useSharedReplicate)
 	}
@@ -423,6 +433,11 @@ type TestOptions struct {
 	ioLatencyProbability float64
 	ioLatencySeed        int64
 	ioLatencyMean        time.Duration
+	// If nonzero (and strictFS is set), a restart reopens the DB on a randomly
+	// sampled post-crash state of the filesystem (see vfs.CrashStates) that can
+	// include any prefix of the unsynced writes and directory changes, instead
+	// of only the synced data. The value seeds the sampling.
+	crashStatesSeed int64
 	// Enables ingest splits. Saved here for serialization as Options does not
 	// serialize this.
 	ingestSplit bool
@@ -774,6 +789,9 @@ func RandomOptions(rng *rand.Rand, kf KeyFormat, cfg RandomOptionsCfg) *TestOpti
 	if rng.IntN(2) == 0 {
 		opts.AllocatorSizeClasses = pebble.JemallocSizeClasses
 	}
+	if rng.IntN(3) == 0 {
+		opts.CompressionWorkers = 1 + rng.IntN(4)
+	}
 
 	opts.TargetFileSizes[0] = int64(randPowerOf2(rng, 0, 28)) // 1B - 256MB
 	if opts.TargetFileSizes[0] < 1<<12 {
@@ -819,6 +837,9 @@ func RandomOptions(rng *rand.Rand, kf KeyFormat, cfg RandomOptionsCfg) *TestOpti
 		pebble.UniformDBTableFilterPolicy(binaryfuse.FilterPolicy(
 			binaryfuse.SupportedBitsPerFingerprint[rng.IntN(len(binaryfuse.SupportedBitsPerFingerprint))],
 		)),
+		pebble.UniformDBTableFilterPolicy(rangefilter.FilterPolicy(
+			rangefilter.MinBitsPerKey + rng.IntN(rangefilter.MaxBitsPerKey-rangefilter.MinBitsPerKey+1),
+		)),
 		pebble.DBTableFilterPolicyUniform,
 		pebble.DBTableFilterPolicyProgressive,
 		pebble.DBTableFilterPolicyBinaryFuseProgressive,
@@ -827,6 +848,15 @@ func RandomOptions(rng *rand.Rand, kf KeyFormat, cfg RandomOptionsCfg) *TestOpti
 	opts.ApplyTableFilterPolicy(func() pebble.DBTableFilterPolicy {
 		return fp
 	})
+	// 25% of the time, enable adaptive sizing of bloom filters.
+	if rng.IntN(4) == 0 {
+		opts.TableFilterMemoryBudget = randPowerOf2(rng, 10, 20) // 1KB - 1MB
+	}
+	// 50% of the time, partition table filters (when the format major version
+	// allows it).
+	if rng.IntN(2) == 0 {
+		opts.PartitionTableFilters = true
+	}
 
 	// Explicitly disable disk-backed FS's for the random configurations. The
 	// single standard test configuration that uses a disk-backed FS is
@@ -841,6 +871,10 @@ func RandomOptions(rng *rand.Rand, kf KeyFormat, cfg RandomOptionsCfg) *TestOpti
 		testOpts.ioLatencyMean = expRandDuration(rng, 3*time.Millisecond, time.Second)
 		testOpts.ioLatencySeed = rng.Int64()
 	}
+	// 50% of the time, restarts crash to a random post-crash state.
+	if testOpts.strictFS && rng.IntN(2) == 0 {
+		testOpts.crashStatesSeed = 1 + rng.Int64N(math.MaxInt64)
+	}
 	testOpts.Threads = rng.IntN(runtime.GOMAXPROCS(0)) + 1
 	if testOpts.strictFS {
 		opts.DisableWAL = false
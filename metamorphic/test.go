@@ -7,6 +7,7 @@ package metamorphic
 import (
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"os"
 	"path"
 	"runtime/debug"
@@ -71,6 +72,9 @@ type Test struct {
 	// enabled, this is the same with testOpts.externalStorageFS; otherwise, this
 	// is an in-memory implementation used only by the test.
 	externalStorage remote.Storage
+
+	// restarts is the number of times a DB was restarted.
+	restarts int
 }
 
 type externalObjMeta struct {
@@ -336,7 +340,16 @@ func (t *Test) restartDB(dbID objID) error {
 		return nil
 	}
 	fs := vfs.Root(t.opts.FS).(*vfs.MemFS)
-	crashFS := fs.CrashClone(vfs.CrashCloneCfg{UnsyncedDataPercent: 0})
+	var crashFS *vfs.MemFS
+	if seed := t.testOpts.crashStatesSeed; seed != 0 {
+		// Crash to a random legal post-crash state. Pebble must recover the same
+		// data from any of them.
+		t.restarts++
+		rng := rand.New(rand.NewPCG(uint64(seed), uint64(t.restarts)))
+		crashFS = fs.CrashStates().Random(rng)
+	} else {
+		crashFS = fs.CrashClone(vfs.CrashCloneCfg{UnsyncedDataPercent: 0})
+	}
 	if err := db.Close(); err != nil {
 		return err
 	}
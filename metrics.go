@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/pebble/internal/manifest"
 	"github.com/cockroachdb/pebble/internal/manual"
 	"github.com/cockroachdb/pebble/metrics"
+	"github.com/cockroachdb/pebble/objstorage"
 	"github.com/cockroachdb/pebble/objstorage/objstorageprovider/sharedcache"
 	"github.com/cockroachdb/pebble/record"
 	"github.com/cockroachdb/pebble/sstable"
@@ -40,6 +41,9 @@ type CacheMetrics = cache.Metrics
 // FilterMetrics holds metrics for the filter policy
 type FilterMetrics = sstable.FilterMetrics
 
+// FilterMetrics.ByLevel must have an entry for each level.
+const _ = uint(numLevels-sstable.NumFilterMetricsLevels) + uint(sstable.NumFilterMetricsLevels-numLevels)
+
 // ThroughputMetric is a cumulative throughput metric. See the detailed
 // comment in base.
 type ThroughputMetric = base.ThroughputMetric
@@ -49,6 +53,10 @@ type ThroughputMetric = base.ThroughputMetric
 // file system.
 type SecondaryCacheMetrics = sharedcache.Metrics
 
+// DirectIOMetrics holds metrics about compaction reads and writes that use
+// direct I/O; see Options.Local.DirectIOForCompactions.
+type DirectIOMetrics = objstorage.DirectIOMetrics
+
 // AllLevelMetrics contains LevelMetrics for each level.
 type AllLevelMetrics [manifest.NumLevels]LevelMetrics
 
@@ -253,6 +261,9 @@ type Metrics struct {
 		Physical struct {
 			// The counts and total sizes of live tables.
 			Live metrics.CountAndSizeByPlacement
+			// The counts and total sizes of live tables, broken down by storage
+			// tier.
+			LiveByTier metrics.CountAndSizeByTier
 			// The counts and total sizes of obsolete tables; these are tables which are
 			// no longer referenced by the current DB state or any open iterators.
 			Obsolete metrics.CountAndSizeByPlacement
@@ -290,6 +301,9 @@ type Metrics struct {
 	BlobFiles struct {
 		// The counts and total physical sizes of blob files.
 		Live metrics.CountAndSizeByPlacement
+		// The counts and total physical sizes of live blob files, broken down by
+		// storage tier.
+		LiveByTier metrics.CountAndSizeByTier
 		// The counts and total physical sizes of obsolete blob files; these are blob
 		// files which are no longer referenced by the current DB state or any open
 		// iterators.
@@ -379,6 +393,9 @@ type Metrics struct {
 
 	SecondaryCacheMetrics SecondaryCacheMetrics
 
+	// DirectIO holds metrics about the use of direct I/O by compactions.
+	DirectIO DirectIOMetrics
+
 	private struct {
 		optionsFileSize  uint64
 		manifestFileSize uint64
@@ -1264,8 +1281,8 @@ func (m *Metrics) StringForTests() string {
 
 	// We recalculate the file cache size using the 64-bit sizes, and we ignore
 	// the genericcache metadata size which is harder to adjust.
-	const sstableReaderSize64bit = 304
-	const blobFileReaderSize64bit = 120
+	const sstableReaderSize64bit = 320
+	const blobFileReaderSize64bit = 128
 	mCopy.FileCache.Size = mCopy.FileCache.TableCount*sstableReaderSize64bit + mCopy.FileCache.BlobFileCount*blobFileReaderSize64bit
 	if math.MaxInt == math.MaxInt64 {
 		// Verify the 64-bit sizes, so they are kept updated.
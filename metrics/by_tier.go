@@ -0,0 +1,45 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package metrics
+
+import (
+	"github.com/cockroachdb/pebble/internal/base"
+	"github.com/cockroachdb/redact"
+)
+
+// CountAndSizeByTier contains space usage information for a set of files,
+// broken down by storage tier.
+type CountAndSizeByTier struct {
+	Hot  CountAndSize
+	Cold CountAndSize
+}
+
+// Ptr returns a pointer to the CountAndSize for the given tier.
+func (cst *CountAndSizeByTier) Ptr(tier base.StorageTier) *CountAndSize {
+	if tier == base.ColdTier {
+		return &cst.Cold
+	}
+	return &cst.Hot
+}
+
+// Accumulate adds the rhs counts and sizes to the receiver.
+func (cst *CountAndSizeByTier) Accumulate(rhs CountAndSizeByTier) {
+	cst.Hot.Accumulate(rhs.Hot)
+	cst.Cold.Accumulate(rhs.Cold)
+}
+
+// Total returns the sum across all tiers.
+func (cst CountAndSizeByTier) Total() CountAndSize {
+	return cst.Hot.Sum(cst.Cold)
+}
+
+func (cst CountAndSizeByTier) String() string {
+	return redact.StringWithoutMarkers(cst)
+}
+
+// SafeFormat implements redact.SafeFormatter.
+func (cst CountAndSizeByTier) SafeFormat(w redact.SafePrinter, verb rune) {
+	w.Printf("hot: %s, cold: %s", cst.Hot, cst.Cold)
+}
@@ -0,0 +1,26 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/pebble/internal/base"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountAndSizeByTier(t *testing.T) {
+	var cst CountAndSizeByTier
+	cst.Ptr(base.HotTier).Inc(1000)
+	cst.Ptr(base.ColdTier).Inc(2000)
+	cst.Ptr(base.ColdTier).Inc(3000)
+	expect(t, cst.Hot, 1, 1000)
+	expect(t, cst.Cold, 2, 5000)
+	expect(t, cst.Total(), 3, 6000)
+
+	cst.Accumulate(CountAndSizeByTier{Hot: CountAndSize{Count: 1, Bytes: 10}})
+	expect(t, cst.Hot, 2, 1010)
+	require.Equal(t, "hot: 2 (1KB), cold: 2 (4.9KB)", cst.String())
+}
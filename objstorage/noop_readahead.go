@@ -28,7 +28,7 @@ func (h *NoopReadHandle) ReadAt(ctx context.Context, p []byte, off int64) error
 func (*NoopReadHandle) Close() error { return nil }
 
 // SetupForCompaction is part of the ReadHandle interface.
-func (*NoopReadHandle) SetupForCompaction() {}
+func (*NoopReadHandle) SetupForCompaction(DirectIOBufferAllocator) {}
 
 // RecordCacheHit is part of the ReadHandle interface.
 func (*NoopReadHandle) RecordCacheHit(_ context.Context, offset, size int64) {}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/pebble/internal/base"
@@ -88,7 +89,11 @@ type ReadHandle interface {
 	// SetupForCompaction informs the implementation that the read handle will
 	// be used to read data blocks for a compaction. The implementation can expect
 	// sequential reads, and can decide to not retain data in any caches.
-	SetupForCompaction()
+	//
+	// If directIOBufs is non-nil, the implementation may read with direct I/O
+	// using buffers from directIOBufs. The buffers are allocated and released
+	// from the goroutine that calls SetupForCompaction and Close.
+	SetupForCompaction(directIOBufs DirectIOBufferAllocator)
 
 	// RecordCacheHit informs the implementation that we were able to retrieve a
 	// block from cache. This is useful for example when the implementation is
@@ -269,12 +274,53 @@ type CreateOptions struct {
 
 	// Tier is the storage tier for this object. If Tier is ColdTier and the
 	// provider has a local cold tier configured (and PreferSharedStorage is
-	// false), the object will be created on the local cold tier.
+	// false), the object will be created on the local cold tier. Otherwise, if
+	// the provider is configured to place cold objects on shared storage, the
+	// object is created there.
 	Tier base.StorageTier
 
 	// WriteCategory is used for the object when it is created on local storage
 	// to collect aggregated write metrics for each write source.
 	WriteCategory vfs.DiskWriteCategory
+
+	// DirectIO, if non-nil, requests that the object be written with direct
+	// I/O, bypassing the OS page cache, using buffers from the given allocator.
+	// The buffers are allocated and released from the goroutine that calls
+	// Create and Finish (or Abort). It only applies to objects created on the
+	// local hot tier; if the file system does not support direct I/O, the
+	// object is written through the page cache.
+	DirectIO DirectIOBufferAllocator
+}
+
+// DirectIOBufferAllocator allocates the buffers used to read and write local
+// objects with direct I/O. It is implemented by sstable/block.BufferPool, which
+// allows compactions to use the same pool for direct I/O buffers and for the
+// blocks they read.
+type DirectIOBufferAllocator interface {
+	// AllocDirectIOBuffer allocates a buffer of length n whose address is a
+	// multiple of vfs.DirectIOAlignment.
+	AllocDirectIOBuffer(n int) DirectIOBuffer
+}
+
+// DirectIOBuffer is a buffer allocated by a DirectIOBufferAllocator.
+type DirectIOBuffer interface {
+	// Bytes returns the buffer.
+	Bytes() []byte
+	// Release returns the buffer to its allocator.
+	Release()
+}
+
+// DirectIOMetrics holds metrics about reads and writes of local objects that
+// use direct I/O.
+type DirectIOMetrics struct {
+	// BytesRead is the number of bytes read with direct I/O.
+	BytesRead uint64
+	// BytesWritten is the number of bytes written with direct I/O.
+	BytesWritten uint64
+	// Fallbacks is the number of files that were requested to use direct I/O
+	// but fell back to buffered I/O because the file system does not support
+	// it.
+	Fallbacks uint64
 }
 
 // Provider is a singleton object used to access and manage objects.
@@ -348,6 +394,10 @@ type Provider interface {
 	// Cannot be called if shared storage is not configured for the provider.
 	SetCreatorID(creatorID CreatorID) error
 
+	// CreatorID returns the CreatorID set with SetCreatorID (or recovered from
+	// disk), or false if it was never set.
+	CreatorID() (CreatorID, bool)
+
 	// IsSharedForeign returns whether this object is owned by a different node.
 	IsSharedForeign(meta ObjectMetadata) bool
 
@@ -359,6 +409,10 @@ type Provider interface {
 	// Pebble and will never be removed by Pebble.
 	CreateExternalObjectBacking(locator remote.Locator, objName string) (RemoteObjectBacking, error)
 
+	// RemoteStorage returns the remote.Storage corresponding to the given
+	// locator. The storage is owned by the provider and must not be closed.
+	RemoteStorage(locator remote.Locator) (remote.Storage, error)
+
 	// GetExternalObjects returns a list of DiskFileNums corresponding to all
 	// objects that are backed by the given external object.
 	GetExternalObjects(locator remote.Locator, objName string) []base.DiskFileNum
@@ -380,9 +434,22 @@ type Provider interface {
 	// path should be able to resolve references to the specified files.
 	CheckpointState(fs vfs.FS, dir string, fileNums []base.DiskFileNum) error
 
+	// PrefetchRemote reads the range [offset, offset+length) of a remote object
+	// into the secondary cache and, if pinUntil is non-zero, protects it from
+	// eviction until that time. Returns the number of bytes read from remote
+	// storage. It is a no-op for local objects or when there is no secondary
+	// cache.
+	PrefetchRemote(
+		ctx context.Context, meta ObjectMetadata, offset, length int64, pinUntil time.Time,
+	) (int64, error)
+
 	// Metrics returns metrics about objstorage. Currently, it only returns metrics
 	// about the shared cache.
 	Metrics() sharedcache.Metrics
+
+	// DirectIOMetrics returns metrics about the use of direct I/O for local
+	// objects.
+	DirectIOMetrics() DirectIOMetrics
 }
 
 // RemoteObjectBacking encodes the metadata necessary to incorporate a shared
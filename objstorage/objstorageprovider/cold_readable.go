@@ -122,8 +122,8 @@ func (rh *coldReadHandle) Close() error {
 }
 
 // SetupForCompaction is part of the objstorage.ReadHandle interface.
-func (rh *coldReadHandle) SetupForCompaction() {
-	rh.cold.SetupForCompaction()
+func (rh *coldReadHandle) SetupForCompaction(directIOBufs objstorage.DirectIOBufferAllocator) {
+	rh.cold.SetupForCompaction(directIOBufs)
 }
 
 // RecordCacheHit is part of the objstorage.ReadHandle interface.
@@ -0,0 +1,215 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package objstorageprovider
+
+import (
+	"io"
+	"sync/atomic"
+
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble/internal/invariants"
+	"github.com/cockroachdb/pebble/objstorage"
+	"github.com/cockroachdb/pebble/vfs"
+)
+
+// directIOBufferSize is the size of the aligned buffers used for direct I/O.
+// Reads are issued in units of this size (acting as readahead, which the OS
+// does not perform for direct I/O) and writes are accumulated up to this size.
+// The buffers are obtained from the objstorage.DirectIOBufferAllocator passed
+// by the caller.
+const directIOBufferSize = 1 << 20 /* 1MB */
+
+type directIOCounters struct {
+	bytesRead    atomic.Uint64
+	bytesWritten atomic.Uint64
+	fallbacks    atomic.Uint64
+}
+
+func (c *directIOCounters) metrics() objstorage.DirectIOMetrics {
+	return objstorage.DirectIOMetrics{
+		BytesRead:    c.bytesRead.Load(),
+		BytesWritten: c.bytesWritten.Load(),
+		Fallbacks:    c.fallbacks.Load(),
+	}
+}
+
+func alignDown(n int64) int64 { return n &^ (vfs.DirectIOAlignment - 1) }
+
+func alignUp(n int64) int64 { return alignDown(n + vfs.DirectIOAlignment - 1) }
+
+// directReader serves reads from a file opened for direct I/O. Direct I/O
+// requires aligned offsets, lengths and buffers, so reads go through an aligned
+// buffer that is filled in large sequential chunks; this is suited to the
+// sequential access pattern of compactions.
+type directReader struct {
+	file     vfs.File
+	size     int64
+	counters *directIOCounters
+
+	bufHandle objstorage.DirectIOBuffer
+	buf       []byte
+	// bufOffset and bufLen describe the part of the file held in buf.
+	bufOffset int64
+	bufLen    int
+}
+
+// openDirectReader reopens the file for direct I/O, with a buffer allocated
+// from bufs. It returns nil if the file system does not support direct I/O.
+func openDirectReader(r *fileReadable, bufs objstorage.DirectIOBufferAllocator) *directReader {
+	f, err := r.fs.Open(r.filename)
+	if err != nil {
+		return nil
+	}
+	if err := vfs.SetDirectIO(f, true); err != nil {
+		_ = f.Close()
+		r.directIO.fallbacks.Add(1)
+		return nil
+	}
+	h := bufs.AllocDirectIOBuffer(directIOBufferSize)
+	return &directReader{
+		file:      f,
+		size:      r.size,
+		counters:  r.directIO,
+		bufHandle: h,
+		buf:       h.Bytes(),
+	}
+}
+
+func (d *directReader) ReadAt(p []byte, off int64) error {
+	end := off + int64(len(p))
+	if off >= d.bufOffset && end <= d.bufOffset+int64(d.bufLen) {
+		copy(p, d.buf[off-d.bufOffset:])
+		return nil
+	}
+	start := alignDown(off)
+	readLen := max(alignUp(end)-start, directIOBufferSize)
+	// Don't read (much) past the end of the file.
+	readLen = min(readLen, max(alignUp(d.size)-start, alignUp(end)-start))
+	buf := d.buf
+	if int64(len(buf)) < readLen {
+		// A read larger than the buffer; use a one-off buffer. Reads may happen
+		// on a different goroutine than SetupForCompaction, so the buffer can't
+		// come from the allocator.
+		buf = vfs.AlignedBuffer(int(readLen))
+	}
+	n, err := d.file.ReadAt(buf[:readLen], start)
+	if err != nil && (err != io.EOF || start+int64(n) < end) {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	d.counters.bytesRead.Add(uint64(n))
+	copy(p, buf[off-start:])
+	if &buf[0] == &d.buf[0] {
+		d.bufOffset, d.bufLen = start, n
+	}
+	return nil
+}
+
+func (d *directReader) Close() error {
+	d.bufHandle.Release()
+	d.bufHandle, d.buf = nil, nil
+	return d.file.Close()
+}
+
+// directWritable is an objstorage.Writable that writes to a file in direct I/O
+// mode. Writes are accumulated in an aligned buffer and written out in full
+// buffers; the unaligned tail is written with direct I/O disabled when the
+// object is finished.
+//
+// The sstable and blob file writers issue their data block writes from a write
+// queue goroutine, so the (synchronous) direct I/O writes don't stall the
+// goroutine building the blocks. The buffer is allocated when the writable is
+// created and released by Finish or Abort, which are called from the
+// goroutine that created the writable.
+type directWritable struct {
+	file      vfs.File
+	counters  *directIOCounters
+	bufHandle objstorage.DirectIOBuffer
+	buf       []byte
+	n         int
+}
+
+var _ objstorage.Writable = (*directWritable)(nil)
+
+func newDirectWritable(
+	file vfs.File, counters *directIOCounters, bufs objstorage.DirectIOBufferAllocator,
+) *directWritable {
+	h := bufs.AllocDirectIOBuffer(directIOBufferSize)
+	return &directWritable{
+		file:      file,
+		counters:  counters,
+		bufHandle: h,
+		buf:       h.Bytes(),
+	}
+}
+
+// Write is part of the objstorage.Writable interface.
+func (w *directWritable) Write(p []byte) error {
+	for len(p) > 0 {
+		c := copy(w.buf[w.n:], p)
+		w.n += c
+		p = p[c:]
+		if w.n == len(w.buf) {
+			if err := w.flush(w.n); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// flush writes out the first n bytes of the buffer, which must be aligned.
+func (w *directWritable) flush(n int) error {
+	if invariants.Enabled && n%vfs.DirectIOAlignment != 0 {
+		panic(errors.AssertionFailedf("unaligned direct I/O write of %d bytes", n))
+	}
+	if _, err := w.file.Write(w.buf[:n]); err != nil {
+		return err
+	}
+	w.counters.bytesWritten.Add(uint64(n))
+	w.n = copy(w.buf, w.buf[n:w.n])
+	return nil
+}
+
+// Finish is part of the objstorage.Writable interface.
+func (w *directWritable) Finish() error {
+	err := func() error {
+		if aligned := int(alignDown(int64(w.n))); aligned > 0 {
+			if err := w.flush(aligned); err != nil {
+				return err
+			}
+		}
+		if w.n > 0 {
+			// The tail is not aligned; write it through the page cache.
+			if err := vfs.SetDirectIO(w.file, false); err != nil {
+				return err
+			}
+			if _, err := w.file.Write(w.buf[:w.n]); err != nil {
+				return err
+			}
+		}
+		return w.file.Sync()
+	}()
+	err = firstError(err, w.file.Close())
+	w.release()
+	return err
+}
+
+// Abort is part of the objstorage.Writable interface.
+func (w *directWritable) Abort() {
+	_ = w.file.Close()
+	w.release()
+}
+
+func (w *directWritable) release() {
+	w.bufHandle.Release()
+	w.bufHandle, w.buf = nil, nil
+	w.file = nil
+}
+
+// StartMetadataPortion is part of the objstorage.Writable interface.
+func (w *directWritable) StartMetadataPortion() error { return nil }
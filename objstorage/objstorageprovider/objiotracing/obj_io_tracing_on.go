@@ -217,13 +217,13 @@ func (rh *readHandle) Close() error {
 }
 
 // SetupForCompaction is part of the objstorage.ReadHandle interface.
-func (rh *readHandle) SetupForCompaction() {
+func (rh *readHandle) SetupForCompaction(directIOBufs objstorage.DirectIOBufferAllocator) {
 	rh.g.add(context.Background(), Event{
 		Op:       SetupForCompactionOp,
 		FileNum:  rh.fileNum,
 		HandleID: rh.handleID,
 	})
-	rh.rh.SetupForCompaction()
+	rh.rh.SetupForCompaction(directIOBufs)
 }
 
 // RecordCacheHit is part of the objstorage.ReadHandle interface.
@@ -52,6 +52,10 @@ type provider struct {
 		// the count to prevent deletion for the life of this instance (these
 		// bumps are intentionally never released).
 		protectedObjects map[base.DiskFileNum]int
+		// unrefOnUnprotect contains the remote objects that were removed while
+		// they were protected. They are unreferenced once they become
+		// unprotected.
+		unrefOnUnprotect map[base.DiskFileNum]objstorage.ObjectMetadata
 	}
 }
 
@@ -99,6 +103,12 @@ type Settings struct {
 		// ReadaheadConfig is used to retrieve the current readahead mode; it is
 		// consulted whenever a read handle is initialized.
 		ReadaheadConfig *ReadaheadConfig
+
+		// DirectIOForCompactions causes read handles set up for compactions
+		// (see ReadHandle.SetupForCompaction) to read local objects with direct
+		// I/O, bypassing the OS page cache. Writes use direct I/O when requested
+		// through CreateOptions.DirectIO.
+		DirectIOForCompactions bool
 	}
 
 	// Fields here are set only if the provider is to support remote objects
@@ -112,6 +122,14 @@ type Settings struct {
 		CreateOnShared        remote.CreateOnSharedStrategy
 		CreateOnSharedLocator remote.Locator
 
+		// ColdTierOnShared, if set, causes objects created with the ColdTier
+		// storage tier to be created on shared storage (using ColdTierLocator)
+		// when no local cold tier is configured. Such objects can only be created
+		// once the creator ID is set; until then, they are created on the hot
+		// tier.
+		ColdTierOnShared bool
+		ColdTierLocator  remote.Locator
+
 		// CacheSizeBytes is the size of the on-disk block cache for objects
 		// on remote storage. If it is 0, no cache is used.
 		CacheSizeBytes int64
@@ -237,6 +255,7 @@ func open(settings Settings) (p *provider, _ error) {
 	}
 	p.mu.knownObjects = make(map[base.DiskFileNum]objstorage.ObjectMetadata)
 	p.mu.protectedObjects = make(map[base.DiskFileNum]int)
+	p.mu.unrefOnUnprotect = make(map[base.DiskFileNum]objstorage.ObjectMetadata)
 
 	if objiotracing.Enabled {
 		p.tracer = objiotracing.Open(settings.Local.FS, settings.Local.FSDirName)
@@ -316,6 +335,8 @@ func (p *provider) Create(
 ) (w objstorage.Writable, meta objstorage.ObjectMetadata, err error) {
 	if opts.PreferSharedStorage && p.st.Remote.CreateOnShared != remote.CreateOnSharedNone {
 		w, meta, err = p.sharedCreate(ctx, fileType, fileNum, p.st.Remote.CreateOnSharedLocator, opts)
+	} else if p.useRemoteColdTier(opts.Tier) {
+		w, meta, err = p.sharedCreate(ctx, fileType, fileNum, p.st.Remote.ColdTierLocator, opts)
 	} else {
 		var category vfs.DiskWriteCategory
 		if opts.WriteCategory != "" {
@@ -327,7 +348,7 @@ func (p *provider) Create(
 		if tier == base.ColdTier && p.st.Local.ColdTier.FS == nil {
 			tier = base.HotTier
 		}
-		w, meta, err = p.vfsCreate(ctx, fileType, fileNum, tier, category)
+		w, meta, err = p.vfsCreate(ctx, fileType, fileNum, tier, category, opts.DirectIO)
 	}
 	if err != nil {
 		err = errors.Wrapf(err, "creating object %s", fileNum)
@@ -340,6 +361,13 @@ func (p *provider) Create(
 	return w, meta, nil
 }
 
+// useRemoteColdTier returns true if an object for the given tier should be
+// created on the remote cold tier.
+func (p *provider) useRemoteColdTier(tier base.StorageTier) bool {
+	return tier == base.ColdTier && p.st.Local.ColdTier.FS == nil &&
+		p.st.Remote.ColdTierOnShared && p.remote.shared.initialized.Load()
+}
+
 // Remove removes an object.
 //
 // Note that if the object is remote, the object is only (conceptually) removed
@@ -537,6 +565,11 @@ func (p *provider) Metrics() sharedcache.Metrics {
 	return sharedcache.Metrics{}
 }
 
+// DirectIOMetrics is part of the objstorage.Provider interface.
+func (p *provider) DirectIOMetrics() objstorage.DirectIOMetrics {
+	return p.local.directIO.metrics()
+}
+
 // CheckpointState is part of the objstorage.Provider interface.
 func (p *provider) CheckpointState(fs vfs.FS, dir string, fileNums []base.DiskFileNum) error {
 	p.mu.Lock()
@@ -603,22 +636,35 @@ func (p *provider) protectObject(fileNum base.DiskFileNum) {
 
 func (p *provider) unprotectObject(fileNum base.DiskFileNum) {
 	p.mu.Lock()
-	defer p.mu.Unlock()
 	v := p.mu.protectedObjects[fileNum]
 	if invariants.Enabled && v == 0 {
 		panic(errors.AssertionFailedf("invalid protection count"))
 	}
 	if v > 1 {
 		p.mu.protectedObjects[fileNum] = v - 1
-	} else {
-		delete(p.mu.protectedObjects, fileNum)
-		// TODO(radu): check if the object is still in knownObject; if not, unref it
-		// now.
+		p.mu.Unlock()
+		return
+	}
+	delete(p.mu.protectedObjects, fileNum)
+	meta, ok := p.mu.unrefOnUnprotect[fileNum]
+	delete(p.mu.unrefOnUnprotect, fileNum)
+	p.mu.Unlock()
+	if ok {
+		// The object was removed while it was protected. Failing to unref it
+		// here only leaks the object on remote storage, where it can be cleaned
+		// up by garbage collection of unreferenced objects.
+		_ = p.sharedUnref(meta)
 	}
 }
 
-func (p *provider) isProtected(fileNum base.DiskFileNum) bool {
+// deferUnrefIfProtected returns true if the object is protected, in which case
+// the object will be unreferenced when it becomes unprotected.
+func (p *provider) deferUnrefIfProtected(meta objstorage.ObjectMetadata) bool {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	return p.mu.protectedObjects[fileNum] > 0
+	if p.mu.protectedObjects[meta.DiskFileNum] == 0 {
+		return false
+	}
+	p.mu.unrefOnUnprotect[meta.DiskFileNum] = meta
+	return true
 }
@@ -242,7 +242,7 @@ func TestProvider(t *testing.T) {
 					rh = UsePreallocatedReadHandle(r, objstorage.NoReadBefore, &prealloc)
 				}
 				if forCompaction {
-					rh.SetupForCompaction()
+					rh.SetupForCompaction(nil /* directIOBufs */)
 				}
 				log.Infof("size: %d", r.Size())
 				for l := range crstrings.LinesSeq(d.Input) {
@@ -738,3 +738,82 @@ func TestParallelSync(t *testing.T) {
 		})
 	}
 }
+
+// testDirectIOBuffers is a DirectIOBufferAllocator that tracks the number of
+// buffers in use.
+type testDirectIOBuffers struct {
+	inUse int
+}
+
+type testDirectIOBuffer struct {
+	a   *testDirectIOBuffers
+	buf []byte
+}
+
+func (a *testDirectIOBuffers) AllocDirectIOBuffer(n int) objstorage.DirectIOBuffer {
+	a.inUse++
+	return &testDirectIOBuffer{a: a, buf: vfs.AlignedBuffer(n)}
+}
+
+func (b *testDirectIOBuffer) Bytes() []byte { return b.buf }
+
+func (b *testDirectIOBuffer) Release() { b.a.inUse-- }
+
+func TestDirectIO(t *testing.T) {
+	dir := t.TempDir()
+	st := DefaultSettings(vfs.Default, dir)
+	st.Local.DirectIOForCompactions = true
+	p, err := Open(st)
+	require.NoError(t, err)
+	defer p.Close()
+
+	ctx := context.Background()
+	rng := rand.New(rand.NewPCG(0, 1))
+	var bufs testDirectIOBuffers
+	for i, size := range []int{0, 100, vfs.DirectIOAlignment, directIOBufferSize + 12345, 3*directIOBufferSize + 1} {
+		fileNum := base.DiskFileNum(i + 1)
+		data := make([]byte, size)
+		for j := range data {
+			data[j] = byte(rng.Uint32())
+		}
+		w, _, err := p.Create(ctx, base.FileTypeTable, fileNum, objstorage.CreateOptions{DirectIO: &bufs})
+		require.NoError(t, err)
+		if p.DirectIOMetrics().Fallbacks > 0 {
+			w.Abort()
+			t.Skip("direct I/O is not supported")
+		}
+		for rest := data; len(rest) > 0; {
+			n := min(len(rest), 1+rng.IntN(100000))
+			require.NoError(t, w.Write(rest[:n]))
+			rest = rest[n:]
+		}
+		require.NoError(t, w.Finish())
+
+		r, err := p.OpenForReading(ctx, base.FileTypeTable, fileNum, objstorage.OpenOptions{})
+		require.NoError(t, err)
+		require.Equal(t, int64(size), r.Size())
+		rh := r.NewReadHandle(objstorage.NoReadBefore)
+		rh.SetupForCompaction(&bufs)
+		// Read sequentially, as a compaction would, with the occasional read
+		// elsewhere in the file.
+		for off := 0; off < size; {
+			n := min(size-off, 1+rng.IntN(64<<10))
+			readOff := off
+			if rng.IntN(10) == 0 {
+				readOff = rng.IntN(size - n + 1)
+			} else {
+				off += n
+			}
+			buf := make([]byte, n)
+			require.NoError(t, rh.ReadAt(ctx, buf, int64(readOff)))
+			require.Equal(t, data[readOff:readOff+n], buf)
+		}
+		require.NoError(t, rh.Close())
+		require.NoError(t, r.Close())
+		require.Zero(t, bufs.inUse)
+	}
+	m := p.DirectIOMetrics()
+	require.Zero(t, m.Fallbacks)
+	require.NotZero(t, m.BytesRead)
+	require.NotZero(t, m.BytesWritten)
+}
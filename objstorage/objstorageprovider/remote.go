@@ -11,6 +11,7 @@ import (
 	"slices"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/pebble/internal/base"
@@ -217,6 +218,14 @@ func (p *provider) SetCreatorID(creatorID objstorage.CreatorID) error {
 	return nil
 }
 
+// CreatorID is part of the objstorage.Provider interface.
+func (p *provider) CreatorID() (objstorage.CreatorID, bool) {
+	if p.st.Remote.StorageFactory == nil || !p.remote.shared.initialized.Load() {
+		return 0, false
+	}
+	return p.remote.shared.creatorID, true
+}
+
 // IsSharedForeign is part of the objstorage.Provider interface.
 func (p *provider) IsSharedForeign(meta objstorage.ObjectMetadata) bool {
 	if !p.remote.shared.initialized.Load() {
@@ -373,6 +382,24 @@ func (p *provider) remoteOpenForReading(
 	return p.newRemoteReadable(reader, size, meta.DiskFileNum, meta.Remote.Storage.IsNotExistError), nil
 }
 
+// PrefetchRemote is part of the objstorage.Provider interface.
+func (p *provider) PrefetchRemote(
+	ctx context.Context, meta objstorage.ObjectMetadata, offset, length int64, pinUntil time.Time,
+) (int64, error) {
+	if !meta.IsRemote() || p.remote.cache == nil {
+		return 0, nil
+	}
+	if err := p.remoteCheckInitialized(); err != nil {
+		return 0, err
+	}
+	reader, size, err := meta.Remote.Storage.ReadObject(ctx, remoteObjectName(meta))
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = reader.Close() }()
+	return p.remote.cache.Prefetch(ctx, meta.DiskFileNum, reader, size, offset, length, pinUntil)
+}
+
 func (p *provider) remoteSize(meta objstorage.ObjectMetadata) (int64, error) {
 	if err := p.remoteCheckInitialized(); err != nil {
 		return 0, err
@@ -389,9 +416,7 @@ func (p *provider) sharedUnref(meta objstorage.ObjectMetadata) error {
 		// Never delete objects in this mode.
 		return nil
 	}
-	if p.isProtected(meta.DiskFileNum) {
-		// TODO(radu): we need a mechanism to unref the object when it becomes
-		// unprotected.
+	if p.deferUnrefIfProtected(meta) {
 		return nil
 	}
 
@@ -438,6 +463,14 @@ func (p *provider) ensureStorage(locator remote.Locator) (remote.Storage, error)
 	return p.ensureStorageLocked(locator)
 }
 
+// RemoteStorage is part of the objstorage.Provider interface.
+func (p *provider) RemoteStorage(locator remote.Locator) (remote.Storage, error) {
+	if err := p.remoteCheckInitialized(); err != nil {
+		return nil, err
+	}
+	return p.ensureStorage(locator)
+}
+
 // GetExternalObjects is part of the Provider interface.
 func (p *provider) GetExternalObjects(locator remote.Locator, objName string) []base.DiskFileNum {
 	p.mu.Lock()
@@ -0,0 +1,230 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package objstorageprovider
+
+import (
+	"slices"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble/objstorage"
+	"github.com/cockroachdb/pebble/objstorage/objstorageprovider/remoteobjcat"
+	"github.com/cockroachdb/pebble/objstorage/remote"
+)
+
+// RemoteGCOptions configures FindRemoteGarbage.
+type RemoteGCOptions struct {
+	// CreatorID is the creator ID of the store whose objects are garbage
+	// collected. Only objects created by this store, and ref markers that
+	// reference these objects or that were created by this store, are
+	// considered.
+	CreatorID objstorage.CreatorID
+
+	// Catalogs contains the contents of the remote object catalogs of all the
+	// stores that can reference objects created by CreatorID (including the
+	// catalog of CreatorID itself). Ref markers created by stores whose catalog
+	// is not provided are conservatively assumed to be live.
+	//
+	// Note that catalog entries are matched against objects by name only; the
+	// locators in the catalogs are not checked.
+	Catalogs []remoteobjcat.CatalogContents
+
+	// GracePeriod is the minimum age of an orphaned object or ref marker before
+	// it is deleted. A process can create an object or a ref marker before it
+	// is recorded in the catalog; the grace period must be long enough to cover
+	// this window. Requires the storage to implement remote.StorageWithModTime
+	// when Delete is set.
+	GracePeriod time.Duration
+
+	// Delete, if set, causes orphaned objects and ref markers older than the
+	// grace period to be deleted.
+	Delete bool
+
+	// Now returns the current time; defaults to time.Now.
+	Now func() time.Time
+}
+
+// RemoteGCReport is the result of FindRemoteGarbage. All lists are sorted.
+type RemoteGCReport struct {
+	// OrphanedObjects contains the names of objects created by the store which
+	// are not referenced by any catalog and have no live ref markers.
+	OrphanedObjects []string
+	// OrphanedRefs contains the names of ref markers which are not backed by a
+	// catalog entry of their creator or whose object no longer exists.
+	OrphanedRefs []string
+	// Deleted contains the names of the objects and ref markers that were
+	// deleted (only when RemoteGCOptions.Delete is set).
+	Deleted []string
+	// WithinGracePeriod contains the names of orphaned objects and ref markers
+	// that were not deleted because they are too recent.
+	WithinGracePeriod []string
+}
+
+// FindRemoteGarbage lists the given remote storage and cross-references the
+// objects and ref markers it contains with the given catalogs, reporting (and
+// optionally deleting) orphaned objects and ref markers. These can be left
+// behind when a process crashes between creating an object and recording it
+// in the catalog, or between removing an object from the catalog and removing
+// its ref marker.
+//
+// Objects with custom object names (external objects) are never considered.
+func FindRemoteGarbage(storage remote.Storage, opts RemoteGCOptions) (RemoteGCReport, error) {
+	if !opts.CreatorID.IsSet() {
+		return RemoteGCReport{}, errors.Errorf("creator ID must be set")
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	var modTimeStorage remote.StorageWithModTime
+	if opts.Delete && opts.GracePeriod > 0 {
+		var ok bool
+		if modTimeStorage, ok = storage.(remote.StorageWithModTime); !ok {
+			return RemoteGCReport{}, errors.Errorf("grace period requires storage that reports modification times")
+		}
+	}
+
+	// Collect the object names and expected ref markers from the catalogs.
+	knownCreators := make(map[objstorage.CreatorID]struct{})
+	referenced := make(map[string]struct{})
+	expectedRefs := make(map[string]struct{})
+	for _, c := range opts.Catalogs {
+		if c.CreatorID.IsSet() {
+			knownCreators[c.CreatorID] = struct{}{}
+		}
+		for _, m := range c.Objects {
+			var meta objstorage.ObjectMetadata
+			meta.DiskFileNum = m.FileNum
+			meta.FileType = m.FileType
+			meta.Remote.CreatorID = m.CreatorID
+			meta.Remote.CreatorFileNum = m.CreatorFileNum
+			meta.Remote.CleanupMethod = m.CleanupMethod
+			meta.Remote.CustomObjectName = m.CustomObjectName
+			referenced[remoteObjectName(meta)] = struct{}{}
+			if m.CleanupMethod == objstorage.SharedRefTracking && c.CreatorID.IsSet() {
+				expectedRefs[sharedObjectRefName(meta, c.CreatorID, m.FileNum)] = struct{}{}
+			}
+		}
+	}
+
+	names, err := storage.List("" /* prefix */, "" /* delimiter */)
+	if err != nil {
+		return RemoteGCReport{}, err
+	}
+	objects := make(map[string]struct{})
+	refs := make(map[string][]string)
+	for _, name := range names {
+		if objName, _, _, ok := parseSharedObjectRefName(name); ok {
+			refs[objName] = append(refs[objName], name)
+		} else if _, _, _, ok := parseRemoteObjectName(name); ok {
+			objects[name] = struct{}{}
+		}
+	}
+
+	var res RemoteGCReport
+	for objName, objRefs := range refs {
+		_, objCreatorID, _, isPebbleObj := parseRemoteObjectName(objName)
+		_, objExists := objects[objName]
+		liveRefs := 0
+		for _, refName := range objRefs {
+			_, refCreatorID, _, _ := parseSharedObjectRefName(refName)
+			if refCreatorID != opts.CreatorID && (!isPebbleObj || objCreatorID != opts.CreatorID) {
+				// The ref marker is unrelated to this store.
+				liveRefs++
+				continue
+			}
+			_, isKnownCreator := knownCreators[refCreatorID]
+			_, isExpected := expectedRefs[refName]
+			switch {
+			case !objExists && isPebbleObj:
+				// The object was already removed; the ref marker is useless.
+				res.OrphanedRefs = append(res.OrphanedRefs, refName)
+			case isKnownCreator && !isExpected:
+				res.OrphanedRefs = append(res.OrphanedRefs, refName)
+			default:
+				liveRefs++
+			}
+		}
+		if liveRefs > 0 {
+			// The object is referenced.
+			delete(objects, objName)
+		}
+	}
+	for objName := range objects {
+		if _, creatorID, _, _ := parseRemoteObjectName(objName); creatorID != opts.CreatorID {
+			continue
+		}
+		if _, ok := referenced[objName]; ok {
+			continue
+		}
+		res.OrphanedObjects = append(res.OrphanedObjects, objName)
+	}
+	slices.Sort(res.OrphanedObjects)
+	slices.Sort(res.OrphanedRefs)
+
+	if !opts.Delete {
+		return res, nil
+	}
+	now := opts.Now()
+	// withinGracePeriod returns true if the object or ref marker is too recent
+	// to be deleted.
+	withinGracePeriod := func(name string) (bool, error) {
+		if modTimeStorage == nil {
+			return false, nil
+		}
+		modTime, err := modTimeStorage.ModTime(name)
+		if err != nil {
+			return false, err
+		}
+		return now.Sub(modTime) < opts.GracePeriod, nil
+	}
+	deleteObj := func(name string) error {
+		if err := storage.Delete(name); err != nil && !storage.IsNotExistError(err) {
+			return errors.Wrapf(err, "deleting %q", errors.Safe(name))
+		}
+		res.Deleted = append(res.Deleted, name)
+		return nil
+	}
+	// Delete ref markers before objects, mirroring the order used when an
+	// object is unreferenced. An object is retained if any of its orphaned ref
+	// markers is retained.
+	retainedObjs := make(map[string]struct{})
+	for _, refName := range res.OrphanedRefs {
+		objName, _, _, _ := parseSharedObjectRefName(refName)
+		if recent, err := withinGracePeriod(refName); err != nil {
+			if storage.IsNotExistError(err) {
+				continue
+			}
+			return res, err
+		} else if recent {
+			res.WithinGracePeriod = append(res.WithinGracePeriod, refName)
+			retainedObjs[objName] = struct{}{}
+			continue
+		}
+		if err := deleteObj(refName); err != nil {
+			return res, err
+		}
+	}
+	for _, objName := range res.OrphanedObjects {
+		if _, ok := retainedObjs[objName]; ok {
+			res.WithinGracePeriod = append(res.WithinGracePeriod, objName)
+			continue
+		}
+		if recent, err := withinGracePeriod(objName); err != nil {
+			if storage.IsNotExistError(err) {
+				continue
+			}
+			return res, err
+		} else if recent {
+			res.WithinGracePeriod = append(res.WithinGracePeriod, objName)
+			continue
+		}
+		if err := deleteObj(objName); err != nil {
+			return res, err
+		}
+	}
+	slices.Sort(res.Deleted)
+	slices.Sort(res.WithinGracePeriod)
+	return res, nil
+}
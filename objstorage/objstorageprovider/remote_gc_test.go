@@ -0,0 +1,143 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package objstorageprovider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/pebble/internal/base"
+	"github.com/cockroachdb/pebble/objstorage"
+	"github.com/cockroachdb/pebble/objstorage/objstorageprovider/remoteobjcat"
+	"github.com/cockroachdb/pebble/objstorage/remote"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindRemoteGarbage(t *testing.T) {
+	storage := remote.NewInMem()
+	create := func(name string) {
+		w, err := storage.CreateObject(name)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+	}
+	makeMeta := func(creatorID objstorage.CreatorID, fileNum, creatorFileNum base.DiskFileNum) remoteobjcat.RemoteObjectMetadata {
+		return remoteobjcat.RemoteObjectMetadata{
+			FileNum:        fileNum,
+			FileType:       base.FileTypeTable,
+			CreatorID:      creatorID,
+			CreatorFileNum: creatorFileNum,
+			CleanupMethod:  objstorage.SharedRefTracking,
+		}
+	}
+	objName := func(m remoteobjcat.RemoteObjectMetadata) string {
+		var meta objstorage.ObjectMetadata
+		meta.FileType = m.FileType
+		meta.Remote.CreatorID = m.CreatorID
+		meta.Remote.CreatorFileNum = m.CreatorFileNum
+		return remoteObjectName(meta)
+	}
+	refName := func(m remoteobjcat.RemoteObjectMetadata, refCreatorID objstorage.CreatorID) string {
+		var meta objstorage.ObjectMetadata
+		meta.FileType = m.FileType
+		meta.Remote.CreatorID = m.CreatorID
+		meta.Remote.CreatorFileNum = m.CreatorFileNum
+		meta.Remote.CleanupMethod = objstorage.SharedRefTracking
+		return sharedObjectRefName(meta, refCreatorID, m.FileNum)
+	}
+
+	// Store 1 has a live object (1), an object that is also referenced by store
+	// 2 (2), an object that was never recorded in the catalog (3) and an object
+	// whose catalog entry was removed but the ref marker leaked (4).
+	live := makeMeta(1, 1, 1)
+	shared := makeMeta(1, 2, 2)
+	unrecorded := makeMeta(1, 3, 3)
+	leaked := makeMeta(1, 4, 4)
+	// Store 2 references object 2 (as its file 10) and has a leaked ref marker
+	// on object 1 (as its file 11).
+	sharedByStore2 := makeMeta(1, 10, 2)
+	leakedByStore2 := makeMeta(1, 11, 1)
+	// Store 3 (whose catalog is not provided) references object 1.
+	store3Ref := makeMeta(1, 20, 1)
+	// Object created by another store; never considered.
+	other := makeMeta(5, 1, 1)
+
+	for _, m := range []remoteobjcat.RemoteObjectMetadata{live, shared, unrecorded, leaked, other} {
+		create(objName(m))
+	}
+	create(refName(live, 1))
+	create(refName(shared, 1))
+	create(refName(unrecorded, 1))
+	create(refName(leaked, 1))
+	create(refName(sharedByStore2, 2))
+	create(refName(leakedByStore2, 2))
+	create(refName(store3Ref, 3))
+	create(refName(other, 5))
+	create("external.sst")
+
+	opts := RemoteGCOptions{
+		CreatorID: 1,
+		Catalogs: []remoteobjcat.CatalogContents{
+			{CreatorID: 1, Objects: []remoteobjcat.RemoteObjectMetadata{live, shared}},
+			{CreatorID: 2, Objects: []remoteobjcat.RemoteObjectMetadata{sharedByStore2}},
+		},
+	}
+	res, err := FindRemoteGarbage(storage, opts)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{objName(unrecorded), objName(leaked)}, res.OrphanedObjects)
+	require.ElementsMatch(t, []string{
+		refName(unrecorded, 1), refName(leaked, 1), refName(leakedByStore2, 2),
+	}, res.OrphanedRefs)
+	require.Empty(t, res.Deleted)
+
+	// With a grace period, nothing is deleted.
+	opts.Delete = true
+	opts.GracePeriod = time.Hour
+	res, err = FindRemoteGarbage(storage, opts)
+	require.NoError(t, err)
+	require.Empty(t, res.Deleted)
+	require.Len(t, res.WithinGracePeriod, 5)
+
+	// After the grace period, the orphans are deleted.
+	opts.Now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	res, err = FindRemoteGarbage(storage, opts)
+	require.NoError(t, err)
+	require.Len(t, res.Deleted, 5)
+	require.Empty(t, res.WithinGracePeriod)
+
+	res, err = FindRemoteGarbage(storage, opts)
+	require.NoError(t, err)
+	require.Empty(t, res.OrphanedObjects)
+	require.Empty(t, res.OrphanedRefs)
+	names, err := storage.List("", "")
+	require.NoError(t, err)
+	require.Len(t, names, 9)
+}
+
+func TestParseRemoteObjectNames(t *testing.T) {
+	var meta objstorage.ObjectMetadata
+	meta.DiskFileNum = base.DiskFileNum(123)
+	meta.FileType = base.FileTypeBlob
+	meta.Remote.CreatorID = objstorage.CreatorID(456)
+	meta.Remote.CreatorFileNum = base.DiskFileNum(789)
+	meta.Remote.CleanupMethod = objstorage.SharedRefTracking
+
+	fileType, creatorID, creatorFileNum, ok := parseRemoteObjectName(remoteObjectName(meta))
+	require.True(t, ok)
+	require.Equal(t, base.FileTypeBlob, fileType)
+	require.Equal(t, meta.Remote.CreatorID, creatorID)
+	require.Equal(t, meta.Remote.CreatorFileNum, creatorFileNum)
+
+	objName, refCreatorID, refFileNum, ok := parseSharedObjectRefName(sharedObjectRefName(meta, 7, meta.DiskFileNum))
+	require.True(t, ok)
+	require.Equal(t, remoteObjectName(meta), objName)
+	require.Equal(t, objstorage.CreatorID(7), refCreatorID)
+	require.Equal(t, meta.DiskFileNum, refFileNum)
+
+	for _, name := range []string{"external.sst", "0000-456-000789.blob", "0e17-456.sst", "foo.ref.x.1"} {
+		_, _, _, ok1 := parseRemoteObjectName(name)
+		_, _, _, ok2 := parseSharedObjectRefName(name)
+		require.False(t, ok1 || ok2, name)
+	}
+}
@@ -6,6 +6,8 @@ package objstorageprovider
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/pebble/internal/base"
@@ -105,3 +107,74 @@ func objHash(meta objstorage.ObjectMetadata) uint16 {
 	const prime2 = 17539
 	return uint16(uint64(meta.Remote.CreatorID)*prime1 + uint64(meta.Remote.CreatorFileNum)*prime2)
 }
+
+// parseRemoteObjectName parses the name of an object that was created by a
+// Pebble instance (i.e. without a custom object name). It returns false if the
+// name does not match the format produced by remoteObjectName.
+func parseRemoteObjectName(
+	name string,
+) (
+	fileType base.FileType,
+	creatorID objstorage.CreatorID,
+	creatorFileNum base.DiskFileNum,
+	ok bool,
+) {
+	var ext string
+	switch {
+	case strings.HasSuffix(name, ".sst"):
+		fileType, ext = base.FileTypeTable, ".sst"
+	case strings.HasSuffix(name, ".blob"):
+		fileType, ext = base.FileTypeBlob, ".blob"
+	default:
+		return 0, 0, 0, false
+	}
+	parts := strings.Split(strings.TrimSuffix(name, ext), "-")
+	if len(parts) != 3 || len(parts[0]) != 4 {
+		return 0, 0, 0, false
+	}
+	hash, err := strconv.ParseUint(parts[0], 16, 16)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	id, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	fileNum, err := strconv.ParseUint(parts[2], 10, 64)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	var meta objstorage.ObjectMetadata
+	meta.FileType = fileType
+	meta.Remote.CreatorID = objstorage.CreatorID(id)
+	meta.Remote.CreatorFileNum = base.DiskFileNum(fileNum)
+	if uint16(hash) != objHash(meta) {
+		return 0, 0, 0, false
+	}
+	return fileType, meta.Remote.CreatorID, meta.Remote.CreatorFileNum, true
+}
+
+// parseSharedObjectRefName parses the name of a ref marker (see
+// sharedObjectRefName), returning the name of the referenced object along
+// with the creator ID and local file number of the referencing provider.
+func parseSharedObjectRefName(
+	name string,
+) (objName string, refCreatorID objstorage.CreatorID, refFileNum base.DiskFileNum, ok bool) {
+	idx := strings.LastIndex(name, ".ref.")
+	if idx <= 0 {
+		return "", 0, 0, false
+	}
+	parts := strings.Split(name[idx+len(".ref."):], ".")
+	if len(parts) != 2 {
+		return "", 0, 0, false
+	}
+	id, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return "", 0, 0, false
+	}
+	fileNum, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return "", 0, 0, false
+	}
+	return name[:idx], objstorage.CreatorID(id), base.DiskFileNum(fileNum), true
+}
@@ -273,7 +273,7 @@ func (r *remoteReadHandle) Close() error {
 }
 
 // SetupForCompaction is part of the objstorage.ReadHandle interface.
-func (r *remoteReadHandle) SetupForCompaction() {
+func (r *remoteReadHandle) SetupForCompaction(objstorage.DirectIOBufferAllocator) {
 	r.forCompaction = true
 }
 
@@ -84,7 +84,7 @@ func TestRemoteReadHandle(t *testing.T) {
 			d.ScanArgs(t, "read-before-size", &readBeforeSize)
 			rh = rr.NewReadHandle(objstorage.ReadBeforeSize(readBeforeSize))
 			if d.HasArg("setup-for-compaction") {
-				rh.SetupForCompaction()
+				rh.SetupForCompaction(nil /* directIOBufs */)
 			}
 			return ""
 
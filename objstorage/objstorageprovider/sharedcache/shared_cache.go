@@ -40,6 +40,10 @@ type Cache struct {
 
 	logger  base.Logger
 	metrics internalMetrics
+
+	// now returns the current time; used to determine whether pinned cache
+	// blocks can be evicted. Can be overridden in tests.
+	now func() time.Time
 }
 
 // Metrics is a struct containing metrics exported by the secondary cache.
@@ -71,6 +75,9 @@ type Metrics struct {
 	// The number of times writing a cache block to the cache failed.
 	WriteBackFailures int64
 
+	// The number of bytes read from the object by Prefetch.
+	PrefetchedBytes int64
+
 	// The latency of calls to get some data from the cache.
 	GetLatency prometheus.Histogram
 	// The latency of reads of a single cache block from disk.
@@ -97,6 +104,7 @@ type internalMetrics struct {
 
 	evictions         atomic.Int64
 	writeBackFailures atomic.Int64
+	prefetchedBytes   atomic.Int64
 
 	getLatency       prometheus.Histogram
 	diskReadLatency  prometheus.Histogram
@@ -140,6 +148,7 @@ func Open(
 		logger:            logger,
 		bm:                makeBlockMath(blockSize),
 		shardingBlockSize: shardingBlockSize,
+		now:               time.Now,
 	}
 	c.shards = make([]shard, numShards)
 	blocksPerShard := sizeBytes / int64(numShards) / int64(blockSize)
@@ -191,6 +200,7 @@ func (c *Cache) Metrics() Metrics {
 		ReadsWithNoHit:      c.metrics.readsWithNoHit.Load(),
 		Evictions:           c.metrics.evictions.Load(),
 		WriteBackFailures:   c.metrics.writeBackFailures.Load(),
+		PrefetchedBytes:     c.metrics.prefetchedBytes.Load(),
 		GetLatency:          c.metrics.getLatency,
 		DiskReadLatency:     c.metrics.diskReadLatency,
 		QueuePutLatency:     c.metrics.queuePutLatency,
@@ -289,6 +299,78 @@ func (c *Cache) ReadAt(
 	return nil
 }
 
+// Prefetch ensures that the range [ofs, ofs+length) of an object is in the
+// cache, reading any missing data from the object. Unlike ReadAt, the data is
+// written to the cache before Prefetch returns.
+//
+// If pinUntil is non-zero, the cache blocks containing the range are not
+// evicted before that time (pinning a block that is already pinned extends the
+// pin). Note that if all the blocks of a shard are pinned, new data cannot be
+// added to that shard until the pins expire.
+//
+// Returns the number of bytes that were read from the object.
+func (c *Cache) Prefetch(
+	ctx context.Context,
+	fileNum base.DiskFileNum,
+	objReader remote.ObjectReader,
+	objSize int64,
+	ofs int64,
+	length int64,
+	pinUntil time.Time,
+) (fetched int64, _ error) {
+	end := min(ofs+length, objSize)
+	if ofs >= end {
+		return 0, nil
+	}
+	var pinUntilNanos int64
+	if !pinUntil.IsZero() {
+		pinUntilNanos = pinUntil.UnixNano()
+	}
+	blockSize := int64(c.bm.BlockSize())
+	// fetch reads the block-aligned range [start, end) from the object and
+	// writes it to the given shard.
+	fetch := func(s *shard, start, end int64) error {
+		buf := make([]byte, end-start)
+		n := min(end, objSize) - start
+		if err := objReader.ReadAt(ctx, buf[:n], start); err != nil {
+			return err
+		}
+		fetched += n
+		c.metrics.prefetchedBytes.Add(n)
+		if err := s.set(fileNum, buf, start, pinUntilNanos); err != nil {
+			c.metrics.writeBackFailures.Add(1)
+			return err
+		}
+		return nil
+	}
+	// Process the range one shard block at a time; within each shard block,
+	// fetch each run of missing cache blocks with a single read.
+	for start := c.bm.BlockOffset(c.bm.Block(ofs)); start < end; {
+		shardEnd := min((start/c.shardingBlockSize+1)*c.shardingBlockSize, c.bm.RoundUp(end))
+		s := c.getShard(fileNum, start)
+		missingStart := int64(-1)
+		for b := start; b < shardEnd; b += blockSize {
+			if s.pin(fileNum, b, pinUntilNanos) {
+				if missingStart >= 0 {
+					if err := fetch(s, missingStart, b); err != nil {
+						return fetched, err
+					}
+					missingStart = -1
+				}
+			} else if missingStart < 0 {
+				missingStart = b
+			}
+		}
+		if missingStart >= 0 {
+			if err := fetch(s, missingStart, shardEnd); err != nil {
+				return fetched, err
+			}
+		}
+		start = shardEnd
+	}
+	return fetched, nil
+}
+
 // get attempts to read the requested data from the cache, if it is already
 // there.
 //
@@ -349,7 +431,7 @@ func (c *Cache) set(fileNum base.DiskFileNum, p []byte, ofs int64) error {
 		if toBoundary := int(c.shardingBlockSize - ((ofs + int64(n)) % c.shardingBlockSize)); cappedLen > toBoundary {
 			cappedLen = toBoundary
 		}
-		err := shard.set(fileNum, p[n:n+cappedLen], ofs+int64(n))
+		err := shard.set(fileNum, p[n:n+cappedLen], ofs+int64(n), 0 /* pinUntil */)
 		if err != nil {
 			return err
 		}
@@ -395,6 +477,9 @@ type shard struct {
 type cacheBlockState struct {
 	lock    lockState
 	logical logicalBlockID
+	// pinnedUntil is the time (in Unix nanoseconds) until which the block must
+	// not be evicted; zero if the block is not pinned.
+	pinnedUntil int64
 
 	// next is the next block in the LRU or free list (or invalidBlockIndex if it
 	// is the last block in the free list).
@@ -621,10 +706,11 @@ func (s *shard) get(fileNum base.DiskFileNum, p []byte, ofs int64) (n int, _ err
 
 // set attempts to write the requested data to the shard. The data must not
 // cross a shard boundary, and both ofs & len(p) must be multiples of the
-// block size.
+// block size. If pinUntil is non-zero, the written blocks (and any blocks that
+// were already present) are pinned until that time (in Unix nanoseconds).
 //
 // If all of p is not written to the shard, set returns a non-nil error.
-func (s *shard) set(fileNum base.DiskFileNum, p []byte, ofs int64) error {
+func (s *shard) set(fileNum base.DiskFileNum, p []byte, ofs int64, pinUntil int64) error {
 	if invariants.Enabled {
 		if ofs/s.shardingBlockSize != (ofs+int64(len(p))-1)/s.shardingBlockSize {
 			panic(errors.AssertionFailedf("set crosses shard boundary: %v %v", errors.Safe(ofs), errors.Safe(len(p))))
@@ -655,7 +741,8 @@ func (s *shard) set(fileNum base.DiskFileNum, p []byte, ofs int64) error {
 			cacheBlockIdx: s.bm.Block(ofs + int64(n)),
 		}
 		s.mu.Lock()
-		if _, ok := s.mu.where[k]; ok {
+		if idx, ok := s.mu.where[k]; ok {
+			s.mu.blocks[idx].pinnedUntil = max(s.mu.blocks[idx].pinnedUntil, pinUntil)
 			s.mu.Unlock()
 			n += s.bm.BlockSize()
 			continue
@@ -667,9 +754,10 @@ func (s *shard) set(fileNum base.DiskFileNum, p []byte, ofs int64) error {
 				panic(errors.AssertionFailedf("both LRU and free lists empty"))
 			}
 
-			// Find the last element in the LRU list which is not locked.
+			// Find the last element in the LRU list which is not locked or pinned.
+			now := s.cache.now().UnixNano()
 			for idx := s.lruPrev(s.mu.lruHead); ; idx = s.lruPrev(idx) {
-				if lock := s.mu.blocks[idx].lock; lock == unlocked {
+				if b := &s.mu.blocks[idx]; b.lock == unlocked && b.pinnedUntil <= now {
 					cacheBlockIdx = idx
 					break
 				}
@@ -695,6 +783,7 @@ func (s *shard) set(fileNum base.DiskFileNum, p []byte, ofs int64) error {
 		s.mu.where[k] = cacheBlockIdx
 		s.mu.blocks[cacheBlockIdx].logical = k
 		s.mu.blocks[cacheBlockIdx].lock = writeLockTaken
+		s.mu.blocks[cacheBlockIdx].pinnedUntil = pinUntil
 		s.mu.Unlock()
 
 		writeAt := s.bm.BlockOffset(cacheBlockIdx)
@@ -712,6 +801,7 @@ func (s *shard) set(fileNum base.DiskFileNum, p []byte, ofs int64) error {
 			s.mu.Lock()
 			delete(s.mu.where, k)
 			s.lruUnlink(cacheBlockIdx)
+			s.mu.blocks[cacheBlockIdx].pinnedUntil = 0
 			s.freePush(cacheBlockIdx)
 			s.mu.Unlock()
 			return err
@@ -721,6 +811,30 @@ func (s *shard) set(fileNum base.DiskFileNum, p []byte, ofs int64) error {
 	}
 }
 
+// pin pins the cache block containing the given offset of the object until
+// pinUntil (in Unix nanoseconds), if the block is present in the shard; it also
+// moves the block to the front of the LRU list. Returns false if the block is
+// not present.
+func (s *shard) pin(fileNum base.DiskFileNum, ofs int64, pinUntil int64) bool {
+	k := logicalBlockID{
+		filenum:       fileNum,
+		cacheBlockIdx: s.bm.Block(ofs),
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cacheBlockIdx, ok := s.mu.where[k]
+	if !ok || s.mu.blocks[cacheBlockIdx].lock == writeLockTaken {
+		// The block is missing or still being populated (in which case the
+		// write could fail).
+		return false
+	}
+	b := &s.mu.blocks[cacheBlockIdx]
+	b.pinnedUntil = max(b.pinnedUntil, pinUntil)
+	s.lruUnlink(cacheBlockIdx)
+	s.lruInsertFront(cacheBlockIdx)
+	return true
+}
+
 // Doesn't inline currently. This might be okay, but something to keep in mind.
 func (s *shard) dropReadLock(cacheBlockInd cacheBlockIndex) {
 	s.mu.Lock()
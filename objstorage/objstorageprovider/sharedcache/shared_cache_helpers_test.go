@@ -1,7 +1,13 @@
 package sharedcache
 
+import "time"
+
 func (c *Cache) WaitForWritesToComplete() {
 	close(c.writeWorkers.tasksCh)
 	c.writeWorkers.doneWaitGroup.Wait()
 	c.writeWorkers.Start(c, c.writeWorkers.numWorkers)
 }
+
+func (c *Cache) SetNowForTesting(now func() time.Time) {
+	c.now = now
+}
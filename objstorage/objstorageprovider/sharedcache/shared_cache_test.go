@@ -40,6 +40,7 @@ func TestSharedCache(t *testing.T) {
 		}()
 
 		var objData []byte
+		now := time.Unix(0, 0)
 		datadriven.RunTest(t, path, func(t *testing.T, d *datadriven.TestData) string {
 			log.Reset()
 			switch d.Cmd {
@@ -57,6 +58,7 @@ func TestSharedCache(t *testing.T) {
 					fs, base.DefaultLogger, "", blockSize, int64(shardingBlockSize), int64(size), numShards,
 				)
 				require.NoError(t, err)
+				cache.SetNowForTesting(func() time.Time { return now })
 				return fmt.Sprintf("initialized with block-size=%d size=%d num-shards=%d", blockSize, size, numShards)
 
 			case "write":
@@ -107,6 +109,33 @@ func TestSharedCache(t *testing.T) {
 				// doesn't trace calls to ReadAt or WriteAt. We should consider changing this.
 				missesAfter := cache.Metrics().ReadsWithPartialHit + cache.Metrics().ReadsWithNoHit
 				return fmt.Sprintf("misses=%d", missesAfter-missesBefore)
+
+			case "prefetch":
+				offset := mustParseBytesArg(t, d, "offset")
+				size := mustParseBytesArg(t, d, "size")
+				var pinUntil time.Time
+				if d.HasArg("pin-for") {
+					var pinFor time.Duration
+					d.ScanArgs(t, "pin-for", &pinFor)
+					pinUntil = now.Add(pinFor)
+				}
+
+				readable, err := provider.OpenForReading(ctx, base.FileTypeTable, base.DiskFileNum(1), objstorage.OpenOptions{})
+				require.NoError(t, err)
+				defer readable.Close()
+
+				fetched, err := cache.Prefetch(ctx, base.DiskFileNum(1), readable, readable.Size(), int64(offset), int64(size), pinUntil)
+				if err != nil {
+					return fmt.Sprintf("fetched=%d error: %v", fetched, err)
+				}
+				return fmt.Sprintf("fetched=%d", fetched)
+
+			case "advance-time":
+				var dur time.Duration
+				d.ScanArgs(t, "dur", &dur)
+				now = now.Add(dur)
+				return ""
+
 			default:
 				d.Fatalf(t, "unknown command %s", d.Cmd)
 				return ""
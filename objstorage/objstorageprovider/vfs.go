@@ -19,6 +19,8 @@ import (
 type localSubsystem struct {
 	fsDir vfs.File
 
+	directIO directIOCounters
+
 	coldTier struct {
 		fsDir vfs.File
 	}
@@ -138,6 +140,9 @@ func (p *provider) localOpenForReading(
 	if err != nil {
 		return nil, err
 	}
+	if p.st.Local.DirectIOForCompactions && tier == base.HotTier {
+		r.directIO = &p.local.directIO
+	}
 	if tier == base.ColdTier {
 		if startOffset, ok := p.getColdObjectMetaFile(fileType, fileNum); ok {
 			metaPath := p.metaPath(fileType, fileNum, startOffset)
@@ -153,6 +158,7 @@ func (p *provider) vfsCreate(
 	fileNum base.DiskFileNum,
 	tier base.StorageTier,
 	category vfs.DiskWriteCategory,
+	directIO objstorage.DirectIOBufferAllocator,
 ) (objstorage.Writable, objstorage.ObjectMetadata, error) {
 	if tier == base.ColdTier && fileType != base.FileTypeBlob {
 		return nil, objstorage.ObjectMetadata{}, errors.Errorf("cold tier not supported for file type %s", fileType)
@@ -163,15 +169,26 @@ func (p *provider) vfsCreate(
 	if err != nil {
 		return nil, objstorage.ObjectMetadata{}, err
 	}
-	file = vfs.NewSyncingFile(file, vfs.SyncingFileOptions{
-		NoSyncOnClose: p.st.Local.NoSyncOnClose,
-		BytesPerSync:  p.st.Local.BytesPerSync,
-	})
 	meta := objstorage.ObjectMetadata{
 		DiskFileNum: fileNum,
 		FileType:    fileType,
 	}
 	meta.Local.Tier = tier
+	useDirectIO := false
+	if directIO != nil && tier == base.HotTier {
+		if err := vfs.SetDirectIO(file, true); err == nil {
+			useDirectIO = true
+		} else {
+			p.local.directIO.fallbacks.Add(1)
+		}
+	}
+	file = vfs.NewSyncingFile(file, vfs.SyncingFileOptions{
+		NoSyncOnClose: p.st.Local.NoSyncOnClose,
+		BytesPerSync:  p.st.Local.BytesPerSync,
+	})
+	if useDirectIO {
+		return newDirectWritable(file, &p.local.directIO, directIO), meta, nil
+	}
 	w := objstorage.Writable(newFileBufferedWritable(file))
 	if tier == base.ColdTier {
 		w = newColdWritable(p, fileType, fileNum, w, category)
@@ -28,6 +28,9 @@ type fileReadable struct {
 	size int64
 
 	readaheadConfig *ReadaheadConfig
+	// directIO is set if read handles set up for compactions should use direct
+	// I/O.
+	directIO *directIOCounters
 
 	// The following fields are used to possibly open the file again using the
 	// sequential reads option (see vfsReadHandle).
@@ -102,6 +105,10 @@ type vfsReadHandle struct {
 	// OS-level readahead. Once this is non-nil, the other variables in
 	// readaheadState don't matter much as we defer to OS-level readahead.
 	sequentialFile vfs.File
+
+	// direct is set when the handle was set up for a compaction and reads
+	// with direct I/O.
+	direct *directReader
 }
 
 var _ objstorage.ReadHandle = (*vfsReadHandle)(nil)
@@ -131,17 +138,28 @@ func (rh *vfsReadHandle) init(r *fileReadable) {
 
 // Close is part of the objstorage.ReadHandle interface.
 func (rh *vfsReadHandle) Close() error {
+	err := rh.closeFiles()
+	*rh = vfsReadHandle{}
+	readHandlePool.Put(rh)
+	return err
+}
+
+func (rh *vfsReadHandle) closeFiles() error {
 	var err error
 	if rh.sequentialFile != nil {
 		err = rh.sequentialFile.Close()
 	}
-	*rh = vfsReadHandle{}
-	readHandlePool.Put(rh)
+	if rh.direct != nil {
+		err = firstError(err, rh.direct.Close())
+	}
 	return err
 }
 
 // ReadAt is part of the objstorage.ReadHandle interface.
 func (rh *vfsReadHandle) ReadAt(_ context.Context, p []byte, offset int64) error {
+	if rh.direct != nil {
+		return rh.direct.ReadAt(p, offset)
+	}
 	if rh.sequentialFile != nil {
 		// Use OS-level read-ahead.
 		n, err := rh.sequentialFile.ReadAt(p, offset)
@@ -169,7 +187,14 @@ func (rh *vfsReadHandle) ReadAt(_ context.Context, p []byte, offset int64) error
 }
 
 // SetupForCompaction is part of the objstorage.ReadHandle interface.
-func (rh *vfsReadHandle) SetupForCompaction() {
+func (rh *vfsReadHandle) SetupForCompaction(directIOBufs objstorage.DirectIOBufferAllocator) {
+	if rh.r.directIO != nil && directIOBufs != nil && rh.direct == nil {
+		// Compactions read their inputs once; bypass the page cache so they
+		// don't evict pages needed by foreground reads.
+		if rh.direct = openDirectReader(rh.r, directIOBufs); rh.direct != nil {
+			return
+		}
+	}
 	rh.readaheadMode = rh.r.readaheadConfig.Informed()
 	if rh.readaheadMode == FadviseSequential {
 		rh.switchToOSReadahead()
@@ -194,8 +219,8 @@ func (rh *vfsReadHandle) switchToOSReadahead() {
 
 // RecordCacheHit is part of the objstorage.ReadHandle interface.
 func (rh *vfsReadHandle) RecordCacheHit(_ context.Context, offset, size int64) {
-	if rh.sequentialFile != nil || rh.readaheadMode == NoReadahead {
-		// Using OS-level or no readahead, so do nothing.
+	if rh.sequentialFile != nil || rh.direct != nil || rh.readaheadMode == NoReadahead {
+		// Using OS-level, direct I/O or no readahead, so do nothing.
 		return
 	}
 	rh.rs.recordCacheHit(offset, size)
@@ -230,10 +255,7 @@ type PreallocatedReadHandle struct {
 
 // Close is part of the objstorage.ReadHandle interface.
 func (rh *PreallocatedReadHandle) Close() error {
-	var err error
-	if rh.sequentialFile != nil {
-		err = rh.sequentialFile.Close()
-	}
+	err := rh.closeFiles()
 	rh.vfsReadHandle = vfsReadHandle{}
 	return err
 }
@@ -9,6 +9,7 @@ import (
 	"io"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/errors/oserror"
@@ -33,7 +34,7 @@ type localFSStore struct {
 	vfs     vfs.FS
 }
 
-var _ Storage = (*localFSStore)(nil)
+var _ StorageWithModTime = (*localFSStore)(nil)
 
 // Close is part of the remote.Storage interface.
 func (s *localFSStore) Close() error {
@@ -157,6 +158,15 @@ func (s *localFSStore) Size(objName string) (int64, error) {
 	return stat.Size(), nil
 }
 
+// ModTime is part of the remote.StorageWithModTime interface.
+func (s *localFSStore) ModTime(objName string) (time.Time, error) {
+	stat, err := s.vfs.Stat(path.Join(s.dirname, objName))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return stat.ModTime(), nil
+}
+
 // IsNotExistError is part of the remote.Storage interface.
 func (s *localFSStore) IsNotExistError(err error) bool {
 	return oserror.IsNotExist(err)
@@ -10,6 +10,7 @@ import (
 	"io"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/cockroachdb/errors"
 )
@@ -31,11 +32,12 @@ type inMemStore struct {
 	}
 }
 
-var _ Storage = (*inMemStore)(nil)
+var _ StorageWithModTime = (*inMemStore)(nil)
 
 type inMemObj struct {
-	name string
-	data []byte
+	name    string
+	data    []byte
+	modTime time.Time
 }
 
 func (s *inMemStore) Close() error {
@@ -104,8 +106,9 @@ func (o *inMemWriter) Write(p []byte) (n int, err error) {
 func (o *inMemWriter) Close() error {
 	if o.store != nil {
 		o.store.addObj(&inMemObj{
-			name: o.name,
-			data: o.buf.Bytes(),
+			name:    o.name,
+			data:    o.buf.Bytes(),
+			modTime: time.Now(),
 		})
 		o.store = nil
 	}
@@ -142,6 +145,14 @@ func (s *inMemStore) Size(objName string) (int64, error) {
 	return int64(len(obj.data)), nil
 }
 
+func (s *inMemStore) ModTime(objName string) (time.Time, error) {
+	obj, err := s.getObj(objName)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return obj.modTime, nil
+}
+
 func (s *inMemStore) IsNotExistError(err error) bool {
 	return errors.Is(err, inMemStoreNotExistErr)
 }
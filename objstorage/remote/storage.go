@@ -7,6 +7,7 @@ package remote
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/redact"
@@ -132,6 +133,18 @@ type Storage interface {
 	IsNotExistError(err error) bool
 }
 
+// StorageWithModTime is an optional interface that can be implemented by
+// Storage implementations which are able to report when an object was last
+// written. It is used by remote object garbage collection to avoid deleting
+// objects that were created very recently and may not yet be recorded in a
+// catalog.
+type StorageWithModTime interface {
+	Storage
+
+	// ModTime returns the time at which the named object was last written.
+	ModTime(objName string) (time.Time, error)
+}
+
 // ObjectReader is used to perform reads on an object.
 type ObjectReader interface {
 	// ReadAt reads len(p) bytes into p starting at offset off.
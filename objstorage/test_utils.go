@@ -84,6 +84,6 @@ func (h *memObjReadHandle) ReadAt(ctx context.Context, p []byte, off int64) erro
 
 func (h *memObjReadHandle) Close() error { return nil }
 
-func (h *memObjReadHandle) SetupForCompaction() {}
+func (h *memObjReadHandle) SetupForCompaction(DirectIOBufferAllocator) {}
 
 func (h *memObjReadHandle) RecordCacheHit(ctx context.Context, offset, size int64) {}
@@ -251,6 +251,10 @@ func (d *DB) deleteObsoleteFiles(jobID JobID) {
 		return
 	}
 	_, noRecycle := d.opts.Cleaner.(base.NeedsFileContents)
+	if _, archiving := d.opts.Cleaner.(*wal.Archiver); archiving {
+		// A recycled WAL would never be archived.
+		noRecycle = true
+	}
 
 	// NB: d.mu.versions.minUnflushedLogNum is the log number of the earliest
 	// log that has not had its contents flushed to an sstable.
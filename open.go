@@ -11,6 +11,7 @@ import (
 	"io"
 	"math"
 	"os"
+	"slices"
 	"sync"
 	"sync/atomic"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/cockroachdb/pebble/internal/tombspan"
 	"github.com/cockroachdb/pebble/objstorage"
 	"github.com/cockroachdb/pebble/objstorage/remote"
+	"github.com/cockroachdb/pebble/record"
 	"github.com/cockroachdb/pebble/vfs"
 	"github.com/cockroachdb/pebble/wal"
 	"github.com/prometheus/client_golang/prometheus"
@@ -78,6 +80,9 @@ func Open(dirname string, opts *Options) (db *DB, err error) {
 	if err := opts.Validate(); err != nil {
 		return nil, err
 	}
+	if opts.RateLimiter != nil {
+		opts.FS = vfs.WithRateLimiting(opts.FS, opts.RateLimiter)
+	}
 	if opts.LoggerAndTracer == nil {
 		opts.LoggerAndTracer = &base.LoggerWithNoopTracer{Logger: opts.Logger}
 	} else {
@@ -160,6 +165,7 @@ func Open(dirname string, opts *Options) (db *DB, err error) {
 		largeBatchThreshold: (opts.MemTableSize - uint64(memTableEmptySize)) / 2,
 		dirs:                rs.dirs,
 		objProvider:         rs.objProvider,
+		replica:             rs.replica,
 		closed:              new(atomic.Value),
 		closedCh:            make(chan struct{}),
 		bgCtx:               ctx,
@@ -292,18 +298,22 @@ func Open(dirname string, opts *Options) (db *DB, err error) {
 	d.mu.log.metrics = WALMetrics{}
 
 	walOpts := wal.Options{
-		Primary:              rs.dirs.WALPrimary,
-		Secondary:            rs.dirs.WALSecondary,
-		MinUnflushedWALNum:   wal.NumWAL(d.mu.versions.minUnflushedLogNum),
-		MaxNumRecyclableLogs: opts.MemTableStopWritesThreshold + 1,
-		NoSyncOnClose:        opts.NoSyncOnClose,
-		BytesPerSync:         opts.WALBytesPerSync,
-		PreallocateSize:      d.walPreallocateSize,
-		MinSyncInterval:      opts.WALMinSyncInterval,
-		QueueSemChan:         d.commit.logSyncQSem,
-		Logger:               opts.Logger,
-		EventListener:        walEventListenerAdaptor{l: opts.EventListener},
-		WriteWALSyncOffsets:  func() bool { return d.FormatMajorVersion() >= FormatWALSyncChunks },
+		Primary:               rs.dirs.WALPrimary,
+		Secondary:             rs.dirs.WALSecondary,
+		AdditionalSecondaries: slices.Clone(rs.dirs.WALAdditionalSecondaries),
+		MinUnflushedWALNum:    wal.NumWAL(d.mu.versions.minUnflushedLogNum),
+		MaxNumRecyclableLogs:  opts.MemTableStopWritesThreshold + 1,
+		NoSyncOnClose:         opts.NoSyncOnClose,
+		BytesPerSync:          opts.WALBytesPerSync,
+		PreallocateSize:       d.walPreallocateSize,
+		MinSyncInterval:       opts.WALMinSyncInterval,
+		QueueSemChan:          d.commit.logSyncQSem,
+		Logger:                opts.Logger,
+		EventListener:         walEventListenerAdaptor{l: opts.EventListener},
+		WriteWALSyncOffsets:   func() bool { return d.FormatMajorVersion() >= FormatWALSyncChunks },
+	}
+	if !opts.ReadOnly {
+		walOpts.External = opts.ExternalWAL
 	}
 
 	// Create and assign WAL file operation histograms
@@ -340,6 +350,34 @@ func Open(dirname string, opts *Options) (db *DB, err error) {
 		}
 		walOpts.Secondary = walDir
 		opts.WALFailover.Secondary.ID = walDir.ID
+
+		for i := range walOpts.AdditionalSecondaries {
+			dir := &walOpts.AdditionalSecondaries[i]
+			optsID := opts.WALFailover.AdditionalSecondaries[i].ID
+			if dir.ID == "" {
+				dir.ID = optsID
+			} else if optsID != "" && dir.ID != optsID {
+				return nil, errors.Errorf("WAL failover additional secondary identifier mismatch: OPTIONS file has %q but %q was provided - wrong disk may be mounted",
+					dir.ID, optsID)
+			}
+			walDir, err := wal.ValidateOrInitWALDir(*dir)
+			if err != nil {
+				return nil, err
+			}
+			*dir = walDir
+			opts.WALFailover.AdditionalSecondaries[i].ID = walDir.ID
+		}
+	}
+	// Configure replication. The replica's file operations are recorded in the
+	// secondary histogram.
+	if !opts.ReadOnly && opts.WALReplication != nil {
+		walOpts.Replica = rs.dirs.WALReplica
+		walOpts.ReplicationQuorum = opts.WALReplication.Quorum
+		walReplicaFileOpHistogram := prometheus.NewHistogram(prometheus.HistogramOpts{
+			Buckets: FsyncLatencyBuckets,
+		})
+		d.mu.log.metrics.SecondaryFileOpLatency = walReplicaFileOpHistogram
+		walOpts.SecondaryFileOpHistogram = walReplicaFileOpHistogram
 	}
 	walManager, err := wal.Init(walOpts, rs.walsReplay)
 	if err != nil {
@@ -369,11 +407,21 @@ func Open(dirname string, opts *Options) (db *DB, err error) {
 
 	d.tableDiskUsageAnnotator = d.makeTableDiskSpaceUsageAnnotator()
 	d.blobFileDiskUsageAnnotator = d.makeBlobFileDiskSpaceUsageAnnotator()
+	d.blobFileTierAnnotator = d.makeBlobFileTierAnnotator()
 
 	d.mu.versions.markFileNumUsed(rs.maxFilenumUsed)
 
-	// Replay any newer log files than the ones named in the manifest.
+	// Replay any newer log files than the ones named in the manifest, up to the
+	// recovery target if one is set.
+	var stopInfo *WALRecoveryStopInfo
+	if opts.WALRecoveryTarget != nil {
+		stopInfo = &WALRecoveryStopInfo{
+			JobID:        int(jobID),
+			TargetSeqNum: opts.WALRecoveryTarget.SeqNum,
+		}
+	}
 	var flushableIngests []*ingestedFlushable
+	numReplayed := 0
 	for i, w := range rs.walsReplay {
 		// WALs other than the last one would have been closed cleanly.
 		//
@@ -382,7 +430,7 @@ func Open(dirname string, opts *Options) (db *DB, err error) {
 		// 20.1 do not guarantee that closed WALs end cleanly. But the earliest
 		// compatible Pebble format is newer and guarantees a clean EOF.
 		strictWALTail := i < len(rs.walsReplay)-1
-		fi, maxSeqNum, err := d.replayWAL(jobID, w, strictWALTail)
+		fi, maxSeqNum, err := d.replayWAL(jobID, w, strictWALTail, stopInfo)
 		if err != nil {
 			return nil, err
 		}
@@ -392,6 +440,20 @@ func Open(dirname string, opts *Options) (db *DB, err error) {
 		if d.mu.versions.logSeqNum.Load() < maxSeqNum {
 			d.mu.versions.logSeqNum.Store(maxSeqNum)
 		}
+		numReplayed++
+		if stopInfo != nil && stopInfo.Stopped {
+			break
+		}
+	}
+	if stopInfo != nil {
+		// The WALs that were not fully replayed are archived before the flush
+		// below makes them obsolete.
+		if stopInfo.Stopped && !opts.ReadOnly {
+			if err := d.archiveUnreplayedWALs(rs, rs.walsReplay[numReplayed-1:], stopInfo); err != nil {
+				return nil, err
+			}
+		}
+		opts.EventListener.WALRecoveryStopped(*stopInfo)
 	}
 	if d.mu.mem.mutable == nil {
 		// Recreate the mutable memtable if replayWAL got rid of it.
@@ -500,6 +562,10 @@ func Open(dirname string, opts *Options) (db *DB, err error) {
 		return nil, err
 	}
 
+	if d.replica != nil {
+		d.replica.startRefreshLoop(d)
+	}
+
 	// Note: this is a no-op if invariants are disabled or race is enabled.
 	//
 	// Setting a finalizer on *DB causes *DB to never be reclaimed and the
@@ -528,6 +594,38 @@ func Open(dirname string, opts *Options) (db *DB, err error) {
 	return d, nil
 }
 
+// archiveUnreplayedWALs copies the WALs that were not fully replayed because
+// of Options.WALRecoveryTarget into its ArchiveDir, recording them in
+// stopInfo.
+func (d *DB) archiveUnreplayedWALs(
+	rs *recoveredState, wals wal.Logs, stopInfo *WALRecoveryStopInfo,
+) error {
+	archiveDir := resolveStorePath(d.dirname, d.opts.WALRecoveryTarget.ArchiveDir)
+	for _, dir := range rs.dirs.WALDirs() {
+		if dir.Dirname == archiveDir {
+			return errors.Errorf("pebble: WAL recovery archive directory %q may contain WALs", archiveDir)
+		}
+	}
+	f, err := mkdirAllAndSyncParents(d.opts.FS, archiveDir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	for _, ll := range wals {
+		// Copy every batch, including the ones that were replayed, so that an
+		// archived WAL can be replayed by itself.
+		err := wal.Copy(d.opts.FS, archiveDir, ll, base.SeqNumMax, record.LogWriterConfig{
+			WriteWALSyncOffsets: func() bool { return d.FormatMajorVersion() >= FormatWALSyncChunks },
+		})
+		if err != nil {
+			return errors.Wrapf(err, "archiving WAL %s", ll.Num)
+		}
+		stopInfo.ArchivedWALs = append(stopInfo.ArchivedWALs, base.DiskFileNum(ll.Num))
+	}
+	stopInfo.ArchiveDir = archiveDir
+	return f.Sync()
+}
+
 // resolvedDirs is a set of resolved directory paths and locks.
 type resolvedDirs struct {
 	DirLocks         base.DirLockSet
@@ -535,16 +633,22 @@ type resolvedDirs struct {
 	DataDir          vfs.File
 	WALPrimary       wal.Dir
 	WALSecondary     wal.Dir
-	WALRecovery      []wal.Dir
+	// WALAdditionalSecondaries parallels
+	// Options.WALFailover.AdditionalSecondaries.
+	WALAdditionalSecondaries []wal.Dir
+	// WALReplica parallels Options.WALReplication.Replica.
+	WALReplica  wal.Dir
+	WALRecovery []wal.Dir
 }
 
 // WALDirs returns the set of resolved directories that may contain WAL files
-// relevant to recovery.
+// relevant to recovery, other than the WALReplica.
 func (d *resolvedDirs) WALDirs() []wal.Dir {
 	dirs := []wal.Dir{d.WALPrimary}
 	if d.WALSecondary.Dirname != "" {
 		dirs = append(dirs, d.WALSecondary)
 	}
+	dirs = append(dirs, d.WALAdditionalSecondaries...)
 	dirs = append(dirs, d.WALRecovery...)
 	return dirs
 }
@@ -578,10 +682,36 @@ func prepareOpenAndLockDirs(dirname string, opts *Options) (dirs *resolvedDirs,
 	if opts.WALFailover != nil {
 		dirs.WALSecondary.Dirname = resolveStorePath(dirname, opts.WALFailover.Secondary.Dirname)
 		dirs.WALSecondary.FS = opts.WALFailover.Secondary.FS
+		for _, d := range opts.WALFailover.AdditionalSecondaries {
+			dirs.WALAdditionalSecondaries = append(dirs.WALAdditionalSecondaries, wal.Dir{
+				FS:      d.FS,
+				Dirname: resolveStorePath(dirname, d.Dirname),
+			})
+		}
+	}
+	if opts.WALReplication != nil {
+		dirs.WALReplica.Dirname = resolveStorePath(dirname, opts.WALReplication.Replica.Dirname)
+		dirs.WALReplica.FS = opts.WALReplication.Replica.FS
+		// The copies of a WAL have the same file name, so they must be in
+		// distinct directories.
+		if dirs.WALReplica.Dirname == dirs.WALPrimary.Dirname {
+			return dirs, errors.Errorf("pebble: WAL replica directory %q must differ from the WAL directory",
+				dirs.WALReplica.Dirname)
+		}
+	}
+	if a, ok := opts.Cleaner.(*wal.Archiver); ok && a.Opts().Dir.FS != nil {
+		// Archived WALs must not be mistaken for live WALs.
+		for _, d := range dirs.WALDirs() {
+			if d.Dirname == a.Opts().Dir.Dirname {
+				return dirs, errors.Errorf("pebble: WAL archive directory %q must differ from the WAL directories",
+					d.Dirname)
+			}
+		}
 	}
 
-	// Create directories if needed.
-	if !opts.ReadOnly {
+	// Create directories if needed. A remote replica is read-only but it keeps
+	// its remote object catalog and secondary cache in its directory.
+	if !opts.ReadOnly || opts.RemoteReplica != nil {
 		f, err := mkdirAllAndSyncParents(opts.FS, dirname)
 		if err != nil {
 			return dirs, err
@@ -600,6 +730,20 @@ func prepareOpenAndLockDirs(dirname string, opts *Options) (dirs *resolvedDirs,
 				return dirs, err
 			}
 			f.Close()
+			for _, d := range dirs.WALAdditionalSecondaries {
+				f, err := mkdirAllAndSyncParents(d.FS, d.Dirname)
+				if err != nil {
+					return dirs, err
+				}
+				f.Close()
+			}
+		}
+		if opts.WALReplication != nil {
+			f, err := mkdirAllAndSyncParents(dirs.WALReplica.FS, dirs.WALReplica.Dirname)
+			if err != nil {
+				return dirs, err
+			}
+			f.Close()
 		}
 	}
 
@@ -640,6 +784,26 @@ func prepareOpenAndLockDirs(dirname string, opts *Options) (dirs *resolvedDirs,
 			return dirs, err
 		}
 	}
+	// Lock the additional secondary WAL directories, with the same exceptions.
+	for i := range dirs.WALAdditionalSecondaries {
+		d := &dirs.WALAdditionalSecondaries[i]
+		if d.Dirname == dirname || d.Dirname == dirs.WALPrimary.Dirname {
+			continue
+		}
+		d.Lock, err = dirs.DirLocks.AcquireOrValidate(
+			opts.WALFailover.AdditionalSecondaries[i].Lock, d.Dirname, d.FS)
+		if err != nil {
+			return dirs, err
+		}
+	}
+	// Lock the WAL replica directory, if distinct from the data directory.
+	if opts.WALReplication != nil && dirs.WALReplica.Dirname != dirname {
+		dirs.WALReplica.Lock, err = dirs.DirLocks.AcquireOrValidate(
+			opts.WALReplication.Replica.Lock, dirs.WALReplica.Dirname, dirs.WALReplica.FS)
+		if err != nil {
+			return dirs, err
+		}
+	}
 
 	// Resolve path names and acquire locks for the WAL recovery directories.
 	for _, dir := range opts.WALRecoveryDirs {
@@ -292,6 +292,13 @@ func TestOpen_WALFailover(t *testing.T) {
 					o.WALFailover = &WALFailoverOptions{
 						Secondary: wal.Dir{FS: fs, Dirname: dir},
 					}
+				case "additional-secondary":
+					if o.WALFailover == nil {
+						td.Fatalf(t, "additional-secondary requires secondary")
+					}
+					fs, dir := extractFSAndPath(cmdArg)
+					o.WALFailover.AdditionalSecondaries = append(o.WALFailover.AdditionalSecondaries,
+						wal.Dir{FS: fs, Dirname: dir})
 				case "wal-recovery-dir":
 					fs, dir := extractFSAndPath(cmdArg)
 					o.WALRecoveryDirs = append(o.WALRecoveryDirs, wal.Dir{FS: fs, Dirname: dir})
@@ -334,6 +341,267 @@ func TestOpen_WALFailover(t *testing.T) {
 	})
 }
 
+// TestOpen_WALReplication tests that a database configured with a WAL replica
+// writes its WALs to both directories, and recovers from either copy.
+func TestOpen_WALReplication(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	fs := vfs.NewMem()
+	newOpts := func() *Options {
+		return &Options{
+			FS:     fs,
+			Logger: testutils.Logger{T: t},
+			WALReplication: &WALReplicationOptions{
+				Replica: wal.Dir{FS: fs, Dirname: "replica"},
+			},
+		}
+	}
+	d, err := Open("db", newOpts())
+	require.NoError(t, err)
+	require.NoError(t, d.Set([]byte("a"), []byte("1"), Sync))
+	require.NoError(t, d.Set([]byte("b"), []byte("2"), Sync))
+	require.NoError(t, d.Close())
+
+	// Both directories contain the same WALs. Remove the primary's copies, so
+	// that recovery must read the replica's copies.
+	primaryLogs, err := fs.List("db")
+	require.NoError(t, err)
+	replicaLogs, err := fs.List("replica")
+	require.NoError(t, err)
+	var numLogs int
+	for _, name := range primaryLogs {
+		if _, _, ok := wal.ParseLogFilename(name); ok {
+			require.Contains(t, replicaLogs, name)
+			require.NoError(t, fs.Remove(fs.PathJoin("db", name)))
+			numLogs++
+		}
+	}
+	require.Greater(t, numLogs, 0)
+
+	d, err = Open("db", newOpts())
+	require.NoError(t, err)
+	for _, k := range []string{"a", "b"} {
+		_, closer, err := d.Get([]byte(k))
+		require.NoError(t, err)
+		require.NoError(t, closer.Close())
+	}
+	require.NoError(t, d.Close())
+
+	// Opening without the replica directory fails, since it may contain WALs
+	// needed for recovery.
+	_, err = Open("db", &Options{FS: fs, Logger: testutils.Logger{T: t}})
+	var missingWALRecoveryDirErr ErrMissingWALRecoveryDir
+	require.True(t, errors.As(err, &missingWALRecoveryDirErr), "%v", err)
+	require.Equal(t, "replica", missingWALRecoveryDirErr.Dir)
+
+	// The replica must be in a separate directory.
+	opts := newOpts()
+	opts.WALReplication.Replica.Dirname = "db"
+	_, err = Open("db", opts)
+	require.Error(t, err)
+}
+
+// TestOpen_WALArchive tests that a database configured with a wal.Archiver as
+// its Cleaner archives its obsolete WALs instead of recycling them.
+func TestOpen_WALArchive(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	fs := vfs.NewMem()
+	archiver, err := wal.NewArchiver(wal.ArchiverOptions{
+		Dir:    wal.Dir{FS: fs, Dirname: "archive"},
+		Logger: testutils.Logger{T: t},
+	})
+	require.NoError(t, err)
+	opts := &Options{
+		FS:      fs,
+		Logger:  testutils.Logger{T: t},
+		Cleaner: archiver,
+	}
+	opts.private.testingAlwaysWaitForCleanup = true
+	d, err := Open("db", opts)
+	require.NoError(t, err)
+	require.NoError(t, d.Set([]byte("a"), []byte("1"), Sync))
+	require.NoError(t, d.Flush())
+	require.NoError(t, d.Set([]byte("b"), []byte("2"), Sync))
+	require.NoError(t, d.Flush())
+
+	// The obsolete WALs are archived instead of being recycled.
+	require.Equal(t, int64(0), d.Metrics().WAL.ObsoleteFiles)
+	require.NotEmpty(t, archiver.List())
+	require.NoError(t, d.Close())
+
+	// The archived WALs hold the writes.
+	logs, err := wal.Scan(wal.Dir{FS: fs, Dirname: "archive"})
+	require.NoError(t, err)
+	var keys []string
+	for _, ll := range logs {
+		r := ll.OpenForRead()
+		for {
+			rr, _, err := r.NextRecord()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			repr, err := io.ReadAll(rr)
+			require.NoError(t, err)
+			var b Batch
+			require.NoError(t, b.SetRepr(repr))
+			iter := b.Reader()
+			for {
+				_, ukey, _, ok, err := iter.Next()
+				require.NoError(t, err)
+				if !ok {
+					break
+				}
+				keys = append(keys, string(ukey))
+			}
+		}
+		require.NoError(t, r.Close())
+	}
+	require.Equal(t, []string{"a", "b"}, keys)
+
+	// The archive cannot be in a WAL directory.
+	opts = &Options{FS: fs, Logger: testutils.Logger{T: t}, Cleaner: archiver, WALDir: "archive"}
+	_, err = Open("db", opts)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "WAL archive directory")
+}
+
+// TestOpen_ExternalWAL tests that a database configured with an ExternalWAL
+// writes its WALs to it instead of log files, and recovers from it.
+func TestOpen_ExternalWAL(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	fs := vfs.NewMem()
+	el := wal.NewInProcessLog()
+	newOpts := func() *Options {
+		return &Options{
+			FS:          fs,
+			Logger:      testutils.Logger{T: t},
+			ExternalWAL: el,
+		}
+	}
+	d, err := Open("db", newOpts())
+	require.NoError(t, err)
+	require.NoError(t, d.Set([]byte("a"), []byte("1"), Sync))
+	require.NoError(t, d.Set([]byte("b"), []byte("2"), NoSync))
+	require.NoError(t, d.Set([]byte("c"), []byte("3"), Sync))
+
+	// A checkpoint copies the external WALs into log files, and can be opened
+	// without the ExternalWAL.
+	require.NoError(t, d.Checkpoint("checkpoint"))
+	require.NoError(t, d.Close())
+
+	ls, err := fs.List("db")
+	require.NoError(t, err)
+	for _, name := range ls {
+		_, _, ok := wal.ParseLogFilename(name)
+		require.False(t, ok, "unexpected log file %s", name)
+	}
+	nums, err := el.List()
+	require.NoError(t, err)
+	require.NotEmpty(t, nums)
+
+	checkKeys := func(d *DB) {
+		for _, k := range []string{"a", "b", "c"} {
+			_, closer, err := d.Get([]byte(k))
+			require.NoError(t, err)
+			require.NoError(t, closer.Close())
+		}
+	}
+	d, err = Open("db", newOpts())
+	require.NoError(t, err)
+	checkKeys(d)
+	require.NoError(t, d.Close())
+
+	d, err = Open("checkpoint", &Options{FS: fs, Logger: testutils.Logger{T: t}})
+	require.NoError(t, err)
+	checkKeys(d)
+	require.NoError(t, d.Close())
+
+	// Opening without the ExternalWAL fails, since it may hold WALs needed for
+	// recovery.
+	_, err = Open("db", &Options{FS: fs, Logger: testutils.Logger{T: t}})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "ExternalWAL is required")
+
+	// The ExternalWAL cannot be used together with WAL failover.
+	opts := newOpts()
+	opts.WALFailover = &WALFailoverOptions{Secondary: wal.Dir{FS: fs, Dirname: "secondary"}}
+	_, err = Open("db", opts)
+	require.Error(t, err)
+}
+
+// TestOpen_WALRecoveryTarget tests point-in-time recovery of the WALs to a
+// target sequence number.
+func TestOpen_WALRecoveryTarget(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	fs := vfs.NewMem()
+	d, err := Open("db", &Options{FS: fs, Logger: testutils.Logger{T: t}})
+	require.NoError(t, err)
+	require.NoError(t, d.Set([]byte("a"), []byte("1"), Sync))
+	require.NoError(t, d.Set([]byte("b"), []byte("2"), Sync))
+	target := d.mu.versions.logSeqNum.Load() - 1
+	// A mistaken DeleteRange, followed by another write.
+	require.NoError(t, d.DeleteRange([]byte("a"), []byte("z"), Sync))
+	require.NoError(t, d.Set([]byte("c"), []byte("3"), Sync))
+	require.NoError(t, d.Close())
+
+	var info WALRecoveryStopInfo
+	opts := &Options{
+		FS:     fs,
+		Logger: testutils.Logger{T: t},
+		EventListener: &EventListener{
+			WALRecoveryStopped: func(i WALRecoveryStopInfo) { info = i },
+		},
+		WALRecoveryTarget: &WALRecoveryTarget{SeqNum: target, ArchiveDir: "archive"},
+	}
+	d, err = Open("db", opts)
+	require.NoError(t, err)
+	require.Equal(t, target, info.LastAppliedSeqNum)
+	require.True(t, info.Stopped)
+	require.Equal(t, target+1, info.StopSeqNum)
+	require.Equal(t, []base.DiskFileNum{info.StopWAL}, info.ArchivedWALs)
+	require.Equal(t, "archive", info.ArchiveDir)
+	checkKeys := func(d *DB) {
+		for _, k := range []string{"a", "b"} {
+			_, closer, err := d.Get([]byte(k))
+			require.NoError(t, err)
+			require.NoError(t, closer.Close())
+		}
+		_, _, err := d.Get([]byte("c"))
+		require.ErrorIs(t, err, ErrNotFound)
+	}
+	checkKeys(d)
+	require.NoError(t, d.Close())
+
+	// The archived WAL contains all the batches, including the ones that were
+	// not applied.
+	logs, err := wal.Scan(wal.Dir{FS: fs, Dirname: "archive"})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	r := logs[0].OpenForRead()
+	numRecords := 0
+	for {
+		_, _, err := r.NextRecord()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		numRecords++
+	}
+	require.NoError(t, r.Close())
+	require.Equal(t, 4, numRecords)
+
+	// Opening again without the target does not replay the batches that were
+	// not applied.
+	d, err = Open("db", &Options{FS: fs, Logger: testutils.Logger{T: t}})
+	require.NoError(t, err)
+	checkKeys(d)
+	require.NoError(t, d.Close())
+}
+
 func TestOpenRecovery(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -1227,6 +1495,66 @@ func TestTwoWALReplayCorrupt(t *testing.T) {
 	require.Error(t, err, "pebble: corruption")
 }
 
+// TestCrashStatesRecovery crashes a database at points where MANIFESTs are
+// being rotated and WALs recycled, and checks that every possible post-crash
+// state (or a random sample, if there are too many) recovers all the writes
+// that were synced.
+func TestCrashStatesRecovery(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	const maxStates = 16
+	// The sampled crash states are determined by the seed, which can be
+	// overridden with --seed.
+	seed := *seed
+	if seed == 0 {
+		seed = 1
+	}
+	t.Logf("seed: %d", seed)
+	rng := rand.New(rand.NewPCG(0, seed))
+
+	fs := vfs.NewCrashableMem()
+	d, err := Open("", &Options{
+		FS:     fs,
+		Logger: testutils.Logger{T: t},
+		// Rotate the MANIFEST on every version edit.
+		MaxManifestFileSize: 1,
+	})
+	require.NoError(t, err)
+
+	key := func(i int) []byte { return []byte(fmt.Sprintf("key%05d", i)) }
+	check := func(crashFS *vfs.MemFS, n int) {
+		d, err := Open("", &Options{FS: crashFS, Logger: testutils.Logger{T: t}})
+		require.NoError(t, err)
+		for i := 0; i < n; i++ {
+			_, closer, err := d.Get(key(i))
+			require.NoError(t, err, "key %d", i)
+			require.NoError(t, closer.Close())
+		}
+		require.NoError(t, d.Close())
+	}
+
+	var n int
+	for round := 0; round < 8; round++ {
+		for j := 0; j < 50; j++ {
+			require.NoError(t, d.Set(key(n), []byte("value"), Sync))
+			n++
+		}
+		if round%2 == 0 {
+			require.NoError(t, d.Flush())
+		}
+		states := fs.CrashStates()
+		if count := states.Count(); count <= maxStates {
+			for i := uint64(0); i < count; i++ {
+				check(states.State(i), n)
+			}
+		} else {
+			for i := 0; i < maxStates; i++ {
+				check(states.Random(rng), n)
+			}
+		}
+	}
+	require.NoError(t, d.Close())
+}
+
 // TestCrashOpenCrashAfterWALCreation tests a database that exits
 // ungracefully, begins recovery, creates the new WAL but promptly exits
 // ungracefully again.
@@ -63,8 +63,9 @@ type (
 )
 
 type (
-	TableFilterPolicy  = base.TableFilterPolicy
-	TableFilterDecoder = base.TableFilterDecoder
+	TableFilterPolicy       = base.TableFilterPolicy
+	TableFilterDecoder      = base.TableFilterDecoder
+	TableRangeFilterDecoder = base.TableRangeFilterDecoder
 )
 
 var NoFilterPolicy = base.NoFilterPolicy
@@ -72,6 +73,12 @@ var NoFilterPolicy = base.NoFilterPolicy
 // KeySchema exports the colblk.KeySchema type.
 type KeySchema = colblk.KeySchema
 
+// ValueSchema exports the colblk.ValueSchema type.
+type ValueSchema = colblk.ValueSchema
+
+// ValueProjection exports the colblk.ValueProjection type.
+type ValueProjection = colblk.ValueProjection
+
 // BlockPropertyCollector exports the sstable.BlockPropertyCollector type.
 type BlockPropertyCollector = sstable.BlockPropertyCollector
 
@@ -84,6 +91,12 @@ type MaximumSuffixProperty = sstable.MaximumSuffixProperty
 // ShortAttributeExtractor exports the base.ShortAttributeExtractor type.
 type ShortAttributeExtractor = base.ShortAttributeExtractor
 
+// PointKeyPredicate exports the base.PointKeyPredicate type.
+type PointKeyPredicate = base.PointKeyPredicate
+
+// PointValueSummary exports the base.PointValueSummary type.
+type PointValueSummary = base.PointValueSummary
+
 // UserKeyPrefixBound exports the sstable.UserKeyPrefixBound type.
 type UserKeyPrefixBound = sstable.UserKeyPrefixBound
 
@@ -195,6 +208,24 @@ type IterOptions struct {
 	// that cap(PointKeyFilters) is at least len(PointKeyFilters)+1. This helps
 	// avoid allocations in Pebble internal code that mutates the slice.
 	PointKeyFilters []BlockPropertyFilter
+	// PointKeyPredicate can be used to skip point keys that don't match a
+	// predicate on the key's suffix and value within sstable iterators, before
+	// the keys reach the merging iterator. Like PointKeyFilters, it is
+	// best-effort: point keys that don't match may still be returned. See
+	// base.PointKeyPredicate for the semantics. The number of skipped keys is
+	// reported in IteratorStats.InternalStats.PointsFilteredByPredicate.
+	PointKeyPredicate PointKeyPredicate
+	// ValueProjection identifies the value columns to decode when reading
+	// values that sstables store in value columns (see Options.ValueSchema).
+	// Such values are composed from the projected fields only; the fields of
+	// the other columns are zero (see ValueSchema.Compose). Values that are not
+	// stored in value columns (e.g. values in memtables, in value blocks or in
+	// blob files, or values written without a ValueSchema) are returned in
+	// full. The zero value decodes all columns.
+	//
+	// Projection is intended for scans that only need some of the fields of
+	// each value. It must not be used if the values can be merge operands.
+	ValueProjection ValueProjection
 	// RangeKeyFilters can be usefd to avoid scanning tables and blocks in tables
 	// when iterating over range keys. The same requirements that apply to
 	// PointKeyFilters apply here too.
@@ -268,6 +299,11 @@ type IterOptions struct {
 	// files and is used to decide whether to hide obsolete points. A value of 0
 	// implies obsolete points should not be hidden.
 	snapshotForHideObsoletePoints base.SeqNum
+	// useRangeFilter is set by levelIter when opening a file whose key range
+	// contains both LowerBound and UpperBound. When set, the table's range
+	// filter (if any) is consulted, and no point iterator is returned if the
+	// filter guarantees that the table has no point keys within the bounds.
+	useRangeFilter bool
 
 	// MaximumSuffixProperty is the maximum suffix property for the iterator.
 	// This is used to perform the synthetic key optimization.
@@ -292,6 +328,22 @@ func (o *IterOptions) GetUpperBound() []byte {
 	return o.UpperBound
 }
 
+// GetPointKeyPredicate returns the PointKeyPredicate.
+func (o *IterOptions) GetPointKeyPredicate() PointKeyPredicate {
+	if o == nil {
+		return nil
+	}
+	return o.PointKeyPredicate
+}
+
+// GetValueProjection returns the ValueProjection.
+func (o *IterOptions) GetValueProjection() ValueProjection {
+	if o == nil {
+		return ValueProjection{}
+	}
+	return o.ValueProjection
+}
+
 // GetMaximumSuffixProperty returns the MaximumSuffixProperty.
 func (o *IterOptions) GetMaximumSuffixProperty() MaximumSuffixProperty {
 	if o == nil {
@@ -582,9 +634,21 @@ type Options struct {
 	// unit from the semaphore for the duration of the read.
 	LoadBlockSema *fifo.Semaphore
 
+	// RateLimiter, if set, limits the bandwidth of I/O to the local file
+	// system, per category. Writes are limited per vfs.DiskWriteCategory (e.g.
+	// compaction, flush and WAL writes) and reads of sstable and blob file
+	// blocks per read category (see IterOptions.Category), with the category
+	// names used as vfs.ReadCategory. By default, WAL writes are high priority
+	// and are not throttled by the total write limit. The limits can be
+	// adjusted while the DB is open.
+	//
+	// Experimental.
+	RateLimiter *vfs.RateLimiter
+
 	// Cleaner cleans obsolete files.
 	//
-	// The default cleaner uses the DeleteCleaner.
+	// The default cleaner uses the DeleteCleaner. A wal.Archiver archives
+	// obsolete WALs, instead of deleting or recycling them.
 	Cleaner Cleaner
 
 	// Local contains option that pertain to files stored on the local filesystem.
@@ -593,6 +657,17 @@ type Options struct {
 		// consulted whenever a read handle is initialized.
 		ReadaheadConfig *ReadaheadConfig
 
+		// DirectIOForCompactions, if true, causes compactions to read their
+		// inputs and write their outputs on the local file system with direct
+		// I/O (O_DIRECT), bypassing the OS page cache. Compaction inputs are
+		// not reread, so caching them only evicts pages needed by foreground
+		// reads. Flushes, the WAL and user reads continue to use buffered I/O.
+		// If the file system does not support direct I/O, buffered I/O is used
+		// (see Metrics.DirectIO).
+		//
+		// Experimental.
+		DirectIOForCompactions bool
+
 		// TODO(radu): move BytesPerSync, LoadBlockSema, Cleaner here.
 	}
 
@@ -783,6 +858,36 @@ type Options struct {
 	// value and stored with the key, when the value is stored elsewhere.
 	ShortAttributeExtractor ShortAttributeExtractor
 
+	// SigningKeys, if set, causes the sstables written by the DB to be signed,
+	// and is used to authenticate signed sstables when they are opened (see
+	// sstable.WriterOptions.SigningKeys). The DB only signs sstables at format
+	// major version FormatSignedTables or later; at earlier versions (and when
+	// opened read-only), the keys are only used for authentication.
+	//
+	// Opening an sstable only authenticates its footer and metaindex block;
+	// the blocks of the sstables owned by the DB are not authenticated against
+	// the signed digest (see sstable.VerifySignature). Sstables that originate
+	// elsewhere are fully verified before they are used: sstables ingested
+	// through Ingest, IngestAndExcise or IngestExternalFiles must be signed
+	// once the DB is at FormatSignedTables, and the sstables attached by a
+	// remote replica (see Options.RemoteReplica) and the external sstables
+	// downloaded by Download must always be signed. Blob files are not signed.
+	//
+	// Experimental.
+	SigningKeys sstable.SigningKeyProvider
+
+	// CompressionWorkers is the number of goroutines used to compress the
+	// data blocks of each sstable written by a flush or compaction (see
+	// sstable.WriterOptions.CompressionWorkers). If zero, data blocks are
+	// compressed by the flush or compaction goroutine. A compaction's
+	// CompactionGrantHandle can override this setting by implementing
+	// CompactionCompressionGrant, for example to use more goroutines when more
+	// CPU is granted to the compaction. Compactions use at most
+	// MaxCompactionCompressionWorkers goroutines. Must be >= 0.
+	//
+	// Experimental.
+	CompressionWorkers int
+
 	// DisableIngestAsFlushable disables lazy ingestion of sstables through
 	// a WAL write and memtable rotation. Only effectual if the format
 	// major version is at least `FormatFlushableIngest`.
@@ -814,6 +919,19 @@ type Options struct {
 	// Experimental.
 	SecondaryCacheSizeBytes int64
 
+	// RemoteReplica, if set, opens the DB as a read-only replica of another
+	// store (the primary) whose tables live entirely on shared storage. Instead
+	// of recovering a local MANIFEST and WALs, the replica reconstructs its
+	// version from the latest snapshot published by the primary (see
+	// DB.PublishReplicaSnapshot) and reads the shared tables through the
+	// secondary cache. The local directory only holds the replica's remote
+	// object catalog and the secondary cache.
+	//
+	// Requires ReadOnly and RemoteStorage to be set.
+	//
+	// Experimental.
+	RemoteReplica *RemoteReplicaOptions
+
 	// EnableDeleteOnlyCompactionExcises enables delete-only compactions to also
 	// apply delete-only compaction hints on sstables that partially overlap
 	// with it. This application happens through an excise, similar to
@@ -879,6 +997,20 @@ type Options struct {
 		// NowFn can be used to override the current time used to decide if data
 		// belongs in the cold or hot tier.
 		NowFn func() time.Time
+
+		// CreateColdOnShared, if set, causes values that are older than the
+		// AgeThreshold of the span's TieringPolicy to be migrated by compactions
+		// into blob files on shared storage (using ColdSharedLocator). Compactions
+		// into the bottommost level also split their output tables at tier
+		// boundaries and create the tables holding cold KVs on ColdSharedLocator.
+		// Requires RemoteStorage and a creator ID; until the creator ID is set,
+		// cold data remains on local storage.
+		//
+		// The tier of a shared object is determined by its locator, so
+		// ColdSharedLocator must differ from CreateOnSharedLocator when both are
+		// in use.
+		CreateColdOnShared bool
+		ColdSharedLocator  remote.Locator
 	}
 
 	// TableFilterDecoders contains the available table filter decoders.
@@ -886,6 +1018,28 @@ type Options struct {
 	// The default value is tablefilters.Decoders.
 	TableFilterDecoders []TableFilterDecoder
 
+	// TableFilterMemoryBudget, if non-zero, enables adaptive sizing of the Bloom
+	// table filters written by compactions, based on the negative lookups
+	// (filter hits and false positives) observed on the compaction's input
+	// tables while they are open. Tables in key ranges that see more negative
+	// lookups per key than the other open tables get more bits per key than the
+	// level's TableFilterPolicy target, and tables in colder ranges get fewer.
+	// The bits per key are further capped so that the total size of the
+	// filters of the open tables stays under the budget (in bytes).
+	// Filter policies other than Bloom filters are not affected.
+	//
+	// Experimental.
+	TableFilterMemoryBudget uint64
+
+	// PartitionTableFilters, if true, causes the table filters of sstables to
+	// be partitioned into one filter block per index partition, so that filter
+	// checks only need to read the relevant partitions (see
+	// sstable.WriterOptions.PartitionFilters). It only takes effect once the
+	// format major version is at least FormatTableFormatV9.
+	//
+	// Experimental.
+	PartitionTableFilters bool
+
 	// FlushDelayDeleteRange configures how long the database should wait before
 	// forcing a flush of a memtable that contains a range deletion. Disk space
 	// cannot be reclaimed until the range deletion is flushed. No automatic
@@ -948,6 +1102,24 @@ type Options struct {
 	// to Open for perpetuity.
 	KeySchemas sstable.KeySchemas
 
+	// ValueSchema is the name of the value schema that should be used when
+	// writing new sstables, to store structured values in value columns so
+	// that iterators can decode only some of the fields of each value (see
+	// IterOptions.ValueProjection). There must be a value schema with this name
+	// defined in ValueSchemas. It only takes effect once the format major
+	// version is at least FormatValueColumns. If not set, values are stored as
+	// opaque byte strings.
+	//
+	// Experimental.
+	ValueSchema string
+
+	// ValueSchemas defines the set of known value schemas. Once a ValueSchema
+	// is used, it must be provided in ValueSchemas in subsequent calls to Open
+	// for perpetuity.
+	//
+	// Experimental.
+	ValueSchemas sstable.ValueSchemas
+
 	// Lock, if set, must be a database lock acquired through LockDirectory for
 	// the same directory passed to Open. If provided, Open will skip locking
 	// the directory. Closing the database will not release the lock, and it's
@@ -1193,6 +1365,30 @@ type Options struct {
 	// unavailability.
 	WALFailover *WALFailoverOptions
 
+	// WALReplication may be set to configure Pebble to write every write-ahead
+	// log entry to both the primary WAL location and a replica location (eg, a
+	// separate physical disk), so that a committed write survives the loss of
+	// one of the two devices. WALReplication and WALFailover may not both be
+	// set.
+	//
+	// The log files in the replica dir are only read as replicas while
+	// WALReplication is set: they cannot be supplied through WALRecoveryDirs,
+	// since log files with the same name in two WAL dirs are an error.
+	WALReplication *WALReplicationOptions
+
+	// ExternalWAL may be set to hand the write-ahead log entries to a backend
+	// provided by the embedding application, such as a replicated log or a
+	// network log service, instead of writing them to log files. The backend
+	// confirms durability asynchronously, and the WALs it holds are replayed
+	// by Open. ExternalWAL may not be set together with WALFailover or
+	// WALReplication.
+	//
+	// Once a database has been opened with an ExternalWAL, it must continue to
+	// be opened with an ExternalWAL holding the same logs (unless
+	// Unsafe.AllowMissingWALDirs is true), since they may be required for
+	// recovery.
+	ExternalWAL wal.ExternalLog
+
 	// WALRecoveryDirs is a list of additional directories that should be
 	// scanned for the existence of additional write-ahead logs. WALRecoveryDirs
 	// is expected to be used when starting Pebble with a new WALDir or a new
@@ -1205,6 +1401,17 @@ type Options struct {
 	// Unsafe.AllowMissingWALDirs is true).
 	WALRecoveryDirs []wal.Dir
 
+	// WALRecoveryTarget may be set to perform point-in-time recovery, for
+	// example after restoring a checkpoint: Open stops replaying the WALs at
+	// the first batch containing a sequence number greater than the target,
+	// instead of replaying every retained WAL to its end. The outcome is
+	// reported through EventListener.WALRecoveryStopped.
+	//
+	// WALRecoveryTarget is only meant to be set for a single Open, since the
+	// WALs that were not fully replayed are copied to an archive directory and
+	// then deleted once the replayed state is flushed.
+	WALRecoveryTarget *WALRecoveryTarget
+
 	// WALMinSyncInterval is the minimum duration between syncs of the WAL. If
 	// WAL syncs are requested faster than this interval, they will be
 	// artificially delayed. Introducing a small artificial delay (500us) between
@@ -1458,6 +1665,12 @@ type WALFailoverOptions struct {
 	// preacquire the lock on the secondary directory.
 	Secondary wal.Dir
 
+	// AdditionalSecondaries are further directories to fail over to, in order
+	// of preference after Secondary. They allow writes to proceed when the
+	// primary and Secondary are both unavailable. Each is validated and
+	// identified in the same way as Secondary.
+	AdditionalSecondaries []wal.Dir
+
 	// FailoverOptions provides configuration of the thresholds and intervals
 	// involved in WAL failover. If any of its fields are left unspecified,
 	// reasonable defaults will be used.
@@ -1468,9 +1681,79 @@ func (o *WALFailoverOptions) Validate() error {
 	if o.Secondary.FS == nil {
 		return errors.New("Secondary.FS is required")
 	}
+	for i := range o.AdditionalSecondaries {
+		if o.AdditionalSecondaries[i].FS == nil {
+			return errors.Newf("AdditionalSecondaries[%d].FS is required", i)
+		}
+	}
+	return nil
+}
+
+// WALRecoveryTarget configures point-in-time recovery of the WALs (see
+// Options.WALRecoveryTarget).
+type WALRecoveryTarget struct {
+	// SeqNum is the largest sequence number to recover. WAL replay stops at the
+	// first batch that contains a larger sequence number, and that batch and
+	// all the later ones are not applied. The WALs carry no timestamps, so a
+	// target time must be mapped to a sequence number by the caller.
+	SeqNum base.SeqNum
+
+	// ArchiveDir is the directory into which the WALs that were not fully
+	// replayed are copied, so that the batches that were not applied are
+	// retained. The directory is created if it does not exist, and is resolved
+	// relative to the database directory in the same way as WALDir. It must not
+	// be a directory that may contain the database's WALs. ArchiveDir is
+	// required unless ReadOnly is set, in which case no WALs are deleted.
+	ArchiveDir string
+}
+
+// WALReplicationOptions configures writing the WAL to two locations (see
+// Options.WALReplication).
+type WALReplicationOptions struct {
+	// Replica indicates the directory and VFS to which every write-ahead log
+	// entry is written, in addition to the primary WAL directory. The Lock
+	// field may be set during setup to preacquire the lock on the directory.
+	Replica wal.Dir
+
+	// Quorum is the number of locations, 1 or 2, in which a write must be
+	// synced before the sync is acknowledged. With a quorum of 2 (the default
+	// when Quorum is 0), a synced write survives the loss of either device,
+	// but a stall on either device stalls syncs. With a quorum of 1, syncs
+	// proceed at the speed of the faster device, and a synced write survives
+	// the loss of a device only if it was also synced on the other.
+	Quorum int
+}
+
+func (o *WALReplicationOptions) Validate() error {
+	if o.Replica.FS == nil {
+		return errors.New("Replica.FS is required")
+	}
+	if o.Quorum < 0 || o.Quorum > 2 {
+		return errors.Newf("Quorum (%d) must be 1 or 2", o.Quorum)
+	}
 	return nil
 }
 
+// RemoteReplicaOptions configures a read-only remote replica (see
+// Options.RemoteReplica).
+type RemoteReplicaOptions struct {
+	// Locator identifies the remote storage to which the primary publishes
+	// snapshots. This is the primary's CreateOnSharedLocator.
+	Locator remote.Locator
+	// PrimaryCreatorID is the creator ID of the primary store (see
+	// DB.SetCreatorID).
+	PrimaryCreatorID uint64
+	// CreatorID is the creator ID of the replica. It is used to take references
+	// on the shared objects used by the replica, which prevents the primary from
+	// deleting them while they are in use. It must be unique across all the
+	// stores that share objects.
+	CreatorID uint64
+	// RefreshInterval is the interval at which the replica checks for newer
+	// published snapshots. If zero, the replica only refreshes when
+	// DB.RefreshReplica is called.
+	RefreshInterval time.Duration
+}
+
 // ReadaheadConfig controls the use of read-ahead.
 type ReadaheadConfig = objstorageprovider.ReadaheadConfig
 
@@ -1633,6 +1916,17 @@ func UniformDBTableFilterPolicy(policy TableFilterPolicy) DBTableFilterPolicy {
 	return levels
 }
 
+// OptimizeForHits returns a copy of the DBTableFilterPolicy that uses no
+// filters in the bottommost level. The bottommost level holds most of the data
+// and thus most of the filter memory, but its filters only avoid data block
+// reads for keys that don't exist. When most lookups find their keys, the
+// memory is better spent on caching data blocks. See also
+// SpanPolicy.OptimizeFiltersForHits.
+func (p DBTableFilterPolicy) OptimizeForHits() DBTableFilterPolicy {
+	p[len(p)-1] = NoFilterPolicy
+	return p
+}
+
 // ApplyTableFilterPolicy sets the TableFilterPolicy field in each LevelOptions
 // to call the given function and return the compression profile for that level.
 func (o *Options) ApplyTableFilterPolicy(dbFilterPolicyFn func() DBTableFilterPolicy) {
@@ -1887,8 +2181,17 @@ func (o *Options) Clone() *Options {
 	n := *o
 	if o.WALFailover != nil {
 		c := *o.WALFailover
+		c.AdditionalSecondaries = slices.Clone(c.AdditionalSecondaries)
 		n.WALFailover = &c
 	}
+	if o.WALReplication != nil {
+		c := *o.WALReplication
+		n.WALReplication = &c
+	}
+	if o.WALRecoveryTarget != nil {
+		c := *o.WALRecoveryTarget
+		n.WALRecoveryTarget = &c
+	}
 	return &n
 }
 
@@ -1911,6 +2214,9 @@ func (o *Options) String() string {
 	fmt.Fprintf(&buf, "  compaction_garbage_fraction_for_max_concurrency=%.2f\n",
 		o.CompactionGarbageFractionForMaxConcurrency())
 	fmt.Fprintf(&buf, "  comparer=%s\n", o.Comparer.Name)
+	if o.CompressionWorkers != 0 {
+		fmt.Fprintf(&buf, "  compression_workers=%d\n", o.CompressionWorkers)
+	}
 	fmt.Fprintf(&buf, "  disable_wal=%t\n", o.DisableWAL)
 	if o.DisableIngestAsFlushable != nil && o.DisableIngestAsFlushable() {
 		fmt.Fprintf(&buf, "  disable_ingest_as_flushable=%t\n", true)
@@ -1955,10 +2261,22 @@ func (o *Options) String() string {
 	// We no longer care about strict_wal_tail, but set it to true in case an
 	// older version reads the options.
 	fmt.Fprintf(&buf, "  strict_wal_tail=%t\n", true)
+	if o.PartitionTableFilters {
+		fmt.Fprintf(&buf, "  partition_table_filters=%t\n", o.PartitionTableFilters)
+	}
 	fmt.Fprintf(&buf, "  table_cache_shards=%d\n", o.FileCacheShards)
+	if o.TableFilterMemoryBudget != 0 {
+		fmt.Fprintf(&buf, "  table_filter_memory_budget=%d\n", o.TableFilterMemoryBudget)
+	}
 	fmt.Fprintf(&buf, "  validate_on_ingest=%t\n", o.ValidateOnIngest)
+	if o.ValueSchema != "" {
+		fmt.Fprintf(&buf, "  value_schema=%s\n", o.ValueSchema)
+	}
 	fmt.Fprintf(&buf, "  wal_dir=%s\n", o.WALDir)
 	fmt.Fprintf(&buf, "  wal_bytes_per_sync=%d\n", o.WALBytesPerSync)
+	if o.ExternalWAL != nil {
+		fmt.Fprintf(&buf, "  external_wal=%t\n", true)
+	}
 	fmt.Fprintf(&buf, "  secondary_cache_size_bytes=%d\n", o.SecondaryCacheSizeBytes)
 	fmt.Fprintf(&buf, "  create_on_shared=%d\n", o.CreateOnShared)
 
@@ -2008,6 +2326,12 @@ func (o *Options) String() string {
 		if o.WALFailover.Secondary.ID != "" {
 			fmt.Fprintf(&buf, "  secondary_identifier=%s\n", o.WALFailover.Secondary.ID)
 		}
+		for _, d := range o.WALFailover.AdditionalSecondaries {
+			fmt.Fprintf(&buf, "  additional_secondary_dir=%s\n", d.Dirname)
+			if d.ID != "" {
+				fmt.Fprintf(&buf, "  additional_secondary_identifier=%s\n", d.ID)
+			}
+		}
 		fmt.Fprintf(&buf, "  primary_dir_probe_interval=%s\n", o.WALFailover.FailoverOptions.PrimaryDirProbeInterval)
 		fmt.Fprintf(&buf, "  healthy_probe_latency_threshold=%s\n", o.WALFailover.FailoverOptions.HealthyProbeLatencyThreshold)
 		fmt.Fprintf(&buf, "  healthy_interval=%s\n", o.WALFailover.FailoverOptions.HealthyInterval)
@@ -2016,6 +2340,13 @@ func (o *Options) String() string {
 		fmt.Fprintf(&buf, "  elevated_write_stall_threshold_lag=%s\n", o.WALFailover.FailoverOptions.ElevatedWriteStallThresholdLag)
 	}
 
+	if o.WALReplication != nil {
+		fmt.Fprintf(&buf, "\n")
+		fmt.Fprintf(&buf, "[WAL Replication]\n")
+		fmt.Fprintf(&buf, "  replica_dir=%s\n", o.WALReplication.Replica.Dirname)
+		fmt.Fprintf(&buf, "  quorum=%d\n", o.WALReplication.Quorum)
+	}
+
 	for i := range o.Levels {
 		l := &o.Levels[i]
 		fmt.Fprintf(&buf, "\n")
@@ -2203,6 +2534,8 @@ func (o *Options) Parse(s string, hooks *ParseHooks) error {
 				if err == nil {
 					o.CompactionGarbageFractionForMaxConcurrency = func() float64 { return frac }
 				}
+			case "compression_workers":
+				o.CompressionWorkers, err = strconv.Atoi(value)
 			case "delete_range_flush_delay":
 				// NB: This is a deprecated serialization of the
 				// `flush_delay_delete_range`.
@@ -2404,8 +2737,12 @@ func (o *Options) Parse(s string, hooks *ParseHooks) error {
 				if err == nil {
 					o.TombstoneDenseCompactionThreshold = func() float64 { return threshold }
 				}
+			case "partition_table_filters":
+				o.PartitionTableFilters, err = strconv.ParseBool(value)
 			case "table_cache_shards":
 				o.FileCacheShards, err = strconv.Atoi(value)
+			case "table_filter_memory_budget":
+				o.TableFilterMemoryBudget, err = strconv.ParseUint(value, 10, 64)
 			case "table_format":
 				switch value {
 				case "leveldb":
@@ -2419,10 +2756,17 @@ func (o *Options) Parse(s string, hooks *ParseHooks) error {
 				}
 			case "validate_on_ingest":
 				o.ValidateOnIngest, err = strconv.ParseBool(value)
+			case "value_schema":
+				// The schema itself must be provided in ValueSchemas.
+				o.ValueSchema = value
 			case "wal_dir":
 				o.WALDir = value
 			case "wal_bytes_per_sync":
 				o.WALBytesPerSync, err = strconv.Atoi(value)
+			case "external_wal":
+				// The ExternalWAL cannot be constructed from the options, and must
+				// be provided when opening. See CheckCompatibility.
+				_, err = strconv.ParseBool(value)
 			case "secondary_cache_size_bytes":
 				o.SecondaryCacheSizeBytes, err = strconv.ParseInt(value, 10, 64)
 			case "create_on_shared":
@@ -2492,6 +2836,16 @@ func (o *Options) Parse(s string, hooks *ParseHooks) error {
 				o.WALFailover.Secondary = wal.Dir{Dirname: value, FS: vfs.Default}
 			case "secondary_identifier":
 				o.WALFailover.Secondary.ID = value
+			case "additional_secondary_dir":
+				o.WALFailover.AdditionalSecondaries = append(o.WALFailover.AdditionalSecondaries,
+					wal.Dir{Dirname: value, FS: vfs.Default})
+			case "additional_secondary_identifier":
+				// The identifier applies to the preceding additional_secondary_dir.
+				if n := len(o.WALFailover.AdditionalSecondaries); n > 0 {
+					o.WALFailover.AdditionalSecondaries[n-1].ID = value
+				} else {
+					err = errors.New("additional_secondary_identifier without additional_secondary_dir")
+				}
 			case "primary_dir_probe_interval":
 				o.WALFailover.PrimaryDirProbeInterval, err = time.ParseDuration(value)
 			case "healthy_probe_latency_threshold":
@@ -2521,6 +2875,29 @@ func (o *Options) Parse(s string, hooks *ParseHooks) error {
 			}
 			return err
 
+		case section == "WAL Replication":
+			if o.WALReplication == nil {
+				o.WALReplication = new(WALReplicationOptions)
+			}
+			var err error
+			switch key {
+			case "replica_dir":
+				o.WALReplication.Replica = wal.Dir{Dirname: value, FS: vfs.Default}
+			case "quorum":
+				o.WALReplication.Quorum, err = strconv.Atoi(value)
+			default:
+				if hooks != nil && hooks.OnUnknown != nil {
+					hooks.OnUnknown(section+"."+key, value)
+					return nil
+				}
+				// Tolerate unknown options, but log them.
+				if o.Logger != nil {
+					o.Logger.Infof("pebble: unknown option: %s.%s", errors.Safe(section), errors.Safe(key))
+				}
+				return nil
+			}
+			return err
+
 		case strings.HasPrefix(section, "Level "):
 			m := regexp.MustCompile(`Level\s*"?(\d+)"?\s*$`).FindStringSubmatch(section)
 			if m == nil {
@@ -2669,11 +3046,23 @@ func (o *Options) CheckCompatibility(storeDir string, previousOptions string) er
 			}
 		case "Options.wal_dir":
 			previousWALDir = value
+		case "Options.external_wal":
+			if value == "true" && o.ExternalWAL == nil && !o.Unsafe.AllowMissingWALDirs {
+				return errors.New("pebble: ExternalWAL is required, since the previous options used an ExternalWAL that may hold WALs needed for recovery")
+			}
 		case "WAL Failover.secondary_dir":
 			previousWALSecondaryDir := value
 			if err := o.checkWALDir(storeDir, previousWALSecondaryDir, "WALFailover.Secondary changed from previous options"); err != nil {
 				return err
 			}
+		case "WAL Failover.additional_secondary_dir":
+			if err := o.checkWALDir(storeDir, value, "WALFailover.AdditionalSecondaries changed from previous options"); err != nil {
+				return err
+			}
+		case "WAL Replication.replica_dir":
+			if err := o.checkWALDir(storeDir, value, "WALReplication.Replica changed from previous options"); err != nil {
+				return err
+			}
 		}
 		return nil
 	}
@@ -2687,7 +3076,8 @@ func (o *Options) CheckCompatibility(storeDir string, previousOptions string) er
 }
 
 // checkWALDir verifies that walDir is among o.WALDir, o.WALFailover.Secondary,
-// or o.WALRecoveryDirs. An empty "walDir" maps to the storeDir.
+// o.WALFailover.AdditionalSecondaries, o.WALReplication.Replica or
+// o.WALRecoveryDirs. An empty "walDir" maps to the storeDir.
 func (o *Options) checkWALDir(storeDir, walDir, errContext string) error {
 	walPath := resolveStorePath(storeDir, walDir)
 	if walDir == "" {
@@ -2704,7 +3094,17 @@ func (o *Options) checkWALDir(storeDir, walDir, errContext string) error {
 		}
 	}
 
-	if o.WALFailover != nil && walPath == resolveStorePath(storeDir, o.WALFailover.Secondary.Dirname) {
+	if o.WALFailover != nil {
+		if walPath == resolveStorePath(storeDir, o.WALFailover.Secondary.Dirname) {
+			return nil
+		}
+		for _, d := range o.WALFailover.AdditionalSecondaries {
+			if walPath == resolveStorePath(storeDir, d.Dirname) {
+				return nil
+			}
+		}
+	}
+	if o.WALReplication != nil && walPath == resolveStorePath(storeDir, o.WALReplication.Replica.Dirname) {
 		return nil
 	}
 
@@ -2735,6 +3135,12 @@ func (o *Options) checkWALDir(storeDir, walDir, errContext string) error {
 	fmt.Fprintf(&buf, "  o.WALDir: %q\n", o.WALDir)
 	if o.WALFailover != nil {
 		fmt.Fprintf(&buf, "  o.WALFailover.Secondary.Dirname: %q\n", o.WALFailover.Secondary.Dirname)
+		for _, d := range o.WALFailover.AdditionalSecondaries {
+			fmt.Fprintf(&buf, "  o.WALFailover.AdditionalSecondaries: %q\n", d.Dirname)
+		}
+	}
+	if o.WALReplication != nil {
+		fmt.Fprintf(&buf, "  o.WALReplication.Replica.Dirname: %q\n", o.WALReplication.Replica.Dirname)
 	}
 	fmt.Fprintf(&buf, "  o.WALRecoveryDirs: %d", len(o.WALRecoveryDirs))
 	for _, d := range o.WALRecoveryDirs {
@@ -2768,6 +3174,9 @@ func (o *Options) Validate() error {
 		fmt.Fprintf(&buf, "MemTableStopWritesThreshold (%d) must be >= 2\n",
 			o.MemTableStopWritesThreshold)
 	}
+	if o.CompressionWorkers < 0 {
+		fmt.Fprintf(&buf, "CompressionWorkers (%d) must be >= 0\n", o.CompressionWorkers)
+	}
 	if o.FormatMajorVersion < FormatMinSupported || o.FormatMajorVersion > internalFormatNewest {
 		fmt.Fprintf(&buf, "FormatMajorVersion (%d) must be between %d and %d\n",
 			o.FormatMajorVersion, FormatMinSupported, internalFormatNewest)
@@ -2776,6 +3185,31 @@ func (o *Options) Validate() error {
 		fmt.Fprintf(&buf, "FormatMajorVersion (%d) when CreateOnShared is set must be at least %d\n",
 			o.FormatMajorVersion, FormatMinForSharedObjects)
 	}
+	if o.Tiering.CreateColdOnShared {
+		if o.RemoteStorage == nil {
+			fmt.Fprintf(&buf, "Tiering.CreateColdOnShared requires RemoteStorage\n")
+		}
+		if o.FormatMajorVersion < FormatMinForSharedObjects {
+			fmt.Fprintf(&buf, "FormatMajorVersion (%d) when Tiering.CreateColdOnShared is set must be at least %d\n",
+				o.FormatMajorVersion, FormatMinForSharedObjects)
+		}
+		if o.CreateOnShared != remote.CreateOnSharedNone && o.Tiering.ColdSharedLocator == o.CreateOnSharedLocator {
+			fmt.Fprintf(&buf, "Tiering.ColdSharedLocator must differ from CreateOnSharedLocator\n")
+		}
+	}
+	if r := o.RemoteReplica; r != nil {
+		if !o.ReadOnly {
+			fmt.Fprintf(&buf, "RemoteReplica requires ReadOnly\n")
+		}
+		if o.RemoteStorage == nil {
+			fmt.Fprintf(&buf, "RemoteReplica requires RemoteStorage\n")
+		}
+		if r.PrimaryCreatorID == 0 || r.CreatorID == 0 {
+			fmt.Fprintf(&buf, "RemoteReplica.PrimaryCreatorID and RemoteReplica.CreatorID must be set\n")
+		} else if r.PrimaryCreatorID == r.CreatorID {
+			fmt.Fprintf(&buf, "RemoteReplica.CreatorID (%d) must differ from the primary's\n", r.CreatorID)
+		}
+	}
 	if len(o.KeySchemas) > 0 {
 		if o.KeySchema == "" {
 			fmt.Fprintf(&buf, "KeySchemas is set but KeySchema is not\n")
@@ -2784,6 +3218,13 @@ func (o *Options) Validate() error {
 			fmt.Fprintf(&buf, "KeySchema %q not found in KeySchemas\n", o.KeySchema)
 		}
 	}
+	if o.ValueSchema != "" {
+		if vs, ok := o.ValueSchemas[o.ValueSchema]; !ok {
+			fmt.Fprintf(&buf, "ValueSchema %q not found in ValueSchemas\n", o.ValueSchema)
+		} else if err := vs.Validate(); err != nil {
+			fmt.Fprintf(&buf, "%s\n", err)
+		}
+	}
 	if policy := o.ValueSeparationPolicy(); policy.Enabled {
 		if policy.MinimumSize <= 0 {
 			fmt.Fprintf(&buf, "ValueSeparationPolicy.MinimumSize (%d) must be > 0\n", policy.MinimumSize)
@@ -2805,6 +3246,20 @@ func (o *Options) Validate() error {
 			fmt.Fprintf(&buf, "WALFailover validation failed: %v\n", err)
 		}
 	}
+	if o.ExternalWAL != nil && (o.WALFailover != nil || o.WALReplication != nil) {
+		fmt.Fprintf(&buf, "ExternalWAL cannot be set together with WALFailover or WALReplication\n")
+	}
+	if o.WALReplication != nil {
+		if o.WALFailover != nil {
+			fmt.Fprintf(&buf, "WALReplication and WALFailover cannot both be set\n")
+		}
+		if err := o.WALReplication.Validate(); err != nil {
+			fmt.Fprintf(&buf, "WALReplication validation failed: %v\n", err)
+		}
+	}
+	if o.WALRecoveryTarget != nil && o.WALRecoveryTarget.ArchiveDir == "" && !o.ReadOnly {
+		fmt.Fprintf(&buf, "WALRecoveryTarget.ArchiveDir is required\n")
+	}
 
 	if buf.Len() == 0 {
 		return nil
@@ -2819,9 +3274,12 @@ func (o *Options) MakeReaderOptions() sstable.ReaderOptions {
 		Comparer:       o.Comparer,
 		FilterDecoders: o.TableFilterDecoders,
 		KeySchemas:     o.KeySchemas,
+		ValueSchemas:   o.ValueSchemas,
 		Merger:         o.Merger,
+		SigningKeys:    o.SigningKeys,
 		ReaderOptions: block.ReaderOptions{
 			LoadBlockSema:   o.LoadBlockSema,
+			RateLimiter:     o.RateLimiter,
 			LoggerAndTracer: o.LoggerAndTracer,
 		},
 	}
@@ -2860,7 +3318,19 @@ func (o *Options) MakeWriterOptions(level int, format sstable.TableFormat) sstab
 	writerOpts.BlockSizeThreshold = levelOpts.BlockSizeThreshold
 	writerOpts.Compression = levelOpts.Compression()
 	writerOpts.FilterPolicy = levelOpts.TableFilterPolicy()
+	writerOpts.PartitionFilters = o.PartitionTableFilters
+	if o.ValueSchema != "" && format.ValueColumns() {
+		var ok bool
+		writerOpts.ValueSchema, ok = o.ValueSchemas[o.ValueSchema]
+		if !ok {
+			panic(errors.AssertionFailedf("invalid value schema %q", redact.Safe(o.ValueSchema)))
+		}
+	}
 	writerOpts.IndexBlockSize = levelOpts.IndexBlockSize
+	if format >= sstable.TableFormatPebblev6 {
+		writerOpts.SigningKeys = o.SigningKeys
+	}
+	writerOpts.CompressionWorkers = o.CompressionWorkers
 	return writerOpts
 }
 
@@ -2868,6 +3338,9 @@ func (o *Options) MakeWriterOptions(level int, format sstable.TableFormat) sstab
 // using the current DB options and format.
 func (d *DB) makeWriterOptions(level int) sstable.WriterOptions {
 	o := d.opts.MakeWriterOptions(level, d.TableFormat())
+	if d.FormatMajorVersion() < FormatSignedTables {
+		o.SigningKeys = nil
+	}
 	o.CompressionCounters = d.compressionCounters.Compressed.ForLevel(base.MakeLevel(level))
 	return o
 }
@@ -2900,9 +3373,12 @@ func (o *Options) MakeObjStorageProviderSettings(dirname string) objstorageprovi
 	s.Local.NoSyncOnClose = o.NoSyncOnClose
 	s.Local.BytesPerSync = o.BytesPerSync
 	s.Local.ReadaheadConfig = o.Local.ReadaheadConfig
+	s.Local.DirectIOForCompactions = o.Local.DirectIOForCompactions
 	s.Remote.StorageFactory = o.RemoteStorage
 	s.Remote.CreateOnShared = o.CreateOnShared
 	s.Remote.CreateOnSharedLocator = o.CreateOnSharedLocator
+	s.Remote.ColdTierOnShared = o.Tiering.CreateColdOnShared
+	s.Remote.ColdTierLocator = o.Tiering.ColdSharedLocator
 	s.Remote.CacheSizeBytes = o.SecondaryCacheSizeBytes
 	return s
 }
@@ -499,6 +499,10 @@ func TestOptionsParse(t *testing.T) {
 			opts.WALFailover = &WALFailoverOptions{
 				Secondary: wal.Dir{Dirname: "wal_secondary", FS: vfs.Default},
 			}
+			opts.WALReplication = &WALReplicationOptions{
+				Replica: wal.Dir{Dirname: "wal_replica", FS: vfs.Default},
+				Quorum:  1,
+			}
 			opts.ReadCompactionRate = 300
 			opts.ReadSamplingMultiplier = 400
 			opts.NumDeletionsThreshold = 500
@@ -633,6 +637,12 @@ func TestOptionsValidate(t *testing.T) {
 `,
 			`MemTableStopWritesThreshold .* must be >= 2`,
 		},
+		{`
+[Options]
+  compression_workers=-1
+`,
+			`CompressionWorkers \(-1\) must be >= 0`,
+		},
 	}
 
 	for _, c := range testCases {
@@ -6,6 +6,7 @@ package pebble
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/binary"
 	"io"
@@ -82,6 +83,21 @@ func (rs *recoveredState) init(opts *Options, dirname string) error {
 		return errors.Wrapf(err, "pebble: database %q", dirname)
 	}
 
+	if opts.RemoteReplica != nil {
+		// A remote replica has no MANIFEST or WALs of its own; its version is
+		// reconstructed from the latest snapshot published by the primary.
+		rs.replica, rs.recoveredVersion, rs.fmv, err = openRemoteReplica(context.TODO(), opts, dirname, rs.objProvider)
+		if err != nil {
+			return err
+		}
+		if !opts.DisableConsistencyCheck {
+			if err := checkConsistency(rs.recoveredVersion.version, rs.objProvider); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
 	// Determine which manifest is current, and if one exists, replay it to
 	// recover the current Version of the LSM.
 	var manifestExists bool
@@ -144,12 +160,22 @@ func (rs *recoveredState) init(opts *Options, dirname string) error {
 		if opts.WALFailover != nil {
 			var secondaryIdentifier string
 			var oldSecondaryPath string
+			// oldAdditionalSecondaries holds the additional secondary paths and
+			// their identifiers.
+			var oldAdditionalSecondaries [][2]string
 			visitKeyValue := func(i, j int, section, key, value string) error {
 				if section == "WAL Failover" {
-					if key == "secondary_identifier" {
+					switch key {
+					case "secondary_identifier":
 						secondaryIdentifier = value
-					} else if key == "secondary_dir" {
+					case "secondary_dir":
 						oldSecondaryPath = value
+					case "additional_secondary_dir":
+						oldAdditionalSecondaries = append(oldAdditionalSecondaries, [2]string{value, ""})
+					case "additional_secondary_identifier":
+						if n := len(oldAdditionalSecondaries); n > 0 {
+							oldAdditionalSecondaries[n-1][1] = value
+						}
 					}
 				}
 				return nil
@@ -164,6 +190,14 @@ func (rs *recoveredState) init(opts *Options, dirname string) error {
 			if resolvedOldPath == rs.dirs.WALSecondary.Dirname {
 				rs.dirs.WALSecondary.ID = secondaryIdentifier
 			}
+			for _, old := range oldAdditionalSecondaries {
+				resolvedOldPath := resolveStorePath(dirname, old[0])
+				for i := range rs.dirs.WALAdditionalSecondaries {
+					if resolvedOldPath == rs.dirs.WALAdditionalSecondaries[i].Dirname {
+						rs.dirs.WALAdditionalSecondaries[i].ID = old[1]
+					}
+				}
+			}
 		}
 	}
 
@@ -175,10 +209,29 @@ func (rs *recoveredState) init(opts *Options, dirname string) error {
 	}
 
 	// Find all the WAL files across the various WAL directories.
-	wals, err := wal.Scan(rs.dirs.WALDirs()...)
+	var wals wal.Logs
+	if rs.dirs.WALReplica.Dirname != "" {
+		wals, err = wal.ScanWithReplica(rs.dirs.WALReplica, rs.dirs.WALDirs()...)
+	} else {
+		wals, err = wal.Scan(rs.dirs.WALDirs()...)
+	}
 	if err != nil {
 		return err
 	}
+	// Add the WALs held by the ExternalWAL, if configured.
+	if opts.ExternalWAL != nil {
+		externalWALs, err := wal.ScanExternal(opts.ExternalWAL)
+		if err != nil {
+			return err
+		}
+		for _, w := range externalWALs {
+			if _, ok := wals.Get(w.Num); ok {
+				return errors.Errorf("pebble: WAL %s is present in both a WAL directory and the ExternalWAL", w.Num)
+			}
+		}
+		wals = append(wals, externalWALs...)
+		slices.SortFunc(wals, func(a, b wal.LogicalLog) int { return cmp.Compare(a.Num, b.Num) })
+	}
 	for _, w := range wals {
 		// Don't reuse any obsolete file numbers to avoid modifying an ingested
 		// sstable's original external file.
@@ -208,6 +261,7 @@ type recoveredState struct {
 	objProvider             objstorage.Provider
 	previousOptionsFilename string
 	recoveredVersion        *recoveredVersion
+	replica                 *remoteReplica
 	walsObsolete            wal.Logs
 	walsReplay              wal.Logs
 }
@@ -316,9 +370,6 @@ func recoverVersion(
 	manifestPath := base.MakeFilepath(opts.FS, dirname, base.FileTypeManifest, rv.manifestFileNum)
 	manifestFilename := opts.FS.PathBase(manifestPath)
 
-	// Read the versionEdits in the manifest file.
-	var bve manifest.BulkVersionEdit
-	bve.AllAddedTables = make(map[base.TableNum]*manifest.TableMetadata)
 	manifestFile, err := opts.FS.Open(manifestPath)
 	if err != nil {
 		return nil, errors.Wrapf(err, "pebble: could not open manifest file %q for DB %q",
@@ -326,13 +377,28 @@ func recoverVersion(
 	}
 	defer manifestFile.Close()
 	rr := record.NewReader(manifestFile, 0 /* logNum */)
+	if err := rv.replay(opts, dirname, manifestFilename, provider, rr); err != nil {
+		return nil, err
+	}
+	return rv, nil
+}
+
+// replay reads the version edits from the given record reader and builds the
+// latest version. The records can come from a manifest file or from a
+// published replica snapshot; manifestFilename is only used in errors.
+func (rv *recoveredVersion) replay(
+	opts *Options, dirname, manifestFilename string, provider objstorage.Provider, rr *record.Reader,
+) error {
+	// Read the versionEdits in the manifest file.
+	var bve manifest.BulkVersionEdit
+	bve.AllAddedTables = make(map[base.TableNum]*manifest.TableMetadata)
 	for {
 		r, err := rr.Next()
 		if err == io.EOF || record.IsInvalidRecord(err) {
 			break
 		}
 		if err != nil {
-			return nil, errors.Wrapf(err, "pebble: error when loading manifest file %q",
+			return errors.Wrapf(err, "pebble: error when loading manifest file %q",
 				errors.Safe(manifestFilename))
 		}
 		var ve manifest.VersionEdit
@@ -343,17 +409,17 @@ func recoverVersion(
 			if err == io.EOF || record.IsInvalidRecord(err) {
 				break
 			}
-			return nil, err
+			return err
 		}
 		if ve.ComparerName != "" {
 			if ve.ComparerName != opts.Comparer.Name {
-				return nil, errors.Errorf("pebble: manifest file %q for DB %q: "+
+				return errors.Errorf("pebble: manifest file %q for DB %q: "+
 					"comparer name from file %q != comparer name from Options %q",
 					errors.Safe(manifestFilename), dirname, errors.Safe(ve.ComparerName), errors.Safe(opts.Comparer.Name))
 			}
 		}
 		if err := bve.Accumulate(&ve); err != nil {
-			return nil, err
+			return err
 		}
 		if ve.MinUnflushedLogNum != 0 {
 			rv.minUnflushedLogNum = ve.MinUnflushedLogNum
@@ -387,7 +453,7 @@ func recoverVersion(
 			// minUnflushedLogNum, even if WALs with non-zero file numbers are
 			// present in the directory.
 		} else {
-			return nil, base.CorruptionErrorf("pebble: malformed manifest file %q for DB %q",
+			return base.CorruptionErrorf("pebble: malformed manifest file %q for DB %q",
 				errors.Safe(manifestFilename), dirname)
 		}
 	}
@@ -423,7 +489,7 @@ func recoverVersion(
 	emptyVersion := manifest.NewInitialVersion(opts.Comparer)
 	newVersion, err := bve.Apply(emptyVersion, opts.ReadCompactionRate)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	rv.latest.l0Organizer.PerformUpdate(rv.latest.l0Organizer.PrepareUpdate(&bve, newVersion), newVersion)
 	rv.latest.l0Organizer.InitCompactingFileInfo(nil /* in-progress compactions */)
@@ -432,7 +498,7 @@ func recoverVersion(
 		MinimumAge:  opts.ValueSeparationPolicy().RewriteMinimumAge,
 	})
 	rv.version = newVersion
-	return rv, nil
+	return nil
 }
 
 // replayWAL replays the edits in the specified WAL. If the DB is in read
@@ -452,10 +518,14 @@ func recoverVersion(
 // WALs into the flushable queue. Flushing of the queue is expected to be handled
 // by callers. A list of flushable ingests (but not memtables) replayed is returned.
 //
+// If stopInfo is non-nil, replay stops at the first batch that contains a
+// sequence number greater than stopInfo.TargetSeqNum, and stopInfo is updated
+// with the last applied batch and the batch replay stopped at, if any.
+//
 // d.mu must be held when calling this, but the mutex may be dropped and
 // re-acquired during the course of this method.
 func (d *DB) replayWAL(
-	jobID JobID, ll wal.LogicalLog, strictWALTail bool,
+	jobID JobID, ll wal.LogicalLog, strictWALTail bool, stopInfo *WALRecoveryStopInfo,
 ) (flushableIngests []*ingestedFlushable, maxSeqNum base.SeqNum, err error) {
 	rr := ll.OpenForRead()
 	defer func() { _ = rr.Close() }()
@@ -584,6 +654,22 @@ func (d *DB) replayWAL(
 			return nil, 0, err
 		}
 		seqNum := b.SeqNum()
+		if stopInfo != nil && b.Count() > 0 {
+			largestSeqNum := seqNum + base.SeqNum(b.Count()-1)
+			if largestSeqNum > stopInfo.TargetSeqNum {
+				// Stop before this batch. Batches are applied atomically, so a
+				// batch straddling the target is not applied either.
+				stopInfo.Stopped = true
+				stopInfo.StopWAL = base.DiskFileNum(ll.Num)
+				stopInfo.StopOffset = offset
+				stopInfo.StopSeqNum = seqNum
+				buf.Reset()
+				break
+			}
+			stopInfo.LastAppliedWAL = base.DiskFileNum(ll.Num)
+			stopInfo.LastAppliedOffset = offset
+			stopInfo.LastAppliedSeqNum = largestSeqNum
+		}
 		maxSeqNum = seqNum + base.SeqNum(b.Count())
 		keysReplayed += int64(b.Count())
 		batchesReplayed++
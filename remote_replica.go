@@ -0,0 +1,775 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package pebble
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble/internal/base"
+	"github.com/cockroachdb/pebble/internal/manifest"
+	"github.com/cockroachdb/pebble/objstorage"
+	"github.com/cockroachdb/pebble/objstorage/remote"
+	"github.com/cockroachdb/pebble/record"
+	"github.com/cockroachdb/pebble/sstable"
+)
+
+// A replica snapshot is an object on shared storage that describes a version
+// of the LSM of a primary store whose tables and blob files all live on shared
+// storage. It is a record stream (see the record package) where:
+//   - the first record is a header containing the format major version of the
+//     primary, the publication time and the remote object backings of all the
+//     objects referenced by the version;
+//   - the following record is a version edit which adds all the tables and blob
+//     files of the version (similar to the snapshot at the start of a MANIFEST).
+//
+// Snapshots are named so that the lexicographic order of the names is the
+// order in which they were published.
+
+const (
+	replicaSnapshotFormatV1 = 1
+	// replicaSnapshotsToKeep is the number of most recent snapshots that are
+	// retained when a new snapshot is published. Keeping the previous snapshot
+	// allows replicas that are in the process of reading it to finish.
+	replicaSnapshotsToKeep = 2
+)
+
+// replicaSnapshotPrefix returns the prefix of the names of the snapshots
+// published by the given primary.
+func replicaSnapshotPrefix(primary objstorage.CreatorID) string {
+	return fmt.Sprintf("pebble-replica-%s-", primary)
+}
+
+func replicaSnapshotName(primary objstorage.CreatorID, seqNum base.SeqNum) string {
+	return fmt.Sprintf("%s%020d.snapshot", replicaSnapshotPrefix(primary), uint64(seqNum))
+}
+
+// listReplicaSnapshots returns the names of the snapshots published by the
+// given primary, in publication order.
+func listReplicaSnapshots(storage remote.Storage, primary objstorage.CreatorID) ([]string, error) {
+	prefix := replicaSnapshotPrefix(primary)
+	names, err := storage.List(prefix, "")
+	if err != nil {
+		return nil, err
+	}
+	names = slices.DeleteFunc(names, func(name string) bool {
+		return !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ".snapshot")
+	})
+	slices.Sort(names)
+	return names, nil
+}
+
+// ReplicaSnapshot is a snapshot published by DB.PublishReplicaSnapshot. Until
+// it is released, it prevents the objects it references from being deleted
+// from shared storage, even if the primary has since compacted them away, so
+// that replicas can attach them.
+type ReplicaSnapshot struct {
+	// Name is the name of the snapshot object on shared storage.
+	Name string
+
+	d       *DB
+	handles []objstorage.RemoteObjectBackingHandle
+}
+
+// Release releases the references that the snapshot holds on its objects. It
+// should be called once all the replicas that are going to use the snapshot
+// have opened or refreshed from it, or have moved on to a newer snapshot; the
+// replicas hold their own references on the objects from then on.
+//
+// A snapshot is also released when it is deleted from shared storage (because
+// newer snapshots were published) and when the DB is closed. Release can be
+// called multiple times.
+func (s *ReplicaSnapshot) Release() {
+	d := s.d
+	d.replicaSnapshots.Lock()
+	defer d.replicaSnapshots.Unlock()
+	s.releaseLocked()
+}
+
+// releaseLocked releases the snapshot's references.
+//
+// DB.replicaSnapshots must be held.
+func (s *ReplicaSnapshot) releaseLocked() {
+	for _, h := range s.handles {
+		h.Close()
+	}
+	s.handles = nil
+	s.d.replicaSnapshots.published = slices.DeleteFunc(s.d.replicaSnapshots.published,
+		func(o *ReplicaSnapshot) bool { return o == s })
+}
+
+// releaseReplicaSnapshots releases the published snapshots that are no longer
+// on shared storage or, if all is set, all the published snapshots.
+func (d *DB) releaseReplicaSnapshots(retained []string, all bool) {
+	d.replicaSnapshots.Lock()
+	defer d.replicaSnapshots.Unlock()
+	for _, s := range slices.Clone(d.replicaSnapshots.published) {
+		if all || !slices.Contains(retained, s.Name) {
+			s.releaseLocked()
+		}
+	}
+}
+
+// PublishReplicaSnapshot writes a snapshot of the current version of the LSM
+// to shared storage (using CreateOnSharedLocator), where it can be used by
+// remote replicas (see Options.RemoteReplica). Data that has not been flushed
+// is not part of the snapshot; callers that need a tighter staleness bound
+// should call Flush first.
+//
+// All tables and blob files must be on shared storage (see CreateOnShared) and
+// the creator ID must be set. Older snapshots are deleted, except for the most
+// recent ones.
+//
+// The returned snapshot holds references on the objects it describes until it
+// is released (see ReplicaSnapshot.Release).
+//
+// PublishReplicaSnapshot must not be called concurrently with itself.
+func (d *DB) PublishReplicaSnapshot(ctx context.Context) (*ReplicaSnapshot, error) {
+	if err := d.closed.Load(); err != nil {
+		panic(err)
+	}
+	if d.opts.ReadOnly {
+		return nil, ErrReadOnly
+	}
+	if d.opts.RemoteStorage == nil {
+		return nil, errors.New("pebble: PublishReplicaSnapshot requires RemoteStorage")
+	}
+	creatorID, ok := d.objProvider.CreatorID()
+	if !ok {
+		return nil, errors.New("pebble: PublishReplicaSnapshot requires the creator ID to be set")
+	}
+
+	// Grab and reference the current readState. This prevents the underlying
+	// files in the associated version from being deleted.
+	readState := d.loadReadState()
+	defer readState.unref()
+	vers := readState.current
+	// All sequence numbers in the version are below logSeqNum.
+	lastSeqNum := d.mu.versions.logSeqNum.Load() - 1
+
+	ve := manifest.VersionEdit{
+		ComparerName: d.opts.Comparer.Name,
+		NextFileNum:  d.mu.versions.nextFileNum.Load(),
+		LastSeqNum:   lastSeqNum,
+	}
+	var objects []objstorage.RemoteObjectToAttach
+	// The backing handles protect the objects from deletion until the snapshot
+	// is released.
+	var handles []objstorage.RemoteObjectBackingHandle
+	defer func() {
+		for _, h := range handles {
+			h.Close()
+		}
+	}()
+	addObject := func(fileType base.FileType, fileNum base.DiskFileNum) error {
+		meta, err := d.objProvider.Lookup(fileType, fileNum)
+		if err != nil {
+			return err
+		}
+		if !meta.IsRemote() {
+			return errors.Newf("pebble: cannot publish replica snapshot: %s %s is not on shared storage",
+				errors.Safe(fileType), fileNum)
+		}
+		h, err := d.objProvider.RemoteObjectBacking(&meta)
+		if err != nil {
+			return err
+		}
+		handles = append(handles, h)
+		backing, err := h.Get()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, objstorage.RemoteObjectToAttach{
+			FileNum:  fileNum,
+			FileType: fileType,
+			Backing:  backing,
+		})
+		return nil
+	}
+
+	seenBackings := make(map[base.DiskFileNum]struct{})
+	for level, lm := range vers.Levels {
+		for f := range lm.All() {
+			ve.NewTables = append(ve.NewTables, manifest.NewTableEntry{Level: level, Meta: f})
+			if _, ok := seenBackings[f.TableBacking.DiskFileNum]; ok {
+				continue
+			}
+			seenBackings[f.TableBacking.DiskFileNum] = struct{}{}
+			if f.Virtual {
+				ve.CreatedBackingTables = append(ve.CreatedBackingTables, f.TableBacking)
+			}
+			if err := addObject(base.FileTypeTable, f.TableBacking.DiskFileNum); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for bf := range vers.BlobFiles.All() {
+		ve.NewBlobFiles = append(ve.NewBlobFiles, bf)
+		if err := addObject(base.FileTypeBlob, bf.Physical.FileNum); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := encodeReplicaSnapshot(d.FormatMajorVersion(), d.opts.private.timeNow(), objects, &ve)
+	if err != nil {
+		return nil, err
+	}
+	storage, err := d.objProvider.RemoteStorage(d.opts.CreateOnSharedLocator)
+	if err != nil {
+		return nil, err
+	}
+	name := replicaSnapshotName(creatorID, lastSeqNum)
+	w, err := storage.CreateObject(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, errors.CombineErrors(err, w.Close())
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	d.opts.Logger.Infof("published replica snapshot %s (%d objects)", name, len(objects))
+	snap := &ReplicaSnapshot{Name: name, d: d, handles: handles}
+	handles = nil
+	d.replicaSnapshots.Lock()
+	d.replicaSnapshots.published = append(d.replicaSnapshots.published, snap)
+	d.replicaSnapshots.Unlock()
+
+	// Delete old snapshots, and release them since no replica can start using
+	// them anymore.
+	names, err := listReplicaSnapshots(storage, creatorID)
+	if err != nil {
+		return snap, err
+	}
+	for len(names) > replicaSnapshotsToKeep {
+		if err := storage.Delete(names[0]); err != nil && !storage.IsNotExistError(err) {
+			return snap, err
+		}
+		names = names[1:]
+	}
+	d.releaseReplicaSnapshots(names, false /* all */)
+	return snap, nil
+}
+
+func encodeReplicaSnapshot(
+	fmv FormatMajorVersion,
+	publishedAt time.Time,
+	objects []objstorage.RemoteObjectToAttach,
+	ve *manifest.VersionEdit,
+) ([]byte, error) {
+	var header []byte
+	header = binary.AppendUvarint(header, replicaSnapshotFormatV1)
+	header = binary.AppendUvarint(header, uint64(fmv))
+	header = binary.AppendUvarint(header, uint64(publishedAt.UnixNano()))
+	header = binary.AppendUvarint(header, uint64(len(objects)))
+	for _, o := range objects {
+		header = binary.AppendUvarint(header, uint64(o.FileType))
+		header = binary.AppendUvarint(header, uint64(o.FileNum))
+		header = binary.AppendUvarint(header, uint64(len(o.Backing)))
+		header = append(header, o.Backing...)
+	}
+
+	var buf bytes.Buffer
+	rw := record.NewWriter(&buf)
+	w, err := rw.Next()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	w, err = rw.Next()
+	if err != nil {
+		return nil, err
+	}
+	if err := ve.Encode(w); err != nil {
+		return nil, err
+	}
+	if err := rw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// replicaSnapshot is a decoded replica snapshot.
+type replicaSnapshot struct {
+	name          string
+	formatVersion FormatMajorVersion
+	publishedAt   time.Time
+	objects       []objstorage.RemoteObjectToAttach
+	// edits is positioned after the header; it yields the version edit
+	// describing the LSM.
+	edits *record.Reader
+}
+
+// readReplicaSnapshot reads and decodes the header of the named snapshot.
+func readReplicaSnapshot(
+	ctx context.Context, storage remote.Storage, name string,
+) (*replicaSnapshot, error) {
+	objReader, size, err := storage.ReadObject(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, size)
+	err = objReader.ReadAt(ctx, data, 0)
+	err = errors.CombineErrors(err, objReader.Close())
+	if err != nil {
+		return nil, err
+	}
+
+	s := &replicaSnapshot{
+		name:  name,
+		edits: record.NewReader(bytes.NewReader(data), 0 /* logNum */),
+	}
+	r, err := s.edits.Next()
+	if err != nil {
+		return nil, errors.Wrapf(err, "pebble: reading replica snapshot %q", errors.Safe(name))
+	}
+	header, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrapf(err, "pebble: reading replica snapshot %q", errors.Safe(name))
+	}
+	if err := s.decodeHeader(header); err != nil {
+		return nil, errors.Wrapf(err, "pebble: decoding replica snapshot %q", errors.Safe(name))
+	}
+	return s, nil
+}
+
+func (s *replicaSnapshot) decodeHeader(header []byte) error {
+	br := bytes.NewReader(header)
+	readUvarint := func() uint64 {
+		v, err := binary.ReadUvarint(br)
+		if err != nil {
+			panic(err)
+		}
+		return v
+	}
+	return func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = base.CorruptionErrorf("invalid header: %v", r)
+			}
+		}()
+		if v := readUvarint(); v != replicaSnapshotFormatV1 {
+			return errors.Newf("unknown replica snapshot format %d", v)
+		}
+		s.formatVersion = FormatMajorVersion(readUvarint())
+		if s.formatVersion > internalFormatNewest {
+			return errors.Newf("written in unknown format major version %d", s.formatVersion)
+		}
+		s.publishedAt = time.Unix(0, int64(readUvarint()))
+		s.objects = make([]objstorage.RemoteObjectToAttach, readUvarint())
+		for i := range s.objects {
+			s.objects[i].FileType = base.FileType(readUvarint())
+			s.objects[i].FileNum = base.DiskFileNum(readUvarint())
+			s.objects[i].Backing = make([]byte, readUvarint())
+			if _, err := io.ReadFull(br, s.objects[i].Backing); err != nil {
+				return err
+			}
+		}
+		return nil
+	}()
+}
+
+// versionEdit decodes the version edit that describes the LSM.
+func (s *replicaSnapshot) versionEdit() (*manifest.VersionEdit, error) {
+	r, err := s.edits.Next()
+	if err != nil {
+		return nil, errors.Wrapf(err, "pebble: reading replica snapshot %q", errors.Safe(s.name))
+	}
+	ve := &manifest.VersionEdit{}
+	if err := ve.Decode(r); err != nil {
+		return nil, errors.Wrapf(err, "pebble: decoding replica snapshot %q", errors.Safe(s.name))
+	}
+	return ve, nil
+}
+
+// attachReplicaObjects makes sure that all the objects referenced by a replica
+// snapshot are known to the provider, taking references on the shared objects
+// that are new. If removeOthers is set, the provider's references to remote
+// objects that are not part of the snapshot are removed; this is used on Open
+// to clean up after a previous incarnation of the replica. If keys is set, the
+// new sstables are authenticated (reading each one in its entirety) and are
+// detached again if any of them fails authentication.
+func attachReplicaObjects(
+	ctx context.Context,
+	provider objstorage.Provider,
+	objs []objstorage.RemoteObjectToAttach,
+	removeOthers bool,
+	keys sstable.SigningKeyProvider,
+) error {
+	var toAttach []objstorage.RemoteObjectToAttach
+	inSnapshot := make(map[base.DiskFileNum]struct{}, len(objs))
+	for _, o := range objs {
+		inSnapshot[o.FileNum] = struct{}{}
+		if _, err := provider.Lookup(o.FileType, o.FileNum); err == nil {
+			continue
+		}
+		toAttach = append(toAttach, o)
+	}
+	if removeOthers {
+		for _, meta := range provider.List() {
+			if _, ok := inSnapshot[meta.DiskFileNum]; ok || !meta.IsRemote() {
+				continue
+			}
+			if err := provider.Remove(meta.FileType, meta.DiskFileNum); err != nil && !provider.IsNotExistError(err) {
+				return err
+			}
+		}
+	}
+	if len(toAttach) > 0 {
+		if _, err := provider.AttachRemoteObjects(toAttach); err != nil {
+			return err
+		}
+		if keys != nil {
+			var tables []base.DiskFileNum
+			for _, o := range toAttach {
+				if o.FileType == base.FileTypeTable {
+					tables = append(tables, o.FileNum)
+				}
+			}
+			if err := verifyTableSignatures(ctx, provider, keys, tables); err != nil {
+				for _, o := range toAttach {
+					err = firstError(err, provider.Remove(o.FileType, o.FileNum))
+				}
+				return err
+			}
+		}
+	}
+	return provider.Sync()
+}
+
+// remoteReplica holds the state of a DB opened with Options.RemoteReplica.
+type remoteReplica struct {
+	opts    RemoteReplicaOptions
+	storage remote.Storage
+
+	// mu serializes refreshes and protects the fields below.
+	mu struct {
+		sync.Mutex
+		// snapshotName is the name of the snapshot the current version was
+		// built from.
+		snapshotName string
+		// publishedAt is the time at which that snapshot was published.
+		publishedAt time.Time
+	}
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// openRemoteReplica recovers the version of a remote replica from the latest
+// snapshot published by the primary.
+func openRemoteReplica(
+	ctx context.Context, opts *Options, dirname string, provider objstorage.Provider,
+) (*remoteReplica, *recoveredVersion, FormatMajorVersion, error) {
+	ro := *opts.RemoteReplica
+	if err := provider.SetCreatorID(objstorage.CreatorID(ro.CreatorID)); err != nil {
+		return nil, nil, 0, err
+	}
+	storage, err := provider.RemoteStorage(ro.Locator)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	names, err := listReplicaSnapshots(storage, objstorage.CreatorID(ro.PrimaryCreatorID))
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if len(names) == 0 {
+		return nil, nil, 0, errors.Wrapf(ErrDBDoesNotExist,
+			"no replica snapshot published by creator %d", errors.Safe(ro.PrimaryCreatorID))
+	}
+	snap, err := readReplicaSnapshot(ctx, storage, names[len(names)-1])
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if err := attachReplicaObjects(ctx, provider, snap.objects, true /* removeOthers */, opts.SigningKeys); err != nil {
+		return nil, nil, 0, err
+	}
+	rv := &recoveredVersion{
+		nextFileNum: 1,
+		logSeqNum:   base.SeqNumStart,
+		latest: &latestVersionState{
+			l0Organizer:     manifest.NewL0Organizer(opts.Comparer, opts.FlushSplitBytes),
+			virtualBackings: manifest.MakeVirtualBackings(),
+		},
+	}
+	if err := rv.replay(opts, dirname, snap.name, provider, snap.edits); err != nil {
+		return nil, nil, 0, err
+	}
+	r := &remoteReplica{
+		opts:    ro,
+		storage: storage,
+		stopCh:  make(chan struct{}),
+	}
+	r.mu.snapshotName = snap.name
+	r.mu.publishedAt = snap.publishedAt
+	opts.Logger.Infof("remote replica opened from snapshot %s", snap.name)
+	return r, rv, snap.formatVersion, nil
+}
+
+// startRefreshLoop starts a goroutine that periodically refreshes the replica,
+// if a refresh interval is configured.
+func (r *remoteReplica) startRefreshLoop(d *DB) {
+	if r.opts.RefreshInterval <= 0 {
+		return
+	}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		t := time.NewTicker(r.opts.RefreshInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-r.stopCh:
+				return
+			case <-t.C:
+				if _, err := d.RefreshReplica(d.bgCtx); err != nil {
+					d.opts.Logger.Errorf("remote replica refresh failed: %s", err)
+				}
+			}
+		}
+	}()
+}
+
+// stop stops the refresh loop and waits for it to exit.
+func (r *remoteReplica) stop() {
+	close(r.stopCh)
+	r.wg.Wait()
+}
+
+// RefreshReplica checks whether the primary published a snapshot newer than
+// the one the replica is reading and, if so, switches the replica to it.
+// Returns true if the replica was updated. It can only be used on a DB opened
+// with Options.RemoteReplica.
+//
+// Iterators and snapshots that were created before the refresh continue to
+// read the previous version. Note that the replica's Snapshots are not stable
+// across refreshes: data that was unflushed on the primary at the time of the
+// previous snapshot can become visible in them.
+func (d *DB) RefreshReplica(ctx context.Context) (bool, error) {
+	if err := d.closed.Load(); err != nil {
+		panic(err)
+	}
+	r := d.replica
+	if r == nil {
+		return false, errors.New("pebble: not a remote replica")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names, err := listReplicaSnapshots(r.storage, objstorage.CreatorID(r.opts.PrimaryCreatorID))
+	if err != nil {
+		return false, err
+	}
+	if len(names) == 0 || names[len(names)-1] <= r.mu.snapshotName {
+		return false, nil
+	}
+	snap, err := readReplicaSnapshot(ctx, r.storage, names[len(names)-1])
+	if err != nil {
+		return false, err
+	}
+	ve, err := snap.versionEdit()
+	if err != nil {
+		return false, err
+	}
+	if ve.ComparerName != d.opts.Comparer.Name {
+		return false, errors.Errorf("pebble: replica snapshot %q: comparer name %q != comparer name from Options %q",
+			errors.Safe(snap.name), errors.Safe(ve.ComparerName), errors.Safe(d.opts.Comparer.Name))
+	}
+	if err := attachReplicaObjects(ctx, d.objProvider, snap.objects, false /* removeOthers */, d.opts.SigningKeys); err != nil {
+		return false, err
+	}
+
+	d.mu.Lock()
+	if err := d.mu.versions.installReplicaSnapshotLocked(ve); err != nil {
+		d.mu.Unlock()
+		return false, err
+	}
+	if snap.formatVersion > d.FormatMajorVersion() {
+		d.mu.formatVers.vers.Store(uint64(snap.formatVersion))
+	}
+	if seqNum := max(ve.LastSeqNum+1, base.SeqNumStart); seqNum > d.mu.versions.logSeqNum.Load() {
+		d.mu.versions.logSeqNum.Store(seqNum)
+		d.mu.versions.visibleSeqNum.Store(seqNum)
+	}
+	d.updateReadStateLocked(d.opts.DebugCheck)
+	d.deleteObsoleteFiles(d.newJobIDLocked())
+	d.mu.Unlock()
+
+	r.mu.snapshotName = snap.name
+	r.mu.publishedAt = snap.publishedAt
+	d.opts.Logger.Infof("remote replica refreshed to snapshot %s", snap.name)
+	return true, nil
+}
+
+// ReplicaPublishedAt returns the time at which the snapshot the replica is
+// currently reading was published by the primary. The replica reflects the
+// flushed state of the primary as of that time. It can only be used on a DB
+// opened with Options.RemoteReplica.
+func (d *DB) ReplicaPublishedAt() time.Time {
+	if d.replica == nil {
+		return time.Time{}
+	}
+	d.replica.mu.Lock()
+	defer d.replica.mu.Unlock()
+	return d.replica.mu.publishedAt
+}
+
+// installReplicaSnapshotLocked makes the current version match the version
+// described by the version edit of a replica snapshot. It is the replica
+// counterpart of UpdateVersionLocked: the difference between the two versions
+// is applied as a regular version edit, but it is not persisted.
+//
+// DB.mu must be held.
+func (vs *versionSet) installReplicaSnapshotLocked(snap *manifest.VersionEdit) error {
+	vs.logLock()
+	defer vs.logUnlockAndInvalidatePickedCompactionCache()
+
+	ve, err := replicaVersionEdit(vs.currentVersion(), vs.latest, snap)
+	if err != nil {
+		return err
+	}
+	zombieBackings, removedVirtualBackings :=
+		getZombieTablesAndUpdateVirtualBackings(ve, &vs.latest.virtualBackings, vs.provider)
+
+	// As in UpdateVersionLocked, failures after this point are fatal: the
+	// virtual backings have already been updated.
+	if err := vs.latest.blobFiles.ApplyAndUpdateVersionEdit(ve); err != nil {
+		vs.opts.Logger.Fatalf("replica blob files apply and update failed: %s", err)
+	}
+	var bulkEdit manifest.BulkVersionEdit
+	if err := bulkEdit.Accumulate(ve); err != nil {
+		vs.opts.Logger.Fatalf("replica version edit accumulate failed: %s", err)
+	}
+	newVersion, err := bulkEdit.Apply(vs.currentVersion(), vs.opts.ReadCompactionRate)
+	if err != nil {
+		vs.opts.Logger.Fatalf("replica version edit apply failed: %s", err)
+	}
+	zombieBlobs := getZombieBlobFiles(ve, vs.provider)
+	vs.latest.l0Organizer.PerformUpdate(vs.latest.l0Organizer.PrepareUpdate(&bulkEdit, newVersion), newVersion)
+	vs.latest.l0Organizer.InitCompactingFileInfo(nil /* in-progress compactions */)
+
+	for _, b := range zombieBackings {
+		vs.zombieTables.Add(objectInfo{
+			fileInfo: fileInfo{
+				FileNum:  b.backing.DiskFileNum,
+				FileSize: b.backing.Size,
+			},
+			placement: b.placement,
+		})
+	}
+	for _, zb := range zombieBlobs {
+		vs.zombieBlobs.Add(zb)
+	}
+	var obsoleteVirtualBackings manifest.ObsoleteFiles
+	for _, b := range removedVirtualBackings {
+		if b.backing.Unref() == 0 {
+			obsoleteVirtualBackings.TableBackings = append(obsoleteVirtualBackings.TableBackings, b.backing)
+		}
+	}
+	vs.addObsoleteLocked(obsoleteVirtualBackings)
+
+	vs.append(newVersion)
+	setBasicLevelMetrics(&vs.metrics.Levels, newVersion)
+	vs.setCompactionPicker(newCompactionPickerByScore(newVersion, vs.latest, vs.opts, nil))
+	return nil
+}
+
+// replicaVersionEdit computes the version edit that transforms the current
+// version into the version described by a replica snapshot. Tables that are
+// present in both versions keep their existing metadata (and backings) so that
+// the backing reference counts remain accurate.
+func replicaVersionEdit(
+	cur *manifest.Version, latest *latestVersionState, snap *manifest.VersionEdit,
+) (*manifest.VersionEdit, error) {
+	type tableLoc struct {
+		level int
+		meta  *manifest.TableMetadata
+	}
+	curTables := make(map[base.TableNum]tableLoc)
+	curPhysicalBackings := make(map[base.DiskFileNum]*manifest.TableBacking)
+	for level, lm := range cur.Levels {
+		for m := range lm.All() {
+			curTables[m.TableNum] = tableLoc{level: level, meta: m}
+			if !m.Virtual {
+				curPhysicalBackings[m.TableBacking.DiskFileNum] = m.TableBacking
+			}
+		}
+	}
+	snapBackings := make(map[base.DiskFileNum]*manifest.TableBacking, len(snap.CreatedBackingTables))
+	for _, b := range snap.CreatedBackingTables {
+		snapBackings[b.DiskFileNum] = b
+	}
+
+	ve := &manifest.VersionEdit{
+		DeletedTables:    make(map[manifest.DeletedTableEntry]*manifest.TableMetadata),
+		DeletedBlobFiles: make(map[manifest.DeletedBlobFileEntry]*manifest.PhysicalBlobFile),
+	}
+	inSnapshot := make(map[manifest.DeletedTableEntry]struct{}, len(snap.NewTables))
+	createdBackings := make(map[base.DiskFileNum]struct{})
+	for _, nt := range snap.NewTables {
+		inSnapshot[manifest.DeletedTableEntry{Level: nt.Level, FileNum: nt.Meta.TableNum}] = struct{}{}
+		c, ok := curTables[nt.Meta.TableNum]
+		switch {
+		case ok && c.level == nt.Level:
+			// Unchanged.
+		case ok:
+			// Moved to a different level.
+			ve.NewTables = append(ve.NewTables, manifest.NewTableEntry{Level: nt.Level, Meta: c.meta})
+		case !nt.Meta.Virtual:
+			ve.NewTables = append(ve.NewTables, nt)
+		default:
+			if backing, ok := latest.virtualBackings.Get(nt.BackingFileNum); ok {
+				nt.Meta.AttachVirtualBacking(backing)
+				ve.NewTables = append(ve.NewTables, manifest.NewTableEntry{Level: nt.Level, Meta: nt.Meta})
+				break
+			}
+			// The backing is new; it is attached when the edit is accumulated.
+			if _, ok := createdBackings[nt.BackingFileNum]; !ok {
+				backing := snapBackings[nt.BackingFileNum]
+				if backing == nil {
+					return nil, base.CorruptionErrorf("pebble: replica snapshot is missing backing %s", nt.BackingFileNum)
+				}
+				// If the backing was a physical table in the current version, reuse
+				// its TableBacking (similar to what an excise does).
+				if pb, ok := curPhysicalBackings[nt.BackingFileNum]; ok {
+					backing = pb
+				}
+				createdBackings[nt.BackingFileNum] = struct{}{}
+				ve.CreatedBackingTables = append(ve.CreatedBackingTables, backing)
+			}
+			ve.NewTables = append(ve.NewTables, nt)
+		}
+	}
+	for num, c := range curTables {
+		e := manifest.DeletedTableEntry{Level: c.level, FileNum: num}
+		if _, ok := inSnapshot[e]; !ok {
+			ve.DeletedTables[e] = c.meta
+		}
+	}
+	// Blob files that are no longer referenced are removed automatically; we
+	// only need to handle new blob files and replaced (rewritten) ones.
+	for _, bf := range snap.NewBlobFiles {
+		phys, ok := cur.BlobFiles.LookupPhysical(bf.FileID)
+		if ok && phys.FileNum == bf.Physical.FileNum {
+			continue
+		}
+		if ok {
+			ve.DeletedBlobFiles[manifest.DeletedBlobFileEntry{FileID: bf.FileID, FileNum: phys.FileNum}] = phys
+		}
+		ve.NewBlobFiles = append(ve.NewBlobFiles, bf)
+	}
+	return ve, nil
+}
@@ -0,0 +1,214 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package pebble
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble/internal/testutils"
+	"github.com/cockroachdb/pebble/objstorage/remote"
+	"github.com/cockroachdb/pebble/sstable"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteReplica(t *testing.T) {
+	ctx := context.Background()
+	storage := remote.NewInMem()
+	factory := remote.MakeSimpleFactory(map[remote.Locator]remote.Storage{
+		remote.MakeLocator(""): storage,
+	})
+
+	primary, err := Open("primary", &Options{
+		FS:                 vfs.NewMem(),
+		FormatMajorVersion: FormatNewest,
+		Logger:             testutils.Logger{T: t},
+		RemoteStorage:      factory,
+		CreateOnShared:     remote.CreateOnSharedAll,
+	})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, primary.Close()) }()
+	require.NoError(t, primary.SetCreatorID(1))
+
+	replicaOpts := &Options{
+		FS:                      vfs.NewMem(),
+		FormatMajorVersion:      FormatNewest,
+		Logger:                  testutils.Logger{T: t},
+		ReadOnly:                true,
+		RemoteStorage:           factory,
+		SecondaryCacheSizeBytes: 1 << 20,
+		RemoteReplica: &RemoteReplicaOptions{
+			PrimaryCreatorID: 1,
+			CreatorID:        2,
+		},
+	}
+	// Nothing was published yet.
+	_, err = Open("replica", replicaOpts)
+	require.True(t, errors.Is(err, ErrDBDoesNotExist), "%v", err)
+
+	set := func(prefix string, n int) {
+		for i := 0; i < n; i++ {
+			require.NoError(t, primary.Set([]byte(fmt.Sprintf("%s%04d", prefix, i)), []byte(strings.Repeat(prefix, 10)), nil))
+		}
+	}
+	set("a", 100)
+	require.NoError(t, primary.Flush())
+	// Unflushed data is not part of the snapshot.
+	set("b", 10)
+	snap1, err := primary.PublishReplicaSnapshot(ctx)
+	require.NoError(t, err)
+
+	// The snapshot protects its tables from deletion, so the replica can open
+	// from it even though the primary compacted them away in the meantime.
+	require.NoError(t, primary.Flush())
+	require.NoError(t, primary.Compact(ctx, []byte("a"), []byte("c"), false /* parallelize */))
+
+	replica, err := Open("replica", replicaOpts)
+	require.NoError(t, err)
+	expect := func(key string, found bool) {
+		t.Helper()
+		v, closer, err := replica.Get([]byte(key))
+		if !found {
+			require.ErrorIs(t, err, ErrNotFound)
+			return
+		}
+		require.NoError(t, err)
+		require.Equal(t, strings.Repeat(key[:1], 10), string(v))
+		require.NoError(t, closer.Close())
+	}
+	expect("a0042", true)
+	expect("b0001", false)
+	require.ErrorIs(t, replica.Set([]byte("x"), nil, nil), ErrReadOnly)
+	require.False(t, replica.ReplicaPublishedAt().IsZero())
+
+	// No newer snapshot.
+	updated, err := replica.RefreshReplica(ctx)
+	require.NoError(t, err)
+	require.False(t, updated)
+
+	// An iterator opened before the refresh keeps reading the old version.
+	iter, err := replica.NewIter(nil)
+	require.NoError(t, err)
+
+	require.NoError(t, primary.DeleteRange([]byte("a0000"), []byte("a0050"), nil))
+	require.NoError(t, primary.Flush())
+	require.NoError(t, primary.Compact(ctx, []byte("a"), []byte("c"), false /* parallelize */))
+	snap2, err := primary.PublishReplicaSnapshot(ctx)
+	require.NoError(t, err)
+
+	updated, err = replica.RefreshReplica(ctx)
+	require.NoError(t, err)
+	require.True(t, updated)
+	expect("a0042", false)
+	expect("a0077", true)
+	expect("b0001", true)
+
+	count := 0
+	for valid := iter.First(); valid; valid = iter.Next() {
+		count++
+	}
+	require.NoError(t, iter.Close())
+	require.Equal(t, 100, count)
+	require.NoError(t, replica.Close())
+
+	// Reopening the replica uses the latest snapshot.
+	set("c", 10)
+	require.NoError(t, primary.Flush())
+	snap3, err := primary.PublishReplicaSnapshot(ctx)
+	require.NoError(t, err)
+	replica, err = Open("replica", replicaOpts)
+	require.NoError(t, err)
+	expect("a0042", false)
+	expect("c0009", true)
+	require.NoError(t, replica.Close())
+
+	// Only the most recent snapshots are retained; the deleted snapshot was
+	// released.
+	names, err := storage.List(replicaSnapshotPrefix(1), "")
+	require.NoError(t, err)
+	require.Len(t, names, replicaSnapshotsToKeep)
+	require.Nil(t, snap1.handles)
+	require.NotNil(t, snap2.handles)
+	snap2.Release()
+	snap3.Release()
+	snap3.Release()
+	require.Empty(t, primary.replicaSnapshots.published)
+
+	// Tables that were compacted away on the primary were deleted once the
+	// replica stopped using them.
+	all, err := storage.List("", "")
+	require.NoError(t, err)
+	var numObjects int
+	for _, name := range all {
+		if strings.HasSuffix(name, ".sst") {
+			numObjects++
+		}
+	}
+	require.Equal(t, int(primary.Metrics().Total().Tables.Count), numObjects)
+}
+
+// TestRemoteReplicaSigningKeys tests that a replica with signing keys
+// authenticates the tables it attaches.
+func TestRemoteReplicaSigningKeys(t *testing.T) {
+	ctx := context.Background()
+	keys := &sstable.StaticSigningKeys{
+		SigningKeyID: 1,
+		Keys:         map[uint32][]byte{1: []byte("key")},
+	}
+	for _, signed := range []bool{false, true} {
+		t.Run(fmt.Sprintf("signed=%t", signed), func(t *testing.T) {
+			storage := remote.NewInMem()
+			factory := remote.MakeSimpleFactory(map[remote.Locator]remote.Storage{
+				remote.MakeLocator(""): storage,
+			})
+			primaryOpts := &Options{
+				FS:                 vfs.NewMem(),
+				FormatMajorVersion: FormatSignedTables,
+				Logger:             testutils.Logger{T: t},
+				RemoteStorage:      factory,
+				CreateOnShared:     remote.CreateOnSharedAll,
+			}
+			if signed {
+				primaryOpts.SigningKeys = keys
+			}
+			primary, err := Open("primary", primaryOpts)
+			require.NoError(t, err)
+			defer func() { require.NoError(t, primary.Close()) }()
+			require.NoError(t, primary.SetCreatorID(1))
+			require.NoError(t, primary.Set([]byte("a"), []byte("a"), nil))
+			require.NoError(t, primary.Flush())
+			snap, err := primary.PublishReplicaSnapshot(ctx)
+			require.NoError(t, err)
+			defer snap.Release()
+
+			replica, err := Open("replica", &Options{
+				FS:                 vfs.NewMem(),
+				FormatMajorVersion: FormatSignedTables,
+				Logger:             testutils.Logger{T: t},
+				ReadOnly:           true,
+				RemoteStorage:      factory,
+				SigningKeys:        keys,
+				RemoteReplica: &RemoteReplicaOptions{
+					PrimaryCreatorID: 1,
+					CreatorID:        2,
+				},
+			})
+			if !signed {
+				require.ErrorIs(t, err, sstable.ErrUnsignedTable)
+				return
+			}
+			require.NoError(t, err)
+			v, closer, err := replica.Get([]byte("a"))
+			require.NoError(t, err)
+			require.Equal(t, "a", string(v))
+			require.NoError(t, closer.Close())
+			require.NoError(t, replica.Close())
+		})
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/pebble/objstorage/objstorageprovider"
 	"github.com/cockroachdb/pebble/objstorage/objstorageprovider/objiotracing"
 	"github.com/cockroachdb/pebble/sstable/block/blockkind"
+	"github.com/cockroachdb/pebble/vfs"
 	"github.com/cockroachdb/redact"
 )
 
@@ -300,6 +301,15 @@ type ReadEnv struct {
 	ValueRetrievalProfile *bytesprofile.Profile
 }
 
+// DirectIOBuffers returns the allocator for the buffers of direct I/O reads,
+// which is the BufferPool if one is set.
+func (env *ReadEnv) DirectIOBuffers() objstorage.DirectIOBufferAllocator {
+	if env.BufferPool == nil {
+		return nil
+	}
+	return env.BufferPool
+}
+
 // BlockServedFromCache updates the stats when a block was found in the cache.
 func (env *ReadEnv) BlockServedFromCache(kind Kind, blockLength uint64) {
 	if env.Stats != nil {
@@ -325,6 +335,14 @@ func (env *ReadEnv) BlockRead(kind Kind, blockLength uint64, readDuration time.D
 	}
 }
 
+// readCategory returns the category used to rate limit block loads.
+func (env *ReadEnv) readCategory() vfs.ReadCategory {
+	if env.IterStats == nil {
+		return vfs.ReadCategory(CategoryUnknown.String())
+	}
+	return vfs.ReadCategory(env.IterStats.Category().String())
+}
+
 // maybeReportCorruption calls the ReportCorruptionFn if the given error
 // indicates corruption.
 func (env *ReadEnv) maybeReportCorruption(err error) error {
@@ -351,6 +369,10 @@ type ReaderOptions struct {
 	// loaded (i.e. read from the filesystem) in parallel. Each load acquires
 	// one unit from the semaphore for the duration of the read.
 	LoadBlockSema *fifo.Semaphore
+	// RateLimiter, if set, is used to limit the bandwidth of block loads. Loads
+	// are charged to the read category of the operation (see
+	// ReadEnv.IterStats).
+	RateLimiter *vfs.RateLimiter
 	// LoggerAndTracer is an optional logger and tracer.
 	LoggerAndTracer base.LoggerAndTracer
 
@@ -503,6 +525,9 @@ func (r *Reader) doRead(
 		}
 		defer sema.Release(1)
 	}
+	if rl := r.opts.RateLimiter; rl != nil {
+		rl.WaitRead(env.readCategory(), int(bh.Length+TrailerLen))
+	}
 
 	compressed := Alloc(int(bh.Length+TrailerLen), env.BufferPool)
 	readStopwatch := base.MakeStopwatch()
@@ -5,10 +5,14 @@
 package block
 
 import (
+	"unsafe"
+
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/pebble/internal/base"
 	"github.com/cockroachdb/pebble/internal/cache"
 	"github.com/cockroachdb/pebble/internal/invariants"
+	"github.com/cockroachdb/pebble/objstorage"
+	"github.com/cockroachdb/pebble/vfs"
 )
 
 // Alloc allocates a new Value for a block of length n (excluding the block
@@ -232,6 +236,23 @@ func (p *BufferPool) Alloc(n int) Buf {
 	return Buf{p: p, i: len(p.pool) - 1}
 }
 
+// AllocDirectIOBuffer allocates a buffer of size n whose address is a multiple
+// of vfs.DirectIOAlignment. It implements objstorage.DirectIOBufferAllocator,
+// allowing the objstorage provider to use the pool for the buffers of reads
+// and writes that use direct I/O.
+func (p *BufferPool) AllocDirectIOBuffer(n int) objstorage.DirectIOBuffer {
+	b := p.Alloc(n + vfs.DirectIOAlignment)
+	buf := p.pool[b.i].b
+	off := 0
+	if rem := int(uintptr(unsafe.Pointer(&buf[0])) & (vfs.DirectIOAlignment - 1)); rem != 0 {
+		off = vfs.DirectIOAlignment - rem
+	}
+	p.pool[b.i].b = buf[off : off+n : off+n]
+	return &b
+}
+
+var _ objstorage.DirectIOBufferAllocator = (*BufferPool)(nil)
+
 // A Buf holds a reference to a manually-managed, pooled byte buffer.
 type Buf struct {
 	p *BufferPool
@@ -246,6 +267,11 @@ func (b Buf) Valid() bool {
 	return b.p != nil
 }
 
+// Bytes returns the buffer.
+func (b *Buf) Bytes() []byte {
+	return b.p.pool[b.i].b
+}
+
 // Release releases the buffer back to the pool.
 func (b *Buf) Release() {
 	if b.p == nil {
@@ -9,8 +9,11 @@ import (
 	"fmt"
 	"io"
 	"testing"
+	"unsafe"
 
 	"github.com/cockroachdb/datadriven"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/stretchr/testify/require"
 )
 
 func writeBufferPool(w io.Writer, bp *BufferPool) {
@@ -76,3 +79,16 @@ func TestBufferPool(t *testing.T) {
 		}
 	})
 }
+
+func TestBufferPoolDirectIOBuffer(t *testing.T) {
+	var bp BufferPool
+	bp.Init(2, ForCompaction)
+	defer bp.Release()
+	for _, n := range []int{1, 100, vfs.DirectIOAlignment, 1 << 20} {
+		b := bp.AllocDirectIOBuffer(n)
+		buf := b.Bytes()
+		require.Len(t, buf, n)
+		require.Zero(t, uintptr(unsafe.Pointer(&buf[0]))%vfs.DirectIOAlignment)
+		b.Release()
+	}
+}
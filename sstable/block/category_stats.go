@@ -196,12 +196,18 @@ var numCategoryStatsShards = func() int {
 // CategoryStatsShard holds CategoryStats with a mutex
 // to ensure safe access.
 type CategoryStatsShard struct {
-	mu struct {
+	category Category
+	mu       struct {
 		sync.Mutex
 		stats CategoryStats
 	}
 }
 
+// Category returns the category whose stats are held by the shard.
+func (c *CategoryStatsShard) Category() Category {
+	return c.category
+}
+
 // Accumulate implements the IterStatsAccumulator interface.
 func (c *CategoryStatsShard) Accumulate(
 	blockBytes, blockBytesInCache uint64, blockReadDuration time.Duration,
@@ -247,10 +253,14 @@ func (c *CategoryStatsCollector) Accumulator(p uint64, category Category) *Categ
 	v, ok := c.statsMap.Load(category)
 	if !ok {
 		c.mu.Lock()
-		v, _ = c.statsMap.LoadOrStore(category, &shardedCategoryStats{
+		s := &shardedCategoryStats{
 			Category: category,
 			shards:   make([]paddedCategoryStatsShard, numCategoryStatsShards),
-		})
+		}
+		for i := range s.shards {
+			s.shards[i].category = category
+		}
+		v, _ = c.statsMap.LoadOrStore(category, s)
 		c.mu.Unlock()
 	}
 	s := v.(*shardedCategoryStats)
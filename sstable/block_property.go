@@ -153,6 +153,27 @@ type BlockPropertyCollector interface {
 	FinishTable(buf []byte) ([]byte, error)
 }
 
+// InPlaceValueBlockPropertyCollector is an optional interface implemented by
+// a BlockPropertyCollector that examines the values of SET and SETWITHDEL keys.
+// By default, AddPointKey is passed a nil value for such keys. If
+// WantsInPlaceValues returns true, values that are stored with the key in the
+// data block are passed instead. Values stored elsewhere (in value blocks or
+// blob files) are still passed as nil, so the collector must treat a nil value
+// as unknown.
+type InPlaceValueBlockPropertyCollector interface {
+	BlockPropertyCollector
+	// WantsInPlaceValues returns true if AddPointKey should be passed the
+	// values of SET and SETWITHDEL keys that are stored in place.
+	WantsInPlaceValues() bool
+}
+
+// wantsInPlaceValues returns whether the collector implements
+// InPlaceValueBlockPropertyCollector and wants in-place values.
+func wantsInPlaceValues(c BlockPropertyCollector) bool {
+	v, ok := c.(InPlaceValueBlockPropertyCollector)
+	return ok && v.WantsInPlaceValues()
+}
+
 // BlockPropertyFilter is used in an Iterator to filter sstables and blocks
 // within the sstable. It should not maintain any per-sstable state, and must
 // be thread-safe.
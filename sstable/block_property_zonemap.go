@@ -0,0 +1,489 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package sstable
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble/internal/base"
+)
+
+// Zone maps are block properties that record the minimum and maximum value of
+// a user-defined field across the point keys of each data block, index block
+// and table. A ZoneMapFilter uses them to skip blocks (and tables) that can't
+// contain a field value within a range.
+//
+// The field is extracted from each point key (and its value) by a
+// ZoneMapExtractor. Fields are compared bytewise, so integer fields must use
+// an order-preserving encoding (see EncodeZoneMapUint64 and
+// EncodeZoneMapInt64).
+//
+// Zone maps are subject to the filtering semantics described at the top of
+// block_property.go. In particular, a point tombstone k.DEL may delete a k.SET
+// with any field value in another block or table: if the block containing
+// k.DEL were filtered while the block with the deleted k.SET is not, the
+// deleted key would be surfaced. So a point tombstone for which the extractor
+// reports the field as absent makes the zone map include all values. Zone maps
+// only describe point keys; they must not be used as range key filters.
+
+// ZoneMapFieldType is the type of the field tracked by a zone map.
+type ZoneMapFieldType uint8
+
+const (
+	// ZoneMapUint64 fields are unsigned 64-bit integers, encoded with
+	// EncodeZoneMapUint64.
+	ZoneMapUint64 ZoneMapFieldType = 1 + iota
+	// ZoneMapInt64 fields are signed 64-bit integers, encoded with
+	// EncodeZoneMapInt64.
+	ZoneMapInt64
+	// ZoneMapBytes fields are arbitrary byte strings, ordered bytewise.
+	ZoneMapBytes
+
+	zoneMapTypeMask ZoneMapFieldType = 0x7f
+)
+
+// String implements fmt.Stringer.
+func (t ZoneMapFieldType) String() string {
+	switch t {
+	case ZoneMapUint64:
+		return "uint64"
+	case ZoneMapInt64:
+		return "int64"
+	case ZoneMapBytes:
+		return "bytes"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(t))
+	}
+}
+
+// ParseZoneMapFieldType parses the string representation of a
+// ZoneMapFieldType.
+func ParseZoneMapFieldType(s string) (ZoneMapFieldType, error) {
+	for _, t := range []ZoneMapFieldType{ZoneMapUint64, ZoneMapInt64, ZoneMapBytes} {
+		if s == t.String() {
+			return t, nil
+		}
+	}
+	return 0, errors.Newf("unknown zone map field type %q", s)
+}
+
+// FormatField returns a human-readable representation of an encoded field.
+func (t ZoneMapFieldType) FormatField(field []byte) string {
+	switch {
+	case t == ZoneMapUint64 && len(field) == 8:
+		return fmt.Sprint(DecodeZoneMapUint64(field))
+	case t == ZoneMapInt64 && len(field) == 8:
+		return fmt.Sprint(DecodeZoneMapInt64(field))
+	default:
+		return fmt.Sprintf("%q", field)
+	}
+}
+
+// EncodeZoneMapUint64 encodes an unsigned integer field such that the encoded
+// fields sort in numeric order.
+func EncodeZoneMapUint64(v uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, v)
+}
+
+// DecodeZoneMapUint64 decodes a field encoded by EncodeZoneMapUint64.
+func DecodeZoneMapUint64(field []byte) uint64 {
+	return binary.BigEndian.Uint64(field)
+}
+
+// EncodeZoneMapInt64 encodes a signed integer field such that the encoded
+// fields sort in numeric order.
+func EncodeZoneMapInt64(v int64) []byte {
+	return EncodeZoneMapUint64(uint64(v) ^ (1 << 63))
+}
+
+// DecodeZoneMapInt64 decodes a field encoded by EncodeZoneMapInt64.
+func DecodeZoneMapInt64(field []byte) int64 {
+	return int64(DecodeZoneMapUint64(field) ^ (1 << 63))
+}
+
+// ZoneMapFieldPresence is returned by a ZoneMapExtractor to indicate whether a
+// point key has a field.
+type ZoneMapFieldPresence uint8
+
+const (
+	// ZoneMapFieldAbsent indicates that the key does not have the field. The
+	// key does not contribute to the zone map, unless it is a point tombstone
+	// (in which case the zone map includes all values).
+	ZoneMapFieldAbsent ZoneMapFieldPresence = iota
+	// ZoneMapFieldPresent indicates that the key has the returned field.
+	ZoneMapFieldPresent
+	// ZoneMapFieldUnknown indicates that the field can't be determined (for
+	// example, because the value is stored separately and is not available to
+	// block property collectors). The zone map of a block containing such a key
+	// matches all filters.
+	ZoneMapFieldUnknown
+)
+
+// ZoneMapExtractor extracts the field tracked by a zone map from a point key
+// and its value. Zone map collectors ask for in-place values (see
+// InPlaceValueBlockPropertyCollector), but the value is nil if it is stored
+// separately from the key; an extractor that examines values must return
+// ZoneMapFieldUnknown in that case. The returned field is only used until the
+// next call and can alias the key or value.
+type ZoneMapExtractor func(key InternalKey, value []byte) (field []byte, _ ZoneMapFieldPresence, _ error)
+
+// ZoneMapConfig configures a zone map collector.
+type ZoneMapConfig struct {
+	// Name is the name of the block property; it must be used by the
+	// corresponding ZoneMapFilters.
+	Name string
+	// Type is the type of the field.
+	Type ZoneMapFieldType
+	// Extract extracts the field from a point key.
+	Extract ZoneMapExtractor
+	// MaxFieldLen is the maximum length of a ZoneMapBytes field stored in a
+	// zone map; longer minimums and maximums are truncated, which makes the zone
+	// map less precise. If zero, DefaultZoneMapMaxFieldLen is used.
+	MaxFieldLen int
+}
+
+// DefaultZoneMapMaxFieldLen is the default value of ZoneMapConfig.MaxFieldLen.
+const DefaultZoneMapMaxFieldLen = 16
+
+// ZoneMap is the decoded form of a zone map block property.
+type ZoneMap struct {
+	// Type is the type of the field; it is zero if the zone map is empty.
+	Type ZoneMapFieldType
+	// All is set if the zone map includes all the possible field values.
+	All bool
+	// Min and Max are the (inclusive) bounds of the field values; they are only
+	// set if the zone map is not empty and All is not set.
+	Min, Max []byte
+}
+
+// zoneMapAllFlag is set in the encoded tag of a zone map that includes all
+// values.
+const zoneMapAllFlag = 0x80
+
+// IsEmpty returns true if the zone map contains no field values.
+func (z *ZoneMap) IsEmpty() bool {
+	return z.Type == 0
+}
+
+// Intersects returns true if the zone map contains a field value within
+// [lower, upper). A nil bound is unbounded.
+func (z *ZoneMap) Intersects(lower, upper []byte) bool {
+	switch {
+	case z.IsEmpty():
+		return false
+	case z.All:
+		return true
+	}
+	return (upper == nil || bytes.Compare(z.Min, upper) < 0) &&
+		(lower == nil || bytes.Compare(z.Max, lower) >= 0)
+}
+
+// String implements fmt.Stringer.
+func (z *ZoneMap) String() string {
+	switch {
+	case z.IsEmpty():
+		return "empty"
+	case z.All:
+		return fmt.Sprintf("%s: all", z.Type)
+	}
+	return fmt.Sprintf("%s: [%s, %s]", z.Type, z.Type.FormatField(z.Min), z.Type.FormatField(z.Max))
+}
+
+// DecodeZoneMap decodes a block property written by a zone map collector. The
+// returned zone map aliases prop.
+func DecodeZoneMap(prop []byte) (ZoneMap, error) {
+	if len(prop) == 0 {
+		return ZoneMap{}, nil
+	}
+	z := ZoneMap{
+		Type: ZoneMapFieldType(prop[0]) & zoneMapTypeMask,
+		All:  prop[0]&zoneMapAllFlag != 0,
+	}
+	if z.Type < ZoneMapUint64 || z.Type > ZoneMapBytes {
+		return ZoneMap{}, base.CorruptionErrorf("invalid zone map type in %x", prop)
+	}
+	if z.All {
+		if len(prop) != 1 {
+			return ZoneMap{}, base.CorruptionErrorf("cannot decode zone map from %x", prop)
+		}
+		return z, nil
+	}
+	n, l := binary.Uvarint(prop[1:])
+	if l <= 0 || n > uint64(len(prop)-1-l) {
+		return ZoneMap{}, base.CorruptionErrorf("cannot decode zone map from %x", prop)
+	}
+	minEnd := 1 + l + int(n)
+	z.Min, z.Max = prop[1+l:minEnd], prop[minEnd:]
+	return z, nil
+}
+
+// zoneMapBuilder accumulates the zone map of a set of keys.
+type zoneMapBuilder struct {
+	empty    bool
+	all      bool
+	min, max []byte
+}
+
+func (b *zoneMapBuilder) reset() {
+	*b = zoneMapBuilder{empty: true, min: b.min[:0], max: b.max[:0]}
+}
+
+func (b *zoneMapBuilder) add(min, max []byte) {
+	switch {
+	case b.all:
+	case b.empty:
+		b.empty = false
+		b.min = append(b.min[:0], min...)
+		b.max = append(b.max[:0], max...)
+	default:
+		if bytes.Compare(min, b.min) < 0 {
+			b.min = append(b.min[:0], min...)
+		}
+		if bytes.Compare(max, b.max) > 0 {
+			b.max = append(b.max[:0], max...)
+		}
+	}
+}
+
+func (b *zoneMapBuilder) addAll() {
+	b.empty = false
+	b.all = true
+}
+
+func (b *zoneMapBuilder) union(other *zoneMapBuilder) {
+	switch {
+	case other.empty:
+	case other.all:
+		b.addAll()
+	default:
+		b.add(other.min, other.max)
+	}
+}
+
+func (b *zoneMapBuilder) encode(typ ZoneMapFieldType, buf []byte) []byte {
+	switch {
+	case b.empty:
+		return buf
+	case b.all:
+		return append(buf, byte(typ)|zoneMapAllFlag)
+	}
+	buf = append(buf, byte(typ))
+	buf = binary.AppendUvarint(buf, uint64(len(b.min)))
+	buf = append(buf, b.min...)
+	return append(buf, b.max...)
+}
+
+// zoneMapCollector is a BlockPropertyCollector that collects zone maps.
+type zoneMapCollector struct {
+	config ZoneMapConfig
+	block  zoneMapBuilder
+	index  zoneMapBuilder
+	table  zoneMapBuilder
+	// maxBuf is used to build truncated maximums.
+	maxBuf []byte
+}
+
+var _ InPlaceValueBlockPropertyCollector = (*zoneMapCollector)(nil)
+
+// NewZoneMapCollector constructs a BlockPropertyCollector that records the
+// minimum and maximum value of the field described by the config.
+func NewZoneMapCollector(config ZoneMapConfig) BlockPropertyCollector {
+	if config.Extract == nil {
+		panic(errors.AssertionFailedf("zone map %q has no extractor", config.Name))
+	}
+	if config.Type < ZoneMapUint64 || config.Type > ZoneMapBytes {
+		panic(errors.AssertionFailedf("zone map %q has invalid type %s", config.Name, config.Type))
+	}
+	if config.MaxFieldLen <= 0 {
+		config.MaxFieldLen = DefaultZoneMapMaxFieldLen
+	}
+	c := &zoneMapCollector{config: config}
+	c.block.reset()
+	c.index.reset()
+	c.table.reset()
+	return c
+}
+
+// Name is part of the BlockPropertyCollector interface.
+func (c *zoneMapCollector) Name() string {
+	return c.config.Name
+}
+
+// WantsInPlaceValues is part of the InPlaceValueBlockPropertyCollector
+// interface.
+func (c *zoneMapCollector) WantsInPlaceValues() bool {
+	return true
+}
+
+// AddPointKey is part of the BlockPropertyCollector interface.
+func (c *zoneMapCollector) AddPointKey(key InternalKey, value []byte) error {
+	if c.block.all {
+		return nil
+	}
+	field, presence, err := c.config.Extract(key, value)
+	if err != nil {
+		return err
+	}
+	switch presence {
+	case ZoneMapFieldAbsent:
+		switch key.Kind() {
+		case base.InternalKeyKindDelete, base.InternalKeyKindSingleDelete, base.InternalKeyKindDeleteSized:
+			// The tombstone may delete a key with any field value, so the block
+			// must not be excluded by any filter.
+			c.block.addAll()
+		}
+	case ZoneMapFieldUnknown:
+		c.block.addAll()
+	case ZoneMapFieldPresent:
+		if c.config.Type != ZoneMapBytes {
+			if len(field) != 8 {
+				return errors.AssertionFailedf("zone map %q: invalid %s field %x",
+					c.config.Name, c.config.Type, field)
+			}
+			c.block.add(field, field)
+		} else if len(field) <= c.config.MaxFieldLen {
+			c.block.add(field, field)
+		} else {
+			// A prefix of the field is a lower bound of the field, and the
+			// successor of the prefix is an upper bound. If there is no successor
+			// (the prefix is all 0xff), the field is stored untruncated.
+			prefix := field[:c.config.MaxFieldLen]
+			c.maxBuf = append(c.maxBuf[:0], prefix...)
+			if succ := zoneMapPrefixSuccessor(c.maxBuf); succ != nil {
+				c.block.add(prefix, succ)
+			} else {
+				c.block.add(prefix, field)
+			}
+		}
+	default:
+		return errors.AssertionFailedf("zone map %q: invalid field presence %d", c.config.Name, presence)
+	}
+	return nil
+}
+
+// zoneMapPrefixSuccessor modifies buf in place to the shortest key that is
+// greater than all keys with the prefix buf. It returns nil if there is no such
+// key.
+func zoneMapPrefixSuccessor(buf []byte) []byte {
+	for i := len(buf) - 1; i >= 0; i-- {
+		if buf[i] != math.MaxUint8 {
+			buf[i]++
+			return buf[:i+1]
+		}
+	}
+	return nil
+}
+
+// AddRangeKeys is part of the BlockPropertyCollector interface. Zone maps only
+// describe point keys.
+func (c *zoneMapCollector) AddRangeKeys(span Span) error {
+	return nil
+}
+
+// AddCollectedWithSuffixReplacement is part of the BlockPropertyCollector
+// interface.
+func (c *zoneMapCollector) AddCollectedWithSuffixReplacement(
+	oldProp []byte, oldSuffix, newSuffix []byte,
+) error {
+	return errors.Errorf("zone map %q does not support suffix replacement", c.config.Name)
+}
+
+// SupportsSuffixReplacement is part of the BlockPropertyCollector interface.
+func (c *zoneMapCollector) SupportsSuffixReplacement() bool {
+	return false
+}
+
+// FinishDataBlock is part of the BlockPropertyCollector interface.
+func (c *zoneMapCollector) FinishDataBlock(buf []byte) ([]byte, error) {
+	c.table.union(&c.block)
+	return c.block.encode(c.config.Type, buf), nil
+}
+
+// AddPrevDataBlockToIndexBlock is part of the BlockPropertyCollector interface.
+func (c *zoneMapCollector) AddPrevDataBlockToIndexBlock() {
+	c.index.union(&c.block)
+	c.block.reset()
+}
+
+// FinishIndexBlock is part of the BlockPropertyCollector interface.
+func (c *zoneMapCollector) FinishIndexBlock(buf []byte) ([]byte, error) {
+	buf = c.index.encode(c.config.Type, buf)
+	c.index.reset()
+	return buf, nil
+}
+
+// FinishTable is part of the BlockPropertyCollector interface.
+func (c *zoneMapCollector) FinishTable(buf []byte) ([]byte, error) {
+	return c.table.encode(c.config.Type, buf), nil
+}
+
+// ZoneMapFilter is a BlockPropertyFilter that filters blocks based on a zone
+// map collected by NewZoneMapCollector, excluding the blocks that contain no
+// field values within [lower, upper).
+type ZoneMapFilter struct {
+	name         string
+	typ          ZoneMapFieldType
+	lower, upper []byte
+}
+
+var _ BlockPropertyFilter = (*ZoneMapFilter)(nil)
+
+// NewZoneMapFilter constructs a ZoneMapFilter for the zone map with the given
+// name and type. The bounds are encoded fields (see EncodeZoneMapUint64 and
+// EncodeZoneMapInt64); a nil bound is unbounded.
+func NewZoneMapFilter(name string, typ ZoneMapFieldType, lower, upper []byte) *ZoneMapFilter {
+	return &ZoneMapFilter{name: name, typ: typ, lower: lower, upper: upper}
+}
+
+// NewUint64ZoneMapFilter constructs a ZoneMapFilter for a ZoneMapUint64 zone
+// map with the given name and [lower, upper) bounds.
+func NewUint64ZoneMapFilter(name string, lower, upper uint64) *ZoneMapFilter {
+	return NewZoneMapFilter(name, ZoneMapUint64, EncodeZoneMapUint64(lower), EncodeZoneMapUint64(upper))
+}
+
+// NewInt64ZoneMapFilter constructs a ZoneMapFilter for a ZoneMapInt64 zone
+// map with the given name and [lower, upper) bounds.
+func NewInt64ZoneMapFilter(name string, lower, upper int64) *ZoneMapFilter {
+	return NewZoneMapFilter(name, ZoneMapInt64, EncodeZoneMapInt64(lower), EncodeZoneMapInt64(upper))
+}
+
+// Name implements the BlockPropertyFilter interface.
+func (f *ZoneMapFilter) Name() string {
+	return f.name
+}
+
+// Intersects implements the BlockPropertyFilter interface.
+func (f *ZoneMapFilter) Intersects(prop []byte) (bool, error) {
+	z, err := DecodeZoneMap(prop)
+	if err != nil {
+		return false, err
+	}
+	if !z.IsEmpty() && z.Type != f.typ {
+		return false, errors.Newf("zone map %q has type %s; filter expects %s", f.name, z.Type, f.typ)
+	}
+	return z.Intersects(f.lower, f.upper), nil
+}
+
+// SyntheticSuffixIntersects implements the BlockPropertyFilter interface. The
+// zone map may not describe the keys with the synthetic suffix, so the block
+// is never excluded.
+func (f *ZoneMapFilter) SyntheticSuffixIntersects(prop []byte, suffix []byte) (bool, error) {
+	return true, nil
+}
+
+// String implements fmt.Stringer.
+func (f *ZoneMapFilter) String() string {
+	bound := func(b []byte, unbounded string) string {
+		if b == nil {
+			return unbounded
+		}
+		return f.typ.FormatField(b)
+	}
+	return fmt.Sprintf("%s(%s): [%s, %s)", f.name, f.typ, bound(f.lower, "-inf"), bound(f.upper, "+inf"))
+}
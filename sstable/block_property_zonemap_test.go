@@ -0,0 +1,278 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package sstable
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/cockroachdb/crlib/testutils/leaktest"
+	"github.com/cockroachdb/pebble/internal/base"
+	"github.com/cockroachdb/pebble/internal/keyspan"
+	"github.com/cockroachdb/pebble/internal/testkeys"
+	"github.com/cockroachdb/pebble/objstorage/objstorageprovider"
+	"github.com/cockroachdb/pebble/sstable/block"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZoneMapFieldEncoding(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	uints := []uint64{0, 1, 255, 256, math.MaxUint32, math.MaxUint64}
+	for i := range uints {
+		require.Equal(t, uints[i], DecodeZoneMapUint64(EncodeZoneMapUint64(uints[i])))
+		if i > 0 {
+			require.Negative(t, bytes.Compare(EncodeZoneMapUint64(uints[i-1]), EncodeZoneMapUint64(uints[i])))
+		}
+	}
+	ints := []int64{math.MinInt64, -256, -1, 0, 1, 256, math.MaxInt64}
+	for i := range ints {
+		require.Equal(t, ints[i], DecodeZoneMapInt64(EncodeZoneMapInt64(ints[i])))
+		if i > 0 {
+			require.Negative(t, bytes.Compare(EncodeZoneMapInt64(ints[i-1]), EncodeZoneMapInt64(ints[i])))
+		}
+	}
+	for _, typ := range []ZoneMapFieldType{ZoneMapUint64, ZoneMapInt64, ZoneMapBytes} {
+		parsed, err := ParseZoneMapFieldType(typ.String())
+		require.NoError(t, err)
+		require.Equal(t, typ, parsed)
+	}
+	_, err := ParseZoneMapFieldType("float")
+	require.Error(t, err)
+
+	// Corrupt zone maps.
+	for _, prop := range [][]byte{{0x00}, {0x04}, {0x81, 0x00}, {0x01}, {0x01, 0x05, 'a'}} {
+		_, err := DecodeZoneMap(prop)
+		require.Error(t, err, "%x", prop)
+	}
+}
+
+// zoneMapTestExtractor extracts the uint64 field from values of the form
+// "<n>" or "<n>:<payload>". Values starting with '?' and values that are not
+// available have an unknown field, and other values have no field.
+func zoneMapTestExtractor(key InternalKey, value []byte) ([]byte, ZoneMapFieldPresence, error) {
+	if value == nil || bytes.HasPrefix(value, []byte("?")) {
+		return nil, ZoneMapFieldUnknown, nil
+	}
+	n, _, _ := bytes.Cut(value, []byte(":"))
+	v, err := strconv.ParseUint(string(n), 10, 64)
+	if err != nil {
+		return nil, ZoneMapFieldAbsent, nil
+	}
+	return EncodeZoneMapUint64(v), ZoneMapFieldPresent, nil
+}
+
+func TestZoneMapCollector(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	c := NewZoneMapCollector(ZoneMapConfig{
+		Name:    "zm",
+		Type:    ZoneMapUint64,
+		Extract: zoneMapTestExtractor,
+	})
+	require.Equal(t, "zm", c.Name())
+	require.False(t, c.SupportsSuffixReplacement())
+	addKind := func(kind base.InternalKeyKind, values ...string) {
+		for _, v := range values {
+			require.NoError(t, c.AddPointKey(base.MakeInternalKey([]byte("k"), 1, kind), []byte(v)))
+		}
+	}
+	add := func(values ...string) {
+		addKind(base.InternalKeyKindSet, values...)
+	}
+	check := func(prop []byte, expected string) {
+		t.Helper()
+		z, err := DecodeZoneMap(prop)
+		require.NoError(t, err)
+		require.Equal(t, expected, z.String())
+	}
+	finishDataBlock := func(expected string) {
+		t.Helper()
+		prop, err := c.FinishDataBlock(nil)
+		require.NoError(t, err)
+		check(prop, expected)
+		c.AddPrevDataBlockToIndexBlock()
+	}
+	finishIndexBlock := func(expected string) {
+		t.Helper()
+		prop, err := c.FinishIndexBlock(nil)
+		require.NoError(t, err)
+		check(prop, expected)
+	}
+
+	// Keys without the field and range keys don't contribute to zone maps.
+	add("foo", "")
+	require.NoError(t, c.AddRangeKeys(keyspan.Span{}))
+	finishDataBlock("empty")
+	add("15", "7:x", "42", "none")
+	finishDataBlock("uint64: [7, 42]")
+	finishIndexBlock("uint64: [7, 42]")
+	add("100", "?", "3")
+	finishDataBlock("uint64: all")
+	finishIndexBlock("uint64: all")
+	add("1000")
+	finishDataBlock("uint64: [1000, 1000]")
+	finishIndexBlock("uint64: [1000, 1000]")
+	// Point tombstones without the field may delete keys with any field value,
+	// so the blocks that contain them match all filters.
+	for _, kind := range []base.InternalKeyKind{
+		base.InternalKeyKindDelete, base.InternalKeyKindSingleDelete, base.InternalKeyKindDeleteSized,
+	} {
+		addKind(kind, "")
+		finishDataBlock("uint64: all")
+	}
+	add("5")
+	addKind(base.InternalKeyKindDelete, "")
+	finishDataBlock("uint64: all")
+	// A tombstone with the field contributes it like any other key.
+	addKind(base.InternalKeyKindDelete, "9")
+	finishDataBlock("uint64: [9, 9]")
+	finishIndexBlock("uint64: all")
+	prop, err := c.FinishTable(nil)
+	require.NoError(t, err)
+	check(prop, "uint64: all")
+
+	// Long bytes fields are truncated.
+	c = NewZoneMapCollector(ZoneMapConfig{
+		Name: "zm",
+		Type: ZoneMapBytes,
+		Extract: func(key InternalKey, value []byte) ([]byte, ZoneMapFieldPresence, error) {
+			return value, ZoneMapFieldPresent, nil
+		},
+		MaxFieldLen: 4,
+	})
+	add("banana", "cherry", "apple")
+	finishDataBlock(`bytes: ["appl", "ches"]`)
+	add("zz\xffzz", "mango")
+	finishDataBlock(`bytes: ["mang", "zz\xff{"]`)
+	// The maximum is not truncated if the prefix has no successor.
+	add("\xff\xff\xff\xffz")
+	finishDataBlock(`bytes: ["\xff\xff\xff\xff", "\xff\xff\xff\xffz"]`)
+	finishIndexBlock(`bytes: ["appl", "\xff\xff\xff\xffz"]`)
+}
+
+func TestZoneMapFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	prop := func(values ...uint64) []byte {
+		c := NewZoneMapCollector(ZoneMapConfig{Name: "zm", Type: ZoneMapUint64, Extract: zoneMapTestExtractor})
+		for _, v := range values {
+			require.NoError(t, c.AddPointKey(base.InternalKey{}, strconv.AppendUint(nil, v, 10)))
+		}
+		p, err := c.FinishDataBlock(nil)
+		require.NoError(t, err)
+		return p
+	}
+	testCases := []struct {
+		filter     *ZoneMapFilter
+		prop       []byte
+		intersects bool
+	}{
+		{filter: NewUint64ZoneMapFilter("zm", 10, 20), prop: prop(), intersects: false},
+		{filter: NewUint64ZoneMapFilter("zm", 10, 20), prop: prop(5, 9), intersects: false},
+		{filter: NewUint64ZoneMapFilter("zm", 10, 20), prop: prop(5, 10), intersects: true},
+		{filter: NewUint64ZoneMapFilter("zm", 10, 20), prop: prop(19, 25), intersects: true},
+		{filter: NewUint64ZoneMapFilter("zm", 10, 20), prop: prop(20, 25), intersects: false},
+		{filter: NewUint64ZoneMapFilter("zm", 10, 20), prop: prop(5, 25), intersects: true},
+		{filter: NewZoneMapFilter("zm", ZoneMapUint64, nil, EncodeZoneMapUint64(10)), prop: prop(0), intersects: true},
+		{filter: NewZoneMapFilter("zm", ZoneMapUint64, EncodeZoneMapUint64(10), nil), prop: prop(math.MaxUint64), intersects: true},
+		{filter: NewZoneMapFilter("zm", ZoneMapUint64, EncodeZoneMapUint64(10), nil), prop: prop(9), intersects: false},
+		{filter: NewUint64ZoneMapFilter("zm", 10, 20), prop: []byte{byte(ZoneMapUint64) | zoneMapAllFlag}, intersects: true},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s/%x", tc.filter, tc.prop), func(t *testing.T) {
+			intersects, err := tc.filter.Intersects(tc.prop)
+			require.NoError(t, err)
+			require.Equal(t, tc.intersects, intersects)
+			// Synthetic suffixes are conservatively assumed to intersect.
+			intersects, err = tc.filter.SyntheticSuffixIntersects(tc.prop, []byte("@1"))
+			require.NoError(t, err)
+			require.True(t, intersects)
+		})
+	}
+
+	// A filter with a different type results in an error.
+	_, err := NewInt64ZoneMapFilter("zm", 10, 20).Intersects(prop(15))
+	require.Error(t, err)
+}
+
+func TestZoneMapFilterIter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	const numKeys = 1000
+	for _, tableFormat := range []TableFormat{TableFormatPebblev4, TableFormatMax} {
+		t.Run(tableFormat.String(), func(t *testing.T) {
+			fs := vfs.NewMem()
+			f, err := fs.Create("test", vfs.WriteCategoryUnspecified)
+			require.NoError(t, err)
+			w := NewWriter(objstorageprovider.NewFileWritable(f), WriterOptions{
+				Comparer:       testkeys.Comparer,
+				KeySchema:      &testkeysSchema,
+				TableFormat:    tableFormat,
+				BlockSize:      256,
+				IndexBlockSize: 256,
+				BlockPropertyCollectors: []func() BlockPropertyCollector{
+					func() BlockPropertyCollector {
+						return NewZoneMapCollector(ZoneMapConfig{Name: "zm", Type: ZoneMapUint64, Extract: zoneMapTestExtractor})
+					},
+				},
+			})
+			// The field increases with the key, so the zone maps of the blocks
+			// don't overlap.
+			for i := 0; i < numKeys; i++ {
+				require.NoError(t, w.Set(fmt.Appendf(nil, "k%05d", i), fmt.Appendf(nil, "%d:payload", 10*i)))
+			}
+			require.NoError(t, w.Close())
+
+			f, err = fs.Open("test")
+			require.NoError(t, err)
+			r, err := newReader(f, ReaderOptions{
+				Comparer:   testkeys.Comparer,
+				KeySchemas: MakeKeySchemas(&testkeysSchema),
+			})
+			require.NoError(t, err)
+			defer func() { require.NoError(t, r.Close()) }()
+
+			scan := func(filter BlockPropertyFilter) (keys int, blocks uint64) {
+				filterer, err := IntersectsTable([]BlockPropertyFilter{filter}, nil, r.UserProperties, nil)
+				require.NoError(t, err)
+				if filterer == nil {
+					return 0, 0
+				}
+				var stats base.InternalIteratorStats
+				iter, err := r.NewPointIter(context.Background(), IterOptions{
+					Transforms:           NoTransforms,
+					Filterer:             filterer,
+					FilterBlockSizeLimit: NeverUseFilterBlock,
+					Env:                  ReadEnv{Block: block.ReadEnv{Stats: &stats}},
+					ReaderProvider:       MakeTrivialReaderProvider(r),
+					BlobContext:          AssertNoBlobHandles,
+				})
+				require.NoError(t, err)
+				for kv := iter.First(); kv != nil; kv = iter.Next() {
+					v, err := strconv.ParseUint(string(bytes.TrimSuffix(kv.InPlaceValue(), []byte(":payload"))), 10, 64)
+					require.NoError(t, err)
+					if v >= 5000 && v < 5100 {
+						keys++
+					}
+				}
+				require.NoError(t, iter.Close())
+				return keys, stats.TotalBlockReads().Count
+			}
+			keys, allBlocks := scan(NewUint64ZoneMapFilter("zm", 0, math.MaxUint64))
+			require.Equal(t, 10, keys)
+			// All the keys within the range are surfaced, and fewer blocks are
+			// read.
+			keys, blocks := scan(NewUint64ZoneMapFilter("zm", 5000, 5100))
+			require.Equal(t, 10, keys)
+			require.Less(t, blocks, allBlocks/10)
+			// The table-level zone map excludes the whole table.
+			keys, blocks = scan(NewUint64ZoneMapFilter("zm", 10*numKeys, math.MaxUint64))
+			require.Zero(t, keys)
+			require.Zero(t, blocks)
+		})
+	}
+}
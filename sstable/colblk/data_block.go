@@ -499,6 +499,9 @@ type DataBlockEncoder struct {
 	// For dual-tier blob values, the cold tier handle is placed here, so that normal
 	// accesses should use the cheaper access path of the primary blob handle.
 	secondaryBlobHandles RawBytesBuilder
+	// valueColumns holds the value columns; only used if columnConfig has a
+	// ValueSchema.
+	valueColumns valueColumnsBuilder
 
 	enc              BlockEncoder
 	rows             int
@@ -579,12 +582,25 @@ type OptionalColumnConfig struct {
 	//     accomplish this with the start key of the policy.
 	HasColumnSpanID    bool
 	HasColumnAttribute bool
+
+	// ValueSchema, if set, configures the storage of structured values in
+	// value columns, which follow all other columns. See ValueSchema.
+	ValueSchema *ValueSchema
 }
 
 func (c *OptionalColumnConfig) SupportsTiering() bool {
 	return c.HasColumnSpanID && c.HasColumnAttribute
 }
 
+// firstValueColumn returns the index of the first value column, relative to
+// the number of key schema columns.
+func (c *OptionalColumnConfig) firstValueColumn() int {
+	if c.SupportsTiering() {
+		return dataBlockColumnMaxV2
+	}
+	return dataBlockColumnMaxV1
+}
+
 // NoTieringColumns returns a TieringColumnConfig with no tiering columns present.
 // Useful for tests and older table formats that don't support tiering metadata.
 func NoTieringColumns() OptionalColumnConfig {
@@ -621,6 +637,9 @@ func (w *DataBlockEncoder) Init(schema *KeySchema, optionalColConfig OptionalCol
 		w.tieringAttributes.InitWithDefault()
 		w.secondaryBlobHandles.Init()
 	}
+	if optionalColConfig.ValueSchema != nil {
+		w.valueColumns.init(optionalColConfig.ValueSchema)
+	}
 	w.rows = 0
 	w.maximumKeyLength = 0
 	w.lastUserKeyTmp = w.lastUserKeyTmp[:0]
@@ -640,6 +659,9 @@ func (w *DataBlockEncoder) Reset() {
 		w.tieringAttributes.Reset()
 		w.secondaryBlobHandles.Reset()
 	}
+	if w.columnConfig.ValueSchema != nil {
+		w.valueColumns.reset()
+	}
 	w.rows = 0
 	w.maximumKeyLength = 0
 	w.lastUserKeyTmp = w.lastUserKeyTmp[:0]
@@ -686,6 +708,9 @@ func (w *DataBlockEncoder) String() string {
 		w.secondaryBlobHandles.WriteDebug(&buf, w.rows)
 		fmt.Fprintln(&buf)
 	}
+	if w.columnConfig.ValueSchema != nil {
+		w.valueColumns.writeDebug(&buf, len(w.Schema.ColumnTypes)+w.columnConfig.firstValueColumn(), w.rows)
+	}
 
 	return buf.String()
 }
@@ -745,6 +770,12 @@ func (w *DataBlockEncoder) internalAdd(
 		// Write the value with the value prefix byte preceding the value.
 		w.valuePrefixTmp[0] = byte(valuePrefix)
 		w.values.PutConcat(w.valuePrefixTmp[:], value)
+		if w.columnConfig.ValueSchema != nil {
+			w.valueColumns.add(w.rows, nil, false /* isInPlace */)
+		}
+	} else if w.columnConfig.ValueSchema != nil && w.valueColumns.add(w.rows, value, true /* isInPlace */) {
+		// The value is stored in the value columns.
+		w.values.Put(nil)
 	} else {
 		// Elide the value prefix. Readers will examine the isValueExternal
 		// bitmap and know there is no value prefix byte if !isValueExternal.
@@ -784,15 +815,21 @@ func (w *DataBlockEncoder) Size() int {
 		off = w.tieringAttributes.Size(w.rows, off)
 		off = w.secondaryBlobHandles.Size(w.rows, off)
 	}
+	if w.columnConfig.ValueSchema != nil {
+		off = w.valueColumns.size(w.rows, off)
+	}
 	off++ // trailer padding byte
 	return int(off)
 }
 
+// numFormatColumns returns the number of columns that follow the key schema
+// columns.
 func (w *DataBlockEncoder) numFormatColumns() int {
-	if w.columnConfig.SupportsTiering() {
-		return dataBlockColumnMaxV2
+	n := w.columnConfig.firstValueColumn()
+	if w.columnConfig.ValueSchema != nil {
+		n += w.valueColumns.numColumns()
 	}
-	return dataBlockColumnMaxV1
+	return n
 }
 
 // MaterializeLastUserKey materializes the last added user key.
@@ -842,6 +879,9 @@ func (w *DataBlockEncoder) Finish(rows, size int) (finished []byte, lastKey base
 		w.enc.Encode(rows, &w.tieringAttributes)
 		w.enc.Encode(rows, &w.secondaryBlobHandles)
 	}
+	if w.columnConfig.ValueSchema != nil {
+		w.valueColumns.encode(&w.enc, rows)
+	}
 	finished = w.enc.Finish()
 
 	w.lastUserKeyTmp = w.lastUserKeyTmp[:0]
@@ -855,8 +895,8 @@ func (w *DataBlockEncoder) Finish(rows, size int) (finished []byte, lastKey base
 
 // DataBlockRewriter rewrites data blocks. See RewriteSuffixes.
 type DataBlockRewriter struct {
-	tieringColConfig *OptionalColumnConfig
-	KeySchema        *KeySchema
+	columnConfig *OptionalColumnConfig
+	KeySchema    *KeySchema
 
 	encoder   DataBlockEncoder
 	decoder   DataBlockDecoder
@@ -870,14 +910,15 @@ type DataBlockRewriter struct {
 	initialized     bool
 }
 
-// NewDataBlockRewriter creates a block rewriter.
+// NewDataBlockRewriter creates a block rewriter. The column config describes
+// the optional columns (tiering and value columns) of the blocks to rewrite.
 func NewDataBlockRewriter(
-	keySchema *KeySchema, comparer *base.Comparer, tieringColConfig OptionalColumnConfig,
+	keySchema *KeySchema, comparer *base.Comparer, columnConfig OptionalColumnConfig,
 ) *DataBlockRewriter {
 	return &DataBlockRewriter{
-		tieringColConfig: &tieringColConfig,
-		KeySchema:        keySchema,
-		comparer:         comparer,
+		columnConfig: &columnConfig,
+		KeySchema:    keySchema,
+		comparer:     comparer,
 	}
 }
 
@@ -908,8 +949,8 @@ func (rw *DataBlockRewriter) RewriteSuffixes(
 	input []byte, from []byte, to []byte,
 ) (start, end base.InternalKey, rewritten []byte, err error) {
 	if !rw.initialized {
-		rw.iter.InitOnce(rw.KeySchema, rw.comparer, assertNoExternalValues{}, *rw.tieringColConfig)
-		rw.encoder.Init(rw.KeySchema, *rw.tieringColConfig)
+		rw.iter.InitOnce(rw.KeySchema, rw.comparer, assertNoExternalValues{}, *rw.columnConfig)
+		rw.encoder.Init(rw.KeySchema, *rw.columnConfig)
 		rw.initialized = true
 	}
 
@@ -939,7 +980,7 @@ func (rw *DataBlockRewriter) RewriteSuffixes(
 	rw.KeySchema.InitKeySeekerMetadata(meta, &rw.decoder, bd)
 	rw.keySeeker = rw.KeySchema.KeySeeker(meta)
 	rw.encoder.Reset()
-	if err = rw.iter.Init(&rw.decoder, bd, blockiter.Transforms{}, *rw.tieringColConfig); err != nil {
+	if err = rw.iter.Init(&rw.decoder, bd, blockiter.Transforms{}, *rw.columnConfig); err != nil {
 		return base.InternalKey{}, base.InternalKey{}, nil, err
 	}
 
@@ -975,7 +1016,7 @@ func (rw *DataBlockRewriter) RewriteSuffixes(
 		}
 		k := base.InternalKey{UserKey: rw.keyBuf, Trailer: kv.K.Trailer}
 		var meta base.KVMeta
-		if rw.tieringColConfig.SupportsTiering() {
+		if rw.columnConfig.SupportsTiering() {
 			meta = rw.iter.decodeMeta()
 		}
 		rw.encoder.Add(k, value, valuePrefix, kcmp, rw.decoder.isObsolete.At(i), meta)
@@ -1211,9 +1252,20 @@ type DataBlockIter struct {
 	// getLazyValuer configures the DataBlockIterConfig to initialize the
 	// DataBlockIter to use the provided handler for retrieving lazy values.
 	getLazyValuer block.GetInternalValueForPrefixAndValueHandler
-	// tieringConfig indicates whether tiering columns are present in blocks.
-	// This is set once in InitOnce.
-	tieringConfig OptionalColumnConfig
+	// columnConfig indicates whether tiering and value columns are present in
+	// blocks. This is set once in InitOnce.
+	columnConfig OptionalColumnConfig
+	// valueProjection is the projection used for values stored in value
+	// columns.
+	valueProjection ValueProjection
+	// pointKeyPredicate, if set, is used to skip point keys that don't match
+	// the predicate. Skipped keys are counted in stats (if non-nil).
+	pointKeyPredicate base.PointKeyPredicate
+	stats             *base.InternalIteratorStats
+	// skippedUserKey is a buffer holding the user key of the last point key
+	// that didn't match pointKeyPredicate, used to skip the other versions of
+	// the user key along with it.
+	skippedUserKey []byte
 
 	// -- Fields that are initialized for each block --
 	// For any changes to these fields, InitHandle should be updated.
@@ -1238,6 +1290,12 @@ type DataBlockIter struct {
 	// decoding. This is only set when Init() is called (not InitHandle()).
 	blockData []byte
 
+	// -- Value columns (lazily initialized) --
+	// The value columns are decoded on-demand when a value stored in value
+	// columns is first read from the current block.
+	valueColumns            valueColumnsDecoder
+	valueColumnsInitialized bool
+
 	// -- State --
 	// For any changes to these fields, InitHandle (which resets them) should be
 	// updated.
@@ -1266,6 +1324,15 @@ type DataBlockIter struct {
 	// prefixChanged.SeekSetBitGE.
 	prefixCacheStart int
 	nextPrefixChange int
+
+	// [acceptedRunStart, acceptedRunEnd] describe a range of rows with the same
+	// user key that are all accepted by the point key predicate (see
+	// filterBackward). It allows Prev to step through the versions of a user key
+	// without re-evaluating the newer versions at every step. Like the prefix
+	// cache above, it is a property of the block and the predicate, and only
+	// needs to be reset when either changes.
+	acceptedRunStart int
+	acceptedRunEnd   int
 }
 
 // InitOnce configures the data block iterator's key schema and lazy value
@@ -1275,23 +1342,46 @@ func (i *DataBlockIter) InitOnce(
 	keySchema *KeySchema,
 	comparer *base.Comparer,
 	getLazyValuer block.GetInternalValueForPrefixAndValueHandler,
-	tieringConfig OptionalColumnConfig,
+	columnConfig OptionalColumnConfig,
 ) {
 	i.keySchema = keySchema
 	i.suffixCmp = comparer.ComparePointSuffixes
 	i.split = comparer.Split
 	i.getLazyValuer = getLazyValuer
-	i.tieringConfig = tieringConfig
+	i.columnConfig = columnConfig
+	i.valueProjection = ValueProjection{}
+	i.pointKeyPredicate = nil
+	i.stats = nil
+}
+
+// SetValueProjection configures the value columns that are decoded for values
+// stored in value columns (see ValueSchema). It must be called after InitOnce.
+func (i *DataBlockIter) SetValueProjection(p ValueProjection) {
+	i.valueProjection = p
+}
+
+// SetPointKeyPredicate configures the iterator to skip SET and SETWITHDEL
+// keys that don't match the given predicate (which can be nil), along with
+// the versions of the same user key that they shadow within the block.
+// Skipped keys are counted in stats.PointsFilteredByPredicate, if stats is
+// non-nil. It must be called after InitOnce.
+func (i *DataBlockIter) SetPointKeyPredicate(
+	p base.PointKeyPredicate, stats *base.InternalIteratorStats,
+) {
+	i.pointKeyPredicate = p
+	i.stats = stats
+	i.acceptedRunStart = -1
+	i.acceptedRunEnd = -1
 }
 
 // Init initializes the data block iterator, configuring it to read from the
-// provided decoder. The tieringConfig parameter indicates whether the block
-// contains tiering columns that should be decoded lazily.
+// provided decoder. The columnConfig parameter indicates whether the block
+// contains tiering or value columns that should be decoded lazily.
 func (i *DataBlockIter) Init(
 	d *DataBlockDecoder,
 	bd BlockDecoder,
 	transforms blockiter.Transforms,
-	tieringConfig OptionalColumnConfig,
+	columnConfig OptionalColumnConfig,
 ) error {
 	i.d = d
 	// Leave i.h unchanged.
@@ -1318,12 +1408,15 @@ func (i *DataBlockIter) Init(
 	i.nextObsoletePoint = 0
 	i.prefixCacheStart = -1
 	i.nextPrefixChange = -1
+	i.acceptedRunStart = -1
+	i.acceptedRunEnd = -1
 
-	// Reset tiering state for lazy initialization.
-	i.tieringConfig = tieringConfig
+	// Reset tiering and value column state for lazy initialization.
+	i.columnConfig = columnConfig
 	i.tieringSpanIDs = UnsafeUints{}
 	i.tieringAttributes = UnsafeUints{}
-	// Store block data for lazy tiering column decoding.
+	i.valueColumnsInitialized = false
+	// Store block data for lazy tiering and value column decoding.
 	i.blockData = bd.Data()
 	return nil
 }
@@ -1359,12 +1452,15 @@ func (i *DataBlockIter) InitHandle(
 	i.nextObsoletePoint = 0
 	i.prefixCacheStart = -1
 	i.nextPrefixChange = -1
+	i.acceptedRunStart = -1
+	i.acceptedRunEnd = -1
 	i.keySeeker = i.keySchema.KeySeeker(keySeekerMeta)
 
-	// Reset tiering state for lazy initialization. Block data will be obtained
-	// from h.BlockData() when needed.
+	// Reset tiering and value column state for lazy initialization. Block data
+	// will be obtained from h.BlockData() when needed.
 	i.tieringSpanIDs = UnsafeUints{}
 	i.tieringAttributes = UnsafeUints{}
+	i.valueColumnsInitialized = false
 	i.blockData = nil
 	return nil
 }
@@ -1475,7 +1571,7 @@ func (i *DataBlockIter) SeekGE(key []byte, flags base.SeekGEFlags) *base.Interna
 	if i.noTransforms {
 		// Fast path.
 		i.row, _ = i.keySeeker.SeekGE(key, i.row, searchDir)
-		return i.decodeRow()
+		return i.filterForward(i.decodeRow())
 	}
 	i.row, _ = i.seekGEInternal(key, i.row, searchDir)
 	if i.transforms.HideObsoletePoints {
@@ -1487,7 +1583,7 @@ func (i *DataBlockIter) SeekGE(key []byte, flags base.SeekGEFlags) *base.Interna
 			}
 		}
 	}
-	return i.decodeRow()
+	return i.filterForward(i.decodeRow())
 }
 
 // SeekPrefixGE implements the blockiter.Data interface. It positions the
@@ -1532,7 +1628,19 @@ func (i *DataBlockIter) SeekPrefixGE(
 	if !equalPrefix {
 		return nil, true
 	}
-	return i.decodeRow(), false
+	kv = i.decodeRow()
+	if i.pointKeyPredicate != nil && !i.matchesPredicate(kv) {
+		startRow := i.row
+		if kv = i.skipUnmatchedForward(kv); kv == nil {
+			return nil, false
+		}
+		// If skipping crossed a prefix boundary, the resulting row has a
+		// different prefix from the seek key.
+		if i.d.prefixChanged.SeekSetBitGE(startRow+1) <= i.row {
+			return nil, true
+		}
+	}
+	return kv, false
 }
 
 // SeekLT implements the blockiter.Data interface.
@@ -1551,7 +1659,7 @@ func (i *DataBlockIter) SeekLT(key []byte, _ base.SeekLTFlags) *base.InternalKV
 			}
 		}
 	}
-	return i.decodeRow()
+	return i.filterBackward(i.decodeRow())
 }
 
 // First implements the base.InternalIterator interface.
@@ -1569,7 +1677,7 @@ func (i *DataBlockIter) First() *base.InternalKV {
 			}
 		}
 	}
-	return i.decodeRow()
+	return i.filterForward(i.decodeRow())
 }
 
 // FirstWithMeta implements the base.MetaIterator interface.
@@ -1605,7 +1713,7 @@ func (i *DataBlockIter) SeekGEWithMeta(
 // been decoded yet for the current block, returning true if they are initialized
 // or have already been decoded.
 func (i *DataBlockIter) initTieringMetadata() bool {
-	if !i.tieringConfig.SupportsTiering() {
+	if !i.columnConfig.SupportsTiering() {
 		return false
 	}
 	if i.tieringSpanIDs.ptr != nil {
@@ -1631,6 +1739,29 @@ func (i *DataBlockIter) initTieringMetadata() bool {
 	return true
 }
 
+// valueFromColumns returns the value of the current row if it is stored in the
+// value columns, composed from the projected columns. Otherwise, the row's
+// value is empty and valueFromColumns returns nil. The returned value is only
+// valid until the next positioning operation.
+func (i *DataBlockIter) valueFromColumns() []byte {
+	if !i.valueColumnsInitialized {
+		// Get block data from either the stored blockData (Init path) or the
+		// buffer handle (InitHandle path).
+		data := i.blockData
+		if data == nil {
+			data = i.h.BlockData()
+		}
+		bd := DecodeBlock(data, DataBlockCustomHeaderSize+i.keySchema.HeaderSize)
+		i.valueColumns.init(i.columnConfig.ValueSchema, &bd,
+			len(i.keySchema.ColumnTypes)+i.columnConfig.firstValueColumn())
+		i.valueColumnsInitialized = true
+	}
+	if !i.valueColumns.isColumnar(i.row) {
+		return nil
+	}
+	return i.valueColumns.compose(i.row, i.valueProjection)
+}
+
 // decodeMeta extracts the KVMeta for the current row.
 func (i *DataBlockIter) decodeMeta() base.KVMeta {
 	if !i.initTieringMetadata() {
@@ -1657,11 +1788,21 @@ func (i *DataBlockIter) Last() *base.InternalKV {
 			}
 		}
 	}
-	return i.decodeRow()
+	return i.filterBackward(i.decodeRow())
 }
 
 // Next advances to the next KV pair in the block.
 func (i *DataBlockIter) Next() *base.InternalKV {
+	kv := i.next()
+	if i.pointKeyPredicate != nil && kv != nil && !i.matchesPredicate(kv) {
+		kv = i.skipUnmatchedForward(kv)
+	}
+	return kv
+}
+
+// next advances to the next KV pair in the block, ignoring the point key
+// predicate.
+func (i *DataBlockIter) next() *base.InternalKV {
 	if i.d == nil {
 		return nil
 	}
@@ -1702,8 +1843,10 @@ func (i *DataBlockIter) Next() *base.InternalKV {
 	v := i.d.values.Slice(i.d.values.offsets.At2(i.row))
 	if i.d.isValueExternal.At(i.row) {
 		i.kv.V = i.getLazyValuer.GetInternalValueForPrefixAndValueHandle(v)
-	} else {
+	} else if i.columnConfig.ValueSchema == nil || len(v) > 0 {
 		i.kv.V = base.MakeInPlaceValue(v)
+	} else {
+		i.kv.V = base.MakeInPlaceValue(i.valueFromColumns())
 	}
 	i.kvRow = i.row
 	return &i.kv
@@ -1716,6 +1859,24 @@ func (i *DataBlockIter) Next() *base.InternalKV {
 // first row with the new (different) prefix. The kv is not materialized; a
 // subsequent KV() call materializes it lazily.
 func (i *DataBlockIter) NextWithSamePrefix() (kv *base.InternalKV, prefixExhausted bool) {
+	kv, prefixExhausted = i.nextWithSamePrefix()
+	for i.pointKeyPredicate != nil && kv != nil && !i.matchesPredicate(kv) {
+		// Skip the older versions of kv's user key (see skipUnmatchedForward),
+		// which share its prefix.
+		i.skippedUserKey = append(i.skippedUserKey[:0], kv.K.UserKey...)
+		for {
+			kv, prefixExhausted = i.nextWithSamePrefix()
+			if kv == nil || !bytes.Equal(kv.K.UserKey, i.skippedUserKey) {
+				break
+			}
+		}
+	}
+	return kv, prefixExhausted
+}
+
+// nextWithSamePrefix implements NextWithSamePrefix, ignoring the point key
+// predicate.
+func (i *DataBlockIter) nextWithSamePrefix() (kv *base.InternalKV, prefixExhausted bool) {
 	// The body is intentionally a copy of Next with extra checks against the
 	// (prefixCacheStart, nextPrefixChange) cache (rather than refactored into
 	// a shared helper) so the hot path stays tight.
@@ -1778,8 +1939,10 @@ func (i *DataBlockIter) NextWithSamePrefix() (kv *base.InternalKV, prefixExhaust
 	v := i.d.values.Slice(i.d.values.offsets.At2(i.row))
 	if i.d.isValueExternal.At(i.row) {
 		i.kv.V = i.getLazyValuer.GetInternalValueForPrefixAndValueHandle(v)
-	} else {
+	} else if i.columnConfig.ValueSchema == nil || len(v) > 0 {
 		i.kv.V = base.MakeInPlaceValue(v)
+	} else {
+		i.kv.V = base.MakeInPlaceValue(i.valueFromColumns())
 	}
 	i.kvRow = i.row
 	return &i.kv, false
@@ -1820,11 +1983,17 @@ func (i *DataBlockIter) NextPrefix(_ []byte) *base.InternalKV {
 		}
 	}
 
-	return i.decodeRow()
+	return i.filterForward(i.decodeRow())
 }
 
 // Prev moves the iterator to the previous KV pair in the block.
 func (i *DataBlockIter) Prev() *base.InternalKV {
+	return i.filterBackward(i.prev())
+}
+
+// prev moves the iterator to the previous KV pair in the block, ignoring the
+// point key predicate.
+func (i *DataBlockIter) prev() *base.InternalKV {
 	if i.d == nil {
 		return nil
 	}
@@ -1838,6 +2007,118 @@ func (i *DataBlockIter) Prev() *base.InternalKV {
 	return i.decodeRow()
 }
 
+// filterForward returns kv if it is nil or matches the point key predicate;
+// otherwise it advances to the next KV pair that matches.
+func (i *DataBlockIter) filterForward(kv *base.InternalKV) *base.InternalKV {
+	if i.pointKeyPredicate == nil || kv == nil || i.matchesPredicate(kv) {
+		return kv
+	}
+	return i.skipUnmatchedForward(kv)
+}
+
+// filterBackward returns kv if it is nil or if it is accepted by the point
+// key predicate; otherwise it moves to the previous KV pair that is accepted.
+//
+// A KV pair is accepted if neither it nor any newer version of its user key
+// within the block is a SET or SETWITHDEL key that doesn't match the predicate
+// (mirroring skipUnmatchedForward). Reverse iteration reaches the oldest
+// version of a user key first, so the newer versions are evaluated along with
+// it.
+func (i *DataBlockIter) filterBackward(kv *base.InternalKV) *base.InternalKV {
+	if i.pointKeyPredicate == nil || kv == nil ||
+		(i.row >= i.acceptedRunStart && i.row <= i.acceptedRunEnd) {
+		return kv
+	}
+	for {
+		if i.d.prefixChanged.At(i.row) {
+			// kv is the first row of its prefix, so the block contains no newer
+			// versions of its user key.
+			if i.matchesPredicate(kv) {
+				return kv
+			}
+			if kv = i.prev(); kv == nil {
+				return nil
+			}
+			continue
+		}
+		// Step through the versions of kv's user key, from oldest to newest.
+		// accepted is the oldest version that is newer than all the rejected
+		// versions seen so far (or -1 if the last version seen was rejected).
+		newest, accepted := i.row, i.row
+		if !i.matchesPredicate(kv) {
+			accepted = -1
+		}
+		i.skippedUserKey = append(i.skippedUserKey[:0], kv.K.UserKey...)
+		for {
+			if kv = i.prev(); kv == nil || !bytes.Equal(kv.K.UserKey, i.skippedUserKey) {
+				break
+			}
+			newest = i.row
+			if !i.matchesPredicate(kv) {
+				accepted = -1
+			} else if accepted < 0 {
+				accepted = i.row
+			}
+		}
+		if accepted >= 0 {
+			i.acceptedRunStart, i.acceptedRunEnd = newest, accepted
+			i.row = accepted
+			if i.transforms.HideObsoletePoints {
+				i.nextObsoletePoint = i.d.isObsolete.SeekSetBitGE(i.row)
+			}
+			return i.decodeRow()
+		}
+		// All the versions of the user key are skipped, and kv is the previous
+		// KV pair with a different user key (or nil).
+		if kv == nil {
+			return nil
+		}
+	}
+}
+
+// skipUnmatchedForward skips kv, which must be the KV pair at i.row and must
+// not match the point key predicate, along with the older versions of its
+// user key, and advances to the next KV pair that matches.
+//
+// The older versions are skipped regardless of the predicate: kv shadows them,
+// so surfacing them would expose stale values.
+func (i *DataBlockIter) skipUnmatchedForward(kv *base.InternalKV) *base.InternalKV {
+	for {
+		i.skippedUserKey = append(i.skippedUserKey[:0], kv.K.UserKey...)
+		for {
+			kv = i.next()
+			if kv == nil || i.d.prefixChanged.At(i.row) || !bytes.Equal(kv.K.UserKey, i.skippedUserKey) {
+				break
+			}
+		}
+		if kv == nil || i.matchesPredicate(kv) {
+			return kv
+		}
+	}
+}
+
+// matchesPredicate returns true if kv (which must be the KV pair at i.row)
+// matches the point key predicate, or if the predicate doesn't apply to kv.
+func (i *DataBlockIter) matchesPredicate(kv *base.InternalKV) bool {
+	if k := kv.K.Kind(); k != base.InternalKeyKindSet && k != base.InternalKeyKindSetWithDelete {
+		return true
+	}
+	var summary base.PointValueSummary
+	if i.d.isValueExternal.At(i.row) {
+		summary.ShortAttribute = block.ValuePrefix(i.d.values.At(i.row)[0]).ShortAttribute()
+	} else {
+		summary.InPlace = true
+		summary.Value = kv.V.LazyValue().ValueOrHandle
+	}
+	if i.pointKeyPredicate(kv.K.UserKey[i.split(kv.K.UserKey):], summary) {
+		return true
+	}
+	if i.stats != nil {
+		i.stats.PointsFilteredByPredicate++
+	}
+	return false
+}
+
 // atObsoletePointForward returns true if i.row is an obsolete point. It is
 // separate from skipObsoletePointsForward() because that method does not
 // inline. It can only be used during forward iteration (i.e. i.row was
@@ -1938,8 +2219,10 @@ func (i *DataBlockIter) decodeRow() *base.InternalKV {
 		invariants.CheckBounds(i.row, i.d.values.slices)
 		if i.d.isValueExternal.At(i.row) {
 			i.kv.V = i.getLazyValuer.GetInternalValueForPrefixAndValueHandle(v)
-		} else {
+		} else if i.columnConfig.ValueSchema == nil || len(v) > 0 {
 			i.kv.V = base.MakeInPlaceValue(v)
+		} else {
+			i.kv.V = base.MakeInPlaceValue(i.valueFromColumns())
 		}
 		i.kvRow = i.row
 		return &i.kv
@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/pebble/internal/treeprinter"
 	"github.com/cockroachdb/pebble/sstable/block"
 	"github.com/cockroachdb/pebble/sstable/blockiter"
+	"github.com/stretchr/testify/require"
 )
 
 var testKeysSchema = DefaultKeySchema(testkeys.Comparer, 16)
@@ -552,3 +553,223 @@ func BenchmarkDataBlockDecoderInit(b *testing.B) {
 		InitDataBlockMetadata(&testKeysSchema, &md, finished)
 	}
 }
+
+// TestDataBlockIterPointKeyPredicate tests that a DataBlockIter configured
+// with a point key predicate skips exactly the SET keys that don't match the
+// predicate, along with the older versions of their user keys, under all
+// positioning operations.
+func TestDataBlockIterPointKeyPredicate(t *testing.T) {
+	const targetBlockSize = 16 << 10
+	seed := uint64(time.Now().UnixNano())
+	t.Logf("seed: %d", seed)
+	rng := rand.New(rand.NewPCG(0, seed))
+	// Use short prefixes so that prefixes have several suffixes.
+	keys, values := makeTestKeyRandomKVs(rng, 2, 8, targetBlockSize)
+	slices.SortFunc(keys, testkeys.Comparer.Compare)
+
+	// The predicate skips in-place values whose first byte is a multiple of 3
+	// and external values with an odd short attribute.
+	predicate := func(suffix []byte, v base.PointValueSummary) bool {
+		if v.InPlace {
+			return len(v.Value) == 0 || v.Value[0]%3 != 0
+		}
+		return v.ShortAttribute%2 == 0
+	}
+
+	type row struct {
+		key      []byte
+		obsolete bool
+		matches  bool
+	}
+	var rows []row
+	var w DataBlockEncoder
+	w.Init(&testKeysSchema, NoTieringColumns())
+	var ik base.InternalKey
+	for j := 0; w.Size() < targetBlockSize; j++ {
+		kind := base.InternalKeyKindSet
+		if rng.IntN(10) == 0 {
+			kind = base.InternalKeyKindDelete
+		}
+		if j > 0 && rng.IntN(4) == 0 {
+			// Write an older version of the previous user key, which the
+			// previous key shadows.
+			ik = base.MakeInternalKey(ik.UserKey, ik.SeqNum()-base.SeqNum(rng.IntN(10)+1), kind)
+		} else {
+			ik = base.MakeInternalKey(keys[j], base.SeqNum(rng.Uint64N(1<<40)+(1<<40)), kind)
+		}
+		kcmp := w.KeyWriter.ComparePrev(ik.UserKey)
+		r := row{key: append([]byte(nil), ik.UserKey...), obsolete: rng.IntN(4) == 0}
+		switch {
+		case kind == base.InternalKeyKindDelete:
+			w.Add(ik, nil, block.InPlaceValuePrefix(kcmp.PrefixEqual()), kcmp, r.obsolete, base.KVMeta{})
+			r.matches = true
+		case rng.IntN(4) == 0:
+			attr := base.ShortAttribute(rng.IntN(8))
+			w.Add(ik, values[j], block.ValueBlockHandlePrefix(kcmp.PrefixEqual(), attr), kcmp, r.obsolete, base.KVMeta{})
+			r.matches = attr%2 == 0
+		default:
+			w.Add(ik, values[j], block.InPlaceValuePrefix(kcmp.PrefixEqual()), kcmp, r.obsolete, base.KVMeta{})
+			r.matches = values[j][0]%3 != 0
+		}
+		rows = append(rows, r)
+	}
+	blockData, _ := w.Finish(w.Rows(), w.Size())
+	var d DataBlockDecoder
+	bd := d.Init(&testKeysSchema, blockData)
+
+	var stats base.InternalIteratorStats
+	var it DataBlockIter
+	it.InitOnce(&testKeysSchema, testkeys.Comparer,
+		getInternalValuer(func([]byte) base.InternalValue {
+			return base.MakeInPlaceValue([]byte("mock external value"))
+		}), NoTieringColumns())
+	it.SetPointKeyPredicate(predicate, &stats)
+
+	cmp := testkeys.Comparer.Compare
+	split := testkeys.Comparer.Split
+	prefix := func(r int) []byte { return rows[r].key[:split(rows[r].key)] }
+	for _, hideObsolete := range []bool{false, true} {
+		t.Run(fmt.Sprintf("hide-obsolete=%t", hideObsolete), func(t *testing.T) {
+			hidden := func(r int) bool { return hideObsolete && rows[r].obsolete }
+			// A visible row is accepted iff neither it nor a newer visible
+			// version of its user key fails to match the predicate. The rows
+			// that don't match and aren't shadowed by a row that doesn't match
+			// are counted in stats during a forward scan.
+			accepted := make([]bool, len(rows))
+			var expectedFiltered uint64
+			var skipped bool
+			for r := range rows {
+				if r == 0 || !bytes.Equal(rows[r].key, rows[r-1].key) {
+					skipped = false
+				}
+				if hidden(r) || skipped {
+					continue
+				}
+				if !rows[r].matches {
+					skipped = true
+					expectedFiltered++
+					continue
+				}
+				accepted[r] = true
+			}
+			visible := func(r int) bool {
+				return accepted[r] && !hidden(r)
+			}
+			// firstVisible returns the first visible row >= r, or -1.
+			firstVisible := func(r int) int {
+				for ; r < len(rows); r++ {
+					if visible(r) {
+						return r
+					}
+				}
+				return -1
+			}
+			// lastVisible returns the last visible row <= r, or -1.
+			lastVisible := func(r int) int {
+				for ; r >= 0; r-- {
+					if visible(r) {
+						return r
+					}
+				}
+				return -1
+			}
+			// seekGERow returns the first row with a key >= key.
+			seekGERow := func(key []byte) int {
+				r, _ := slices.BinarySearchFunc(rows, key, func(r row, k []byte) int { return cmp(r.key, k) })
+				return r
+			}
+			checkRow := func(kv *base.InternalKV, r int) {
+				t.Helper()
+				if r < 0 {
+					require.Nil(t, kv)
+					return
+				}
+				require.NotNil(t, kv)
+				require.Equal(t, string(rows[r].key), string(kv.K.UserKey))
+				require.Equal(t, r, it.row)
+			}
+
+			transforms := blockiter.Transforms{HideObsoletePoints: hideObsolete}
+			require.NoError(t, it.Init(&d, bd, transforms, NoTieringColumns()))
+			defer func() { require.NoError(t, it.Close()) }()
+
+			// Full forward and backward scans.
+			stats = base.InternalIteratorStats{}
+			r := firstVisible(0)
+			for kv := it.First(); kv != nil; kv = it.Next() {
+				checkRow(kv, r)
+				r = firstVisible(r + 1)
+			}
+			require.Equal(t, -1, r)
+			require.Equal(t, expectedFiltered, stats.PointsFilteredByPredicate)
+
+			r = lastVisible(len(rows) - 1)
+			for kv := it.Last(); kv != nil; kv = it.Prev() {
+				checkRow(kv, r)
+				r = lastVisible(r - 1)
+			}
+			require.Equal(t, -1, r)
+
+			for range 200 {
+				seekKey := rows[rng.IntN(len(rows))].key
+				if rng.IntN(2) == 0 {
+					// Seek to a key that is not necessarily in the block.
+					seekKey = append(seekKey[:split(seekKey):split(seekKey)], fmt.Sprintf("@%d", rng.IntN(100))...)
+				}
+				r := firstVisible(seekGERow(seekKey))
+				checkRow(it.SeekGE(seekKey, base.SeekGEFlagsNone), r)
+				if r >= 0 {
+					// NextPrefix moves to the first visible row with a different
+					// prefix.
+					next := r + 1
+					for next < len(rows) && bytes.Equal(prefix(next), prefix(r)) {
+						next++
+					}
+					checkRow(it.NextPrefix(nil), firstVisible(next))
+				}
+
+				checkRow(it.SeekLT(seekKey, base.SeekLTFlagsNone), lastVisible(seekGERow(seekKey)-1))
+
+				kv, prefixDidNotMatch := it.SeekPrefixGE(seekKey, base.SeekGEFlagsNone)
+				r = firstVisible(seekGERow(seekKey))
+				samePrefix := r >= 0 && bytes.Equal(prefix(r), seekKey[:split(seekKey)])
+				if !samePrefix {
+					// If the first row >= the seek key that isn't hidden has a
+					// different prefix, the iterator stops there even if the
+					// predicate skips it.
+					land := seekGERow(seekKey)
+					for land < len(rows) && hidden(land) {
+						land++
+					}
+					require.Nil(t, kv)
+					require.Equal(t, r >= 0 || (land < len(rows) &&
+						!bytes.Equal(prefix(land), seekKey[:split(seekKey)])), prefixDidNotMatch)
+					continue
+				}
+				require.False(t, prefixDidNotMatch)
+				checkRow(kv, r)
+				for {
+					kv, prefixExhausted := it.NextWithSamePrefix()
+					next := firstVisible(r + 1)
+					if next < 0 || !bytes.Equal(prefix(next), prefix(r)) {
+						// The iterator stops at the first row with a different
+						// prefix that isn't hidden, even if the predicate
+						// skips it.
+						require.Nil(t, kv)
+						exhausted := false
+						for j := r + 1; j < len(rows); j++ {
+							if !hidden(j) && !bytes.Equal(prefix(j), prefix(r)) {
+								exhausted = true
+								break
+							}
+						}
+						require.Equal(t, exhausted, prefixExhausted)
+						break
+					}
+					checkRow(kv, next)
+					r = next
+				}
+			}
+		})
+	}
+}
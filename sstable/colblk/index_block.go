@@ -97,6 +97,23 @@ func (w *IndexBlockWriter) AddBlockHandle(
 	return idx
 }
 
+// SetBlockHandles replaces the block handles of all the entries in the index
+// block; handles[i] is the new block handle of the i'th entry. It's used when
+// the final block handles are not yet known when the entries are added.
+//
+// SetBlockHandles should only be used for first-level index blocks.
+func (w *IndexBlockWriter) SetBlockHandles(handles []block.Handle) {
+	if invariants.Enabled && len(handles) != w.rows {
+		panic(errors.AssertionFailedf("index block has %d rows; got %d handles", errors.Safe(w.rows), errors.Safe(len(handles))))
+	}
+	w.offsets.Reset()
+	w.lengths.Reset()
+	for i := range handles {
+		w.offsets.Set(i, handles[i].Offset)
+		w.lengths.Set(i, handles[i].Length)
+	}
+}
+
 // UnsafeSeparator returns the separator of the i'th entry.
 func (w *IndexBlockWriter) UnsafeSeparator(i int) []byte {
 	return w.separators.UnsafeGet(i)
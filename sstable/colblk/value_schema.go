@@ -0,0 +1,273 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package colblk
+
+import (
+	"fmt"
+	"io"
+	"math/bits"
+
+	"github.com/cockroachdb/errors"
+)
+
+// MaxValueColumns is the maximum number of columns in a ValueSchema.
+const MaxValueColumns = 64
+
+// ValueSchema describes how structured values are stored in a data block as
+// separate columns, rather than as opaque byte strings. This allows iterators
+// to decode only a subset of a value's fields (see ValueProjection).
+//
+// Values that do not conform to the schema (Decompose returns false) and
+// values stored outside of the data block (in value blocks or blob files) are
+// stored as opaque values.
+type ValueSchema struct {
+	// Name identifies the value schema. It is persisted in the sstable
+	// properties and used to find the schema when the sstable is read.
+	Name string
+	// ColumnTypes defines the types of the value columns; each type must be
+	// DataTypeUint or DataTypeBytes. There can be at most MaxValueColumns
+	// columns.
+	ColumnTypes []DataType
+	// Decompose decomposes a value into its fields, setting fields[i] for
+	// each column i (len(fields) == len(ColumnTypes)). It returns false if the
+	// value does not conform to the schema, in which case the value is stored
+	// as an opaque value. The Bytes fields can alias the value.
+	Decompose func(value []byte, fields []ValueField) bool
+	// Compose appends to dst the value with the given fields, and returns the
+	// resulting slice. Compose(Decompose(v)) must be equal to v.
+	//
+	// When a value is read through a ValueProjection, the fields of the columns
+	// that are not part of the projection are zero.
+	Compose func(dst []byte, fields []ValueField) []byte
+}
+
+// ValueField holds the value of a field of a structured value. Uint is used for
+// DataTypeUint columns and Bytes is used for DataTypeBytes columns.
+type ValueField struct {
+	Uint  uint64
+	Bytes []byte
+}
+
+// String returns a string representation of the schema.
+func (s *ValueSchema) String() string {
+	return fmt.Sprintf("%s%v", s.Name, s.ColumnTypes)
+}
+
+// Validate returns an error if the schema is not valid.
+func (s *ValueSchema) Validate() error {
+	if s.Name == "" {
+		return errors.New("value schema has no name")
+	}
+	if len(s.ColumnTypes) == 0 || len(s.ColumnTypes) > MaxValueColumns {
+		return errors.Newf("value schema %q has %d columns; must have between 1 and %d",
+			errors.Safe(s.Name), errors.Safe(len(s.ColumnTypes)), errors.Safe(MaxValueColumns))
+	}
+	for i, t := range s.ColumnTypes {
+		if t != DataTypeUint && t != DataTypeBytes {
+			return errors.Newf("value schema %q column %d has unsupported type %s",
+				errors.Safe(s.Name), errors.Safe(i), t)
+		}
+	}
+	if s.Decompose == nil || s.Compose == nil {
+		return errors.Newf("value schema %q must define Decompose and Compose", errors.Safe(s.Name))
+	}
+	return nil
+}
+
+// ValueProjection identifies the value columns that an iterator decodes when
+// reading values stored in value columns. The zero value decodes all columns.
+type ValueProjection struct {
+	// excluded is a bitmap of the columns that are not decoded.
+	excluded uint64
+}
+
+// ProjectValueColumns returns a ValueProjection that decodes only the given
+// columns (identified by their index in ValueSchema.ColumnTypes).
+func ProjectValueColumns(cols ...int) ValueProjection {
+	var included uint64
+	for _, c := range cols {
+		if c < 0 || c >= MaxValueColumns {
+			panic(errors.AssertionFailedf("invalid value column %d", errors.Safe(c)))
+		}
+		included |= 1 << c
+	}
+	return ValueProjection{excluded: ^included}
+}
+
+// IsAll returns true if the projection decodes all columns.
+func (p ValueProjection) IsAll() bool {
+	return p.excluded == 0
+}
+
+// Includes returns true if the projection decodes the given column.
+func (p ValueProjection) Includes(col int) bool {
+	return p.excluded&(1<<col) == 0
+}
+
+// String implements fmt.Stringer.
+func (p ValueProjection) String() string {
+	if p.IsAll() {
+		return "all"
+	}
+	var buf []byte
+	for included := ^p.excluded; included != 0; included &= included - 1 {
+		if len(buf) > 0 {
+			buf = append(buf, ',')
+		}
+		buf = fmt.Appendf(buf, "%d", bits.TrailingZeros64(included))
+	}
+	if len(buf) == 0 {
+		return "none"
+	}
+	return string(buf)
+}
+
+// valueColumnsBuilder builds the value columns of a data block: a bitmap
+// indicating which rows are stored in the value columns, followed by one
+// column per ValueSchema column.
+type valueColumnsBuilder struct {
+	schema   *ValueSchema
+	columnar BitmapBuilder
+	uints    []UintBuilder
+	bytes    []RawBytesBuilder
+	// cols contains a ColumnWriter for each ValueSchema column.
+	cols   []ColumnWriter
+	fields []ValueField
+}
+
+func (b *valueColumnsBuilder) init(schema *ValueSchema) {
+	if err := schema.Validate(); err != nil {
+		panic(errors.AssertionFailedf("%v", err))
+	}
+	b.schema = schema
+	b.columnar.Reset()
+	n := len(schema.ColumnTypes)
+	b.uints = make([]UintBuilder, n)
+	b.bytes = make([]RawBytesBuilder, n)
+	b.cols = make([]ColumnWriter, n)
+	b.fields = make([]ValueField, n)
+	for i, t := range schema.ColumnTypes {
+		if t == DataTypeUint {
+			b.uints[i].InitWithDefault()
+			b.cols[i] = &b.uints[i]
+		} else {
+			b.bytes[i].Init()
+			b.cols[i] = &b.bytes[i]
+		}
+	}
+}
+
+func (b *valueColumnsBuilder) reset() {
+	b.columnar.Reset()
+	for i := range b.cols {
+		b.cols[i].Reset()
+	}
+}
+
+// numColumns returns the number of columns of the value columns.
+func (b *valueColumnsBuilder) numColumns() int {
+	return 1 + len(b.cols)
+}
+
+// add adds the given row. If the value can be stored in the value columns,
+// it returns true and the caller must store an empty value for the row in the
+// values column.
+func (b *valueColumnsBuilder) add(row int, value []byte, isInPlace bool) bool {
+	columnar := isInPlace && b.schema.Decompose(value, b.fields)
+	if columnar {
+		b.columnar.Set(row)
+	}
+	for i, t := range b.schema.ColumnTypes {
+		if t == DataTypeUint {
+			if columnar && b.fields[i].Uint != 0 {
+				b.uints[i].Set(row, b.fields[i].Uint)
+			}
+		} else if columnar {
+			b.bytes[i].Put(b.fields[i].Bytes)
+		} else {
+			b.bytes[i].Put(nil)
+		}
+	}
+	clear(b.fields)
+	return columnar
+}
+
+func (b *valueColumnsBuilder) size(rows int, offset uint32) uint32 {
+	offset = b.columnar.Size(rows, offset)
+	for _, c := range b.cols {
+		offset = c.Size(rows, offset)
+	}
+	return offset
+}
+
+func (b *valueColumnsBuilder) encode(enc *BlockEncoder, rows int) {
+	enc.Encode(rows, &b.columnar)
+	for _, c := range b.cols {
+		enc.Encode(rows, c)
+	}
+}
+
+func (b *valueColumnsBuilder) writeDebug(w io.Writer, firstCol, rows int) {
+	fmt.Fprintf(w, "%d: value-columnar: ", firstCol)
+	b.columnar.WriteDebug(w, rows)
+	fmt.Fprintln(w)
+	for i, c := range b.cols {
+		fmt.Fprintf(w, "%d: value-column-%d: ", firstCol+1+i, i)
+		c.WriteDebug(w, rows)
+		fmt.Fprintln(w)
+	}
+}
+
+// valueColumnsDecoder decodes the value columns of a data block.
+type valueColumnsDecoder struct {
+	schema   *ValueSchema
+	columnar Bitmap
+	uints    []UnsafeUints
+	bytes    []RawBytes
+	fields   []ValueField
+	buf      []byte
+}
+
+// init decodes the value columns of the given block, starting at column
+// firstCol.
+func (d *valueColumnsDecoder) init(schema *ValueSchema, bd *BlockDecoder, firstCol int) {
+	if d.schema != schema {
+		d.schema = schema
+		n := len(schema.ColumnTypes)
+		d.uints = make([]UnsafeUints, n)
+		d.bytes = make([]RawBytes, n)
+		d.fields = make([]ValueField, n)
+	}
+	d.columnar = bd.Bitmap(firstCol)
+	for i, t := range schema.ColumnTypes {
+		if t == DataTypeUint {
+			d.uints[i] = bd.Uints(firstCol + 1 + i)
+		} else {
+			d.bytes[i] = bd.RawBytes(firstCol + 1 + i)
+		}
+	}
+}
+
+// isColumnar returns true if the value of the given row is stored in the value
+// columns.
+func (d *valueColumnsDecoder) isColumnar(row int) bool {
+	return d.columnar.At(row)
+}
+
+// compose composes the value of the given row from the projected columns. The
+// returned value is valid until the next call to compose.
+func (d *valueColumnsDecoder) compose(row int, projection ValueProjection) []byte {
+	for i, t := range d.schema.ColumnTypes {
+		if !projection.Includes(i) {
+			d.fields[i] = ValueField{}
+		} else if t == DataTypeUint {
+			d.fields[i] = ValueField{Uint: d.uints[i].At(row)}
+		} else {
+			d.fields[i] = ValueField{Bytes: d.bytes[i].At(row)}
+		}
+	}
+	d.buf = d.schema.Compose(d.buf[:0], d.fields)
+	return d.buf
+}
@@ -0,0 +1,175 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package colblk
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/pebble/internal/base"
+	"github.com/cockroachdb/pebble/internal/testkeys"
+	"github.com/cockroachdb/pebble/sstable/block"
+	"github.com/cockroachdb/pebble/sstable/blockiter"
+	"github.com/stretchr/testify/require"
+)
+
+// testValueSchema decomposes values of the form "<count>:<name>" into a uint
+// column and a bytes column.
+var testValueSchema = ValueSchema{
+	Name:        "test-value-schema",
+	ColumnTypes: []DataType{DataTypeUint, DataTypeBytes},
+	Decompose: func(value []byte, fields []ValueField) bool {
+		count, name, ok := bytes.Cut(value, []byte(":"))
+		if !ok {
+			return false
+		}
+		n, err := strconv.ParseUint(string(count), 10, 64)
+		if err != nil || strconv.FormatUint(n, 10) != string(count) {
+			// Only canonical encodings can be recomposed.
+			return false
+		}
+		fields[0].Uint = n
+		fields[1].Bytes = name
+		return true
+	},
+	Compose: func(dst []byte, fields []ValueField) []byte {
+		dst = strconv.AppendUint(dst, fields[0].Uint, 10)
+		dst = append(dst, ':')
+		return append(dst, fields[1].Bytes...)
+	},
+}
+
+func TestValueSchemaValidate(t *testing.T) {
+	require.NoError(t, testValueSchema.Validate())
+
+	s := testValueSchema
+	s.Name = ""
+	require.Error(t, s.Validate())
+
+	s = testValueSchema
+	s.ColumnTypes = nil
+	require.Error(t, s.Validate())
+
+	s = testValueSchema
+	s.ColumnTypes = []DataType{DataTypeBool}
+	require.Error(t, s.Validate())
+
+	s = testValueSchema
+	s.Compose = nil
+	require.Error(t, s.Validate())
+}
+
+func TestValueProjection(t *testing.T) {
+	var p ValueProjection
+	require.True(t, p.IsAll())
+	require.True(t, p.Includes(0))
+	require.Equal(t, "all", p.String())
+
+	p = ProjectValueColumns(0, 2)
+	require.False(t, p.IsAll())
+	require.True(t, p.Includes(0))
+	require.False(t, p.Includes(1))
+	require.True(t, p.Includes(2))
+	require.Equal(t, "0,2", p.String())
+
+	require.Equal(t, "none", ProjectValueColumns().String())
+}
+
+func TestDataBlockValueColumns(t *testing.T) {
+	kvs := []struct {
+		key, value string
+		external   bool
+	}{
+		{key: "a@3", value: "1:apple"},
+		{key: "b@2", value: "opaque"},
+		{key: "c@9", value: "0:"},
+		{key: "d@1", value: ""},
+		{key: "e@5", value: "handle", external: true},
+		{key: "f@4", value: "007:not-canonical"},
+		{key: "g@8", value: "42:grape"},
+	}
+	for _, tieringConfig := range []OptionalColumnConfig{NoTieringColumns(), WithTieringColumns()} {
+		t.Run(fmt.Sprintf("tiering=%t", tieringConfig.SupportsTiering()), func(t *testing.T) {
+			config := tieringConfig
+			config.ValueSchema = &testValueSchema
+
+			var w DataBlockEncoder
+			w.Init(&testKeysSchema, config)
+			for i, kv := range kvs {
+				ik := base.MakeInternalKey([]byte(kv.key), base.SeqNum(len(kvs)-i), base.InternalKeyKindSet)
+				kcmp := w.KeyWriter.ComparePrev(ik.UserKey)
+				vp := block.InPlaceValuePrefix(kcmp.PrefixEqual())
+				if kv.external {
+					vp = block.ValueBlockHandlePrefix(kcmp.PrefixEqual(), 0)
+				}
+				w.Add(ik, []byte(kv.value), vp, kcmp, false /* isObsolete */, base.KVMeta{})
+			}
+			require.Contains(t, w.String(), "value-columnar")
+			finished, _ := w.Finish(w.Rows(), w.Size())
+			// The encoder's buffer is reused; copy the block.
+			data := append([]byte(nil), finished...)
+
+			var d DataBlockDecoder
+			bd := d.Init(&testKeysSchema, data)
+			var v DataBlockValidator
+			require.NoError(t, v.Validate(data, testkeys.Comparer, &testKeysSchema))
+
+			var it DataBlockIter
+			it.InitOnce(&testKeysSchema, testkeys.Comparer,
+				getInternalValuer(func([]byte) base.InternalValue {
+					return base.MakeInPlaceValue([]byte("mock external value"))
+				}), config)
+			readValues := func(p ValueProjection) string {
+				it.SetValueProjection(p)
+				require.NoError(t, it.Init(&d, bd, blockiter.Transforms{}, config))
+				defer func() { require.NoError(t, it.Close()) }()
+				var buf strings.Builder
+				for kv := it.First(); kv != nil; kv = it.Next() {
+					fmt.Fprintf(&buf, "%s=%q\n", kv.K.UserKey, kv.V.LazyValue().ValueOrHandle)
+				}
+				return buf.String()
+			}
+
+			require.Equal(t, `a@3="1:apple"
+b@2="opaque"
+c@9="0:"
+d@1=""
+e@5="mock external value"
+f@4="007:not-canonical"
+g@8="42:grape"
+`, readValues(ValueProjection{}))
+
+			// Columns that are not part of the projection are zero; values that
+			// are not stored in value columns are unaffected.
+			require.Equal(t, `a@3="0:apple"
+b@2="opaque"
+c@9="0:"
+d@1=""
+e@5="mock external value"
+f@4="007:not-canonical"
+g@8="0:grape"
+`, readValues(ProjectValueColumns(1)))
+			require.Equal(t, `a@3="1:"
+b@2="opaque"
+c@9="0:"
+d@1=""
+e@5="mock external value"
+f@4="007:not-canonical"
+g@8="42:"
+`, readValues(ProjectValueColumns(0)))
+
+			// Seeking decodes the value from the columns as well.
+			it.SetValueProjection(ValueProjection{})
+			require.NoError(t, it.Init(&d, bd, blockiter.Transforms{}, config))
+			kv := it.SeekGE([]byte("g"), base.SeekGEFlagsNone)
+			require.NotNil(t, kv)
+			require.Equal(t, "42:grape", string(kv.V.LazyValue().ValueOrHandle))
+			require.NoError(t, it.Close())
+		})
+	}
+}
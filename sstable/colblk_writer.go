@@ -39,9 +39,12 @@ type RawColumnWriter struct {
 	dataFlush           block.FlushGovernor
 	indexFlush          block.FlushGovernor
 	blockPropCollectors []BlockPropertyCollector
-	blockPropsEncoder   blockPropertiesEncoder
-	obsoleteCollector   obsoleteKeyBlockPropertyCollector
-	props               Properties
+	// collectorWantsValues[i] is true if blockPropCollectors[i] wants in-place
+	// values (see InPlaceValueBlockPropertyCollector).
+	collectorWantsValues []bool
+	blockPropsEncoder    blockPropertiesEncoder
+	obsoleteCollector    obsoleteKeyBlockPropertyCollector
+	props                Properties
 	// block writers buffering unflushed data.
 	dataBlock struct {
 		colblk.DataBlockEncoder
@@ -64,6 +67,19 @@ type RawColumnWriter struct {
 	// filterWriter accumulates the filter block. If not nil, the filter writer
 	// ingests the prefix of each key.
 	filterWriter base.TableFilterWriter
+	// filterPartitions holds the state for writing a partitioned filter (see
+	// TableFormat.PartitionedFilters). The filter is partitioned along the
+	// index blocks: when an index block is finished, the filter for the keys
+	// of its data blocks is finished as well, and filterWriter is reset.
+	filterPartitions struct {
+		enabled bool
+		// pendingPrefixes holds the key prefixes of the pending data block.
+		// They're added to the filterWriter when the data block is enqueued,
+		// after we know which index block the data block belongs to.
+		pendingPrefixes    []byte
+		pendingPrefixEnds  []int
+		finishedPartitions []filterPartition
+	}
 	prevPointKey struct {
 		trailer    base.InternalKeyTrailer
 		isObsolete bool
@@ -102,6 +118,24 @@ type RawColumnWriter struct {
 		ch  chan block.OwnedPhysicalBlock
 		err error
 	}
+	// compression holds the state used when data blocks are compressed by
+	// compression workers (see WriterOptions.CompressionWorkers). Data blocks
+	// are added to the index block when they're handed off to the workers,
+	// before their compressed length (and hence the offsets of the subsequent
+	// blocks) is known. The index block is built with provisional block handles
+	// and rebuilt with the final block handles when it's finished.
+	compression struct {
+		workers *compressionWorkers
+		// pending contains the data blocks which were submitted to the workers
+		// but not yet sent to the write queue, in order.
+		pending []*columnarDataBlock
+		// pendingSize is the sum of the uncompressed sizes of the pending data
+		// blocks, including their trailers.
+		pendingSize uint64
+		// indexHandles contains the final block handles of the entries in the
+		// pending index block that were sent to the write queue.
+		indexHandles []block.Handle
+	}
 	layout layoutWriter
 
 	lastKeyBuf            []byte
@@ -127,6 +161,9 @@ func newColumnarWriter(
 	if !o.TableFormat.BlockColumnar() {
 		panic(errors.AssertionFailedf("newColumnarWriter cannot create sstables with %s format", o.TableFormat))
 	}
+	if o.ValueSchema != nil && !o.TableFormat.ValueColumns() {
+		panic(errors.AssertionFailedf("value schemas require TableFormatPebblev10+; got %s", o.TableFormat))
+	}
 	o = o.ensureDefaults()
 	w := &RawColumnWriter{
 		comparer: o.Comparer,
@@ -139,7 +176,7 @@ func newColumnarWriter(
 	w.layout.Init(writable, o)
 	w.dataFlush = block.MakeFlushGovernor(o.BlockSize, o.BlockSizeThreshold, o.SizeClassAwareThreshold, o.AllocatorSizeClasses)
 	w.indexFlush = block.MakeFlushGovernor(o.IndexBlockSize, o.BlockSizeThreshold, o.SizeClassAwareThreshold, o.AllocatorSizeClasses)
-	w.dataBlock.Init(o.KeySchema, o.TableFormat.TieringColumnConfig())
+	w.dataBlock.Init(o.KeySchema, o.TableFormat.DataBlockColumnConfig(o.ValueSchema))
 	w.indexBlock.Init()
 	w.topLevelIndexBlock.Init()
 	w.rangeDelBlock.Init(w.comparer.Equal)
@@ -153,6 +190,7 @@ func newColumnarWriter(
 	}
 	if o.FilterPolicy != base.NoFilterPolicy {
 		w.filterWriter = o.FilterPolicy.NewWriter()
+		w.filterPartitions.enabled = o.PartitionFilters && o.TableFormat.PartitionedFilters()
 	}
 
 	numBlockPropertyCollectors := len(o.BlockPropertyCollectors)
@@ -169,6 +207,10 @@ func newColumnarWriter(
 	if !o.disableObsoleteCollector {
 		w.blockPropCollectors = append(w.blockPropCollectors, &w.obsoleteCollector)
 	}
+	w.collectorWantsValues = make([]bool, len(w.blockPropCollectors))
+	for i, c := range w.blockPropCollectors {
+		w.collectorWantsValues[i] = wantsInPlaceValues(c)
+	}
 	var buf bytes.Buffer
 	buf.WriteString("[")
 	for i := range w.blockPropCollectors {
@@ -183,11 +225,17 @@ func newColumnarWriter(
 	w.props.ComparerName = o.Comparer.Name
 	w.props.CompressionName = o.Compression.Name
 	w.props.KeySchemaName = o.KeySchema.Name
+	if o.ValueSchema != nil {
+		w.props.ValueSchemaName = o.ValueSchema.Name
+	}
 	w.props.MergerName = o.MergerName
 
 	w.writeQueue.ch = make(chan block.OwnedPhysicalBlock)
 	w.cpuMeasurer = cpuMeasurer
 	w.writeQueue.wg.Go(w.drainWriteQueue)
+	if o.CompressionWorkers > 0 {
+		w.compression.workers = newCompressionWorkers(o.CompressionWorkers, o)
+	}
 
 	return w
 }
@@ -205,6 +253,8 @@ func (w *RawColumnWriter) EstimatedSize() uint64 {
 	// post-compression and the footer is not compressed, so this initial
 	// quantity is exact.
 	sz := uint64(w.opts.TableFormat.FooterSize()) + w.queuedDataSize
+	// Add the uncompressed size of the data blocks that are being compressed.
+	sz += w.compression.pendingSize
 
 	// Add the size of value blocks. If any value blocks have already been
 	// finished, these blocks will contribute post-compression size. If there is
@@ -625,11 +675,12 @@ func (w *RawColumnWriter) internalAdd(
 
 	for i := range w.blockPropCollectors {
 		v := valueStoredWithKey
-		if key.Kind() == base.InternalKeyKindSet || key.Kind() == base.InternalKeyKindSetWithDelete || !valuePrefix.IsInPlaceValue() {
+		if !valuePrefix.IsInPlaceValue() ||
+			((key.Kind() == base.InternalKeyKindSet || key.Kind() == base.InternalKeyKindSetWithDelete) && !w.collectorWantsValues[i]) {
 			// Values for SET, SETWITHDEL keys are not required to be in-place,
 			// and may not even be read by the compaction, so pass nil values.
 			// Block property collectors in such Pebble DB's must not look at
-			// the value.
+			// the value, unless they ask for in-place values.
 			v = nil
 		}
 		if err := w.blockPropCollectors[i].AddPointKey(key, v); err != nil {
@@ -638,7 +689,10 @@ func (w *RawColumnWriter) internalAdd(
 		}
 	}
 	w.obsoleteCollector.AddPoint(eval.isObsolete)
-	if w.filterWriter != nil {
+	if w.filterPartitions.enabled {
+		w.filterPartitions.pendingPrefixes = append(w.filterPartitions.pendingPrefixes, key.UserKey[:eval.kcmp.PrefixLen]...)
+		w.filterPartitions.pendingPrefixEnds = append(w.filterPartitions.pendingPrefixEnds, len(w.filterPartitions.pendingPrefixes))
+	} else if w.filterWriter != nil {
 		w.filterWriter.AddKey(key.UserKey[:eval.kcmp.PrefixLen])
 	}
 	w.meta.updateSeqNum(key.SeqNum())
@@ -843,6 +897,18 @@ func (w *RawColumnWriter) enqueueDataBlock(
 		}
 	}
 
+	if w.compression.workers != nil {
+		// Hand the data block off to the compression workers. The block is
+		// indexed with a provisional block handle which assumes that all the
+		// pending blocks are uncompressed.
+		dataBlockHandle := block.Handle{
+			Offset: w.queuedDataSize + w.compression.pendingSize,
+			Length: uint64(len(serializedBlock)),
+		}
+		w.submitDataBlock(serializedBlock)
+		return w.indexDataBlock(dataBlockHandle, separator)
+	}
+
 	// Compress and checksum the data block and send it to the write queue.
 	pb := w.layout.physBlockMaker.Make(serializedBlock, blockkind.SSTableData, block.NoFlags)
 	return w.enqueuePhysicalBlock(pb.Take(), separator)
@@ -853,13 +919,82 @@ func (w *RawColumnWriter) enqueueDataBlock(
 func (w *RawColumnWriter) enqueuePhysicalBlock(
 	pb block.OwnedPhysicalBlock, separator []byte,
 ) error {
+	// Blocks must be written in order, after any data blocks that are being
+	// compressed.
+	w.enqueuePendingDataBlocks(len(w.compression.pending))
 	dataBlockHandle := block.Handle{
 		Offset: w.queuedDataSize,
 		Length: uint64(pb.Length().WithoutTrailer()),
 	}
 	w.queuedDataSize += dataBlockHandle.Length + block.TrailerLen
 	w.writeQueue.ch <- pb
+	if w.opts.CompressionWorkers > 0 {
+		w.compression.indexHandles = append(w.compression.indexHandles, dataBlockHandle)
+	}
+	return w.indexDataBlock(dataBlockHandle, separator)
+}
+
+// columnarDataBlock is a data block which is compressed by the compression
+// workers.
+type columnarDataBlock struct {
+	// uncompressed is a copy of the serialized data block.
+	uncompressed []byte
+	// physical is the compressed block, set by the compression worker.
+	physical block.PhysicalBlock
+	// done is signaled once the block is compressed.
+	done chan struct{}
+}
+
+var columnarDataBlockPool = sync.Pool{
+	New: func() interface{} {
+		return &columnarDataBlock{done: make(chan struct{}, 1)}
+	},
+}
 
+// compress implements compressionJob.
+func (b *columnarDataBlock) compress(m *block.PhysicalBlockMaker) {
+	b.physical = m.Make(b.uncompressed, blockkind.SSTableData, block.NoFlags)
+	b.done <- struct{}{}
+}
+
+// submitDataBlock submits a copy of the serialized data block to the
+// compression workers. If too many data blocks are pending, it waits for the
+// oldest one to be compressed and sends it to the write queue.
+func (w *RawColumnWriter) submitDataBlock(serializedBlock []byte) {
+	b := columnarDataBlockPool.Get().(*columnarDataBlock)
+	b.uncompressed = append(b.uncompressed[:0], serializedBlock...)
+	w.compression.workers.submit(b)
+	w.compression.pending = append(w.compression.pending, b)
+	w.compression.pendingSize += uint64(len(serializedBlock)) + block.TrailerLen
+	if len(w.compression.pending) >= w.compression.workers.maxInflight {
+		w.enqueuePendingDataBlocks(1)
+	}
+}
+
+// enqueuePendingDataBlocks waits for the n oldest pending data blocks to be
+// compressed and sends them to the write queue, recording their final block
+// handles.
+func (w *RawColumnWriter) enqueuePendingDataBlocks(n int) {
+	for _, b := range w.compression.pending[:n] {
+		<-b.done
+		dataBlockHandle := block.Handle{
+			Offset: w.queuedDataSize,
+			Length: uint64(b.physical.LengthWithoutTrailer()),
+		}
+		w.queuedDataSize += dataBlockHandle.Length + block.TrailerLen
+		w.compression.pendingSize -= uint64(len(b.uncompressed)) + block.TrailerLen
+		w.compression.indexHandles = append(w.compression.indexHandles, dataBlockHandle)
+		w.writeQueue.ch <- b.physical.Take()
+		b.uncompressed = b.uncompressed[:0]
+		columnarDataBlockPool.Put(b)
+	}
+	clear(w.compression.pending[:n])
+	w.compression.pending = w.compression.pending[:copy(w.compression.pending, w.compression.pending[n:])]
+}
+
+// indexDataBlock adds a data block with the given handle to the index block,
+// after finishing the data block's properties.
+func (w *RawColumnWriter) indexDataBlock(dataBlockHandle block.Handle, separator []byte) error {
 	var err error
 	w.blockPropsEncoder.resetProps()
 	for i := range w.blockPropCollectors {
@@ -896,6 +1031,17 @@ func (w *RawColumnWriter) enqueuePhysicalBlock(
 	for i := range w.blockPropCollectors {
 		w.blockPropCollectors[i].AddPrevDataBlockToIndexBlock()
 	}
+	// Similarly, add the data block's key prefixes to the filter partition of
+	// the index block.
+	if w.filterPartitions.enabled {
+		start := 0
+		for _, end := range w.filterPartitions.pendingPrefixEnds {
+			w.filterWriter.AddKey(w.filterPartitions.pendingPrefixes[start:end])
+			start = end
+		}
+		w.filterPartitions.pendingPrefixes = w.filterPartitions.pendingPrefixes[:0]
+		w.filterPartitions.pendingPrefixEnds = w.filterPartitions.pendingPrefixEnds[:0]
+	}
 	return nil
 }
 
@@ -929,6 +1075,16 @@ func (w *RawColumnWriter) finishIndexBlock(rows int) error {
 	w.indexBuffering.sepAlloc, bib.sep.UserKey = w.indexBuffering.sepAlloc.Copy(
 		w.indexBlock.UnsafeSeparator(rows - 1))
 
+	if w.opts.CompressionWorkers > 0 {
+		// Replace the provisional block handles with the final ones. The final
+		// handle of the remaining entry (if rows == w.indexBlock.Rows()-1) is
+		// kept for the next index block.
+		w.enqueuePendingDataBlocks(len(w.compression.pending))
+		w.indexBlock.SetBlockHandles(w.compression.indexHandles)
+		handles := w.compression.indexHandles
+		w.compression.indexHandles = handles[:copy(handles, handles[rows:])]
+	}
+
 	// Finish the index block and copy it so that w.indexBlock may be reused.
 	blk := w.indexBlock.Finish(rows)
 	if len(w.indexBuffering.blockAlloc) < len(blk) {
@@ -947,6 +1103,14 @@ func (w *RawColumnWriter) finishIndexBlock(rows int) error {
 	// and produce an exact calculation of the current top-level index
 	// block's size.
 	w.indexBuffering.partitionSizeSum += uint64(len(blk) + block.TrailerLen + len(bib.sep.UserKey))
+
+	if w.filterPartitions.enabled {
+		// Finish the filter partition for the index block's data blocks.
+		part := filterPartition{sep: bib.sep.UserKey}
+		part.data, part.family, part.ok = w.filterWriter.Finish()
+		w.filterPartitions.finishedPartitions = append(w.filterPartitions.finishedPartitions, part)
+		w.filterWriter = w.opts.FilterPolicy.NewWriter()
+	}
 	return nil
 }
 
@@ -1006,6 +1170,68 @@ func (w *RawColumnWriter) flushBufferedIndexBlocks() (rootIndex block.Handle, er
 	return rootIndex, nil
 }
 
+// filterPartition is a finished partition of a partitioned filter.
+type filterPartition struct {
+	// sep is the separator of the index block whose data blocks' keys are
+	// contained in the partition.
+	sep    []byte
+	data   []byte
+	family base.TableFilterFamily
+	// ok is false if no filter was created for the partition.
+	ok bool
+}
+
+// writeFilterPartitions writes the finished filter partitions. If there is a
+// single partition (i.e. a single-level index), it is written as a full filter
+// block. Otherwise, the partitions are written followed by a top-level filter
+// index that maps each partition's separator to its block handle. Partitions
+// without a filter are recorded with an empty block handle.
+func (w *RawColumnWriter) writeFilterPartitions() error {
+	parts := w.filterPartitions.finishedPartitions
+	var family base.TableFilterFamily
+	for i := range parts {
+		if parts[i].ok {
+			family = parts[i].family
+			break
+		}
+	}
+	if family == "" {
+		// No partition has a filter.
+		return nil
+	}
+	if len(parts) == 1 {
+		bh, err := w.layout.WriteFilterBlock(parts[0].data, family)
+		if err != nil {
+			return err
+		}
+		w.props.FilterFamily = string(family)
+		w.props.FilterSize = bh.Length
+		return nil
+	}
+
+	var filterIndex colblk.IndexBlockWriter
+	filterIndex.Init()
+	var filterSize uint64
+	for i := range parts {
+		var bh block.Handle
+		if parts[i].ok && parts[i].family == family {
+			var err error
+			if bh, err = w.layout.WriteFilterPartitionBlock(parts[i].data); err != nil {
+				return err
+			}
+			filterSize += bh.Length
+		}
+		filterIndex.AddBlockHandle(parts[i].sep, bh, nil /* blockProperties */)
+	}
+	bh, err := w.layout.WritePartitionedFilterIndexBlock(filterIndex.Finish(filterIndex.Rows()), family)
+	if err != nil {
+		return err
+	}
+	w.props.FilterFamily = string(family)
+	w.props.FilterSize = filterSize + bh.Length
+	return nil
+}
+
 // drainWriteQueue runs in its own goroutine and is responsible for writing
 // finished, compressed data blocks to the writable. It reads from w.writeQueue
 // until the channel is closed. All data blocks are written by this goroutine.
@@ -1050,6 +1276,13 @@ func (w *RawColumnWriter) Close() (err error) {
 		w.err = errors.CombineErrors(w.err, w.enqueueDataBlock(serializedBlock, lastKey, w.separatorBuf))
 		w.maybeIncrementTombstoneDenseBlocks(len(serializedBlock))
 	}
+	if w.compression.workers != nil {
+		// Send the remaining data blocks to the write queue and stop the
+		// compression workers.
+		w.enqueuePendingDataBlocks(len(w.compression.pending))
+		w.compression.workers.close(w.layout.physBlockMaker.Compressor.Stats())
+		w.compression.workers = nil
+	}
 	// Close the write queue channel so that the goroutine responsible for
 	// writing data blocks to disk knows to exit. Any subsequent blocks (eg,
 	// index, metadata, range key, etc) will be written by the goroutine that
@@ -1078,7 +1311,11 @@ func (w *RawColumnWriter) Close() (err error) {
 	}
 
 	// Write the filter block.
-	if w.filterWriter != nil {
+	if w.filterPartitions.enabled || len(w.filterPartitions.finishedPartitions) > 0 {
+		if err := w.writeFilterPartitions(); err != nil {
+			return err
+		}
+	} else if w.filterWriter != nil {
 		filterData, filterFamily, ok := w.filterWriter.Finish()
 		if ok {
 			bh, err := w.layout.WriteFilterBlock(filterData, filterFamily)
@@ -1228,7 +1465,9 @@ func (w *RawColumnWriter) rewriteSuffixes(
 	}
 	// Copy data blocks in parallel, rewriting suffixes as we go.
 	blocks, err := rewriteDataBlocksInParallel(r, sstBytes, wo, l.Data, from, to, concurrency, w.layout.physBlockMaker.Compressor.Stats(), func() blockRewriter {
-		return colblk.NewDataBlockRewriter(wo.KeySchema, w.comparer, wo.TableFormat.TieringColumnConfig())
+		// Decode the blocks with the input sstable's value schema: the value
+		// columns are copied as-is.
+		return colblk.NewDataBlockRewriter(wo.KeySchema, w.comparer, r.tableFormat.DataBlockColumnConfig(r.valueSchema))
 	})
 	if err != nil {
 		return errors.Wrap(err, "rewriting data blocks")
@@ -1300,13 +1539,23 @@ func (w *RawColumnWriter) rewriteSuffixes(
 		// Clone the filter block, because readBlockBuf allows the
 		// returned byte slice to point directly into sst.
 		w.setFilter(slices.Clone(filterBlock), family)
+	} else if len(l.Filter) > 0 {
+		// Copy over the partitions of a partitioned filter. The filter
+		// contains key prefixes, which are unaffected by the suffix
+		// replacement.
+		family, _ := partitionedFilterFamilyFromBlockName(l.Filter[0].Name)
+		parts, err := r.readFilterPartitions(context.TODO(), noReadHandle, l.Filter[0].Handle, family)
+		if err != nil {
+			return errors.Wrap(err, "reading filter partitions")
+		}
+		w.setPartitionedFilter(parts)
 	}
 	return nil
 }
 
 // getExistingFilter returns any existing table filter block handle, along with
 // the policy name (derived from the block name). Returns ok=false if there is
-// no filter block.
+// no filter block, or if the filter is partitioned.
 func getExistingFilter(layout *Layout) (family base.TableFilterFamily, bh block.Handle, ok bool) {
 	if len(layout.Filter) == 0 {
 		return "", block.Handle{}, false
@@ -1316,6 +1565,9 @@ func getExistingFilter(layout *Layout) (family base.TableFilterFamily, bh block.
 	}
 	family, ok = filterFamilyFromBlockName(layout.Filter[0].Name)
 	if !ok {
+		if _, partitioned := partitionedFilterFamilyFromBlockName(layout.Filter[0].Name); partitioned {
+			return "", block.Handle{}, false
+		}
 		if invariants.Enabled {
 			panic(errors.AssertionFailedf("l.Filter has invalid filter block name %q", errors.Safe(layout.Filter[0].Name)))
 		}
@@ -1415,6 +1667,19 @@ func (w *RawColumnWriter) setFilter(filterData []byte, family base.TableFilterFa
 		data:   filterData,
 		family: family,
 	}
+	w.filterPartitions.enabled = false
+}
+
+// setPartitionedFilter sets the pre-populated partitions of a partitioned
+// filter. It is used when we are rewriting suffixes or copying parts of an
+// sstable via CopySpan(). The partitions are written as is, along with a new
+// top-level filter index.
+//
+// The writer takes ownership of the partitions.
+func (w *RawColumnWriter) setPartitionedFilter(parts []filterPartition) {
+	w.filterWriter = nil
+	w.filterPartitions.enabled = false
+	w.filterPartitions.finishedPartitions = parts
 }
 
 // copyProperties copies properties from the specified props, and resets others
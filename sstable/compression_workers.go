@@ -0,0 +1,76 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package sstable
+
+import (
+	"sync"
+
+	"github.com/cockroachdb/pebble/sstable/block"
+)
+
+// compressionJob is a unit of work for compressionWorkers.
+type compressionJob interface {
+	// compress compresses the job's block using the given PhysicalBlockMaker,
+	// which is owned by the calling worker, and signals the job's completion.
+	compress(m *block.PhysicalBlockMaker)
+}
+
+// compressionWorkers is a pool of goroutines that compress data blocks on
+// behalf of a writer (see WriterOptions.CompressionWorkers). Jobs are
+// compressed in no particular order; the writer is responsible for waiting for
+// their completion and emitting the blocks in order.
+type compressionWorkers struct {
+	jobs chan compressionJob
+	wg   sync.WaitGroup
+	// maxInflight is the maximum number of blocks a writer should have
+	// submitted but not yet emitted. It bounds the memory used by the blocks
+	// waiting to be compressed or emitted.
+	maxInflight int
+
+	mu struct {
+		sync.Mutex
+		// stats accumulates the compression stats of the workers that exited.
+		stats block.CompressionStats
+	}
+}
+
+func newCompressionWorkers(n int, o WriterOptions) *compressionWorkers {
+	c := &compressionWorkers{
+		jobs:        make(chan compressionJob, n),
+		maxInflight: 2 * n,
+	}
+	c.wg.Add(n)
+	for range n {
+		go c.runWorker(o)
+	}
+	return c
+}
+
+func (c *compressionWorkers) runWorker(o WriterOptions) {
+	defer c.wg.Done()
+	var physBlockMaker block.PhysicalBlockMaker
+	physBlockMaker.Init(o.Compression, o.Checksum, o.CompressionCounters)
+	defer physBlockMaker.Close()
+	for job := range c.jobs {
+		job.compress(&physBlockMaker)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mu.stats.Add(physBlockMaker.Compressor.Stats())
+}
+
+// submit schedules the compression of a block.
+func (c *compressionWorkers) submit(job compressionJob) {
+	c.jobs <- job
+}
+
+// close waits for the submitted jobs to complete and for the workers to exit,
+// and adds their compression stats to stats. No jobs may be submitted after
+// close is called.
+func (c *compressionWorkers) close(stats *block.CompressionStats) {
+	close(c.jobs)
+	c.wg.Wait()
+	stats.Add(&c.mu.stats)
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package sstable
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/cockroachdb/pebble/internal/base"
+	"github.com/cockroachdb/pebble/internal/testkeys"
+	"github.com/cockroachdb/pebble/objstorage"
+	"github.com/cockroachdb/pebble/sstable/block"
+	"github.com/cockroachdb/pebble/sstable/colblk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressionWorkers(t *testing.T) {
+	seed := rand.Uint64()
+	t.Logf("seed: %d", seed)
+	rng := rand.New(rand.NewPCG(0, seed))
+	keySchema := colblk.DefaultKeySchema(testkeys.Comparer, 16)
+
+	type kv struct {
+		key   base.InternalKey
+		value []byte
+	}
+	ks := testkeys.Alpha(3)
+	var kvs []kv
+	for i := uint64(0); i < ks.Count(); i += 1 + uint64(rng.IntN(10)) {
+		key := base.MakeInternalKey(testkeys.KeyAt(ks, i, rng.Int64N(100)), base.SeqNum(i), base.InternalKeyKindSet)
+		value := make([]byte, rng.IntN(100))
+		for j := range value {
+			// Use a small alphabet so that the values are compressible.
+			value[j] = byte('a' + rng.IntN(4))
+		}
+		kvs = append(kvs, kv{key: key, value: value})
+	}
+
+	write := func(tf TableFormat, compression *block.CompressionProfile, workers int) []byte {
+		f := &objstorage.MemObj{}
+		w := NewRawWriter(f, WriterOptions{
+			TableFormat:             tf,
+			Comparer:                testkeys.Comparer,
+			KeySchema:               &keySchema,
+			Compression:             compression,
+			BlockSize:               256,
+			IndexBlockSize:          512,
+			BlockPropertyCollectors: []func() BlockPropertyCollector{NewTestKeysBlockPropertyCollector},
+			CompressionWorkers:      workers,
+		})
+		for _, kv := range kvs {
+			require.NoError(t, w.Add(kv.key, kv.value, false /* forceObsolete */, base.KVMeta{}))
+		}
+		require.NoError(t, w.Close())
+		return f.Data()
+	}
+	open := func(data []byte) *Reader {
+		r, err := NewMemReader(data, ReaderOptions{
+			Comparer:   testkeys.Comparer,
+			KeySchemas: KeySchemas{keySchema.Name: &keySchema},
+		})
+		require.NoError(t, err)
+		return r
+	}
+
+	for _, tf := range []TableFormat{TableFormatPebblev2, TableFormatPebblev4, TableFormatMax} {
+		for _, compression := range []*block.CompressionProfile{block.NoCompression, block.SnappyCompression, block.ZstdCompression} {
+			t.Run(fmt.Sprintf("%s/%s", tf, compression.Name), func(t *testing.T) {
+				r := open(write(tf, compression, 0 /* workers */))
+				defer func() { require.NoError(t, r.Close()) }()
+				l, err := r.Layout()
+				require.NoError(t, err)
+				require.Greater(t, len(l.Index), 1)
+				props, err := r.ReadPropertiesBlock(context.Background(), nil /* buffer pool */)
+				require.NoError(t, err)
+
+				for _, workers := range []int{1, 2, 4} {
+					pr := open(write(tf, compression, workers))
+					// The data blocks are identical to the ones written without
+					// compression workers, but the index blocks may be split
+					// differently.
+					pl, err := pr.Layout()
+					require.NoError(t, err)
+					require.Equal(t, l.Data, pl.Data)
+					require.NoError(t, pr.ValidateBlockChecksums())
+					pprops, err := pr.ReadPropertiesBlock(context.Background(), nil /* buffer pool */)
+					require.NoError(t, err)
+					require.Equal(t, props.NumDataBlocks, pprops.NumDataBlocks)
+					require.Equal(t, props.DataSize, pprops.DataSize)
+
+					iter, err := pr.NewIter(NoTransforms, nil /* lower */, nil /* upper */, AssertNoBlobHandles)
+					require.NoError(t, err)
+					var got []kv
+					for ikv := iter.First(); ikv != nil; ikv = iter.Next() {
+						v, _, err := ikv.Value(nil)
+						require.NoError(t, err)
+						got = append(got, kv{key: ikv.K.Clone(), value: slices.Clone(v)})
+					}
+					require.NoError(t, iter.Close())
+					require.Equal(t, len(kvs), len(got))
+					for i := range kvs {
+						require.Equal(t, kvs[i].key, got[i].key)
+						require.Equal(t, kvs[i].value, got[i].value)
+					}
+
+					// Seek to each key through the index blocks.
+					iter, err = pr.NewIter(NoTransforms, nil /* lower */, nil /* upper */, AssertNoBlobHandles)
+					require.NoError(t, err)
+					for range 100 {
+						i := rng.IntN(len(kvs))
+						ikv := iter.SeekGE(kvs[i].key.UserKey, base.SeekGEFlagsNone)
+						require.NotNil(t, ikv)
+						require.Equal(t, kvs[i].key, ikv.K)
+					}
+					require.NoError(t, iter.Close())
+					require.NoError(t, pr.Close())
+				}
+			})
+		}
+	}
+}
@@ -71,7 +71,7 @@ func (fa *FileAnalyzer) SSTable(ctx context.Context, readable objstorage.Readabl
 	}
 
 	rh := readable.NewReadHandle(objstorage.NoReadBefore)
-	rh.SetupForCompaction()
+	rh.SetupForCompaction(nil /* directIOBufs */)
 	defer func() { _ = rh.Close() }()
 	br := r.BlockReader()
 
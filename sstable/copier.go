@@ -61,6 +61,10 @@ func CopySpan(
 	// filter exists within the sstable verbatim regardless.
 	o.FilterPolicy = base.NoFilterPolicy
 	o.TableFormat = r.tableFormat
+	if o.TableFormat < TableFormatPebblev6 {
+		// Signed tables require TableFormatPebblev6+.
+		o.SigningKeys = nil
+	}
 	// We don't want the writer to attempt to write out block property data in
 	// index blocks. This data won't be valid since we're not passing the actual
 	// key data through the writer. We also remove the table-level properties
@@ -84,7 +88,7 @@ func CopySpan(
 	rh := r.blockReader.UsePreallocatedReadHandle(
 		objstorage.ReadBeforeForIndexAndFilter, &preallocRH)
 	defer func() { _ = rh.Close() }()
-	rh.SetupForCompaction()
+	rh.SetupForCompaction(nil /* directIOBufs */)
 
 	bufferPool := metaBufferPools.Get().(*block.BufferPool)
 	defer metaBufferPools.Put(bufferPool)
@@ -102,18 +106,27 @@ func CopySpan(
 	// Copy the filter block if it exists. Note that we don't rely on the reader
 	// having been configured with a matching filter decoder.
 	if props.FilterFamily != "" {
-		bh, ok := metaIndex[filterFamilyToBlockName(base.TableFilterFamily(props.FilterFamily))]
-		if !ok {
+		family := base.TableFilterFamily(props.FilterFamily)
+		if bh, ok := metaIndex[filterFamilyToBlockName(family)]; ok {
+			filterBlock, err := r.readFilterBlock(ctx, block.NoReadEnv, rh, bh)
+			if err != nil {
+				return 0, errors.Wrap(err, "reading filter")
+			}
+			filterData := slices.Clone(filterBlock.BlockData())
+			filterBlock.Release()
+
+			w.setFilter(filterData, family)
+		} else if bh, ok := metaIndex[partitionedFilterFamilyToBlockName(family)]; ok {
+			// Copy all the partitions of a partitioned filter. As with a full
+			// filter, they remain valid for the subset of the data.
+			parts, err := r.readFilterPartitions(ctx, rh, bh, family)
+			if err != nil {
+				return 0, errors.Wrap(err, "reading filter partitions")
+			}
+			w.setPartitionedFilter(parts)
+		} else {
 			return 0, errors.Newf("table has filter policy %q but no corresponding filter block", props.FilterFamily)
 		}
-		filterBlock, err := r.readFilterBlock(ctx, block.NoReadEnv, rh, bh)
-		if err != nil {
-			return 0, errors.Wrap(err, "reading filter")
-		}
-		filterData := slices.Clone(filterBlock.BlockData())
-		filterBlock.Release()
-
-		w.setFilter(filterData, base.TableFilterFamily(props.FilterFamily))
 	}
 
 	indexH, err := r.readTopLevelIndexBlock(ctx, block.NoReadEnv, rh)
@@ -293,7 +306,7 @@ func copyWholeFileBecauseOfUnsupportedFeature(
 ) (size uint64, _ error) {
 	length := uint64(input.Size())
 	rh := input.NewReadHandle(objstorage.NoReadBefore)
-	rh.SetupForCompaction()
+	rh.SetupForCompaction(nil /* directIOBufs */)
 	if err := objstorage.Copy(ctx, rh, output, 0, length); err != nil {
 		output.Abort()
 		return 0, err
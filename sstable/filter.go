@@ -5,6 +5,7 @@
 package sstable
 
 import (
+	"sync"
 	"sync/atomic"
 
 	"github.com/cockroachdb/pebble/internal/base"
@@ -20,6 +21,27 @@ type FilterMetrics struct {
 	// the filter policy was checked but was unable to filter an access of a data
 	// block.
 	Misses int64
+	// RangeHits and RangeMisses are the hits and misses of range filter checks
+	// (see Reader.MayContainRange). They are not included in Hits, Misses or
+	// ByLevel.
+	RangeHits   int64
+	RangeMisses int64
+	// ByLevel holds the hits and misses broken down by the LSM level of the
+	// sstables. Filter checks that are not associated with a level (e.g. on
+	// flushable ingests) are only included in Hits and Misses.
+	ByLevel [NumFilterMetricsLevels]FilterLevelMetrics
+}
+
+// NumFilterMetricsLevels is the number of LSM levels for which filter metrics
+// are tracked.
+const NumFilterMetricsLevels = 7
+
+// FilterLevelMetrics holds the filter metrics for an LSM level.
+type FilterLevelMetrics struct {
+	// See FilterMetrics.Hits.
+	Hits int64
+	// See FilterMetrics.Misses.
+	Misses int64
 }
 
 // FilterMetricsTracker is used to keep track of filter metrics. It contains the
@@ -30,19 +52,170 @@ type FilterMetricsTracker struct {
 	hits atomic.Int64
 	// See FilterMetrics.Misses.
 	misses atomic.Int64
+	// See FilterMetrics.RangeHits and FilterMetrics.RangeMisses.
+	rangeChecks tableFilterCounters
+	// See FilterMetrics.ByLevel.
+	byLevel [NumFilterMetricsLevels]tableFilterCounters
+	// openLookups is the number of negative lookups (filter hits and false
+	// positives) recorded for the sstables in tables.m.
+	openLookups atomic.Int64
+	// tables holds the filter metrics of each open sstable, keyed by the file
+	// number of the sstable's backing object. The metrics of an sstable are
+	// discarded once all of its Readers are closed.
+	tables struct {
+		sync.Mutex
+		m map[base.DiskFileNum]*tableFilterMetrics
+		// numEntries and filterSize are the sums of the number of entries and
+		// the filter sizes of the sstables in m.
+		numEntries uint64
+		filterSize uint64
+	}
+}
+
+// tableFilterCounters holds filter hit and miss counts.
+type tableFilterCounters struct {
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// tableFilterMetrics holds the filter metrics of an open sstable.
+type tableFilterMetrics struct {
+	tableFilterCounters
+	// falsePositives is the number of point lookups that the filter did not
+	// exclude but that did not find any key with the prefix.
+	falsePositives atomic.Int64
+	// The following fields are protected by FilterMetricsTracker.tables.
+	refs       int
+	numEntries uint64
+	filterSize uint64
+}
+
+func (c *tableFilterCounters) record(mayContain bool) {
+	if mayContain {
+		c.misses.Add(1)
+	} else {
+		c.hits.Add(1)
+	}
 }
 
 // Load returns the current values as FilterMetrics.
 func (m *FilterMetricsTracker) Load() FilterMetrics {
-	return FilterMetrics{
-		Hits:   m.hits.Load(),
-		Misses: m.misses.Load(),
+	fm := FilterMetrics{
+		Hits:        m.hits.Load(),
+		Misses:      m.misses.Load(),
+		RangeHits:   m.rangeChecks.hits.Load(),
+		RangeMisses: m.rangeChecks.misses.Load(),
+	}
+	for i := range m.byLevel {
+		fm.ByLevel[i] = FilterLevelMetrics{
+			Hits:   m.byLevel[i].hits.Load(),
+			Misses: m.byLevel[i].misses.Load(),
+		}
+	}
+	return fm
+}
+
+// TableFilterMetrics holds the filter metrics of an sstable.
+type TableFilterMetrics struct {
+	// Hits is the number of point lookups answered by the filter, i.e. negative
+	// lookups that did not need to read a data block.
+	Hits int64
+	// Misses is the number of point lookups that the filter did not exclude.
+	Misses int64
+	// FalsePositives is the number of misses that did not find any key with
+	// the prefix.
+	FalsePositives int64
+}
+
+// TableMetrics returns the filter metrics recorded for the sstable with the
+// given backing file number while the sstable is open. It returns false if no
+// Reader of the sstable is open.
+func (m *FilterMetricsTracker) TableMetrics(fileNum base.DiskFileNum) (TableFilterMetrics, bool) {
+	m.tables.Lock()
+	defer m.tables.Unlock()
+	t, ok := m.tables.m[fileNum]
+	if !ok {
+		return TableFilterMetrics{}, false
+	}
+	return TableFilterMetrics{
+		Hits:           t.hits.Load(),
+		Misses:         t.misses.Load(),
+		FalsePositives: t.falsePositives.Load(),
+	}, true
+}
+
+// OpenTablesMetrics returns the total number of entries, the total filter size
+// and the total number of negative lookups (filter hits and false positives)
+// of the sstables with filters that are currently open.
+func (m *FilterMetricsTracker) OpenTablesMetrics() (numEntries, filterSize uint64, lookups int64) {
+	m.tables.Lock()
+	defer m.tables.Unlock()
+	return m.tables.numEntries, m.tables.filterSize, m.openLookups.Load()
+}
+
+// openTable returns the metrics of the sstable with the given backing file
+// number, creating them if necessary. Each call must be paired with a call to
+// closeTable.
+func (m *FilterMetricsTracker) openTable(
+	fileNum base.DiskFileNum, numEntries, filterSize uint64,
+) *tableFilterMetrics {
+	m.tables.Lock()
+	defer m.tables.Unlock()
+	t, ok := m.tables.m[fileNum]
+	if !ok {
+		if m.tables.m == nil {
+			m.tables.m = make(map[base.DiskFileNum]*tableFilterMetrics)
+		}
+		t = &tableFilterMetrics{numEntries: numEntries, filterSize: filterSize}
+		m.tables.m[fileNum] = t
+		m.tables.numEntries += numEntries
+		m.tables.filterSize += filterSize
+	}
+	t.refs++
+	return t
+}
+
+// closeTable releases a reference obtained by openTable, discarding the
+// metrics of the sstable once they are no longer referenced.
+func (m *FilterMetricsTracker) closeTable(fileNum base.DiskFileNum) {
+	m.tables.Lock()
+	defer m.tables.Unlock()
+	t, ok := m.tables.m[fileNum]
+	if !ok {
+		return
+	}
+	if t.refs--; t.refs > 0 {
+		return
+	}
+	delete(m.tables.m, fileNum)
+	m.tables.numEntries -= t.numEntries
+	m.tables.filterSize -= t.filterSize
+	m.openLookups.Add(-(t.hits.Load() + t.falsePositives.Load()))
+}
+
+// record records the result of a filter check on an sstable in the given
+// level.
+func (m *FilterMetricsTracker) record(level base.Level, mayContain bool) {
+	if mayContain {
+		m.misses.Add(1)
+	} else {
+		m.hits.Add(1)
+	}
+	if l, ok := level.Get(); ok && l < NumFilterMetricsLevels {
+		m.byLevel[l].record(mayContain)
 	}
 }
 
 type tableFilterReader struct {
 	decoder base.TableFilterDecoder
 	metrics *FilterMetricsTracker
+	// fileNum and tableMetrics identify and hold the filter metrics of this
+	// sstable; they are set by open, if metrics is set.
+	fileNum      base.DiskFileNum
+	tableMetrics *tableFilterMetrics
+	// partitioned is true if the table's filter block is the top-level index of
+	// a partitioned filter rather than a full filter block.
+	partitioned bool
 }
 
 func newTableFilterReader(
@@ -54,14 +227,72 @@ func newTableFilterReader(
 	}
 }
 
+// open starts tracking the filter metrics of the sstable. It must be paired
+// with a call to close.
+func (f *tableFilterReader) open(fileNum base.DiskFileNum, props *Properties) {
+	if f.metrics != nil {
+		f.fileNum = fileNum
+		f.tableMetrics = f.metrics.openTable(fileNum, props.NumEntries, props.FilterSize)
+	}
+}
+
+// close stops tracking the filter metrics of the sstable.
+func (f *tableFilterReader) close() {
+	if f.tableMetrics != nil {
+		f.metrics.closeTable(f.fileNum)
+		f.tableMetrics = nil
+	}
+}
+
+// mayContain returns whether the filter block data may contain the given key.
 func (f *tableFilterReader) mayContain(data, key []byte) bool {
-	mayContain := f.decoder.MayContain(data, key)
+	return f.decoder.MayContain(data, key)
+}
+
+// mayContainRange returns whether the filter block data may contain a key
+// within the inclusive range [lo, hi]. It returns true if the filter does not
+// support range queries.
+func (f *tableFilterReader) mayContainRange(data, lo, hi []byte) bool {
+	rd, ok := f.decoder.(base.TableRangeFilterDecoder)
+	if !ok {
+		return true
+	}
+	return rd.MayContainRange(data, lo, hi)
+}
+
+// recordResult updates the filter metrics with the result of a filter check on
+// an sstable in the given level.
+func (f *tableFilterReader) recordResult(level base.Level, mayContain bool) {
 	if f.metrics != nil {
-		if mayContain {
-			f.metrics.misses.Add(1)
-		} else {
-			f.metrics.hits.Add(1)
+		f.metrics.record(level, mayContain)
+	}
+	if f.tableMetrics != nil {
+		f.tableMetrics.record(mayContain)
+		if !mayContain {
+			f.metrics.openLookups.Add(1)
 		}
 	}
-	return mayContain
+}
+
+// recordFalsePositive records a point lookup that the filter did not exclude
+// but that did not find any key with the prefix.
+func (f *tableFilterReader) recordFalsePositive() {
+	if f.tableMetrics != nil {
+		f.tableMetrics.falsePositives.Add(1)
+		f.metrics.openLookups.Add(1)
+	}
+}
+
+// recordRangeResult updates the filter metrics with the result of a range
+// filter check.
+func (f *tableFilterReader) recordRangeResult(mayContain bool) {
+	if f.metrics != nil {
+		f.metrics.rangeChecks.record(mayContain)
+	}
+}
+
+// supportsRangeQueries returns true if the filter can answer range queries.
+func (f *tableFilterReader) supportsRangeQueries() bool {
+	_, ok := f.decoder.(base.TableRangeFilterDecoder)
+	return ok
 }
@@ -19,7 +19,8 @@ type TableFormat uint32
 // The available table formats, representing the tuple (magic number, version
 // number). Note that these values are not (and should not) be serialized to
 // disk. The ordering should follow the order the versions were introduced to
-// Pebble (i.e. the history is linear).
+// Pebble (i.e. the history is linear), with the exception of
+// TableFormatPebblev4ColumnarIndex.
 const (
 	TableFormatUnspecified TableFormat = iota
 	TableFormatLevelDB
@@ -37,6 +38,21 @@ const (
 	// TableFormatPebblev4 adds DELSIZED tombstones.
 	TableFormatPebblev4
 
+	// TableFormatPebblev4ColumnarIndex is TableFormatPebblev4 with columnar
+	// index blocks (see colblk.IndexBlockWriter), which are typically smaller
+	// and faster to seek than row-based index blocks; data blocks are still
+	// row-based. It was introduced after TableFormatPebblev10, but it is
+	// ordered here because it has the features of TableFormatPebblev4 and none
+	// of the later formats. As a consequence, FormatMajorVersion.MaxTableFormat
+	// does not determine whether a DB supports it (see
+	// pebble.FormatRowBlockColumnarIndex).
+	//
+	// A DB never writes tables in this format: the format major version that
+	// allows it also allows columnar data blocks, which the DB writes instead.
+	// The format is only produced by sstable writers configured with it (e.g.
+	// by applications that build sstables for ingestion).
+	TableFormatPebblev4ColumnarIndex
+
 	// TableFormatPebblev5 adds columnar blocks.
 	TableFormatPebblev5 // Columnar blocks.
 
@@ -60,6 +76,14 @@ const (
 	//  - support for tiering metadata (spanID, key).
 	TableFormatPebblev8
 
+	// TableFormatPebblev9 adds:
+	//  - partitioned table filters.
+	TableFormatPebblev9
+
+	// TableFormatPebblev10 adds:
+	//  - value columns in data blocks (see colblk.ValueSchema).
+	TableFormatPebblev10
+
 	NumTableFormats
 
 	TableFormatMax = NumTableFormats - 1
@@ -71,16 +95,19 @@ const (
 )
 
 var footerSizes [NumTableFormats]int = [NumTableFormats]int{
-	TableFormatLevelDB:   levelDBFooterLen,
-	TableFormatRocksDBv2: rocksDBFooterLen,
-	TableFormatPebblev1:  rocksDBFooterLen,
-	TableFormatPebblev2:  rocksDBFooterLen,
-	TableFormatPebblev3:  rocksDBFooterLen,
-	TableFormatPebblev4:  rocksDBFooterLen,
-	TableFormatPebblev5:  rocksDBFooterLen,
-	TableFormatPebblev6:  checkedPebbleDBFooterLen,
-	TableFormatPebblev7:  pebbleDBv7FooterLen,
-	TableFormatPebblev8:  pebbleDBv7FooterLen,
+	TableFormatLevelDB:               levelDBFooterLen,
+	TableFormatRocksDBv2:             rocksDBFooterLen,
+	TableFormatPebblev1:              rocksDBFooterLen,
+	TableFormatPebblev2:              rocksDBFooterLen,
+	TableFormatPebblev3:              rocksDBFooterLen,
+	TableFormatPebblev4:              rocksDBFooterLen,
+	TableFormatPebblev4ColumnarIndex: rocksDBFooterLen,
+	TableFormatPebblev5:              rocksDBFooterLen,
+	TableFormatPebblev6:              checkedPebbleDBFooterLen,
+	TableFormatPebblev7:              pebbleDBv7FooterLen,
+	TableFormatPebblev8:              pebbleDBv7FooterLen,
+	TableFormatPebblev9:              pebbleDBv7FooterLen,
+	TableFormatPebblev10:             pebbleDBv7FooterLen,
 }
 
 // TableFormatPebblev4, in addition to DELSIZED, introduces the use of
@@ -268,6 +295,16 @@ func parseTableFormat(magic []byte, version uint32) (TableFormat, error) {
 				"(unsupported rocksdb format version %d)", errors.Safe(version))
 		}
 		return TableFormatRocksDBv2, nil
+	case pebbleDBSignedMagic:
+		f, err := parseTableFormat([]byte(pebbleDBMagic), version)
+		if err != nil {
+			return TableFormatUnspecified, err
+		}
+		if f < TableFormatPebblev6 {
+			return TableFormatUnspecified, base.CorruptionErrorf(
+				"(unsupported signed pebble format version %d)", errors.Safe(version))
+		}
+		return f, nil
 	case pebbleDBMagic:
 		switch version {
 		case 1:
@@ -286,6 +323,12 @@ func parseTableFormat(magic []byte, version uint32) (TableFormat, error) {
 			return TableFormatPebblev7, nil
 		case 8:
 			return TableFormatPebblev8, nil
+		case 9:
+			return TableFormatPebblev9, nil
+		case 10:
+			return TableFormatPebblev10, nil
+		case 11:
+			return TableFormatPebblev4ColumnarIndex, nil
 		default:
 			return TableFormatUnspecified, base.CorruptionErrorf(
 				"(unsupported pebble format version %d)", errors.Safe(version))
@@ -302,11 +345,29 @@ func (f TableFormat) BlockColumnar() bool {
 	return f >= TableFormatPebblev5
 }
 
+// ColumnarIndex returns true iff the table format uses the columnar format for
+// index blocks.
+func (f TableFormat) ColumnarIndex() bool {
+	return f.BlockColumnar() || f == TableFormatPebblev4ColumnarIndex
+}
+
 // TieringMetadata returns true iff the table format supports tiering metadata.
 func (f TableFormat) TieringMetadata() bool {
 	return f >= TableFormatPebblev8
 }
 
+// PartitionedFilters returns true iff the table format supports partitioning
+// the table filter into per-index-partition filter blocks.
+func (f TableFormat) PartitionedFilters() bool {
+	return f >= TableFormatPebblev9
+}
+
+// ValueColumns returns true iff the table format supports storing structured
+// values in value columns (see colblk.ValueSchema).
+func (f TableFormat) ValueColumns() bool {
+	return f >= TableFormatPebblev10
+}
+
 // TieringColumnConfig returns the TieringColumnConfig for this table format.
 func (f TableFormat) TieringColumnConfig() colblk.OptionalColumnConfig {
 	if f.TieringMetadata() {
@@ -315,18 +376,28 @@ func (f TableFormat) TieringColumnConfig() colblk.OptionalColumnConfig {
 	return colblk.NoTieringColumns()
 }
 
-// FooterSize returns the maximum size of the footer for the table format.
-func (f TableFormat) FooterSize() int {
-	return footerSizes[f]
+// DataBlockColumnConfig returns the OptionalColumnConfig for data blocks of
+// this table format, with the given value schema (which can be nil).
+func (f TableFormat) DataBlockColumnConfig(
+	valueSchema *colblk.ValueSchema,
+) colblk.OptionalColumnConfig {
+	c := f.TieringColumnConfig()
+	c.ValueSchema = valueSchema
+	return c
 }
 
 func (f TableFormat) newIndexIter() blockiter.Index {
-	if !f.BlockColumnar() {
+	if !f.ColumnarIndex() {
 		return new(rowblk.IndexIter)
 	}
 	return new(colblk.IndexIter)
 }
 
+// FooterSize returns the maximum size of the footer for the table format.
+func (f TableFormat) FooterSize() int {
+	return footerSizes[f]
+}
+
 // AsTuple returns the TableFormat's (Magic String, Version) tuple.
 func (f TableFormat) AsTuple() (string, uint32) {
 	switch f {
@@ -350,6 +421,12 @@ func (f TableFormat) AsTuple() (string, uint32) {
 		return pebbleDBMagic, 7
 	case TableFormatPebblev8:
 		return pebbleDBMagic, 8
+	case TableFormatPebblev9:
+		return pebbleDBMagic, 9
+	case TableFormatPebblev10:
+		return pebbleDBMagic, 10
+	case TableFormatPebblev4ColumnarIndex:
+		return pebbleDBMagic, 11
 	default:
 		panic(errors.AssertionFailedf("sstable: unknown table format version tuple"))
 	}
@@ -380,6 +457,12 @@ func (f TableFormat) String() string {
 		return "(Pebble,v7)"
 	case TableFormatPebblev8:
 		return "(Pebble,v8)"
+	case TableFormatPebblev9:
+		return "(Pebble,v9)"
+	case TableFormatPebblev10:
+		return "(Pebble,v10)"
+	case TableFormatPebblev4ColumnarIndex:
+		return "(Pebble,v4-columnar-index)"
 	default:
 		panic(errors.AssertionFailedf("sstable: unknown table format version tuple"))
 	}
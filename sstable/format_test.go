@@ -83,6 +83,24 @@ func TestTableFormat_RoundTrip(t *testing.T) {
 			version: 8,
 			want:    TableFormatPebblev8,
 		},
+		{
+			name:    "PebbleDBv9",
+			magic:   pebbleDBMagic,
+			version: 9,
+			want:    TableFormatPebblev9,
+		},
+		{
+			name:    "PebbleDBv10",
+			magic:   pebbleDBMagic,
+			version: 10,
+			want:    TableFormatPebblev10,
+		},
+		{
+			name:    "PebbleDBv4ColumnarIndex",
+			magic:   pebbleDBMagic,
+			version: 11,
+			want:    TableFormatPebblev4ColumnarIndex,
+		},
 		// Invalid cases.
 		{
 			name:    "Invalid RocksDB version",
@@ -93,8 +111,8 @@ func TestTableFormat_RoundTrip(t *testing.T) {
 		{
 			name:    "Invalid PebbleDB version",
 			magic:   pebbleDBMagic,
-			version: 9,
-			wantErr: "pebble/table: invalid table 000001: (unsupported pebble format version 9)",
+			version: 12,
+			wantErr: "pebble/table: invalid table 000001: (unsupported pebble format version 12)",
 		},
 		{
 			name:    "Unknown magic string",
@@ -40,10 +40,13 @@ type Layout struct {
 	// ValidateBlockChecksums, which validates a static list of BlockHandles
 	// referenced in this struct.
 
-	Data               []block.HandleWithProperties
-	Index              []block.Handle
-	TopIndex           block.Handle
-	Filter             []NamedBlockHandle
+	Data     []block.HandleWithProperties
+	Index    []block.Handle
+	TopIndex block.Handle
+	Filter   []NamedBlockHandle
+	// FilterPartitions holds the partitions of a partitioned filter, in which
+	// case Filter holds the top-level filter index.
+	FilterPartitions   []block.Handle
 	RangeDel           block.Handle
 	RangeKey           block.Handle
 	ValueBlock         []block.Handle
@@ -74,6 +77,9 @@ func (l *Layout) orderedBlocks() []NamedBlockHandle {
 		blocks = append(blocks, NamedBlockHandle{l.TopIndex, "top-index"})
 	}
 	blocks = append(blocks, l.Filter...)
+	for i := range l.FilterPartitions {
+		blocks = append(blocks, NamedBlockHandle{l.FilterPartitions[i], "filter-partition"})
+	}
 	if l.RangeDel.Length != 0 {
 		blocks = append(blocks, NamedBlockHandle{l.RangeDel, "range-del"})
 	}
@@ -151,18 +157,22 @@ func (l *Layout) Describe(
 				panic(errors.AssertionFailedf("Error parsing table format."))
 			}
 
+			// The checksum, attributes and signature are located relative to the
+			// end of the footer.
+			checksumOffset := len(trailer) - magicLen - versionLen - checksumLen
 			var attributes Attributes
 			if format >= TableFormatPebblev7 {
-				attributes = Attributes(binary.LittleEndian.Uint32(trailer[pebbleDBV7FooterAttributesOffset:]))
+				attributes = Attributes(binary.LittleEndian.Uint32(trailer[checksumOffset-attributesLen:]))
+			}
+			var signature *footerSignature
+			if string(magicNumber) == pebbleDBSignedMagic {
+				signature = &footerSignature{}
+				signature.decode(trailer[signedFooterOffsets(trailer).signature:])
 			}
 
 			var computedChecksum uint32
 			var encodedChecksum uint32
 			if format >= TableFormatPebblev6 {
-				checksumOffset := checkedPebbleDBChecksumOffset
-				if format >= TableFormatPebblev7 {
-					checksumOffset = pebbleDBv7FooterChecksumOffset
-				}
 				computedChecksum = crc.CRC(0).
 					Update(trailer[:checksumOffset]).
 					Update(trailer[checksumOffset+checksumLen:]).
@@ -196,6 +206,16 @@ func (l *Layout) Describe(
 			}
 			offset += len(trailer) - trailing
 
+			if signature != nil {
+				sigOffset := offset - signatureLen
+				if format >= TableFormatPebblev7 {
+					sigOffset -= attributesLen
+				}
+				tpNode.Childf("%03d  signature key id: %d", sigOffset, signature.keyID)
+				tpNode.Childf("%03d  signature digest: %x", sigOffset+signatureKeyIDLen, signature.digest)
+				tpNode.Childf("%03d  signature mac: %x", sigOffset+signatureKeyIDLen+signatureDigestLen, signature.mac)
+			}
+
 			if format >= TableFormatPebblev7 {
 				// Attributes should be just prior to the checksum.
 				tpNode.Childf("%03d  attributes: %s", offset-attributesLen, attributes.String())
@@ -267,7 +287,17 @@ func (l *Layout) Describe(
 				if err != nil {
 					return err
 				}
-				err = formatting.formatIndexBlock(tpNode, r, *b, h.BlockData())
+				if r.tableFormat.ColumnarIndex() {
+					// Tables with row-based data blocks may use columnar index
+					// blocks.
+					err = formatColblkIndexBlock(tpNode, r, *b, h.BlockData())
+				} else {
+					err = formatting.formatIndexBlock(tpNode, r, *b, h.BlockData())
+				}
+
+			case "filter-partition":
+				// We don't peer into filter partitions; their encoding depends on
+				// the filter family.
 
 			case "properties":
 				h, err = r.blockReader.Read(ctx, block.NoReadEnv, noReadHandle, b.Handle, blockkind.Metadata, noInitBlockMetadataFn)
@@ -410,6 +440,15 @@ func (l *Layout) Describe(
 					tpNode.Childf("span=%d kind=%d: total-bytes=%d total-count=%d bytes-no-attr=%d",
 						key.TieringSpanID, key.KindAndTier, histogram.TotalBytes, histogram.TotalCount, histogram.BytesNoAttr)
 				}
+
+			default:
+				if _, ok := partitionedFilterFamilyFromBlockName(b.Name); ok {
+					h, err = r.readFilterBlock(ctx, block.NoReadEnv, noReadHandle, b.Handle)
+					if err != nil {
+						return err
+					}
+					err = formatting.formatIndexBlock(tpNode, r, *b, h.BlockData())
+				}
 			}
 
 			// Format the trailer.
@@ -483,9 +522,9 @@ func formatColblkDataBlock(
 
 	if fmtKV != nil {
 		var iter colblk.DataBlockIter
-		tieringConfig := r.tableFormat.TieringColumnConfig()
-		iter.InitOnce(r.keySchema, r.Comparer, describingLazyValueHandler{}, tieringConfig)
-		if err := iter.Init(&decoder, bd, blockiter.Transforms{}, tieringConfig); err != nil {
+		columnConfig := r.tableFormat.DataBlockColumnConfig(r.valueSchema)
+		iter.InitOnce(r.keySchema, r.Comparer, describingLazyValueHandler{}, columnConfig)
+		if err := iter.Init(&decoder, bd, blockiter.Transforms{}, columnConfig); err != nil {
 			return err
 		}
 		defer func() { _ = iter.Close() }()
@@ -644,13 +683,14 @@ func decodeLayout(comparer *base.Comparer, data []byte, tableFormat TableFormat)
 		return Layout{}, err
 	}
 
+	columnarIndex := foot.format.ColumnarIndex()
 	if props.IndexType == twoLevelIndex {
 		decompressed, err := decompressInMemory(data, foot.indexBH)
 		if err != nil {
 			return Layout{}, errors.Wrap(err, "decompressing two-level index")
 		}
 		layout.TopIndex = foot.indexBH
-		topLevelIter, err := newIndexIter(foot.format, comparer, decompressed)
+		topLevelIter, err := newIndexIter(columnarIndex, comparer, decompressed)
 		if err != nil {
 			return Layout{}, err
 		}
@@ -668,7 +708,7 @@ func decodeLayout(comparer *base.Comparer, data []byte, tableFormat TableFormat)
 		if err != nil {
 			return Layout{}, errors.Wrap(err, "decompressing index block")
 		}
-		indexIter, err := newIndexIter(foot.format, comparer, decompressed)
+		indexIter, err := newIndexIter(columnarIndex, comparer, decompressed)
 		if err != nil {
 			return Layout{}, err
 		}
@@ -715,11 +755,11 @@ func decompressInMemory(data []byte, bh block.Handle) ([]byte, error) {
 }
 
 func newIndexIter(
-	tableFormat TableFormat, comparer *base.Comparer, data []byte,
+	columnarIndex bool, comparer *base.Comparer, data []byte,
 ) (blockiter.Index, error) {
 	var iter blockiter.Index
 	var err error
-	if tableFormat <= TableFormatPebblev4 {
+	if !columnarIndex {
 		iter = new(rowblk.IndexIter)
 		err = iter.Init(comparer, data, blockiter.NoTransforms)
 	} else {
@@ -838,6 +878,11 @@ type layoutWriter struct {
 
 	physBlockMaker block.PhysicalBlockMaker
 
+	// signingKeys and signing are set when writing a signed table, in which
+	// case signing wraps the writable.
+	signingKeys SigningKeyProvider
+	signing     *signingWritable
+
 	// Attribute bitset of the sstable, derived from sstable Properties at the time
 	// of writing.
 	attributes Attributes
@@ -861,6 +906,14 @@ func (w *layoutWriter) Init(writable objstorage.Writable, opts WriterOptions) {
 	w.cacheOpts = opts.internal.CacheOpts
 	w.tableFormat = opts.TableFormat
 	w.physBlockMaker.Init(opts.Compression, opts.Checksum, opts.CompressionCounters)
+	if opts.SigningKeys != nil {
+		if opts.TableFormat < TableFormatPebblev6 {
+			panic(errors.AssertionFailedf("signed tables require TableFormatPebblev6+; got %s", opts.TableFormat))
+		}
+		w.signingKeys = opts.SigningKeys
+		w.signing = newSigningWritable(writable)
+		w.writable = w.signing
+	}
 }
 
 type metaIndexHandle struct {
@@ -913,6 +966,21 @@ func (w *layoutWriter) WriteFilterBlock(
 	return w.writeNamedBlockUncompressed(data, blockkind.Filter, filterFamilyToBlockName(family))
 }
 
+// WriteFilterPartitionBlock constructs a trailer and writes a partition of a
+// partitioned filter. The partition is referenced by the top-level filter
+// index; see WritePartitionedFilterIndexBlock.
+func (w *layoutWriter) WriteFilterPartitionBlock(data []byte) (block.Handle, error) {
+	return w.writeBlockUncompressed(data, blockkind.Filter)
+}
+
+// WritePartitionedFilterIndexBlock constructs a trailer, writes the top-level
+// index of a partitioned filter and adds it to the metaindex.
+func (w *layoutWriter) WritePartitionedFilterIndexBlock(
+	data []byte, family base.TableFilterFamily,
+) (block.Handle, error) {
+	return w.writeNamedBlockUncompressed(data, blockkind.Filter, partitionedFilterFamilyToBlockName(family))
+}
+
 // WritePropertiesBlock constructs a trailer for the provided properties block
 // and writes the block and trailer to the writer. It automatically adds the
 // properties block to the file's meta index when the writer is finished.
@@ -1088,6 +1156,9 @@ func (w *layoutWriter) Finish() (size uint64, err error) {
 		}
 		b = bw.Finish()
 	}
+	if w.signing != nil {
+		w.signing.writingMetaindex = true
+	}
 	metaIndexHandle, err := w.writeBlockUncompressed(b, blockkind.Metadata)
 	if err != nil {
 		return 0, err
@@ -1101,7 +1172,17 @@ func (w *layoutWriter) Finish() (size uint64, err error) {
 		indexBH:     w.lastIndexBlockHandle,
 		attributes:  w.attributes,
 	}
-	encodedFooter := footer.encode(w.tmp[:])
+	var footerBuf []byte
+	if w.signing != nil {
+		// Signed footers don't fit in tmp.
+		footerBuf = make([]byte, maxFooterLen)
+		if err := w.signing.sign(&footer, w.signingKeys, footerBuf); err != nil {
+			return 0, err
+		}
+	} else {
+		footerBuf = w.tmp[:]
+	}
+	encodedFooter := footer.encode(footerBuf)
 	if err := w.writable.Write(encodedFooter); err != nil {
 		return 0, err
 	}
@@ -1128,3 +1209,21 @@ func filterFamilyFromBlockName(blockMetaHandle string) (_ base.TableFilterFamily
 	}
 	return base.TableFilterFamily(res), ok
 }
+
+// partitionedFilterFamilyToBlockName returns the metaindex block key for the
+// top-level index of a partitioned filter with the given policy name.
+func partitionedFilterFamilyToBlockName(filterPolicy base.TableFilterFamily) string {
+	return "partitionedfilter." + string(filterPolicy)
+}
+
+// partitionedFilterFamilyFromBlockName is the inverse of
+// partitionedFilterFamilyToBlockName.
+func partitionedFilterFamilyFromBlockName(
+	blockMetaHandle string,
+) (_ base.TableFilterFamily, ok bool) {
+	res, ok := strings.CutPrefix(blockMetaHandle, "partitionedfilter.")
+	if !ok {
+		return "", ok
+	}
+	return base.TableFilterFamily(res), ok
+}
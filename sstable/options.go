@@ -54,6 +54,10 @@ type Mergers map[string]*base.Merger
 // contain sstables with multiple key schemas.
 type KeySchemas map[string]*colblk.KeySchema
 
+// ValueSchemas is a map from value schema name to value schema. A single
+// database may contain sstables with multiple value schemas.
+type ValueSchemas map[string]*colblk.ValueSchema
+
 // MakeKeySchemas constructs a KeySchemas from a slice of key schemas.
 func MakeKeySchemas(keySchemas ...*colblk.KeySchema) KeySchemas {
 	m := make(KeySchemas, len(keySchemas))
@@ -66,6 +70,18 @@ func MakeKeySchemas(keySchemas ...*colblk.KeySchema) KeySchemas {
 	return m
 }
 
+// MakeValueSchemas constructs a ValueSchemas from a slice of value schemas.
+func MakeValueSchemas(valueSchemas ...*colblk.ValueSchema) ValueSchemas {
+	m := make(ValueSchemas, len(valueSchemas))
+	for _, valueSchema := range valueSchemas {
+		if _, ok := m[valueSchema.Name]; ok {
+			panic(errors.AssertionFailedf("duplicate value schemas with name %q", errors.Safe(valueSchema.Name)))
+		}
+		m[valueSchema.Name] = valueSchema
+	}
+	return m
+}
+
 // ReaderOptions holds the parameters needed for reading an sstable.
 type ReaderOptions struct {
 	block.ReaderOptions
@@ -86,6 +102,11 @@ type ReaderOptions struct {
 	// columnar data blocks. Only used for sstables encoded in format
 	// TableFormatPebblev5 or higher.
 	KeySchemas KeySchemas
+	// ValueSchemas contains the set of known value schemas to use when
+	// interpreting value columns in columnar data blocks. Only used for
+	// sstables encoded in format TableFormatPebblev10 or higher that were
+	// written with a value schema.
+	ValueSchemas ValueSchemas
 
 	// FilterDecoders contains decoders for supported table filter families. If
 	// the filter family does not have a decoder in this list, the filter block
@@ -95,6 +116,12 @@ type ReaderOptions struct {
 	// FilterMetricsTracker is optionally used to track filter metrics.
 	FilterMetricsTracker *FilterMetricsTracker
 
+	// SigningKeys, if set, is used to authenticate signed tables (see
+	// WriterOptions.SigningKeys) when they are opened. Signed tables can't be
+	// opened without SigningKeys. Note that opening a table only authenticates
+	// its footer and metaindex block; see VerifySignature.
+	SigningKeys SigningKeyProvider
+
 	// InitFileReadStats is to only be used for reads in NewReader and forgotten
 	// after.
 	InitFileReadStats block.InitFileReadStats
@@ -184,6 +211,13 @@ type WriterOptions struct {
 	// The default value is NoFilterPolicy.
 	FilterPolicy base.TableFilterPolicy
 
+	// PartitionFilters, if true, causes the table filter to be partitioned into
+	// one filter block per index partition, so that a filter check only reads
+	// the filter partitions that may contain the key. It is ignored if the table
+	// format does not support partitioned filters (see
+	// TableFormat.PartitionedFilters).
+	PartitionFilters bool
+
 	// IndexBlockSize is the target uncompressed size in bytes of each index
 	// block. When the index block size is larger than this target, two-level
 	// indexes are automatically enabled. Setting this option to a large value
@@ -193,11 +227,27 @@ type WriterOptions struct {
 	// The default value is the value of BlockSize.
 	IndexBlockSize int
 
+	// SigningKeys, if set, causes the table to be signed: the footer includes
+	// an HMAC, keyed with SigningKeys.SigningKey(), which authenticates the
+	// metaindex block and a digest of all the other blocks. Readers verify the
+	// HMAC when opening the table; VerifySignature verifies the entire table.
+	// Requires TableFormat >= TableFormatPebblev6.
+	//
+	// Signed tables can't be read by versions of Pebble that do not support
+	// them.
+	SigningKeys SigningKeyProvider
+
 	// KeySchema describes the schema to use for sstable formats that make use
 	// of columnar blocks, decomposing keys into their constituent components.
-	// Ignored if TableFormat <= TableFormatPebblev4.
+	// Ignored if TableFormat < TableFormatPebblev5.
 	KeySchema *colblk.KeySchema
 
+	// ValueSchema optionally describes how to store structured values as
+	// separate columns in columnar data blocks, allowing iterators to decode
+	// only a subset of each value (see IterOptions.ValueProjection). Requires
+	// TableFormat >= TableFormatPebblev10.
+	ValueSchema *colblk.ValueSchema
+
 	// Merger defines the associative merge operation to use for merging values
 	// written with {Batch,DB}.Merge. The MergerName is checked for consistency
 	// with the value stored in the sstable when it was written.
@@ -280,6 +330,15 @@ type WriterOptions struct {
 	// CompressionCounters are updated by the writer (if not nil).
 	CompressionCounters *block.ByKind[block.LogicalBytesCompressed]
 
+	// CompressionWorkers is the number of goroutines used to compress data
+	// blocks. Blocks are compressed concurrently but are always written in
+	// order; the data blocks are identical to the ones written without
+	// compression workers, but index blocks may be split differently since the
+	// writer decides when to finish an index block before the compressed sizes
+	// of the pending data blocks are known. If zero, data blocks are compressed
+	// by the goroutine that writes to the sstable Writer.
+	CompressionWorkers int
+
 	// internal options can only be used from within the pebble package.
 	internal sstableinternal.WriterOptions
 
@@ -88,6 +88,9 @@ type Properties struct {
 	// The name of the key schema used in this table. Empty for formats <=
 	// TableFormatPebblev4.
 	KeySchemaName string `prop:"pebble.colblk.schema" options:"intern"`
+	// The name of the value schema used in this table. Empty if the table does
+	// not store values in value columns (see colblk.ValueSchema).
+	ValueSchemaName string `prop:"pebble.colblk.value-schema" options:"intern"`
 	// The name of the merger used in this table. Empty if no merger is used.
 	MergerName string `prop:"rocksdb.merge.operator" options:"encodeempty,intern"`
 	// The number of merge operands in the table.
@@ -102,6 +102,9 @@ func (p *Properties) load(i iter.Seq2[[]byte, []byte]) error {
 		case "pebble.colblk.schema":
 			p.Loaded |= 1 << _bit_KeySchemaName
 			p.KeySchemaName = intern.Bytes(v)
+		case "pebble.colblk.value-schema":
+			p.Loaded |= 1 << _bit_ValueSchemaName
+			p.ValueSchemaName = intern.Bytes(v)
 		case "rocksdb.merge.operator":
 			p.Loaded |= 1 << _bit_MergerName
 			p.MergerName = intern.Bytes(v)
@@ -319,6 +322,11 @@ func (p *Properties) encodeAll() map[string][]byte {
 		copy(val, p.KeySchemaName)
 		m["pebble.colblk.schema"] = val
 	}
+	if p.ValueSchemaName != "" {
+		val := alloc(len(p.ValueSchemaName))
+		copy(val, p.ValueSchemaName)
+		m["pebble.colblk.value-schema"] = val
+	}
 	if true {
 		val := alloc(len(p.MergerName))
 		copy(val, p.MergerName)
@@ -495,6 +503,9 @@ func (p *Properties) String() string {
 	if p.KeySchemaName != "" || p.isLoaded(_bit_KeySchemaName) {
 		fmt.Fprintf(&buf, "%s: %v\n", "pebble.colblk.schema", p.KeySchemaName)
 	}
+	if p.ValueSchemaName != "" || p.isLoaded(_bit_ValueSchemaName) {
+		fmt.Fprintf(&buf, "%s: %v\n", "pebble.colblk.value-schema", p.ValueSchemaName)
+	}
 	if p.MergerName != "" || p.isLoaded(_bit_MergerName) {
 		fmt.Fprintf(&buf, "%s: %v\n", "rocksdb.merge.operator", p.MergerName)
 	}
@@ -584,22 +595,23 @@ const (
 	_bit_IndexType                       = 19
 	_bit_IsStrictObsolete                = 20
 	_bit_KeySchemaName                   = 21
-	_bit_MergerName                      = 22
-	_bit_NumMergeOperands                = 23
-	_bit_NumRangeKeyUnsets               = 24
-	_bit_NumValueBlocks                  = 25
-	_bit_NumValuesInValueBlocks          = 26
-	_bit_NumValuesInBlobFiles            = 27
-	_bit_PropertyCollectorNames          = 28
-	_bit_RawRangeKeyKeySize              = 29
-	_bit_RawRangeKeyValueSize            = 30
-	_bit_SnapshotPinnedKeys              = 31
-	_bit_SnapshotPinnedKeySize           = 32
-	_bit_SnapshotPinnedValueSize         = 33
-	_bit_TopLevelIndexSize               = 34
-	_bit_CompressionName                 = 35
-	_bit_CompressionStats                = 36
-	_bit_ValueSeparationMinSize          = 37
-	_bit_ValueSeparationBySuffixDisabled = 38
-	_numPropBits                         = 39
+	_bit_ValueSchemaName                 = 22
+	_bit_MergerName                      = 23
+	_bit_NumMergeOperands                = 24
+	_bit_NumRangeKeyUnsets               = 25
+	_bit_NumValueBlocks                  = 26
+	_bit_NumValuesInValueBlocks          = 27
+	_bit_NumValuesInBlobFiles            = 28
+	_bit_PropertyCollectorNames          = 29
+	_bit_RawRangeKeyKeySize              = 30
+	_bit_RawRangeKeyValueSize            = 31
+	_bit_SnapshotPinnedKeys              = 32
+	_bit_SnapshotPinnedKeySize           = 33
+	_bit_SnapshotPinnedValueSize         = 34
+	_bit_TopLevelIndexSize               = 35
+	_bit_CompressionName                 = 36
+	_bit_CompressionStats                = 37
+	_bit_ValueSeparationMinSize          = 38
+	_bit_ValueSeparationBySuffixDisabled = 39
+	_numPropBits                         = 40
 )
@@ -318,6 +318,7 @@ func (cfg *randomTableConfig) randomize() {
 		}
 		if bitsPerKey := cfg.rng.Uint32N(11); bitsPerKey > 0 {
 			cfg.wopts.FilterPolicy = bloom.FilterPolicy(bitsPerKey)
+			cfg.wopts.PartitionFilters = cfg.rng.IntN(2) == 1
 		}
 		if cfg.wopts.TableFormat >= TableFormatPebblev1 && cfg.rng.Float64() < 0.75 {
 			cfg.wopts.BlockPropertyCollectors = append(cfg.wopts.BlockPropertyCollectors, NewTestKeysBlockPropertyCollector)
@@ -47,6 +47,7 @@ type Reader struct {
 
 	// The following fields are copied from the ReadOptions.
 	keySchema            *colblk.KeySchema
+	valueSchema          *colblk.ValueSchema
 	filterMetricsTracker *FilterMetricsTracker
 	Comparer             *base.Comparer
 
@@ -84,6 +85,9 @@ var NoReadEnv = ReadEnv{}
 
 // Close the reader and the underlying objstorage.Readable.
 func (r *Reader) Close() error {
+	if r.tableFilter != nil {
+		r.tableFilter.close()
+	}
 	r.err = firstError(r.err, r.blockReader.Close())
 	if r.err != nil {
 		return r.err
@@ -103,6 +107,14 @@ type IterOptions struct {
 	ReaderProvider        valblk.ReaderProvider
 	BlobContext           TableBlobContext
 	MaximumSuffixProperty MaximumSuffixProperty
+	// ValueProjection identifies the value columns to decode when reading
+	// values stored in value columns (see WriterOptions.ValueSchema). The zero
+	// value decodes all columns.
+	ValueProjection colblk.ValueProjection
+	// PointKeyPredicate, if set, is used to skip point keys that don't match
+	// the predicate. Only used for tables with columnar data blocks. See
+	// base.PointKeyPredicate.
+	PointKeyPredicate base.PointKeyPredicate
 }
 
 // MaximumSuffixProperty is an interface used by the sstable iterator's
@@ -165,6 +177,121 @@ func (r *Reader) NewPointIter(ctx context.Context, opts IterOptions) (Iterator,
 	return r.newPointIter(ctx, opts)
 }
 
+// MayContainRange returns false if the table's filter guarantees that the
+// table contains no point keys within the bounds [lower, upper). It returns
+// true if the table has no range filter (see rangefilter.FilterPolicy), if the
+// filter block exceeds filterBlockSizeLimit, or if the Comparer does not declare
+// BytewisePrefixOrder.
+//
+// The bounds are user keys; they must not include a synthetic prefix.
+func (r *Reader) MayContainRange(
+	ctx context.Context, env ReadEnv, filterBlockSizeLimit FilterBlockSizeLimit, lower, upper []byte,
+) (bool, error) {
+	if !r.Comparer.BytewisePrefixOrder || !shouldUseFilterBlock(r, filterBlockSizeLimit) ||
+		!r.tableFilter.supportsRangeQueries() {
+		return true, nil
+	}
+	// The filter contains the prefixes of the keys. Prefixes are ordered
+	// bytewise, and any key k with lower <= k < upper has a prefix within
+	// [Prefix(lower), Prefix(upper)].
+	lo := lower[:r.Comparer.Split(lower)]
+	hi := upper[:r.Comparer.Split(upper)]
+	return r.filterMayContain(ctx, env.Block, nil /* readHandle */, filterBlockSizeLimit, lo, hi)
+}
+
+// filterMayContain returns false if the table filter guarantees that the table
+// contains no point keys with a prefix within the inclusive range [lo, hi]. If
+// hi is nil, the filter is checked for the single prefix lo. The caller must
+// have checked shouldUseFilterBlock.
+//
+// If the filter is partitioned, only the partitions that may contain prefixes
+// in [lo, hi] are read. Partitions that are larger than filterBlockSizeLimit
+// are assumed to contain the prefixes.
+func (r *Reader) filterMayContain(
+	ctx context.Context,
+	env block.ReadEnv,
+	readHandle objstorage.ReadHandle,
+	filterBlockSizeLimit FilterBlockSizeLimit,
+	lo, hi []byte,
+) (bool, error) {
+	filterH, err := r.readFilterBlock(ctx, env, readHandle, r.filterBH)
+	if err != nil {
+		return false, err
+	}
+	defer filterH.Release()
+	if !r.tableFilter.partitioned {
+		mayContain := r.tableFilterMayContain(filterH.BlockData(), lo, hi)
+		r.recordFilterResult(env.Level, hi != nil, mayContain)
+		return mayContain, nil
+	}
+
+	// The top-level filter index maps the separator of each index partition to
+	// the filter partition containing the prefixes of that partition's keys.
+	// The keys of partition i are in (sep[i-1], sep[i]], so the partitions that
+	// may contain a prefix within [lo, hi] are those starting at the first
+	// separator >= lo, up to the first separator with a prefix > hi.
+	var iter colblk.IndexIter
+	if err := iter.Init(r.Comparer, filterH.BlockData(), NoTransforms); err != nil {
+		return false, err
+	}
+	defer func() { _ = iter.Close() }()
+	last := hi
+	if last == nil {
+		last = lo
+	}
+	mayContain := false
+	for valid := iter.SeekGE(lo); valid && !mayContain; valid = iter.Next() {
+		bh, err := iter.BlockHandleWithProperties()
+		if err != nil {
+			return false, err
+		}
+		if bh.Length == 0 || bh.Length > uint64(filterBlockSizeLimit) {
+			// There is no filter for this partition, or it is too large to use.
+			mayContain = true
+			break
+		}
+		partitionH, err := r.readFilterBlock(ctx, env, readHandle, bh.Handle)
+		if err != nil {
+			return false, err
+		}
+		mayContain = r.tableFilterMayContain(partitionH.BlockData(), lo, hi)
+		partitionH.Release()
+		if sep := iter.Separator(); bytes.Compare(sep[:r.Comparer.Split(sep)], last) > 0 {
+			break
+		}
+	}
+	r.recordFilterResult(env.Level, hi != nil, mayContain)
+	return mayContain, nil
+}
+
+// recordFilterResult records the result of a point (or, if isRange is set,
+// range) filter check.
+func (r *Reader) recordFilterResult(level base.Level, isRange bool, mayContain bool) {
+	if isRange {
+		r.tableFilter.recordRangeResult(mayContain)
+	} else {
+		r.tableFilter.recordResult(level, mayContain)
+	}
+}
+
+// maybeRecordFilterFalsePositive records a filter false positive if kv, the
+// result of a point lookup for the given prefix that was not excluded by the
+// filter, does not have the prefix.
+func (r *Reader) maybeRecordFilterFalsePositive(prefix []byte, kv *base.InternalKV) {
+	if kv == nil || !bytes.Equal(r.Comparer.Split.Prefix(kv.K.UserKey), prefix) {
+		r.tableFilter.recordFalsePositive()
+	}
+}
+
+// tableFilterMayContain checks a single filter block for the prefix lo (if hi
+// is nil) or for the inclusive prefix range [lo, hi].
+func (r *Reader) tableFilterMayContain(data, lo, hi []byte) bool {
+	if hi == nil {
+		return r.tableFilter.mayContain(data, lo)
+	}
+	return r.tableFilter.mayContainRange(data, lo, hi)
+}
+
 // TryAddBlockPropertyFilterForHideObsoletePoints is expected to be called
 // before the call to NewPointIter, to get the value of hideObsoletePoints and
 // potentially add a block property filter.
@@ -191,6 +318,9 @@ func (r *Reader) newPointIter(ctx context.Context, opts IterOptions) (Iterator,
 		if r.tableFormat.BlockColumnar() {
 			res, err = newColumnBlockTwoLevelIterator(
 				ctx, r, opts)
+		} else if r.tableFormat.ColumnarIndex() {
+			res, err = newRowBlockColumnarIndexTwoLevelIterator(
+				ctx, r, opts)
 		} else {
 			res, err = newRowBlockTwoLevelIterator(
 				ctx, r, opts)
@@ -199,6 +329,9 @@ func (r *Reader) newPointIter(ctx context.Context, opts IterOptions) (Iterator,
 		if r.tableFormat.BlockColumnar() {
 			res, err = newColumnBlockSingleLevelIterator(
 				ctx, r, opts)
+		} else if r.tableFormat.ColumnarIndex() {
+			res, err = newRowBlockColumnarIndexSingleLevelIterator(
+				ctx, r, opts)
 		} else {
 			res, err = newRowBlockSingleLevelIterator(
 				ctx, r, opts)
@@ -269,6 +402,14 @@ func (r *Reader) newCompactionIter(
 	}
 
 	if r.Attributes.Has(AttributeTwoLevelIndex) {
+		if !r.tableFormat.BlockColumnar() && r.tableFormat.ColumnarIndex() {
+			i, err := newRowBlockColumnarIndexTwoLevelIterator(ctx, r, opts)
+			if err != nil {
+				return nil, err
+			}
+			i.SetupForCompaction()
+			return i, nil
+		}
 		if !r.tableFormat.BlockColumnar() {
 			i, err := newRowBlockTwoLevelIterator(ctx, r, opts)
 			if err != nil {
@@ -284,6 +425,14 @@ func (r *Reader) newCompactionIter(
 		i.SetupForCompaction()
 		return i, nil
 	}
+	if !r.tableFormat.BlockColumnar() && r.tableFormat.ColumnarIndex() {
+		i, err := newRowBlockColumnarIndexSingleLevelIterator(ctx, r, opts)
+		if err != nil {
+			return nil, err
+		}
+		i.SetupForCompaction()
+		return i, nil
+	}
 	if !r.tableFormat.BlockColumnar() {
 		i, err := newRowBlockSingleLevelIterator(ctx, r, opts)
 		if err != nil {
@@ -419,7 +568,7 @@ func (r *Reader) readIndexBlock(
 // initIndexBlockMetadata initializes the Metadata for a data block. This will
 // later be used (and reused) when reading from the block.
 func (r *Reader) initIndexBlockMetadata(metadata *block.Metadata, data []byte) error {
-	if r.tableFormat.BlockColumnar() {
+	if r.tableFormat.ColumnarIndex() {
 		return colblk.InitIndexBlockMetadata(metadata, data)
 	}
 	return nil
@@ -594,6 +743,12 @@ func (r *Reader) initMetaindexBlocks(
 			r.tableFilter = newTableFilterReader(fd, r.filterMetricsTracker)
 			break
 		}
+		if bh, ok := meta[partitionedFilterFamilyToBlockName(fd.Family())]; ok {
+			r.filterBH = bh
+			r.tableFilter = newTableFilterReader(fd, r.filterMetricsTracker)
+			r.tableFilter.partitioned = true
+			break
+		}
 	}
 	return nil
 }
@@ -688,6 +843,17 @@ func (r *Reader) Layout() (*Layout, error) {
 	for name, bh := range meta {
 		if _, ok := filterFamilyFromBlockName(name); ok {
 			l.Filter = append(l.Filter, NamedBlockHandle{Name: name, Handle: bh})
+		} else if _, ok := partitionedFilterFamilyFromBlockName(name); ok {
+			l.Filter = append(l.Filter, NamedBlockHandle{Name: name, Handle: bh})
+			err := r.forEachFilterPartition(ctx, noReadHandle, bh, func(_ []byte, bh block.Handle) error {
+				if bh.Length != 0 {
+					l.FilterPartitions = append(l.FilterPartitions, bh)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -773,6 +939,67 @@ func (r *Reader) Layout() (*Layout, error) {
 	return l, nil
 }
 
+// forEachFilterPartition reads the top-level index of a partitioned filter and
+// calls fn with the separator and block handle of each partition, in order. The
+// block handle is empty for partitions without a filter. The separator is only
+// valid for the duration of the call.
+func (r *Reader) forEachFilterPartition(
+	ctx context.Context,
+	readHandle objstorage.ReadHandle,
+	bh block.Handle,
+	fn func(sep []byte, bh block.Handle) error,
+) error {
+	h, err := r.readFilterBlock(ctx, block.NoReadEnv, readHandle, bh)
+	if err != nil {
+		return err
+	}
+	defer h.Release()
+	var iter colblk.IndexIter
+	if err := iter.Init(r.Comparer, h.BlockData(), NoTransforms); err != nil {
+		return err
+	}
+	defer func() { _ = iter.Close() }()
+	for valid := iter.First(); valid; valid = iter.Next() {
+		partitionBH, err := iter.BlockHandleWithProperties()
+		if err != nil {
+			return errCorruptIndexEntry(err)
+		}
+		if err := fn(iter.Separator(), partitionBH.Handle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readFilterPartitions reads the partitions of a partitioned filter with the
+// given family. The returned partitions don't reference the block cache.
+func (r *Reader) readFilterPartitions(
+	ctx context.Context,
+	readHandle objstorage.ReadHandle,
+	bh block.Handle,
+	family base.TableFilterFamily,
+) ([]filterPartition, error) {
+	var parts []filterPartition
+	err := r.forEachFilterPartition(ctx, readHandle, bh, func(sep []byte, bh block.Handle) error {
+		part := filterPartition{sep: slices.Clone(sep), family: family}
+		if bh.Length != 0 {
+			h, err := r.readFilterBlock(ctx, block.NoReadEnv, readHandle, bh)
+			if err != nil {
+				return err
+			}
+			part.data = slices.Clone(h.BlockData())
+			part.ok = true
+			h.Release()
+		}
+		parts = append(parts, part)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parts, nil
+}
+
 // ValidateBlockChecksums validates the checksums for each block in the SSTable.
 func (r *Reader) ValidateBlockChecksums() error {
 	// Pre-compute the BlockHandles for the underlying file.
@@ -810,6 +1037,12 @@ func (r *Reader) ValidateBlockChecksums() error {
 			readFn: r.readFilterBlock,
 		})
 	}
+	for _, bh := range l.FilterPartitions {
+		blocks = append(blocks, blk{
+			bh:     bh,
+			readFn: r.readFilterBlock,
+		})
+	}
 	blocks = append(blocks, blk{
 		bh:     l.RangeDel,
 		readFn: r.readRangeDelBlock,
@@ -874,26 +1107,86 @@ func (r *Reader) ValidateBlockChecksums() error {
 func (r *Reader) EstimateDiskUsage(
 	start []byte, end []byte, env ReadEnv, transforms IterTransforms,
 ) (uint64, error) {
+	startOffset, endOffset, props, err := r.dataBlockRange(start, end, env, transforms)
+	if err != nil || startOffset == endOffset {
+		return 0, err
+	}
+	// INVARIANT: props.DataSize > 0 since some data block overlaps the range.
+	// Linearly interpolate what is stored in value blocks.
+	//
+	// TODO(sumeer): if we need more accuracy, without loading any data blocks
+	// (which contain the value handles, and which may also be insufficient if
+	// the values are in separate files), we will need to accumulate the
+	// logical size of the key-value pairs and store the cumulative value for
+	// each data block in the index block entry. This increases the size of
+	// the BlockHandle, so wait until this becomes necessary.
+	dataBlockSize := endOffset - startOffset
+	return dataBlockSize +
+		uint64((float64(dataBlockSize)/float64(props.DataSize))*
+			float64(props.ValueBlocksSize)), nil
+}
+
+// ByteRange is a range of bytes [Offset, Offset+Length) within a table.
+type ByteRange struct {
+	Offset uint64
+	Length uint64
+}
+
+// ByteRangesForSpan returns the byte ranges of the table that are read when
+// iterating over the keys in `[start, end]`: the data blocks overlapping the
+// span and the region that follows all the data blocks, which contains the
+// index, filter, value and metadata blocks. The ranges are in increasing offset
+// order. If no data block overlaps the span, only the latter range is
+// returned.
+func (r *Reader) ByteRangesForSpan(
+	start []byte, end []byte, env ReadEnv, transforms IterTransforms,
+) ([]ByteRange, error) {
+	startOffset, endOffset, props, err := r.dataBlockRange(start, end, env, transforms)
+	if err != nil {
+		return nil, err
+	}
+	if startOffset == endOffset {
+		// The properties are not read when no data block overlaps the span.
+		if props, err = r.ReadPropertiesBlock(context.TODO(), nil /* buffer pool */); err != nil {
+			return nil, err
+		}
+	}
+	var ranges []ByteRange
+	if startOffset < endOffset {
+		ranges = append(ranges, ByteRange{Offset: startOffset, Length: endOffset - startOffset})
+	}
+	if fileSize := uint64(r.blockReader.Readable().Size()); props.DataSize < fileSize {
+		ranges = append(ranges, ByteRange{Offset: props.DataSize, Length: fileSize - props.DataSize})
+	}
+	return ranges, nil
+}
+
+// dataBlockRange returns the byte range [startOffset, endOffset) of the data
+// blocks overlapping `[start, end]`. If no data block overlaps, startOffset ==
+// endOffset, and the table properties are not read.
+func (r *Reader) dataBlockRange(
+	start []byte, end []byte, env ReadEnv, transforms IterTransforms,
+) (startOffset, endOffset uint64, props Properties, _ error) {
 	if env.Virtual != nil {
 		_, start, end = env.Virtual.ConstrainBounds(start, end, false, r.Comparer.Compare)
 	}
-	if !r.tableFormat.BlockColumnar() {
-		return estimateDiskUsage[rowblk.IndexIter, *rowblk.IndexIter](r, start, end, transforms)
+	if !r.tableFormat.ColumnarIndex() {
+		return dataBlockRange[rowblk.IndexIter, *rowblk.IndexIter](r, start, end, transforms)
 	}
-	return estimateDiskUsage[colblk.IndexIter, *colblk.IndexIter](r, start, end, transforms)
+	return dataBlockRange[colblk.IndexIter, *colblk.IndexIter](r, start, end, transforms)
 }
 
-func estimateDiskUsage[I any, PI indexBlockIterator[I]](
+func dataBlockRange[I any, PI indexBlockIterator[I]](
 	r *Reader, start, end []byte, transforms IterTransforms,
-) (uint64, error) {
+) (startOffset, endOffset uint64, props Properties, _ error) {
 	if r.err != nil {
-		return 0, r.err
+		return 0, 0, Properties{}, r.err
 	}
 	ctx := context.TODO()
 
 	indexH, err := r.readTopLevelIndexBlock(ctx, block.NoReadEnv, noReadHandle)
 	if err != nil {
-		return 0, err
+		return 0, 0, Properties{}, err
 	}
 	// We are using InitHandle below but we never Close those iterators, which
 	// allows us to release the index handle ourselves.
@@ -907,47 +1200,47 @@ func estimateDiskUsage[I any, PI indexBlockIterator[I]](
 	if !r.Attributes.Has(AttributeTwoLevelIndex) {
 		startIdxIter = new(I)
 		if err := startIdxIter.InitHandle(r.Comparer, indexH, transforms); err != nil {
-			return 0, err
+			return 0, 0, Properties{}, err
 		}
 		endIdxIter = startIdxIter
 	} else {
 		var topIter PI = new(I)
 		if err := topIter.InitHandle(r.Comparer, indexH, transforms); err != nil {
-			return 0, err
+			return 0, 0, Properties{}, err
 		}
 		if !topIter.SeekGE(start) {
 			// The range falls completely after this file.
-			return 0, nil
+			return 0, 0, Properties{}, nil
 		}
 		startIndexBH, err := topIter.BlockHandleWithProperties()
 		if err != nil {
-			return 0, errCorruptIndexEntry(err)
+			return 0, 0, Properties{}, errCorruptIndexEntry(err)
 		}
 		startIdxBlock, err := r.readIndexBlock(ctx, block.NoReadEnv, noReadHandle, startIndexBH.Handle)
 		if err != nil {
-			return 0, err
+			return 0, 0, Properties{}, err
 		}
 		defer startIdxBlock.Release()
 		startIdxIter = new(I)
 		err = startIdxIter.InitHandle(r.Comparer, startIdxBlock, transforms)
 		if err != nil {
-			return 0, err
+			return 0, 0, Properties{}, err
 		}
 
 		if topIter.SeekGE(end) {
 			endIndexBH, err := topIter.BlockHandleWithProperties()
 			if err != nil {
-				return 0, errCorruptIndexEntry(err)
+				return 0, 0, Properties{}, errCorruptIndexEntry(err)
 			}
 			endIdxBlock, err := r.readIndexBlock(ctx, block.NoReadEnv, noReadHandle, endIndexBH.Handle)
 			if err != nil {
-				return 0, err
+				return 0, 0, Properties{}, err
 			}
 			defer endIdxBlock.Release()
 			endIdxIter = new(I)
 			err = endIdxIter.InitHandle(r.Comparer, endIdxBlock, transforms)
 			if err != nil {
-				return 0, err
+				return 0, 0, Properties{}, err
 			}
 		}
 	}
@@ -956,46 +1249,27 @@ func estimateDiskUsage[I any, PI indexBlockIterator[I]](
 
 	if !startIdxIter.SeekGE(start) {
 		// The range falls completely after this file.
-		return 0, nil
+		return 0, 0, Properties{}, nil
 	}
 	startBH, err := startIdxIter.BlockHandleWithProperties()
 	if err != nil {
-		return 0, errCorruptIndexEntry(err)
+		return 0, 0, Properties{}, errCorruptIndexEntry(err)
 	}
 
-	props, err := r.ReadPropertiesBlock(ctx, nil /* buffer pool */)
+	props, err = r.ReadPropertiesBlock(ctx, nil /* buffer pool */)
 	if err != nil {
-		return 0, err
+		return 0, 0, Properties{}, err
 	}
 
-	includeInterpolatedValueBlocksSize := func(dataBlockSize uint64) uint64 {
-		// INVARIANT: props.DataSize > 0 since startIdxIter is not nil.
-		// Linearly interpolate what is stored in value blocks.
-		//
-		// TODO(sumeer): if we need more accuracy, without loading any data blocks
-		// (which contain the value handles, and which may also be insufficient if
-		// the values are in separate files), we will need to accumulate the
-		// logical size of the key-value pairs and store the cumulative value for
-		// each data block in the index block entry. This increases the size of
-		// the BlockHandle, so wait until this becomes necessary.
-		return dataBlockSize +
-			uint64((float64(dataBlockSize)/float64(props.DataSize))*
-				float64(props.ValueBlocksSize))
-	}
-	if endIdxIter == nil {
+	if endIdxIter == nil || !endIdxIter.SeekGE(end) {
 		// The range spans beyond this file. Include data blocks through the last.
-		return includeInterpolatedValueBlocksSize(props.DataSize - startBH.Offset), nil
-	}
-	if !endIdxIter.SeekGE(end) {
-		// The range spans beyond this file. Include data blocks through the last.
-		return includeInterpolatedValueBlocksSize(props.DataSize - startBH.Offset), nil
+		return startBH.Offset, props.DataSize, props, nil
 	}
 	endBH, err := endIdxIter.BlockHandleWithProperties()
 	if err != nil {
-		return 0, errCorruptIndexEntry(err)
+		return 0, 0, Properties{}, errCorruptIndexEntry(err)
 	}
-	return includeInterpolatedValueBlocksSize(
-		endBH.Offset + endBH.Length + block.TrailerLen - startBH.Offset), nil
+	return startBH.Offset, endBH.Offset + endBH.Length + block.TrailerLen, props, nil
 }
 
 // BlockEntry represents a single data block's contribution to an SST's disk
@@ -1020,7 +1294,7 @@ func (r *Reader) CollectBlockEntries(
 	if env.Virtual != nil {
 		_, start, _ = env.Virtual.ConstrainBounds(start, nil, false, r.Comparer.Compare)
 	}
-	if !r.tableFormat.BlockColumnar() {
+	if !r.tableFormat.ColumnarIndex() {
 		return collectBlockEntries[rowblk.IndexIter, *rowblk.IndexIter](ctx, r, start, end, transforms)
 	}
 	return collectBlockEntries[colblk.IndexIter, *colblk.IndexIter](ctx, r, start, end, transforms)
@@ -1173,6 +1447,15 @@ func NewReader(ctx context.Context, f objstorage.Readable, o ReaderOptions) (*Re
 	if err != nil {
 		return nil, err
 	}
+	if footer.signature != nil {
+		// Authenticate the footer and metaindex block before using them. The
+		// blocks themselves are only authenticated by VerifySignature.
+		if err := verifyFooterSignature(
+			ctx, f, rh, o.LoggerAndTracer, o.CacheOpts.FileNum, &footer, o.SigningKeys,
+		); err != nil {
+			return nil, err
+		}
+	}
 	r.blockReader.Init(f, o.ReaderOptions, footer.checksum)
 	r.tableFormat = footer.format
 	r.indexBH = footer.indexBH
@@ -1256,9 +1539,21 @@ func NewReader(ctx context.Context, f objstorage.Readable, o ReaderOptions) (*Re
 		}
 	}
 
+	if props.ValueSchemaName != "" {
+		if vs, ok := o.ValueSchemas[props.ValueSchemaName]; ok {
+			r.valueSchema = vs
+		} else {
+			r.err = errors.Newf("pebble/table: %d: unknown value schema %q",
+				errors.Safe(r.blockReader.FileNum()), errors.Safe(props.ValueSchemaName))
+		}
+	}
+
 	if r.err != nil {
 		return nil, r.err
 	}
+	if r.tableFilter != nil {
+		r.tableFilter.open(r.blockReader.FileNum(), &props)
+	}
 	return r, nil
 }
 
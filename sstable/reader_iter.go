@@ -148,6 +148,10 @@ type (
 	twoLevelIteratorRowBlocks       = twoLevelIterator[rowblk.IndexIter, *rowblk.IndexIter, rowblk.Iter, *rowblk.Iter]
 	singleLevelIteratorColumnBlocks = singleLevelIterator[colblk.IndexIter, *colblk.IndexIter, colblk.DataBlockIter, *colblk.DataBlockIter]
 	twoLevelIteratorColumnBlocks    = twoLevelIterator[colblk.IndexIter, *colblk.IndexIter, colblk.DataBlockIter, *colblk.DataBlockIter]
+	// Iterators over tables with row-oriented data blocks and column-oriented
+	// index blocks (see TableFormatPebblev4ColumnarIndex).
+	singleLevelIteratorRowBlocksColumnarIndex = singleLevelIterator[colblk.IndexIter, *colblk.IndexIter, rowblk.Iter, *rowblk.Iter]
+	twoLevelIteratorRowBlocksColumnarIndex    = twoLevelIterator[colblk.IndexIter, *colblk.IndexIter, rowblk.Iter, *rowblk.Iter]
 )
 
 var (
@@ -155,6 +159,9 @@ var (
 	twoLevelIterRowBlockPool       sync.Pool // *twoLevelIteratorRowBlocks
 	singleLevelIterColumnBlockPool sync.Pool // *singleLevelIteratorColumnBlocks
 	twoLevelIterColumnBlockPool    sync.Pool // *twoLevelIteratorColumnBlocks
+
+	singleLevelIterRowBlockColumnarIndexPool sync.Pool // *singleLevelIteratorRowBlocksColumnarIndex
+	twoLevelIterRowBlockColumnarIndexPool    sync.Pool // *twoLevelIteratorRowBlocksColumnarIndex
 )
 
 func init() {
@@ -198,6 +205,28 @@ func init() {
 			return i
 		},
 	}
+	singleLevelIterRowBlockColumnarIndexPool = sync.Pool{
+		New: func() interface{} {
+			i := &singleLevelIteratorRowBlocksColumnarIndex{
+				pool: &singleLevelIterRowBlockColumnarIndexPool,
+			}
+			if invariants.UseFinalizers {
+				invariants.SetFinalizer(i, checkSingleLevelIterator[colblk.IndexIter, *colblk.IndexIter, rowblk.Iter, *rowblk.Iter])
+			}
+			return i
+		},
+	}
+	twoLevelIterRowBlockColumnarIndexPool = sync.Pool{
+		New: func() interface{} {
+			i := &twoLevelIteratorRowBlocksColumnarIndex{
+				pool: &twoLevelIterRowBlockColumnarIndexPool,
+			}
+			if invariants.UseFinalizers {
+				invariants.SetFinalizer(i, checkTwoLevelIterator[colblk.IndexIter, *colblk.IndexIter, rowblk.Iter, *rowblk.Iter])
+			}
+			return i
+		},
+	}
 }
 
 func checkSingleLevelIterator[I any, PI indexBlockIterator[I], D any, PD dataBlockIterator[D]](
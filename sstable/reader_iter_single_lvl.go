@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/pebble/objstorage"
 	"github.com/cockroachdb/pebble/objstorage/objstorageprovider"
 	"github.com/cockroachdb/pebble/sstable/block"
+	"github.com/cockroachdb/pebble/sstable/rowblk"
 	"github.com/cockroachdb/pebble/sstable/valblk"
 )
 
@@ -182,6 +183,9 @@ type singleLevelIterator[I any, PI indexBlockIterator[I], D any, PD dataBlockIte
 	// a match is high).
 	useFilterBlock         bool
 	lastBloomFilterMatched bool
+	// filterBlockSizeLimit is the size limit for the partitions of a
+	// partitioned filter; see Reader.filterMayContain.
+	filterBlockSizeLimit FilterBlockSizeLimit
 	// lastOpWasSeekPrefixGE tracks if the previous operation was SeekPrefixGE
 	// that returned nil due to bloom filter miss. Used for invariant checking
 	// in Next() to ensure the block is not invalidated when it doesn't have to be.
@@ -251,7 +255,9 @@ func newColumnBlockSingleLevelIterator(
 			i, opts.ReaderProvider, r.valueBIH, opts.Env.Block.Stats, opts.Env.Block.IterStats)
 		i.vbRH = r.blockReader.UsePreallocatedReadHandle(objstorage.NoReadBefore, &i.vbRHPrealloc)
 	}
-	i.data.InitOnce(r.keySchema, r.Comparer, &i.internalValueConstructor, r.tableFormat.TieringColumnConfig())
+	i.data.InitOnce(r.keySchema, r.Comparer, &i.internalValueConstructor, r.tableFormat.DataBlockColumnConfig(r.valueSchema))
+	i.data.SetValueProjection(opts.ValueProjection)
+	i.data.SetPointKeyPredicate(opts.PointKeyPredicate, opts.Env.Block.Stats)
 
 	return i, nil
 }
@@ -271,7 +277,43 @@ func newRowBlockSingleLevelIterator(
 	if r.tableFormat.BlockColumnar() {
 		panic(errors.AssertionFailedf("table format %s uses block columnar format", r.tableFormat))
 	}
+	if r.tableFormat.ColumnarIndex() {
+		panic(errors.AssertionFailedf("table uses columnar index blocks"))
+	}
 	i := singleLevelIterRowBlockPool.Get().(*singleLevelIteratorRowBlocks)
+	initRowBlockSingleLevelIterator(i, ctx, r, opts)
+	return i, nil
+}
+
+// newRowBlockColumnarIndexSingleLevelIterator is like
+// newRowBlockSingleLevelIterator, but for sstables with row-oriented data
+// blocks and column-oriented index blocks (see
+// TableFormatPebblev4ColumnarIndex).
+func newRowBlockColumnarIndexSingleLevelIterator(
+	ctx context.Context, r *Reader, opts IterOptions,
+) (*singleLevelIteratorRowBlocksColumnarIndex, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.tableFormat.BlockColumnar() {
+		panic(errors.AssertionFailedf("table format %s uses block columnar format", r.tableFormat))
+	}
+	if !r.tableFormat.ColumnarIndex() {
+		panic(errors.AssertionFailedf("table does not use columnar index blocks"))
+	}
+	i := singleLevelIterRowBlockColumnarIndexPool.Get().(*singleLevelIteratorRowBlocksColumnarIndex)
+	initRowBlockSingleLevelIterator(i, ctx, r, opts)
+	return i, nil
+}
+
+// initRowBlockSingleLevelIterator initializes a singleLevelIterator over an
+// sstable with row-oriented data blocks.
+func initRowBlockSingleLevelIterator[I any, PI indexBlockIterator[I]](
+	i *singleLevelIterator[I, PI, rowblk.Iter, *rowblk.Iter],
+	ctx context.Context,
+	r *Reader,
+	opts IterOptions,
+) {
 	i.init(ctx, r, opts)
 	if r.tableFormat >= TableFormatPebblev3 {
 		if r.Attributes.Has(AttributeValueBlocks) {
@@ -284,8 +326,6 @@ func newRowBlockSingleLevelIterator(
 		}
 		i.data.SetHasValuePrefix(true)
 	}
-
-	return i, nil
 }
 
 // init initializes the singleLevelIterator struct. It does not read the index.
@@ -297,6 +337,7 @@ func (i *singleLevelIterator[I, PI, D, PD]) init(ctx context.Context, r *Reader,
 	i.upper = opts.Upper
 	i.bpfs = opts.Filterer
 	i.useFilterBlock = shouldUseFilterBlock(r, opts.FilterBlockSizeLimit)
+	i.filterBlockSizeLimit = opts.FilterBlockSizeLimit
 	i.reader = r
 	i.cmp = r.Comparer.Compare
 	i.transforms = opts.Transforms
@@ -331,9 +372,10 @@ func (i *singleLevelIterator[I, PI, D, PD]) maybeVerifyKey(kv *base.InternalKV)
 // SetupForCompaction sets up the singleLevelIterator for use with compactionIter.
 // Currently, it skips readahead ramp-up. It should be called after init is called.
 func (i *singleLevelIterator[I, PI, D, PD]) SetupForCompaction() {
-	i.dataRH.SetupForCompaction()
+	directIOBufs := i.readEnv.Block.DirectIOBuffers()
+	i.dataRH.SetupForCompaction(directIOBufs)
 	if i.vbRH != nil {
-		i.vbRH.SetupForCompaction()
+		i.vbRH.SetupForCompaction(directIOBufs)
 	}
 }
 
@@ -344,6 +386,12 @@ const clearLenColBlocks = unsafe.Offsetof(singleLevelIteratorColumnBlocks{}.clea
 const _ uintptr = clearLen - clearLenColBlocks
 const _ uintptr = clearLenColBlocks - clearLen
 
+// Assert that clearLen is consistent with the row-based implementation using
+// columnar index blocks.
+const clearLenColIndex = unsafe.Offsetof(singleLevelIteratorRowBlocksColumnarIndex{}.clearForResetBoundary)
+const _ uintptr = clearLen - clearLenColIndex
+const _ uintptr = clearLenColIndex - clearLen
+
 func (i *singleLevelIterator[I, PI, D, PD]) resetForReuse() {
 	*(*[clearLen]byte)(unsafe.Pointer(i)) = [clearLen]byte{}
 	i.inPool = true
@@ -1082,11 +1130,16 @@ func (i *singleLevelIterator[I, PI, D, PD]) seekPrefixGE(
 	i.boundsCmp = 0
 	i.positionedUsingLatestBounds = true
 	kv, kvMeta = i.seekGEHelper(key, boundsCmp, flags, shouldReturnMeta)
+	if i.lastBloomFilterMatched && i.err == nil {
+		i.reader.maybeRecordFilterFalsePositive(prefix, kv)
+	}
 	return i.maybeVerifyKey(kv), kvMeta
 }
 
 // shouldUseFilterBlock returns whether we should use the filter block, based on
-// its length and the size limit.
+// its length and the size limit. For a partitioned filter, the length is that
+// of the top-level filter index; the limit is applied to each partition when
+// it is checked.
 func shouldUseFilterBlock(reader *Reader, filterBlockSizeLimit FilterBlockSizeLimit) bool {
 	return reader.tableFilter != nil && reader.filterBH.Length <= uint64(filterBlockSizeLimit)
 }
@@ -1104,12 +1157,8 @@ func (i *singleLevelIterator[I, PI, D, PD]) bloomFilterMayContain(prefix []byte)
 		}
 	}
 
-	dataH, err := i.reader.readFilterBlock(i.ctx, i.readEnv.Block, i.indexFilterRH, i.reader.filterBH)
-	if err != nil {
-		return false, err
-	}
-	defer dataH.Release()
-	return i.reader.tableFilter.mayContain(dataH.BlockData(), prefixToCheck), nil
+	return i.reader.filterMayContain(
+		i.ctx, i.readEnv.Block, i.indexFilterRH, i.filterBlockSizeLimit, prefixToCheck, nil /* hi */)
 }
 
 // virtualLast should only be called if i.readBlockEnv.Virtual != nil
@@ -17,6 +17,7 @@ import (
 	"github.com/cockroachdb/pebble/internal/invariants"
 	"github.com/cockroachdb/pebble/internal/treesteps"
 	"github.com/cockroachdb/pebble/objstorage"
+	"github.com/cockroachdb/pebble/sstable/rowblk"
 	"github.com/cockroachdb/pebble/sstable/valblk"
 )
 
@@ -202,7 +203,9 @@ func newColumnBlockTwoLevelIterator(
 			objstorage.NoReadBefore, &i.secondLevel.vbRHPrealloc)
 	}
 	i.secondLevel.data.InitOnce(r.keySchema,
-		r.Comparer, &i.secondLevel.internalValueConstructor, r.tableFormat.TieringColumnConfig())
+		r.Comparer, &i.secondLevel.internalValueConstructor, r.tableFormat.DataBlockColumnConfig(r.valueSchema))
+	i.secondLevel.data.SetValueProjection(opts.ValueProjection)
+	i.secondLevel.data.SetPointKeyPredicate(opts.PointKeyPredicate, opts.Env.Block.Stats)
 
 	return i, nil
 }
@@ -223,7 +226,42 @@ func newRowBlockTwoLevelIterator(
 	if r.tableFormat.BlockColumnar() {
 		panic(errors.AssertionFailedf("table format %s uses block columnar format", r.tableFormat))
 	}
+	if r.tableFormat.ColumnarIndex() {
+		panic(errors.AssertionFailedf("table uses columnar index blocks"))
+	}
 	i := twoLevelIterRowBlockPool.Get().(*twoLevelIteratorRowBlocks)
+	initRowBlockTwoLevelIterator(i, ctx, r, opts)
+	return i, nil
+}
+
+// newRowBlockColumnarIndexTwoLevelIterator is like newRowBlockTwoLevelIterator,
+// but for sstables with row-oriented data blocks and column-oriented index
+// blocks (see TableFormatPebblev4ColumnarIndex).
+func newRowBlockColumnarIndexTwoLevelIterator(
+	ctx context.Context, r *Reader, opts IterOptions,
+) (*twoLevelIteratorRowBlocksColumnarIndex, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.tableFormat.BlockColumnar() {
+		panic(errors.AssertionFailedf("table format %s uses block columnar format", r.tableFormat))
+	}
+	if !r.tableFormat.ColumnarIndex() {
+		panic(errors.AssertionFailedf("table does not use columnar index blocks"))
+	}
+	i := twoLevelIterRowBlockColumnarIndexPool.Get().(*twoLevelIteratorRowBlocksColumnarIndex)
+	initRowBlockTwoLevelIterator(i, ctx, r, opts)
+	return i, nil
+}
+
+// initRowBlockTwoLevelIterator initializes a twoLevelIterator over an sstable
+// with row-oriented data blocks.
+func initRowBlockTwoLevelIterator[I any, PI indexBlockIterator[I]](
+	i *twoLevelIterator[I, PI, rowblk.Iter, *rowblk.Iter],
+	ctx context.Context,
+	r *Reader,
+	opts IterOptions,
+) {
 	i.secondLevel.init(ctx, r, opts)
 	i.secondLevel.indexLoaded = true
 	// Only check the bloom filter at the top level.
@@ -251,8 +289,6 @@ func newRowBlockTwoLevelIterator(
 		}
 		i.secondLevel.data.SetHasValuePrefix(true)
 	}
-
-	return i, nil
 }
 
 func (i *twoLevelIterator[I, PI, D, PD]) String() string {
@@ -704,13 +740,19 @@ func (i *twoLevelIterator[I, PI, D, PD]) seekPrefixGE(
 		// bug https://github.com/cockroachdb/pebble/issues/2036.
 	}
 
+	var kv *base.InternalKV
+	var kvMeta base.KVMeta
 	if !dontSeekWithinSingleLevelIter {
-		if ikv, kvMeta := i.secondLevel.seekPrefixGE(prefix, key, flags, shouldReturnMeta); ikv != nil {
-			return ikv, kvMeta
-		}
+		kv, kvMeta = i.secondLevel.seekPrefixGE(prefix, key, flags, shouldReturnMeta)
 	}
-	// NB: skipForward checks whether exhaustedBounds > 0 (upper or prefix).
-	return i.skipForward(shouldReturnMeta)
+	if kv == nil {
+		// NB: skipForward checks whether exhaustedBounds > 0 (upper or prefix).
+		kv, kvMeta = i.skipForward(shouldReturnMeta)
+	}
+	if i.lastBloomFilterMatched && i.secondLevel.err == nil {
+		i.secondLevel.reader.maybeRecordFilterFalsePositive(prefix, kv)
+	}
+	return kv, kvMeta
 }
 
 // virtualLast should only be called if i.secondLevel.readBlockEnv.Virtual != nil.
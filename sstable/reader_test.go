@@ -37,6 +37,7 @@ import (
 	"github.com/cockroachdb/pebble/objstorage/remote"
 	"github.com/cockroachdb/pebble/sstable/block"
 	"github.com/cockroachdb/pebble/sstable/blockiter"
+	"github.com/cockroachdb/pebble/sstable/colblk"
 	"github.com/cockroachdb/pebble/sstable/tablefilters/bloom"
 	"github.com/cockroachdb/pebble/sstable/valblk"
 	"github.com/cockroachdb/pebble/sstable/virtual"
@@ -53,18 +54,17 @@ func (r *Reader) get(key []byte) (value []byte, err error) {
 	}
 
 	if r.tableFilter != nil {
-		dataH, err := r.readFilterBlock(context.Background(), block.NoReadEnv, noReadHandle, r.filterBH)
-		if err != nil {
-			return nil, err
-		}
 		var lookupKey []byte
 		if r.Comparer.Split != nil {
 			lookupKey = key[:r.Comparer.Split(key)]
 		} else {
 			lookupKey = key
 		}
-		mayContain := r.tableFilter.mayContain(dataH.BlockData(), lookupKey)
-		dataH.Release()
+		mayContain, err := r.filterMayContain(context.Background(), block.NoReadEnv, noReadHandle,
+			AlwaysUseFilterBlock, lookupKey, nil /* hi */)
+		if err != nil {
+			return nil, err
+		}
 		if !mayContain {
 			return nil, base.ErrNotFound
 		}
@@ -1824,6 +1824,50 @@ func TestReader_TableFormat(t *testing.T) {
 	}
 }
 
+func TestReaderByteRangesForSpan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	provider, err := objstorageprovider.Open(objstorageprovider.DefaultSettings(vfs.NewMem(), ""))
+	require.NoError(t, err)
+	defer provider.Close()
+	for _, indexBlockSize := range []int{100, math.MaxInt32} {
+		t.Run(fmt.Sprintf("indexBlockSize=%d", indexBlockSize), func(t *testing.T) {
+			c := cache.New(1 << 20)
+			defer c.Unref()
+			ch := c.NewHandle()
+			defer ch.Close()
+			r := buildTestTableWithProvider(t, provider, 1000, 100, indexBlockSize, block.NoCompression, nil, ch)
+			defer r.Close()
+			props, err := r.ReadPropertiesBlock(context.Background(), nil)
+			require.NoError(t, err)
+			fileSize := uint64(r.blockReader.Readable().Size())
+			key := func(i uint64) []byte { return binary.BigEndian.AppendUint64(nil, i) }
+
+			// The whole table.
+			ranges, err := r.ByteRangesForSpan(key(0), key(1000), NoReadEnv, NoTransforms)
+			require.NoError(t, err)
+			require.Equal(t, []ByteRange{
+				{Offset: 0, Length: props.DataSize},
+				{Offset: props.DataSize, Length: fileSize - props.DataSize},
+			}, ranges)
+
+			// A narrow span only includes some of the data blocks.
+			ranges, err = r.ByteRangesForSpan(key(500), key(510), NoReadEnv, NoTransforms)
+			require.NoError(t, err)
+			require.Len(t, ranges, 2)
+			require.Greater(t, ranges[0].Offset, uint64(0))
+			require.Less(t, ranges[0].Offset+ranges[0].Length, props.DataSize)
+			size, err := r.EstimateDiskUsage(key(500), key(510), NoReadEnv, NoTransforms)
+			require.NoError(t, err)
+			require.Equal(t, size, ranges[0].Length)
+
+			// A span after the table only includes the metadata region.
+			ranges, err = r.ByteRangesForSpan([]byte{0xff}, []byte{0xff, 0xff}, NoReadEnv, NoTransforms)
+			require.NoError(t, err)
+			require.Equal(t, []ByteRange{{Offset: props.DataSize, Length: fileSize - props.DataSize}}, ranges)
+		})
+	}
+}
+
 func buildTestTableWithProvider(
 	t *testing.T,
 	provider objstorage.Provider,
@@ -2693,3 +2737,303 @@ func TestReaderReportsCorruption(t *testing.T) {
 	require.ErrorContains(t, lastReportedCorruption, "in-mem remote storage object does not exist")
 	require.True(t, base.IsCorruptionError(lastReportedCorruption))
 }
+
+func TestReaderPartitionedFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	const numPrefixes = 500
+	prefix := func(i int) []byte { return fmt.Appendf(nil, "k%05d", i) }
+	writerOpts := func(tf TableFormat, partition bool) WriterOptions {
+		return WriterOptions{
+			Comparer:         testkeys.Comparer,
+			KeySchema:        &testkeysSchema,
+			TableFormat:      tf,
+			BlockSize:        64,
+			IndexBlockSize:   128,
+			FilterPolicy:     bloom.FilterPolicy(10),
+			PartitionFilters: partition,
+		}
+	}
+	openReader := func(t *testing.T, fs vfs.FS, name string, metrics *FilterMetricsTracker) *Reader {
+		f, err := fs.Open(name)
+		require.NoError(t, err)
+		r, err := newReader(f, ReaderOptions{
+			Comparer:             testkeys.Comparer,
+			KeySchemas:           MakeKeySchemas(&testkeysSchema),
+			FilterDecoders:       []base.TableFilterDecoder{bloom.Decoder},
+			FilterMetricsTracker: metrics,
+		})
+		require.NoError(t, err)
+		return r
+	}
+	// checkFilter verifies that every prefix in the table is found with
+	// SeekPrefixGE, and that the filter excludes most of the absent prefixes.
+	checkFilter := func(t *testing.T, r *Reader, metrics *FilterMetricsTracker) {
+		iter, err := r.NewIter(NoTransforms, nil /* lower */, nil /* upper */, AssertNoBlobHandles)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, iter.Close()) }()
+		before := metrics.Load()
+		tableBefore, ok := metrics.TableMetrics(r.blockReader.FileNum())
+		require.True(t, ok)
+		for i := 0; i < numPrefixes; i++ {
+			key := append(prefix(i), "@3"...)
+			kv := iter.SeekPrefixGE(prefix(i), key, base.SeekGEFlagsNone)
+			if i%2 == 0 {
+				require.NotNil(t, kv, "prefix %s", prefix(i))
+				require.Equal(t, key, kv.K.UserKey)
+			} else {
+				require.Nil(t, kv)
+			}
+		}
+		hits := metrics.Load().Hits - before.Hits
+		require.Greater(t, hits, int64(numPrefixes/4))
+		// The hits are also tracked per table, along with the false positives
+		// (the absent prefixes that the filter did not exclude).
+		table, ok := metrics.TableMetrics(r.blockReader.FileNum())
+		require.True(t, ok)
+		require.Equal(t, hits, table.Hits-tableBefore.Hits)
+		require.Equal(t, int64(numPrefixes/2)-hits, table.FalsePositives-tableBefore.FalsePositives)
+	}
+
+	testCases := []struct {
+		tf          TableFormat
+		partition   bool
+		partitioned bool
+	}{
+		// Partitioning is ignored by formats that don't support it.
+		{tf: TableFormatPebblev8, partition: true, partitioned: false},
+		{tf: TableFormatPebblev9, partition: false, partitioned: false},
+		{tf: TableFormatPebblev9, partition: true, partitioned: true},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s/partition=%t", tc.tf, tc.partition), func(t *testing.T) {
+			fs := vfs.NewMem()
+			f, err := fs.Create("test", vfs.WriteCategoryUnspecified)
+			require.NoError(t, err)
+			w := NewWriter(objstorageprovider.NewFileWritable(f), writerOpts(tc.tf, tc.partition))
+			for i := 0; i < numPrefixes; i += 2 {
+				for s := 3; s >= 1; s-- {
+					require.NoError(t, w.Set(fmt.Appendf(prefix(i), "@%d", s), []byte("v")))
+				}
+			}
+			require.NoError(t, w.Close())
+
+			var metrics FilterMetricsTracker
+			r := openReader(t, fs, "test", &metrics)
+			defer func() { require.NoError(t, r.Close()) }()
+			require.Equal(t, tc.partitioned, r.tableFilter.partitioned)
+			l, err := r.Layout()
+			require.NoError(t, err)
+			require.Greater(t, len(l.Index), 1)
+			require.Len(t, l.Filter, 1)
+			if tc.partitioned {
+				require.Len(t, l.FilterPartitions, len(l.Index))
+				require.Contains(t, l.Describe(true /* verbose */, r, nil), "filter-partition")
+			} else {
+				require.Empty(t, l.FilterPartitions)
+			}
+			require.NoError(t, r.ValidateBlockChecksums())
+			checkFilter(t, r, &metrics)
+
+			// Copying a span of the table retains the filter.
+			out, err := fs.Create("copy", vfs.WriteCategoryUnspecified)
+			require.NoError(t, err)
+			in, err := fs.Open("test")
+			require.NoError(t, err)
+			readable, err := objstorage.NewSimpleReadable(in)
+			require.NoError(t, err)
+			_, err = CopySpan(context.Background(), readable, r, 0, /* level */
+				objstorageprovider.NewFileWritable(out), writerOpts(tc.tf, tc.partition),
+				base.MakeSearchKey(prefix(0)), base.MakeSearchKey(prefix(numPrefixes)))
+			require.NoError(t, err)
+			rCopy := openReader(t, fs, "copy", &metrics)
+			defer func() { require.NoError(t, rCopy.Close()) }()
+			require.Equal(t, tc.partitioned, rCopy.tableFilter.partitioned)
+			require.NoError(t, rCopy.ValidateBlockChecksums())
+			checkFilter(t, rCopy, &metrics)
+		})
+	}
+}
+
+// TestFilterMetricsTrackerOpenTables tests that the filter metrics of an
+// sstable are tracked while any of its Readers are open.
+func TestFilterMetricsTrackerOpenTables(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	fs := vfs.NewMem()
+	f, err := fs.Create("test", vfs.WriteCategoryUnspecified)
+	require.NoError(t, err)
+	w := NewWriter(objstorageprovider.NewFileWritable(f), WriterOptions{
+		Comparer:     testkeys.Comparer,
+		KeySchema:    &testkeysSchema,
+		TableFormat:  TableFormatMax,
+		FilterPolicy: bloom.FilterPolicy(10),
+	})
+	for i := 0; i < 100; i++ {
+		require.NoError(t, w.Set(fmt.Appendf(nil, "k%03d", i), []byte("v")))
+	}
+	require.NoError(t, w.Close())
+
+	var metrics FilterMetricsTracker
+	openReader := func() *Reader {
+		f, err := fs.Open("test")
+		require.NoError(t, err)
+		r, err := newReader(f, ReaderOptions{
+			Comparer:             testkeys.Comparer,
+			KeySchemas:           MakeKeySchemas(&testkeysSchema),
+			FilterDecoders:       []base.TableFilterDecoder{bloom.Decoder},
+			FilterMetricsTracker: &metrics,
+			ReaderOptions: block.ReaderOptions{
+				CacheOpts: sstableinternal.CacheOptions{FileNum: 7},
+			},
+		})
+		require.NoError(t, err)
+		return r
+	}
+	r1 := openReader()
+	r2 := openReader()
+	props, err := r1.ReadPropertiesBlock(context.Background(), nil /* buffer pool */)
+	require.NoError(t, err)
+	numEntries, filterSize, lookups := metrics.OpenTablesMetrics()
+	require.Equal(t, uint64(100), numEntries)
+	require.Equal(t, props.FilterSize, filterSize)
+	require.Zero(t, lookups)
+
+	iter, err := r1.NewIter(NoTransforms, nil /* lower */, nil /* upper */, AssertNoBlobHandles)
+	require.NoError(t, err)
+	for i := 0; i < 100; i++ {
+		iter.SeekPrefixGE([]byte("x"), fmt.Appendf(nil, "x%03d", i), base.SeekGEFlagsNone)
+	}
+	require.NoError(t, iter.Close())
+	m, ok := metrics.TableMetrics(7)
+	require.True(t, ok)
+	require.Equal(t, int64(100), m.Hits+m.FalsePositives)
+	_, _, lookups = metrics.OpenTablesMetrics()
+	require.Equal(t, int64(100), lookups)
+
+	// The metrics are discarded once all the Readers are closed.
+	require.NoError(t, r1.Close())
+	_, ok = metrics.TableMetrics(7)
+	require.True(t, ok)
+	require.NoError(t, r2.Close())
+	_, ok = metrics.TableMetrics(7)
+	require.False(t, ok)
+	numEntries, filterSize, lookups = metrics.OpenTablesMetrics()
+	require.Zero(t, numEntries)
+	require.Zero(t, filterSize)
+	require.Zero(t, lookups)
+}
+
+// countNameValueSchema is a value schema that decomposes values of the form
+// "<count>:<name>".
+var countNameValueSchema = &colblk.ValueSchema{
+	Name:        "count-name",
+	ColumnTypes: []colblk.DataType{colblk.DataTypeUint, colblk.DataTypeBytes},
+	Decompose: func(value []byte, fields []colblk.ValueField) bool {
+		count, name, ok := bytes.Cut(value, []byte(":"))
+		if !ok {
+			return false
+		}
+		n, err := strconv.ParseUint(string(count), 10, 64)
+		if err != nil || strconv.FormatUint(n, 10) != string(count) {
+			return false
+		}
+		fields[0].Uint = n
+		fields[1].Bytes = name
+		return true
+	},
+	Compose: func(dst []byte, fields []colblk.ValueField) []byte {
+		dst = strconv.AppendUint(dst, fields[0].Uint, 10)
+		dst = append(dst, ':')
+		return append(dst, fields[1].Bytes...)
+	},
+}
+
+func TestReaderValueColumns(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	valueSchema := countNameValueSchema
+	const numKeys = 200
+	value := func(i int) string {
+		if i%10 == 0 {
+			// Some values don't conform to the schema.
+			return fmt.Sprintf("opaque-%d", i)
+		}
+		return fmt.Sprintf("%d:name-%d", i*7, i)
+	}
+
+	fs := vfs.NewMem()
+	f, err := fs.Create("test", vfs.WriteCategoryUnspecified)
+	require.NoError(t, err)
+	w := NewWriter(objstorageprovider.NewFileWritable(f), WriterOptions{
+		Comparer:       testkeys.Comparer,
+		KeySchema:      &testkeysSchema,
+		ValueSchema:    valueSchema,
+		TableFormat:    TableFormatPebblev10,
+		BlockSize:      256,
+		IndexBlockSize: 128,
+	})
+	for i := 0; i < numKeys; i++ {
+		require.NoError(t, w.Set(fmt.Appendf(nil, "k%05d", i), []byte(value(i))))
+	}
+	require.NoError(t, w.Close())
+
+	open := func(valueSchemas ValueSchemas) (*Reader, error) {
+		f, err := fs.Open("test")
+		require.NoError(t, err)
+		return newReader(f, ReaderOptions{
+			Comparer:     testkeys.Comparer,
+			KeySchemas:   MakeKeySchemas(&testkeysSchema),
+			ValueSchemas: valueSchemas,
+		})
+	}
+	_, err = open(nil)
+	require.ErrorContains(t, err, `unknown value schema "count-name"`)
+
+	r, err := open(MakeValueSchemas(valueSchema))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, r.Close()) }()
+	props, err := r.ReadPropertiesBlock(context.Background(), nil /* buffer pool */)
+	require.NoError(t, err)
+	require.Equal(t, "count-name", props.ValueSchemaName)
+	l, err := r.Layout()
+	require.NoError(t, err)
+	require.Greater(t, len(l.Index), 1)
+	require.NoError(t, r.ValidateBlockChecksums())
+
+	for _, tc := range []struct {
+		projection colblk.ValueProjection
+		expected   func(i int) string
+	}{
+		{projection: colblk.ValueProjection{}, expected: value},
+		{projection: colblk.ProjectValueColumns(0), expected: func(i int) string {
+			if i%10 == 0 {
+				return value(i)
+			}
+			return fmt.Sprintf("%d:", i*7)
+		}},
+		{projection: colblk.ProjectValueColumns(1), expected: func(i int) string {
+			if i%10 == 0 {
+				return value(i)
+			}
+			return fmt.Sprintf("0:name-%d", i)
+		}},
+	} {
+		t.Run(tc.projection.String(), func(t *testing.T) {
+			iter, err := r.NewPointIter(context.Background(), IterOptions{
+				Transforms:      NoTransforms,
+				ReaderProvider:  MakeTrivialReaderProvider(r),
+				BlobContext:     AssertNoBlobHandles,
+				ValueProjection: tc.projection,
+			})
+			require.NoError(t, err)
+			defer func() { require.NoError(t, iter.Close()) }()
+			i := 0
+			for kv := iter.First(); kv != nil; kv = iter.Next() {
+				require.Equal(t, fmt.Sprintf("k%05d", i), string(kv.K.UserKey))
+				v, _, err := kv.Value(nil)
+				require.NoError(t, err)
+				require.Equal(t, tc.expected(i), string(v))
+				i++
+			}
+			require.Equal(t, numKeys, i)
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/pebble/sstable/blob"
 	"github.com/cockroachdb/pebble/sstable/block"
 	"github.com/cockroachdb/pebble/sstable/block/blockkind"
+	"github.com/cockroachdb/pebble/sstable/colblk"
 	"github.com/cockroachdb/pebble/sstable/rowblk"
 	"github.com/cockroachdb/pebble/sstable/valblk"
 )
@@ -36,8 +37,9 @@ const encodedBHPEstimatedSize = binary.MaxVarintLen64 * 2
 var errWriterClosed = errors.New("pebble: writer is closed")
 
 // RawRowWriter is a sstable RawWriter that writes sstables with row-oriented
-// blocks. All table formats TableFormatPebblev4 and earlier write row-oriented
-// blocks and use RawRowWriter.
+// blocks. All table formats TableFormatPebblev4 and earlier (including
+// TableFormatPebblev4ColumnarIndex) write row-oriented data blocks and use
+// RawRowWriter.
 type RawRowWriter struct {
 	layout     layoutWriter
 	meta       WriterMetadata
@@ -75,15 +77,21 @@ type RawRowWriter struct {
 	// re-read many times from the disk. The top level index, which has a much
 	// smaller memory footprint, can be used to prevent the entire index block from
 	// being loaded into the block cache.
-	twoLevelIndex       bool
-	indexBlock          *indexBlockBuf
-	rangeDelBlock       rowblk.Writer
-	rangeKeyBlock       rowblk.Writer
-	topLevelIndexBlock  rowblk.Writer
+	twoLevelIndex      bool
+	indexBlock         *indexBlockBuf
+	rangeDelBlock      rowblk.Writer
+	rangeKeyBlock      rowblk.Writer
+	topLevelIndexBlock rowblk.Writer
+	// columnarIndex is non-nil if the index blocks are re-encoded using the
+	// columnar index block encoding (see TableFormatPebblev4ColumnarIndex).
+	columnarIndex       *columnarIndexEncoder
 	props               Properties
 	blockPropCollectors []BlockPropertyCollector
-	obsoleteCollector   obsoleteKeyBlockPropertyCollector
-	blockPropsEncoder   blockPropertiesEncoder
+	// collectorWantsValues[i] is true if blockPropCollectors[i] wants in-place
+	// values (see InPlaceValueBlockPropertyCollector).
+	collectorWantsValues []bool
+	obsoleteCollector    obsoleteKeyBlockPropertyCollector
+	blockPropsEncoder    blockPropertiesEncoder
 	// filterWriter accumulates the filter block. If not nil, the filterWriter ingests
 	// the key prefixes.
 	filterWriter    base.TableFilterWriter
@@ -142,14 +150,61 @@ type coordinationState struct {
 	// this reason, every single data block write must be done through the writeQueue.
 	writeQueue *writeQueue
 
+	// compressionWorkers, if non-nil, compress data blocks concurrently with the
+	// Writer client goroutine (see WriterOptions.CompressionWorkers).
+	compressionWorkers *compressionWorkers
+	// pending contains the tasks which were submitted to the compressionWorkers
+	// but not yet written, in the order in which they must be written.
+	pending []*writeTask
+
 	sizeEstimate dataBlockEstimates
 }
 
-func (c *coordinationState) init(writer *RawRowWriter) {
+func (c *coordinationState) init(writer *RawRowWriter, o WriterOptions) {
 	// writeQueueSize determines the size of the write queue, or the number of
 	// items which can be added to the queue without blocking. We always use a
 	// writeQueue size of 0.
 	c.writeQueue = newWriteQueue(0 /* size */, writer)
+	if o.CompressionWorkers > 0 {
+		c.compressionWorkers = newCompressionWorkers(o.CompressionWorkers, o)
+	}
+}
+
+// submit schedules the compression of the task's data block on the
+// compression workers. Tasks are written in the order in which they are
+// submitted; if too many tasks are pending, submit waits for the oldest one to
+// be compressed and writes it.
+func (c *coordinationState) submit(task *writeTask) error {
+	c.compressionWorkers.submit(task)
+	c.pending = append(c.pending, task)
+	if len(c.pending) < c.compressionWorkers.maxInflight {
+		return nil
+	}
+	return c.writePending(1)
+}
+
+// writePending writes the n oldest pending tasks, waiting for their data blocks
+// to be compressed.
+func (c *coordinationState) writePending(n int) error {
+	var err error
+	for _, task := range c.pending[:n] {
+		err = c.writeQueue.addSync(task)
+	}
+	clear(c.pending[:n])
+	c.pending = c.pending[:copy(c.pending, c.pending[n:])]
+	return err
+}
+
+// finishCompression writes all the pending tasks and stops the compression
+// workers, adding their compression stats to stats. Any error encountered while
+// writing the tasks is returned by writeQueue.finish.
+func (c *coordinationState) finishCompression(stats *block.CompressionStats) {
+	if c.compressionWorkers == nil {
+		return
+	}
+	_ = c.writePending(len(c.pending))
+	c.compressionWorkers.close(stats)
+	c.compressionWorkers = nil
 }
 
 // sizeEstimate is a general purpose helper for estimating two kinds of sizes:
@@ -161,8 +216,8 @@ func (c *coordinationState) init(writer *RawRowWriter) {
 // B. The size of index blocks to decide when to start a new index block.
 //
 // There are some terminology peculiarities which are due to the origin of
-// sizeEstimate for use case A with parallel compression enabled (for which
-// the code has not been merged). Specifically this relates to the terms
+// sizeEstimate for use case A with parallel compression enabled (see
+// WriterOptions.CompressionWorkers). Specifically this relates to the terms
 // "written" and "compressed".
 //   - The notion of "written" for case A is sufficiently defined by saying that
 //     the data block is compressed. Waiting for the actual data block write to
@@ -192,9 +247,9 @@ func (c *coordinationState) init(writer *RawRowWriter) {
 // provide the actual delta size or the total size (latter must be
 // monotonically non-decreasing). If there were no calls to addInflight, there
 // isn't any real estimation happening here. So case A does not do any real
-// estimation. However, when we introduce parallel compression, there will be
-// estimation in that the client goroutine will call addInFlight and the
-// compression goroutines will call writtenWithDelta.
+// estimation, unless data blocks are compressed by compression workers: the
+// client goroutine calls addInflight when it hands a block off to the workers
+// and writtenWithDelta once the compressed block is written.
 type sizeEstimate struct {
 	// emptySize is the size when there is no inflight data, and numEntries is 0.
 	// emptySize is constant once set.
@@ -346,11 +401,10 @@ func (i *indexBlockBuf) addInflight(inflightSize int) {
 }
 
 func (i *indexBlockBuf) estimatedSize() uint64 {
-	// Make sure that the size estimation works as expected.
-	if invariants.Enabled {
-		if i.size.estimate.inflightSize != 0 {
-			panic(errors.AssertionFailedf("unexpected inflight entry in index block size estimation"))
-		}
+	// Make sure that the size estimation works as expected. There are inflight
+	// entries when data blocks are being compressed by compression workers, in
+	// which case the size is only an estimate.
+	if invariants.Enabled && i.size.estimate.inflightSize == 0 {
 		if i.size.estimate.size() != uint64(i.block.EstimatedSize()) {
 			panic(errors.AssertionFailedf("index block size estimation is incorrect"))
 		}
@@ -374,20 +428,14 @@ func (d *dataBlockEstimates) dataBlockCompressed(compressedSize int, inflightSiz
 	d.estimate.writtenWithDelta(compressedSize+block.TrailerLen, inflightSize)
 }
 
-// size is an estimated size of datablock data which has been written to disk.
+// size is an estimated size of datablock data which has been written to disk,
+// including the blocks that are still being compressed.
 func (d *dataBlockEstimates) size() uint64 {
-	if invariants.Enabled {
-		if d.estimate.inflightSize != 0 {
-			panic(errors.AssertionFailedf("unexpected inflight entry in data block size estimation"))
-		}
-	}
 	return d.estimate.size()
 }
 
-// Avoid linter unused error.
-var _ = (&dataBlockEstimates{}).addInflightDataBlock
-
-// NB: unused since no parallel compression.
+// addInflightDataBlock is called with the uncompressed size of a data block
+// which was handed off to the compression workers.
 func (d *dataBlockEstimates) addInflightDataBlock(size int) {
 	d.estimate.addInflight(size)
 }
@@ -748,10 +796,11 @@ func (w *RawRowWriter) addPoint(key InternalKey, value []byte, forceObsolete boo
 
 	for i := range w.blockPropCollectors {
 		v := value
-		if addPrefixToValueStoredWithKey {
+		if addPrefixToValueStoredWithKey && (writeToValueBlock || !w.collectorWantsValues[i]) {
 			// Values for SET are not required to be in-place, and in the future may
 			// not even be read by the compaction, so pass nil values. Block
-			// property collectors in such Pebble DB's must not look at the value.
+			// property collectors in such Pebble DB's must not look at the value,
+			// unless they ask for in-place values.
 			v = nil
 		}
 		if err := w.blockPropCollectors[i].AddPointKey(key, v); err != nil {
@@ -1001,10 +1050,18 @@ func (w *RawRowWriter) flush(key InternalKey) error {
 	}
 	w.dataBlockBuf.finish()
 	w.maybeIncrementTombstoneDenseBlocks()
-	w.dataBlockBuf.compressAndChecksum(&w.layout.physBlockMaker)
-	// Since dataBlockEstimates.addInflightDataBlock was never called, the
-	// inflightSize is set to 0.
-	w.coordination.sizeEstimate.dataBlockCompressed(w.dataBlockBuf.physical.LengthWithoutTrailer(), 0)
+	var dataInflightSize int
+	if w.coordination.compressionWorkers != nil {
+		// The block is compressed by the compression workers; its compressed size
+		// is accounted for when it is written.
+		dataInflightSize = len(w.dataBlockBuf.uncompressed)
+		w.coordination.sizeEstimate.addInflightDataBlock(dataInflightSize)
+	} else {
+		w.dataBlockBuf.compressAndChecksum(&w.layout.physBlockMaker)
+		// Since dataBlockEstimates.addInflightDataBlock was never called, the
+		// inflightSize is set to 0.
+		w.coordination.sizeEstimate.dataBlockCompressed(w.dataBlockBuf.physical.LengthWithoutTrailer(), 0)
+	}
 
 	// Determine if the index block should be flushed. Since we're accessing the
 	// dataBlockBuf.dataBlock.curKey here, we have to make sure that once we start
@@ -1045,10 +1102,8 @@ func (w *RawRowWriter) flush(key InternalKey) error {
 
 	// Schedule a write.
 	writeTask := writeTaskPool.Get().(*writeTask)
-	// We're setting compressionDone to indicate that compression of this block
-	// has already been completed.
-	writeTask.compressionDone <- true
 	writeTask.buf = w.dataBlockBuf
+	writeTask.dataInflightSize = dataInflightSize
 	writeTask.indexEntrySep = sep
 	writeTask.currIndexBlock = w.indexBlock
 	writeTask.indexInflightSize = sep.Size() + encodedBHPEstimatedSize
@@ -1059,7 +1114,14 @@ func (w *RawRowWriter) flush(key InternalKey) error {
 	w.indexBlock.addInflight(writeTask.indexInflightSize)
 
 	w.dataBlockBuf = nil
-	err = w.coordination.writeQueue.addSync(writeTask)
+	if w.coordination.compressionWorkers != nil {
+		err = w.coordination.submit(writeTask)
+	} else {
+		// We're setting compressionDone to indicate that compression of this
+		// block has already been completed.
+		writeTask.compressionDone <- true
+		err = w.coordination.writeQueue.addSync(writeTask)
+	}
 	w.dataBlockBuf = newDataBlockBuf(w.restartInterval)
 
 	return err
@@ -1334,6 +1396,11 @@ func (w *RawRowWriter) writeTwoLevelIndex() (block.Handle, error) {
 		w.props.NumDataBlocks += uint64(b.nEntries)
 
 		data := b.block
+		if w.columnarIndex != nil {
+			if data, err = w.columnarIndex.encode(data); err != nil {
+				return block.Handle{}, err
+			}
+		}
 		w.props.IndexSize += uint64(len(data))
 		bh, err := w.layout.WriteIndexBlock(data)
 		if err != nil {
@@ -1353,8 +1420,49 @@ func (w *RawRowWriter) writeTwoLevelIndex() (block.Handle, error) {
 	// index size property.
 	w.props.IndexPartitions = uint64(len(w.indexPartitions))
 	w.props.TopLevelIndexSize = uint64(w.topLevelIndexBlock.EstimatedSize())
+	topLevelIndex := w.topLevelIndexBlock.Finish()
+	if w.columnarIndex != nil {
+		if topLevelIndex, err = w.columnarIndex.encode(topLevelIndex); err != nil {
+			return block.Handle{}, err
+		}
+		w.props.TopLevelIndexSize = uint64(len(topLevelIndex))
+	}
 	w.props.IndexSize += w.props.TopLevelIndexSize + block.TrailerLen
-	return w.layout.WriteIndexBlock(w.topLevelIndexBlock.Finish())
+	return w.layout.WriteIndexBlock(topLevelIndex)
+}
+
+// columnarIndexEncoder re-encodes finished row-based index blocks using the
+// columnar index block encoding.
+type columnarIndexEncoder struct {
+	comparer *base.Comparer
+	iter     rowblk.IndexIter
+	w        colblk.IndexBlockWriter
+}
+
+func newColumnarIndexEncoder(comparer *base.Comparer) *columnarIndexEncoder {
+	e := &columnarIndexEncoder{comparer: comparer}
+	e.w.Init()
+	return e
+}
+
+// encode returns the columnar encoding of the provided row-based index block.
+// The returned slice is only valid until the next call to encode.
+func (e *columnarIndexEncoder) encode(rowIndex []byte) ([]byte, error) {
+	e.w.Reset()
+	if err := e.iter.Init(e.comparer, rowIndex, NoTransforms); err != nil {
+		return nil, err
+	}
+	for valid := e.iter.First(); valid; valid = e.iter.Next() {
+		bhp, err := e.iter.BlockHandleWithProperties()
+		if err != nil {
+			return nil, err
+		}
+		e.w.AddBlockHandle(e.iter.Separator(), bhp.Handle, bhp.Props)
+	}
+	if err := e.iter.Close(); err != nil {
+		return nil, err
+	}
+	return e.w.Finish(e.w.Rows()), nil
 }
 
 // assertFormatCompatibility ensures that the features present on the table are
@@ -1467,9 +1575,10 @@ func (w *RawRowWriter) Close() (err error) {
 		}
 	}()
 
-	// finish must be called before we check for an error, because finish will
-	// block until every single task added to the writeQueue has been processed,
-	// and an error could be encountered while any of those tasks are processed.
+	// finishCompression and finish must be called before we check for an error,
+	// because they block until every single task has been processed, and an
+	// error could be encountered while any of those tasks are processed.
+	w.coordination.finishCompression(w.layout.physBlockMaker.Compressor.Stats())
 	if err := w.coordination.writeQueue.finish(); err != nil {
 		return err
 	}
@@ -1539,8 +1648,15 @@ func (w *RawRowWriter) Close() (err error) {
 		// property.
 		w.props.IndexSize = uint64(w.indexBlock.estimatedSize()) + block.TrailerLen
 		w.props.NumDataBlocks = uint64(w.indexBlock.block.EntryCount())
+		indexBlock := w.indexBlock.finish()
+		if w.columnarIndex != nil {
+			if indexBlock, err = w.columnarIndex.encode(indexBlock); err != nil {
+				return err
+			}
+			w.props.IndexSize = uint64(len(indexBlock)) + block.TrailerLen
+		}
 		// Write the single level index block.
-		if _, err = w.layout.WriteIndexBlock(w.indexBlock.finish()); err != nil {
+		if _, err = w.layout.WriteIndexBlock(indexBlock); err != nil {
 			return err
 		}
 	}
@@ -1682,6 +1798,13 @@ func (w *RawRowWriter) EstimatedSize() uint64 {
 	// until the sstable is finished.  Including the uncompressed size bounds
 	// the memory usage used by the writer to the physical size limit.
 	size += w.indexPartitionsSizeSum
+	// Add the size of the index blocks which will be finished once the pending
+	// data blocks are compressed.
+	for _, task := range w.coordination.pending {
+		if task.flushableIndexBlock != nil {
+			size += task.flushableIndexBlock.size.estimate.size()
+		}
+	}
 	size += uint64(w.dataBlockBuf.dataBlock.EstimatedSize())
 	size += w.indexBlock.estimatedSize()
 	size += uint64(w.rangeDelBlock.EstimatedSize())
@@ -1745,10 +1868,11 @@ func newRowWriter(writable objstorage.Writable, o WriterOptions) *RawRowWriter {
 
 	w.dataBlockBuf = newDataBlockBuf(w.restartInterval)
 
-	w.coordination.init(w)
+	w.coordination.init(w, o)
 	defer func() {
 		if r := recover(); r != nil {
-			// Don't leak a goroutine if we hit a panic.
+			// Don't leak goroutines if we hit a panic.
+			w.coordination.finishCompression(w.layout.physBlockMaker.Compressor.Stats())
 			_ = w.coordination.writeQueue.finish()
 			panic(r)
 		}
@@ -1765,6 +1889,9 @@ func newRowWriter(writable objstorage.Writable, o WriterOptions) *RawRowWriter {
 
 	w.props.ComparerName = o.Comparer.Name
 	w.props.CompressionName = o.Compression.Name
+	if o.TableFormat.ColumnarIndex() {
+		w.columnarIndex = newColumnarIndexEncoder(o.Comparer)
+	}
 	w.props.MergerName = o.MergerName
 	w.props.PropertyCollectorNames = "[]"
 
@@ -1786,6 +1913,10 @@ func newRowWriter(writable objstorage.Writable, o WriterOptions) *RawRowWriter {
 		if shouldAddObsoleteCollector {
 			w.blockPropCollectors = append(w.blockPropCollectors, &w.obsoleteCollector)
 		}
+		w.collectorWantsValues = make([]bool, len(w.blockPropCollectors))
+		for i, c := range w.blockPropCollectors {
+			w.collectorWantsValues[i] = wantsInPlaceValues(c)
+		}
 
 		var buf bytes.Buffer
 		buf.WriteString("[")
@@ -1834,6 +1965,9 @@ func (w *RawRowWriter) rewriteSuffixes(
 	}
 	oldProps := make([][]byte, len(w.blockPropCollectors))
 
+	if err := w.coordination.writePending(len(w.coordination.pending)); err != nil {
+		return err
+	}
 	for i := range blocks {
 		// Write the rewritten block to the file.
 		bh, err := w.layout.WritePrecompressedDataBlock(blocks[i].physical.Take())
@@ -1910,6 +2044,9 @@ func (w *RawRowWriter) rewriteSuffixes(
 func (w *RawRowWriter) copyDataBlocks(
 	ctx context.Context, blocks []indexEntry, rh objstorage.ReadHandle,
 ) error {
+	if err := w.coordination.writePending(len(w.coordination.pending)); err != nil {
+		return err
+	}
 	blockOffset := blocks[0].bh.Offset
 	// The block lengths don't include their trailers, which just sit after the
 	// block length, before the next offset; We get the ones between the blocks
@@ -1937,6 +2074,9 @@ func (w *RawRowWriter) copyDataBlocks(
 
 // addDataBlock implements RawWriter.
 func (w *RawRowWriter) addDataBlock(b, sep []byte, bhp block.HandleWithProperties) error {
+	if err := w.coordination.writePending(len(w.coordination.pending)); err != nil {
+		return err
+	}
 	pb := w.layout.physBlockMaker.Make(b, blockkind.SSTableData, block.NoFlags)
 
 	// layout.writePhysicalBlock keeps layout.offset up-to-date for us.
@@ -1979,6 +2119,15 @@ func (w *RawRowWriter) setFilter(filterData []byte, family base.TableFilterFamil
 	}
 }
 
+// setPartitionedFilter implements RawWriter.
+func (w *RawRowWriter) setPartitionedFilter(parts []filterPartition) {
+	// Partitioned filters require TableFormatPebblev9+ which uses column
+	// writers.
+	if len(parts) > 0 {
+		w.err = errors.AssertionFailedf("partitioned filters not supported in table format %s", w.tableFormat)
+	}
+}
+
 // SetValueSeparationProps implements RawWriter.
 func (w *RawRowWriter) SetValueSeparationProps(_ uint64, _ bool) {
 	// Value separation requires TableFormatPebblev7+ which uses column writers.
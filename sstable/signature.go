@@ -0,0 +1,260 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package sstable
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble/internal/base"
+	"github.com/cockroachdb/pebble/objstorage"
+	"github.com/cockroachdb/pebble/sstable/block"
+)
+
+// Signed tables
+//
+// A table written with WriterOptions.SigningKeys has a signed footer, which
+// uses a distinct magic number so that readers that don't support signatures
+// reject the table. The signed footer extends the footer of the table format
+// with:
+//
+//	key ID: identifies the signing key (4 bytes)
+//	digest: SHA-256 over all the bytes preceding the metaindex block (32 bytes)
+//	MAC:    HMAC-SHA256 keyed with the signing key (32 bytes)
+//
+// The MAC is computed over the metaindex block (including its trailer) and
+// the footer, with the MAC and footer checksum fields zeroed. The digest
+// covers the contents and trailers (and thus the checksums) of all the other
+// blocks; block checksums alone are not sufficient for tamper evidence since
+// they are not cryptographic.
+//
+// The Reader verifies the MAC when a signed table is opened, which
+// authenticates the metaindex and the digest. VerifySignature additionally
+// verifies the digest, which requires reading the entire table. The other
+// blocks of a table are not authenticated when the table is opened (only their
+// checksums are verified when they are read), so tables that originate from an
+// untrusted source must be checked with VerifySignature before they are used.
+// Pebble does so for the tables it adopts (ingested, external, downloaded and
+// remote replica tables), but not for the tables the DB wrote itself.
+
+// SigningKeyProvider provides the keys used to sign tables and to
+// authenticate signed tables.
+type SigningKeyProvider interface {
+	// SigningKey returns the key used to sign new tables, along with an ID
+	// which is stored in the footer of signed tables.
+	SigningKey() (id uint32, key []byte, _ error)
+	// VerificationKey returns the key with the given ID. It returns an error if
+	// the key is not known.
+	VerificationKey(id uint32) ([]byte, error)
+}
+
+// StaticSigningKeys is a SigningKeyProvider with a fixed set of keys.
+type StaticSigningKeys struct {
+	// SigningKeyID is the ID of the key used to sign new tables.
+	SigningKeyID uint32
+	// Keys maps key IDs to keys.
+	Keys map[uint32][]byte
+}
+
+var _ SigningKeyProvider = (*StaticSigningKeys)(nil)
+
+// SigningKey is part of the SigningKeyProvider interface.
+func (k *StaticSigningKeys) SigningKey() (uint32, []byte, error) {
+	key, err := k.VerificationKey(k.SigningKeyID)
+	return k.SigningKeyID, key, err
+}
+
+// VerificationKey is part of the SigningKeyProvider interface.
+func (k *StaticSigningKeys) VerificationKey(id uint32) ([]byte, error) {
+	key, ok := k.Keys[id]
+	if !ok {
+		return nil, errors.Newf("unknown signing key %d", errors.Safe(id))
+	}
+	return key, nil
+}
+
+// ErrUnsignedTable is returned by VerifySignature for tables that are not
+// signed.
+var ErrUnsignedTable = errors.New("pebble/table: table is not signed")
+
+const (
+	pebbleDBSignedMagic = "\xf0\x9f\xaa\xb3\xf0\x9f\x94\x8f" // 🪳🔏
+
+	signatureKeyIDLen  = 4
+	signatureDigestLen = sha256.Size
+	signatureMACLen    = sha256.Size
+	signatureLen       = signatureKeyIDLen + signatureDigestLen + signatureMACLen
+)
+
+// footerSignature is the signature stored in the footer of a signed table.
+type footerSignature struct {
+	keyID  uint32
+	digest [signatureDigestLen]byte
+	mac    [signatureMACLen]byte
+}
+
+func (s *footerSignature) encode(buf []byte) {
+	binary.LittleEndian.PutUint32(buf, s.keyID)
+	copy(buf[signatureKeyIDLen:], s.digest[:])
+	copy(buf[signatureKeyIDLen+signatureDigestLen:], s.mac[:])
+}
+
+func (s *footerSignature) decode(buf []byte) {
+	s.keyID = binary.LittleEndian.Uint32(buf)
+	copy(s.digest[:], buf[signatureKeyIDLen:])
+	copy(s.mac[:], buf[signatureKeyIDLen+signatureDigestLen:])
+}
+
+// computeFooterMAC computes the MAC of a signed table, given its raw metaindex
+// block (including the trailer) and its encoded footer.
+func computeFooterMAC(key, metaindex, encodedFooter []byte) (mac [signatureMACLen]byte) {
+	offsets := signedFooterOffsets(encodedFooter)
+	footer := make([]byte, len(encodedFooter))
+	copy(footer, encodedFooter)
+	clear(footer[offsets.mac : offsets.mac+signatureMACLen])
+	clear(footer[offsets.checksum : offsets.checksum+checksumLen])
+
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write(metaindex)
+	_, _ = h.Write(footer)
+	h.Sum(mac[:0])
+	return mac
+}
+
+// signedFooterFieldOffsets contains the offsets of the fields of a signed
+// footer.
+type signedFooterFieldOffsets struct {
+	signature int
+	mac       int
+	checksum  int
+}
+
+// signedFooterOffsets returns the offsets of the fields of the given encoded
+// signed footer. The signature is stored right before the attributes (or the
+// checksum, for formats without attributes).
+func signedFooterOffsets(encodedFooter []byte) signedFooterFieldOffsets {
+	var o signedFooterFieldOffsets
+	o.checksum = len(encodedFooter) - magicLen - versionLen - checksumLen
+	o.signature = o.checksum - signatureLen
+	version := binary.LittleEndian.Uint32(encodedFooter[len(encodedFooter)-magicLen-versionLen:])
+	if version >= 7 {
+		o.signature -= attributesLen
+	}
+	o.mac = o.signature + signatureKeyIDLen + signatureDigestLen
+	return o
+}
+
+// signingWritable wraps the Writable of a signed table, computing the digest
+// of the blocks and retaining the metaindex block, which are used to compute
+// the signature.
+type signingWritable struct {
+	objstorage.Writable
+	digest hash.Hash
+	// writingMetaindex is set once all the blocks preceding the metaindex block
+	// have been written. Subsequent writes are accumulated in metaindex.
+	writingMetaindex bool
+	metaindex        []byte
+}
+
+func newSigningWritable(w objstorage.Writable) *signingWritable {
+	return &signingWritable{Writable: w, digest: sha256.New()}
+}
+
+// Write is part of the objstorage.Writable interface.
+func (w *signingWritable) Write(p []byte) error {
+	// Note that the underlying Write can mangle p.
+	if w.writingMetaindex {
+		w.metaindex = append(w.metaindex, p...)
+	} else {
+		_, _ = w.digest.Write(p)
+	}
+	return w.Writable.Write(p)
+}
+
+// sign returns the signature of the table.
+func (w *signingWritable) sign(f *footer, keys SigningKeyProvider, buf []byte) error {
+	id, key, err := keys.SigningKey()
+	if err != nil {
+		return errors.Wrap(err, "pebble/table: retrieving signing key")
+	}
+	f.signature = &footerSignature{keyID: id}
+	w.digest.Sum(f.signature.digest[:0])
+	f.signature.mac = computeFooterMAC(key, w.metaindex, f.encode(buf))
+	return nil
+}
+
+// verifyFooterSignature verifies the MAC of a signed table, authenticating
+// its footer and metaindex block.
+func verifyFooterSignature(
+	ctx context.Context,
+	f objstorage.Readable,
+	readHandle objstorage.ReadHandle,
+	logger base.LoggerAndTracer,
+	fileNum base.DiskFileNum,
+	foot *footer,
+	keys SigningKeyProvider,
+) error {
+	if keys == nil {
+		return errors.Newf("pebble/table: %s: table is signed but no signing keys are configured", errors.Safe(fileNum))
+	}
+	key, err := keys.VerificationKey(foot.signature.keyID)
+	if err != nil {
+		return errors.Wrapf(err, "pebble/table: %s", errors.Safe(fileNum))
+	}
+	metaindex := make([]byte, foot.metaindexBH.Length+block.TrailerLen)
+	metaindex, err = block.ReadRaw(ctx, f, readHandle, logger, fileNum, metaindex, int64(foot.metaindexBH.Offset))
+	if err != nil {
+		return err
+	}
+	encodedFooter := make([]byte, foot.footerBH.Length)
+	encodedFooter, err = block.ReadRaw(ctx, f, readHandle, logger, fileNum, encodedFooter, int64(foot.footerBH.Offset))
+	if err != nil {
+		return err
+	}
+	mac := computeFooterMAC(key, metaindex, encodedFooter)
+	if !hmac.Equal(mac[:], foot.signature.mac[:]) {
+		return base.CorruptionErrorf("pebble/table: %s: footer signature mismatch", errors.Safe(fileNum))
+	}
+	return nil
+}
+
+// VerifySignature authenticates a signed table, verifying the footer
+// signature and that the contents of all the blocks match the signed digest.
+// It reads the entire table. Returns ErrUnsignedTable if the table is not
+// signed.
+func VerifySignature(ctx context.Context, f objstorage.Readable, keys SigningKeyProvider) error {
+	foot, err := readFooter(ctx, f, nil /* readHandle */, base.NoopLoggerAndTracer{}, 0 /* fileNum */)
+	if err != nil {
+		return err
+	}
+	if foot.signature == nil {
+		return ErrUnsignedTable
+	}
+	if err := verifyFooterSignature(
+		ctx, f, nil /* readHandle */, base.NoopLoggerAndTracer{}, 0 /* fileNum */, &foot, keys,
+	); err != nil {
+		return err
+	}
+
+	const readSize = 256 << 10
+	digest := sha256.New()
+	buf := make([]byte, readSize)
+	for off, end := uint64(0), foot.metaindexBH.Offset; off < end; {
+		n := min(end-off, readSize)
+		if err := f.ReadAt(ctx, buf[:n], int64(off)); err != nil {
+			return err
+		}
+		_, _ = digest.Write(buf[:n])
+		off += n
+	}
+	if !hmac.Equal(digest.Sum(nil), foot.signature.digest[:]) {
+		return base.CorruptionErrorf("pebble/table: block digest does not match the signed digest")
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package sstable
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble/internal/base"
+	"github.com/cockroachdb/pebble/objstorage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignedTables(t *testing.T) {
+	ctx := context.Background()
+	keys := &StaticSigningKeys{
+		SigningKeyID: 7,
+		Keys: map[uint32][]byte{
+			6: []byte("old-key"),
+			7: []byte("current-key"),
+		},
+	}
+	write := func(tf TableFormat, signingKeys SigningKeyProvider) []byte {
+		f := &objstorage.MemObj{}
+		w := NewWriter(f, WriterOptions{
+			TableFormat: tf,
+			BlockSize:   128,
+			SigningKeys: signingKeys,
+		})
+		for i := 0; i < 1000; i++ {
+			require.NoError(t, w.Set(fmt.Appendf(nil, "key-%04d", i), fmt.Appendf(nil, "value-%d", i)))
+		}
+		require.NoError(t, w.DeleteRange([]byte("key-0100"), []byte("key-0200")))
+		require.NoError(t, w.Close())
+		return f.Data()
+	}
+	readFooterOf := func(data []byte) footer {
+		foot, err := readFooter(ctx, newMemReader(data), nil, base.NoopLoggerAndTracer{}, 0)
+		require.NoError(t, err)
+		return foot
+	}
+
+	for _, tf := range []TableFormat{TableFormatPebblev6, TableFormatPebblev7, TableFormatMax} {
+		t.Run(tf.String(), func(t *testing.T) {
+			data := write(tf, keys)
+			foot := readFooterOf(data)
+			require.Equal(t, tf, foot.format)
+			require.NotNil(t, foot.signature)
+			require.Equal(t, uint32(7), foot.signature.keyID)
+			require.Equal(t, uint64(tf.FooterSize()+signatureLen), foot.footerBH.Length)
+
+			// The signed table is readable and verifiable with the signing keys.
+			r, err := NewMemReader(data, ReaderOptions{SigningKeys: keys})
+			require.NoError(t, err)
+			iter, err := r.NewIter(NoTransforms, nil, nil, AssertNoBlobHandles)
+			require.NoError(t, err)
+			n := 0
+			for kv := iter.First(); kv != nil; kv = iter.Next() {
+				n++
+			}
+			require.NoError(t, iter.Close())
+			require.Equal(t, 1000, n)
+			l, err := r.Layout()
+			require.NoError(t, err)
+			require.Contains(t, l.Describe(true /* verbose */, r, nil), "signature key id: 7")
+			require.NoError(t, r.Close())
+			require.NoError(t, VerifySignature(ctx, newMemReader(data), keys))
+
+			// An unsigned table is otherwise identical, apart from the footer.
+			unsigned := write(tf, nil /* signingKeys */)
+			require.Nil(t, readFooterOf(unsigned).signature)
+			require.Equal(t, data[:foot.footerBH.Offset], unsigned[:foot.footerBH.Offset])
+			require.True(t, errors.Is(VerifySignature(ctx, newMemReader(unsigned), keys), ErrUnsignedTable))
+
+			// Signed tables can't be opened without the signing keys.
+			_, err = NewMemReader(data, ReaderOptions{})
+			require.ErrorContains(t, err, "no signing keys are configured")
+			_, err = NewMemReader(data, ReaderOptions{SigningKeys: &StaticSigningKeys{
+				Keys: map[uint32][]byte{6: []byte("old-key")},
+			}})
+			require.ErrorContains(t, err, "unknown signing key 7")
+			_, err = NewMemReader(data, ReaderOptions{SigningKeys: &StaticSigningKeys{
+				Keys: map[uint32][]byte{7: []byte("wrong-key")},
+			}})
+			require.True(t, base.IsCorruptionError(err))
+
+			// Tampering with the metaindex is detected when opening the table.
+			tampered := slices.Clone(data)
+			tampered[foot.metaindexBH.Offset] ^= 0xff
+			_, err = NewMemReader(tampered, ReaderOptions{SigningKeys: keys})
+			require.True(t, base.IsCorruptionError(err))
+			require.Error(t, VerifySignature(ctx, newMemReader(tampered), keys))
+
+			// Tampering with a data block, including its checksum, is detected by
+			// VerifySignature.
+			tampered = slices.Clone(data)
+			tampered[10] ^= 0xff
+			require.True(t, base.IsCorruptionError(VerifySignature(ctx, newMemReader(tampered), keys)))
+		})
+	}
+
+	t.Run("unsupported-format", func(t *testing.T) {
+		require.Panics(t, func() { write(TableFormatPebblev5, keys) })
+	})
+}
@@ -45,6 +45,9 @@ import (
 // some error checking. Suffix rewriting is meant to be efficient, and allowing
 // changes in the TableFormat detracts from that efficiency. Similarly,
 // WriterOptions.FilterPolicy is ignored and any filter block is copied over.
+// Values stored in value columns are copied over as well, so the output
+// sstable has the same value schema as the input; WriterOptions.ValueSchema
+// must either be nil or match the input's value schema.
 //
 // Any obsolete bits that key-value pairs may be annotated with are ignored
 // and lost during the rewrite. Additionally, the output sstable has the
@@ -99,9 +102,13 @@ func rewriteKeySuffixesInBlocks(
 	case props.ComparerName != o.Comparer.Name:
 		return nil, TableFormatUnspecified, errors.Errorf("mismatched Comparer %s vs %s, replacement requires same splitter to copy filters",
 			props.ComparerName, o.Comparer.Name)
+	case o.ValueSchema != nil && o.ValueSchema.Name != props.ValueSchemaName:
+		return nil, TableFormatUnspecified, errors.Errorf("mismatched ValueSchema %q vs %q, replacement copies value columns as-is",
+			props.ValueSchemaName, o.ValueSchema.Name)
 	}
 
 	o.TableFormat = r.tableFormat
+	o.ValueSchema = r.valueSchema
 	// Don't set up a filter; rewriteSuffixes will copy over any existing filter.
 	o.FilterPolicy = base.NoFilterPolicy
 	w := NewRawWriter(out, o)
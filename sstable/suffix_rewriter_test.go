@@ -15,6 +15,7 @@ import (
 	"github.com/cockroachdb/pebble/internal/testkeys"
 	"github.com/cockroachdb/pebble/objstorage"
 	"github.com/cockroachdb/pebble/sstable/block"
+	"github.com/cockroachdb/pebble/sstable/colblk"
 	"github.com/cockroachdb/pebble/sstable/tablefilters/bloom"
 	"github.com/stretchr/testify/require"
 )
@@ -181,6 +182,85 @@ func TestRewriteSuffixProps(t *testing.T) {
 	}
 }
 
+func TestRewriteSuffixesValueColumns(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	const numKeys = 500
+	from, to := []byte("@3"), []byte("@7")
+	key := func(i int, suffix []byte) []byte { return fmt.Appendf(nil, "k%05d%s", i, suffix) }
+	value := func(i int) string {
+		if i%10 == 0 {
+			return fmt.Sprintf("opaque-%d", i)
+		}
+		return fmt.Sprintf("%d:name-%d", i*7, i)
+	}
+	wOpts := WriterOptions{
+		Comparer:    testkeys.Comparer,
+		KeySchema:   &testkeysSchema,
+		ValueSchema: countNameValueSchema,
+		TableFormat: TableFormatPebblev10,
+		BlockSize:   256,
+	}
+	f := &objstorage.MemObj{}
+	w := NewWriter(f, wOpts)
+	for i := 0; i < numKeys; i++ {
+		require.NoError(t, w.Set(key(i, from), []byte(value(i))))
+	}
+	require.NoError(t, w.Close())
+	readerOpts := ReaderOptions{
+		Comparer:     testkeys.Comparer,
+		KeySchemas:   MakeKeySchemas(&testkeysSchema),
+		ValueSchemas: MakeValueSchemas(countNameValueSchema),
+	}
+	r, err := NewMemReader(f.Data(), readerOpts)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, r.Close()) }()
+
+	// The writer's value schema must be unset or match the sstable's.
+	rwOpts := wOpts
+	rwOpts.ValueSchema = &colblk.ValueSchema{
+		Name:        "other",
+		ColumnTypes: countNameValueSchema.ColumnTypes,
+		Decompose:   countNameValueSchema.Decompose,
+		Compose:     countNameValueSchema.Compose,
+	}
+	_, _, err = rewriteKeySuffixesInBlocks(r, f.Data(), &objstorage.MemObj{}, rwOpts, from, to, 2)
+	require.ErrorContains(t, err, "mismatched ValueSchema")
+
+	for _, valueSchema := range []*colblk.ValueSchema{nil, countNameValueSchema} {
+		t.Run(fmt.Sprintf("value-schema=%t", valueSchema != nil), func(t *testing.T) {
+			rwOpts := wOpts
+			rwOpts.ValueSchema = valueSchema
+			rewritten := &objstorage.MemObj{}
+			_, _, err := rewriteKeySuffixesInBlocks(r, f.Data(), rewritten, rwOpts, from, to, 2)
+			require.NoError(t, err)
+
+			rRewritten, err := NewMemReader(rewritten.Data(), readerOpts)
+			require.NoError(t, err)
+			defer func() { require.NoError(t, rRewritten.Close()) }()
+			props, err := rRewritten.ReadPropertiesBlock(context.Background(), nil /* buffer pool */)
+			require.NoError(t, err)
+			require.Equal(t, countNameValueSchema.Name, props.ValueSchemaName)
+
+			iter, err := rRewritten.NewPointIter(context.Background(), IterOptions{
+				Transforms:     NoTransforms,
+				ReaderProvider: MakeTrivialReaderProvider(rRewritten),
+				BlobContext:    AssertNoBlobHandles,
+			})
+			require.NoError(t, err)
+			defer func() { require.NoError(t, iter.Close()) }()
+			i := 0
+			for kv := iter.First(); kv != nil; kv = iter.Next() {
+				require.Equal(t, string(key(i, to)), string(kv.K.UserKey))
+				v, _, err := kv.Value(nil)
+				require.NoError(t, err)
+				require.Equal(t, value(i), string(v))
+				i++
+			}
+			require.Equal(t, numKeys, i)
+		})
+	}
+}
+
 func makeTestkeySSTable(
 	t testing.TB, writerOpts WriterOptions, suffix []byte, keys int, rangeKeys int,
 ) []byte {
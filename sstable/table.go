@@ -210,18 +210,14 @@ const (
 
 	pebbleDBMagic = "\xf0\x9f\xaa\xb3\xf0\x9f\xaa\xb3" // 🪳🪳
 
-	checkedPebbleDBFooterLen      = rocksDBFooterLen + checksumLen
-	checkedPebbleDBMagicOffset    = checkedPebbleDBFooterLen - magicLen
-	checkedPebbleDBVersionOffset  = checkedPebbleDBMagicOffset - versionLen
-	checkedPebbleDBChecksumOffset = checkedPebbleDBVersionOffset - checksumLen
+	checkedPebbleDBFooterLen = rocksDBFooterLen + checksumLen
 
 	// TableFormatPebblev7 footer introduces the Attributes bitset.
-	pebbleDBv7FooterLen              = checkedPebbleDBFooterLen + attributesLen
-	pebbleDBv7FooterChecksumOffset   = pebbleDBv7FooterLen - magicLen - versionLen - checksumLen
-	pebbleDBV7FooterAttributesOffset = pebbleDBv7FooterChecksumOffset - attributesLen
+	pebbleDBv7FooterLen = checkedPebbleDBFooterLen + attributesLen
 
-	minFooterLen = levelDBFooterLen
-	maxFooterLen = pebbleDBv7FooterLen
+	minFooterLen         = levelDBFooterLen
+	maxUnsignedFooterLen = pebbleDBv7FooterLen
+	maxFooterLen         = maxUnsignedFooterLen + signatureLen
 
 	levelDBFormatVersion  = 0
 	rocksDBFormatVersion2 = 2
@@ -283,6 +279,18 @@ const (
 //	checksum: CRC over footer data (4 bytes)
 //	footer version (4 bytes)
 //	table_magic_number (8 bytes)
+//
+// (signed) footer format [applies to signed tables, TableFormatPebblev6 and later]
+//
+//	checksum type (char, 1 byte)
+//	metaindex handle (varint64 offset, varint64 size)
+//	index handle     (varint64 offset, varint64 size)
+//	<padding> to make the total size 2 * BlockHandle::kMaxEncodedLength + 1
+//	signature: key ID, digest and MAC (68 bytes, see signature.go)
+//	attributes: feature bitset (4 bytes) [TableFormatPebblev7 and later]
+//	checksum: CRC over footer data (4 bytes)
+//	footer version (4 bytes)
+//	table_magic_number (8 bytes, pebbleDBSignedMagic)
 type footer struct {
 	format      TableFormat
 	attributes  Attributes
@@ -290,6 +298,16 @@ type footer struct {
 	metaindexBH block.Handle
 	indexBH     block.Handle
 	footerBH    block.Handle
+	// signature is set for signed tables.
+	signature *footerSignature
+}
+
+// footerLen returns the length of the encoded footer.
+func (f *footer) footerLen() int {
+	if f.signature != nil {
+		return f.format.FooterSize() + signatureLen
+	}
+	return f.format.FooterSize()
 }
 
 // readFooter reads the footer from the end of the file.
@@ -302,19 +320,26 @@ func readFooter(
 	logger base.LoggerAndTracer,
 	fileNum base.DiskFileNum,
 ) (footer, error) {
-	var err error
 	size := f.Size()
 	if size < minFooterLen {
 		return footer{}, base.CorruptionErrorf("pebble/table: invalid table %s (file size is too small)",
 			errors.Safe(fileNum))
 	}
-	buf := make([]byte, maxFooterLen)
-	off := size - maxFooterLen
-	if off < 0 {
-		off = 0
-		buf = buf[:size]
+	readTail := func(n int64) (buf []byte, off int64, err error) {
+		buf = make([]byte, n)
+		off = size - n
+		if off < 0 {
+			off = 0
+			buf = buf[:size]
+		}
+		buf, err = block.ReadRaw(ctx, f, readHandle, logger, fileNum, buf, off)
+		return buf, off, err
+	}
+	// Signed footers are longer than the others, so they require a second read.
+	buf, off, err := readTail(maxUnsignedFooterLen)
+	if err == nil && len(buf) >= magicLen && string(buf[len(buf)-magicLen:]) == pebbleDBSignedMagic {
+		buf, off, err = readTail(maxFooterLen)
 	}
-	buf, err = block.ReadRaw(ctx, f, readHandle, logger, fileNum, buf, off)
 	if err != nil {
 		return footer{}, err
 	}
@@ -338,7 +363,7 @@ func parseFooter(buf []byte, off, size int64) (footer, error) {
 		footer.format = TableFormatLevelDB
 		footer.checksum = block.ChecksumTypeCRC32c
 
-	case rocksDBMagic, pebbleDBMagic:
+	case rocksDBMagic, pebbleDBMagic, pebbleDBSignedMagic:
 		// NOTE: The Pebble magic string implies the same footer format as that used
 		// by the RocksDBv2 table format.
 		if len(buf) < rocksDBFooterLen {
@@ -353,6 +378,9 @@ func parseFooter(buf []byte, off, size int64) (footer, error) {
 		}
 
 		footerLen := format.FooterSize()
+		if string(magic) == pebbleDBSignedMagic {
+			footerLen += signatureLen
+		}
 		if len(buf) < footerLen {
 			return footer, base.CorruptionErrorf("(footer too short): %d", errors.Safe(len(buf)))
 		}
@@ -371,10 +399,17 @@ func parseFooter(buf []byte, off, size int64) (footer, error) {
 		}
 
 		if format >= TableFormatPebblev6 {
-			checksumOffset := checkedPebbleDBChecksumOffset
+			// The checksum and attributes are located relative to the end of the
+			// footer, which can also include a signature.
+			checksumOffset := footerLen - magicLen - versionLen - checksumLen
+			fieldsEnd := checksumOffset
 			if format >= TableFormatPebblev7 {
-				checksumOffset = pebbleDBv7FooterChecksumOffset
-				footer.attributes = Attributes(binary.LittleEndian.Uint32(buf[pebbleDBV7FooterAttributesOffset:]))
+				fieldsEnd -= attributesLen
+				footer.attributes = Attributes(binary.LittleEndian.Uint32(buf[fieldsEnd:]))
+			}
+			if string(magic) == pebbleDBSignedMagic {
+				footer.signature = &footerSignature{}
+				footer.signature.decode(buf[fieldsEnd-signatureLen:])
 			}
 			encodedChecksum := binary.LittleEndian.Uint32(buf[checksumOffset:])
 			computedChecksum := crc.CRC(0).
@@ -410,8 +445,15 @@ func parseFooter(buf []byte, off, size int64) (footer, error) {
 }
 
 func (f footer) encode(buf []byte) []byte {
-	footerLen := f.format.FooterSize()
-	switch magic, version := f.format.AsTuple(); magic {
+	footerLen := f.footerLen()
+	magic, version := f.format.AsTuple()
+	if f.signature != nil {
+		if f.format < TableFormatPebblev6 {
+			panic(errors.AssertionFailedf("sstable: signed footers require at least %s", TableFormatPebblev6))
+		}
+		magic = pebbleDBSignedMagic
+	}
+	switch magic {
 	case levelDBMagic:
 		buf = buf[:footerLen]
 		clear(buf)
@@ -419,7 +461,7 @@ func (f footer) encode(buf []byte) []byte {
 		f.indexBH.EncodeVarints(buf[n:])
 		copy(buf[len(buf)-len(levelDBMagic):], levelDBMagic)
 
-	case rocksDBMagic, pebbleDBMagic:
+	case rocksDBMagic, pebbleDBMagic, pebbleDBSignedMagic:
 		buf = buf[:footerLen]
 		clear(buf)
 		switch f.checksum {
@@ -442,11 +484,15 @@ func (f footer) encode(buf []byte) []byte {
 		copy(buf[len(buf)-magicLen:], magic)
 
 		if f.format >= TableFormatPebblev6 {
-			checksumOffset := checkedPebbleDBChecksumOffset
+			checksumOffset := footerLen - magicLen - versionLen - checksumLen
+			fieldsEnd := checksumOffset
 			if f.format >= TableFormatPebblev7 {
-				checksumOffset = pebbleDBv7FooterChecksumOffset
+				fieldsEnd -= attributesLen
 				// Write the attributes bitset.
-				binary.LittleEndian.PutUint32(buf[pebbleDBV7FooterAttributesOffset:], uint32(f.attributes))
+				binary.LittleEndian.PutUint32(buf[fieldsEnd:], uint32(f.attributes))
+			}
+			if f.signature != nil {
+				f.signature.encode(buf[fieldsEnd-signatureLen:])
 			}
 
 			computedChecksum := crc.CRC(0).
@@ -468,7 +514,7 @@ func supportsTwoLevelIndex(format TableFormat) bool {
 	case TableFormatLevelDB:
 		return false
 	case TableFormatRocksDBv2, TableFormatPebblev1, TableFormatPebblev2, TableFormatPebblev3, TableFormatPebblev4,
-		TableFormatPebblev5, TableFormatPebblev6, TableFormatPebblev7:
+		TableFormatPebblev4ColumnarIndex, TableFormatPebblev5, TableFormatPebblev6, TableFormatPebblev7:
 		return true
 	default:
 		panic(errors.AssertionFailedf("sstable: unspecified table format version"))
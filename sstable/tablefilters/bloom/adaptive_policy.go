@@ -34,6 +34,33 @@ func (p adaptivePolicyImpl) NewWriter() base.TableFilterWriter {
 	return newAdaptiveFilterWriter(p.TargetBitsPerKey, p.MaxSize)
 }
 
+// BitsPerKey returns the target bits per key of a policy returned by
+// FilterPolicy or AdaptivePolicy. Returns ok=false for any other policy.
+func BitsPerKey(policy base.TableFilterPolicy) (_ uint32, ok bool) {
+	switch p := policy.(type) {
+	case filterPolicyImpl:
+		return p.BitsPerKey, true
+	case adaptivePolicyImpl:
+		return p.TargetBitsPerKey, true
+	default:
+		return 0, false
+	}
+}
+
+// WithBitsPerKey returns a policy that is the same as the given policy, except
+// that it uses the given target bits per key. The policy must have been
+// returned by FilterPolicy or AdaptivePolicy.
+func WithBitsPerKey(policy base.TableFilterPolicy, bitsPerKey uint32) base.TableFilterPolicy {
+	switch p := policy.(type) {
+	case filterPolicyImpl:
+		return FilterPolicy(bitsPerKey)
+	case adaptivePolicyImpl:
+		return AdaptivePolicy(bitsPerKey, p.MaxSize)
+	default:
+		panic(errors.AssertionFailedf("%s is not a bloom filter policy", errors.Safe(policy.Name())))
+	}
+}
+
 // adaptiveFilterWriter is a TableFilterWriter that uses up to w.bitsPerKey to
 // create a filter of up to maxSize bytes.
 type adaptiveFilterWriter struct {
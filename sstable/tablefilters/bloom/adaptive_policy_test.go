@@ -228,3 +228,18 @@ func TestAdaptivePolicyFromName(t *testing.T) {
 		})
 	}
 }
+
+func TestWithBitsPerKey(t *testing.T) {
+	for _, p := range []base.TableFilterPolicy{FilterPolicy(10), AdaptivePolicy(10, 1000)} {
+		bitsPerKey, ok := BitsPerKey(p)
+		require.True(t, ok)
+		require.Equal(t, uint32(10), bitsPerKey)
+		p2 := WithBitsPerKey(p, 14)
+		bitsPerKey, ok = BitsPerKey(p2)
+		require.True(t, ok)
+		require.Equal(t, uint32(14), bitsPerKey)
+		require.Equal(t, WithBitsPerKey(p2, 10), p)
+	}
+	_, ok := BitsPerKey(base.NoFilterPolicy)
+	require.False(t, ok)
+}
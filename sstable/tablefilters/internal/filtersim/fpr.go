@@ -5,10 +5,12 @@
 package filtersim
 
 import (
+	"encoding/binary"
 	"fmt"
 	"math"
 	"math/rand/v2"
 	"runtime"
+	"slices"
 	"sync"
 
 	"github.com/cockroachdb/crlib/crhumanize"
@@ -55,3 +57,53 @@ func FormatFPR(fpr float64) string {
 func FormatFPRWithStdDev(mean, stdDev float64) string {
 	return fmt.Sprintf("%s ± %s", FormatFPR(mean), crhumanize.Percent(stdDev, mean))
 }
+
+// RangeFPR builds a range filter over numKeys random keys and returns the
+// false positive rate of queries for ranges that contain none of the keys,
+// along with the size of the filter.
+//
+// The keys consist of a common prefix followed by 8 random bytes. Each query
+// range spans gapFraction times the average gap between consecutive keys; a
+// gapFraction of 0 corresponds to point queries.
+func RangeFPR(
+	numKeys int,
+	gapFraction float64,
+	numQueries int,
+	buildFilter func(keys [][]byte) []byte,
+	mayContainRange func(filter, lo, hi []byte) bool,
+) (fpr float64, filterSize int) {
+	const prefix = "range-sim/"
+	makeKey := func(v uint64) []byte {
+		return binary.BigEndian.AppendUint64([]byte(prefix), v)
+	}
+	vals := make([]uint64, numKeys)
+	for i := range vals {
+		vals[i] = rand.Uint64()
+	}
+	slices.Sort(vals)
+	keys := make([][]byte, numKeys)
+	for i, v := range vals {
+		keys[i] = makeKey(v)
+	}
+	filter := buildFilter(keys)
+
+	width := uint64(gapFraction * math.MaxUint64 / float64(numKeys))
+	var queries, positives int
+	for queries < numQueries {
+		lo := rand.Uint64()
+		hi := lo + width
+		if hi < lo {
+			// Overflow.
+			continue
+		}
+		if i, _ := slices.BinarySearch(vals, lo); i < len(vals) && vals[i] <= hi {
+			// The range is not empty.
+			continue
+		}
+		queries++
+		if mayContainRange(filter, makeKey(lo), makeKey(hi)) {
+			positives++
+		}
+	}
+	return float64(positives) / float64(queries), len(filter)
+}
@@ -0,0 +1,305 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package rangefilter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// A range filter maps each key to a 64-bit integer in an order-preserving
+// manner: the common prefix of all the keys is stripped, and the next 8 bytes
+// are interpreted as a big-endian integer (zero-padded if necessary). The
+// integers are then scaled to the interval [0, 2^T), by subtracting the
+// smallest integer (the base) and shifting right, where T is chosen so that
+// the scaled integers are sparse within the interval. The sorted set of scaled
+// integers is stored as an Elias-Fano sequence.
+//
+// Since the mapping preserves the order, a range [lo, hi] contains no keys if
+// the scaled range [s(lo), s(hi)] contains no integers from the set. False
+// positives occur when an integer from the set falls within the scaled range
+// even though no key falls within the original range.
+//
+// The filter is encoded as follows:
+//
+//	uvarint len(prefix), prefix
+//	uint64  base, little-endian
+//	byte    shift
+//	byte    highBits (H)
+//	byte    lowBits (L), T = H + L
+//	uvarint n, the number of integers
+//	low     ceil(n*L/64) words; the low L bits of each integer, packed
+//	high    ceil((n+2^H)/64) words; for the i-th integer with high bits h, the
+//	        bit h+i is set
+//	samples ceil(2^H/selectSampleRate) uint32s; the position of every
+//	        selectSampleRate-th zero in the high bits
+//
+// The words are little-endian uint64s, with bit i of a bit sequence stored in
+// bit i%64 of word i/64.
+//
+// This is similar to the Grafite range filter, except that the integers are
+// obtained by an order-preserving scaling of the keys instead of a
+// locality-preserving hash.
+
+// selectSampleRate is the number of zeros in the high bits between the
+// samples used to accelerate select0.
+const selectSampleRate = 256
+
+// maxLowBits is the maximum number of low bits per integer.
+const maxLowBits = 56
+
+// buildFilter encodes a filter for the given integers, which must be the
+// mapped keys (see keyToUint64) of a non-empty set of keys. The integers are
+// sorted in place.
+func buildFilter(prefix []byte, vals []uint64, lowBits int) []byte {
+	slices.Sort(vals)
+	// The number of high bits is chosen so that there are between n and 2n
+	// buckets.
+	highBits := bits.Len(uint(len(vals) - 1))
+	lowBits = min(lowBits, 64-highBits, maxLowBits)
+	base := vals[0]
+	shift := max(0, bits.Len64(vals[len(vals)-1]-base)-(highBits+lowBits))
+	n := 0
+	for i := range vals {
+		v := (vals[i] - base) >> shift
+		if n == 0 || v != vals[n-1] {
+			vals[n] = v
+			n++
+		}
+	}
+	vals = vals[:n]
+
+	numBuckets := 1 << highBits
+	numLowWords := (n*lowBits + 63) / 64
+	numHighWords := (n + numBuckets + 63) / 64
+	numSamples := (numBuckets + selectSampleRate - 1) / selectSampleRate
+
+	buf := make([]byte, 0, 2*binary.MaxVarintLen64+len(prefix)+11+8*(numLowWords+numHighWords)+4*numSamples)
+	buf = binary.AppendUvarint(buf, uint64(len(prefix)))
+	buf = append(buf, prefix...)
+	buf = binary.LittleEndian.AppendUint64(buf, base)
+	buf = append(buf, byte(shift), byte(highBits), byte(lowBits))
+	buf = binary.AppendUvarint(buf, uint64(n))
+
+	low := make([]uint64, numLowWords)
+	high := make([]uint64, numHighWords)
+	lowMask := uint64(1)<<lowBits - 1
+	for i, v := range vals {
+		if lowBits > 0 {
+			setBits(low, i*lowBits, lowBits, v&lowMask)
+		}
+		pos := int(v>>lowBits) + i
+		high[pos/64] |= 1 << (pos % 64)
+	}
+	for _, w := range low {
+		buf = binary.LittleEndian.AppendUint64(buf, w)
+	}
+	for _, w := range high {
+		buf = binary.LittleEndian.AppendUint64(buf, w)
+	}
+	// Record the position of every selectSampleRate-th zero.
+	zeros := 0
+	for pos := 0; pos < n+numBuckets; pos++ {
+		if high[pos/64]&(1<<(pos%64)) == 0 {
+			if zeros%selectSampleRate == 0 {
+				buf = binary.LittleEndian.AppendUint32(buf, uint32(pos))
+			}
+			zeros++
+		}
+	}
+	return buf
+}
+
+// setBits sets the n bits starting at bit position pos to v.
+func setBits(words []uint64, pos, n int, v uint64) {
+	w, b := pos/64, pos%64
+	words[w] |= v << b
+	if b+n > 64 {
+		words[w+1] |= v >> (64 - b)
+	}
+}
+
+// keyToUint64 maps a key that starts with the given prefix to an integer,
+// preserving the order.
+func keyToUint64(prefix, key []byte) uint64 {
+	var buf [8]byte
+	copy(buf[:], key[len(prefix):])
+	return binary.BigEndian.Uint64(buf[:])
+}
+
+// decodedFilter is a decoded range filter.
+type decodedFilter struct {
+	prefix   []byte
+	base     uint64
+	shift    int
+	highBits int
+	lowBits  int
+	n        int
+	low      []byte
+	high     []byte
+	samples  []byte
+}
+
+func decodeFilter(data []byte) (f decodedFilter, ok bool) {
+	prefixLen, n := binary.Uvarint(data)
+	if n <= 0 || uint64(len(data)-n) < prefixLen+11 {
+		return decodedFilter{}, false
+	}
+	data = data[n:]
+	f.prefix = data[:prefixLen]
+	data = data[prefixLen:]
+	f.base = binary.LittleEndian.Uint64(data)
+	f.shift, f.highBits, f.lowBits = int(data[8]), int(data[9]), int(data[10])
+	data = data[11:]
+	numVals, n := binary.Uvarint(data)
+	if n <= 0 || f.highBits > 40 || f.lowBits > maxLowBits || f.shift+f.highBits+f.lowBits > 64 {
+		return decodedFilter{}, false
+	}
+	data = data[n:]
+	f.n = int(numVals)
+	numBuckets := 1 << f.highBits
+	lowLen := 8 * ((f.n*f.lowBits + 63) / 64)
+	highLen := 8 * ((f.n + numBuckets + 63) / 64)
+	samplesLen := 4 * ((numBuckets + selectSampleRate - 1) / selectSampleRate)
+	if len(data) != lowLen+highLen+samplesLen {
+		return decodedFilter{}, false
+	}
+	f.low, data = data[:lowLen], data[lowLen:]
+	f.high, f.samples = data[:highLen], data[highLen:]
+	return f, true
+}
+
+// scale maps a key to the scaled integer space of the filter. It returns -1
+// if the key sorts before all the keys in the filter, +1 if it sorts after all
+// of them, and 0 otherwise, in which case v is the scaled integer.
+func (f *decodedFilter) scale(key []byte) (c int, v uint64) {
+	if !bytes.HasPrefix(key, f.prefix) {
+		if len(key) < len(f.prefix) && bytes.HasPrefix(f.prefix, key) {
+			return -1, 0
+		}
+		return bytes.Compare(key, f.prefix), 0
+	}
+	v = keyToUint64(f.prefix, key)
+	if v < f.base {
+		return -1, 0
+	}
+	v = (v - f.base) >> f.shift
+	if v > f.maxScaled() {
+		return +1, 0
+	}
+	return 0, v
+}
+
+// maxScaled returns the largest scaled integer, 2^T-1.
+func (f *decodedFilter) maxScaled() uint64 {
+	return math.MaxUint64 >> (64 - f.highBits - f.lowBits)
+}
+
+// mayContainRange returns whether the filter may contain a key k with
+// lo <= k <= hi.
+func mayContainRange(data, lo, hi []byte) bool {
+	f, ok := decodeFilter(data)
+	if !ok {
+		// Be conservative with filters we don't understand.
+		return true
+	}
+	if bytes.Compare(lo, hi) > 0 {
+		return false
+	}
+	cLo, vLo := f.scale(lo)
+	cHi, vHi := f.scale(hi)
+	switch {
+	case cLo > 0 || cHi < 0:
+		return false
+	case cLo < 0:
+		vLo = 0
+	}
+	if cHi > 0 {
+		vHi = f.maxScaled()
+	}
+	v, ok := f.lowerBound(vLo)
+	return ok && v <= vHi
+}
+
+func (f *decodedFilter) highWord(i int) uint64 {
+	return binary.LittleEndian.Uint64(f.high[8*i:])
+}
+
+// lowerBound returns the smallest integer in the filter that is >= x, or
+// false if there is no such integer.
+func (f *decodedFilter) lowerBound(x uint64) (uint64, bool) {
+	bucket := int(x >> f.lowBits)
+	// Find the position in the high bits where the bucket starts, which is
+	// right after the (bucket-1)-th zero.
+	pos := 0
+	if bucket > 0 {
+		pos = f.select0(bucket-1) + 1
+	}
+	// The number of integers before the bucket.
+	i := pos - bucket
+	numHighBits := f.n + 1<<f.highBits
+	for i < f.n && pos < numHighBits {
+		word := f.highWord(pos/64) >> (pos % 64)
+		if word == 0 {
+			// The rest of the word consists of zeros, each of which ends a
+			// bucket.
+			n := 64 - pos%64
+			bucket += n
+			pos += n
+			continue
+		}
+		if z := bits.TrailingZeros64(word); z > 0 {
+			bucket += z
+			pos += z
+			continue
+		}
+		// The bit at pos is the i-th integer, which is in this bucket.
+		v := uint64(bucket)<<f.lowBits | f.lowValue(i)
+		if v >= x {
+			return v, true
+		}
+		i++
+		pos++
+	}
+	return 0, false
+}
+
+// lowValue returns the low bits of the i-th integer.
+func (f *decodedFilter) lowValue(i int) uint64 {
+	if f.lowBits == 0 {
+		return 0
+	}
+	pos := i * f.lowBits
+	w, b := pos/64, pos%64
+	v := binary.LittleEndian.Uint64(f.low[8*w:]) >> b
+	if b+f.lowBits > 64 {
+		v |= binary.LittleEndian.Uint64(f.low[8*(w+1):]) << (64 - b)
+	}
+	return v & (1<<f.lowBits - 1)
+}
+
+// select0 returns the position of the k-th zero (starting from 0) in the high
+// bits.
+func (f *decodedFilter) select0(k int) int {
+	s := k / selectSampleRate
+	pos := int(binary.LittleEndian.Uint32(f.samples[4*s:]))
+	k -= s * selectSampleRate
+	for {
+		w := ^f.highWord(pos/64) >> (pos % 64)
+		if c := bits.OnesCount64(w); k >= c {
+			k -= c
+			pos += 64 - pos%64
+			continue
+		}
+		for ; k > 0; k-- {
+			// Clear the lowest set bit.
+			w &= w - 1
+		}
+		return pos + bits.TrailingZeros64(w)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+// Package rangefilter implements range filters: table filters which, in
+// addition to point membership queries, can answer whether a table may
+// contain any key within a range. Iterators with narrow bounds use them to
+// skip tables that contain no keys within the bounds.
+package rangefilter
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble/internal/base"
+)
+
+// Family name for range filters.
+const Family base.TableFilterFamily = "rangefilter"
+
+// MinBitsPerKey and MaxBitsPerKey are the bounds of the bitsPerKey values
+// supported by FilterPolicy.
+const (
+	MinBitsPerKey = 4
+	MaxBitsPerKey = 32
+)
+
+// FilterPolicy returns a base.TableFilterPolicy that creates range filters
+// using approximately the given number of bits per key.
+//
+// The keys are mapped to integers in an order-preserving manner (by stripping
+// the common prefix of all the keys and using the next 8 bytes), so the
+// accuracy of the filter depends on the distribution of the keys. For keys
+// whose distinguishing bytes are uniformly distributed, a query for an empty
+// range has a false positive rate of about 1/2^(bitsPerKey-3), regardless of
+// the length of the range: a false positive only occurs when a key is close
+// to one of the ends of the range. See simulation.md for exact figures.
+//
+// Notes:
+//   - Older Pebble versions do not understand range filters and will not use
+//     these filters.
+//   - Keys that only differ after the first 8 bytes following the common
+//     prefix are indistinguishable to the filter.
+func FilterPolicy(bitsPerKey int) base.TableFilterPolicy {
+	if bitsPerKey < MinBitsPerKey || bitsPerKey > MaxBitsPerKey {
+		panic(errors.AssertionFailedf("invalid bitsPerKey %d", errors.Safe(bitsPerKey)))
+	}
+	return filterPolicyImpl{BitsPerKey: bitsPerKey}
+}
+
+type filterPolicyImpl struct {
+	BitsPerKey int
+}
+
+var _ base.TableFilterPolicy = filterPolicyImpl{}
+
+// Name is part of the base.TableFilterPolicy interface.
+func (p filterPolicyImpl) Name() string {
+	return fmt.Sprintf("rangefilter(%d)", p.BitsPerKey)
+}
+
+// NewWriter is part of the base.TableFilterPolicy interface.
+func (p filterPolicyImpl) NewWriter() base.TableFilterWriter {
+	return newTableFilterWriter(p.BitsPerKey)
+}
+
+// lowBitsForBitsPerKey returns the number of low bits per integer in the
+// Elias-Fano encoding. The high bits take up 2-3 bits per key.
+func lowBitsForBitsPerKey(bitsPerKey int) int {
+	return bitsPerKey - 3
+}
+
+// PolicyFromName returns the filterPolicyImpl corresponding to the given name,
+// or false if the string is not recognized as a range filter policy.
+func PolicyFromName(name string) (_ base.TableFilterPolicy, ok bool) {
+	var bitsPerKey int
+	if n, err := fmt.Sscanf(name, "rangefilter(%d)", &bitsPerKey); err == nil && n == 1 {
+		if bitsPerKey >= MinBitsPerKey && bitsPerKey <= MaxBitsPerKey {
+			return FilterPolicy(bitsPerKey), true
+		}
+	}
+	return nil, false
+}
+
+// Decoder implements base.TableRangeFilterDecoder for range filters.
+var Decoder base.TableRangeFilterDecoder = decoderImpl{}
+
+type decoderImpl struct{}
+
+func (d decoderImpl) Family() base.TableFilterFamily {
+	return Family
+}
+
+func (d decoderImpl) MayContain(filter, key []byte) bool {
+	return mayContainRange(filter, key, key)
+}
+
+func (d decoderImpl) MayContainRange(filter, lo, hi []byte) bool {
+	return mayContainRange(filter, lo, hi)
+}
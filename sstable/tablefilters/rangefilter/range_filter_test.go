@@ -0,0 +1,173 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package rangefilter
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/cockroachdb/pebble/sstable/tablefilters/internal/filtertestutils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndToEnd(t *testing.T) {
+	filtertestutils.RunEndToEndTest(t, FilterPolicy(4), Decoder, 0.5)
+	filtertestutils.RunEndToEndTest(t, FilterPolicy(8), Decoder, 0.2)
+	filtertestutils.RunEndToEndTest(t, FilterPolicy(12), Decoder, 0.1)
+	filtertestutils.RunEndToEndTest(t, FilterPolicy(16), Decoder, 0.1)
+}
+
+func TestPolicyFromName(t *testing.T) {
+	p, ok := PolicyFromName("rangefilter(10)")
+	require.True(t, ok)
+	require.Equal(t, "rangefilter(10)", p.Name())
+	for _, name := range []string{"rangefilter(2)", "rangefilter(40)", "bloom(10)", "rangefilter"} {
+		_, ok := PolicyFromName(name)
+		require.False(t, ok, name)
+	}
+}
+
+// TestMayContainRange checks that range queries have no false negatives.
+func TestMayContainRange(t *testing.T) {
+	for range 100 {
+		prefix := []byte(fmt.Sprintf("prefix-%d/", rand.IntN(10)))[:rand.IntN(10)]
+		alphabet := 2 + rand.IntN(254)
+		randKey := func() []byte {
+			k := slices.Clone(prefix)
+			for range rand.IntN(12) {
+				k = append(k, byte(rand.IntN(alphabet)))
+			}
+			return k
+		}
+		numKeys := 1 + rand.IntN(1000)
+		keys := make([][]byte, numKeys)
+		for i := range keys {
+			keys[i] = randKey()
+		}
+		slices.SortFunc(keys, bytes.Compare)
+		bitsPerKey := MinBitsPerKey + rand.IntN(MaxBitsPerKey-MinBitsPerKey+1)
+		w := FilterPolicy(bitsPerKey).NewWriter()
+		for _, k := range keys {
+			w.AddKey(k)
+		}
+		filter, family, ok := w.Finish()
+		require.True(t, ok)
+		require.Equal(t, Family, family)
+
+		for range 1000 {
+			lo, hi := randKey(), randKey()
+			if bytes.Compare(lo, hi) > 0 {
+				lo, hi = hi, lo
+			}
+			i, _ := slices.BinarySearchFunc(keys, lo, bytes.Compare)
+			nonEmpty := i < len(keys) && bytes.Compare(keys[i], hi) <= 0
+			if nonEmpty && !Decoder.MayContainRange(filter, lo, hi) {
+				t.Fatalf("false negative for [%q, %q] (bitsPerKey=%d)", lo, hi, bitsPerKey)
+			}
+		}
+	}
+}
+
+func TestMayContainRangeExact(t *testing.T) {
+	w := FilterPolicy(10).NewWriter()
+	for _, k := range []string{"foo/a", "foo/c", "foo/e"} {
+		w.AddKey([]byte(k))
+	}
+	filter, _, _ := w.Finish()
+	for _, tc := range []struct {
+		lo, hi   string
+		expected bool
+	}{
+		{"foo/a", "foo/a", true},
+		{"foo/b", "foo/b", false},
+		{"foo/b", "foo/c", true},
+		{"foo/b", "foo/bz", false},
+		{"foo/d", "foo/e", true},
+		{"foo/f", "foo/z", false},
+		{"a", "foo/", false},
+		{"a", "foo/a", true},
+		{"foo0", "z", false},
+		{"a", "z", true},
+		{"foo/c", "foo/a", false},
+	} {
+		require.Equal(t, tc.expected, Decoder.MayContainRange(filter, []byte(tc.lo), []byte(tc.hi)), "[%s, %s]", tc.lo, tc.hi)
+	}
+}
+
+// TestLowerBound checks the Elias-Fano encoding against a sorted slice.
+func TestLowerBound(t *testing.T) {
+	for range 100 {
+		n := 1 + rand.IntN(5000)
+		lowBits := rand.IntN(20)
+		vals := make([]uint64, n)
+		for i := range vals {
+			vals[i] = rand.Uint64()
+			if rand.IntN(4) == 0 && i > 0 {
+				// Add some duplicates.
+				vals[i] = vals[i-1]
+			}
+		}
+		data := buildFilter(nil, slices.Clone(vals), lowBits)
+		f, ok := decodeFilter(data)
+		require.True(t, ok)
+		var expected []uint64
+		for _, v := range vals {
+			expected = append(expected, (v-f.base)>>f.shift)
+		}
+		slices.Sort(expected)
+		expected = slices.Compact(expected)
+		require.Equal(t, len(expected), f.n)
+
+		for range 1000 {
+			var x uint64
+			if rand.IntN(2) == 0 {
+				x = expected[rand.IntN(len(expected))] + uint64(rand.IntN(3)) - 1
+			} else {
+				x = rand.Uint64()
+			}
+			x = min(x, f.maxScaled())
+			i, _ := slices.BinarySearch(expected, x)
+			v, ok := f.lowerBound(x)
+			if i == len(expected) {
+				require.False(t, ok)
+			} else {
+				require.True(t, ok)
+				require.Equal(t, expected[i], v)
+			}
+		}
+	}
+}
+
+func TestDecodeCorrupt(t *testing.T) {
+	w := FilterPolicy(10).NewWriter()
+	for i := range 100 {
+		w.AddKey([]byte(fmt.Sprintf("key-%03d", i)))
+	}
+	filter, _, _ := w.Finish()
+	for i := range len(filter) {
+		// A truncated filter must be rejected, and treated as containing
+		// everything.
+		require.True(t, Decoder.MayContainRange(filter[:i], []byte("a"), []byte("b")))
+	}
+}
+
+func BenchmarkWriter(b *testing.B) {
+	for _, bitsPerKey := range []int{8, 12, 16} {
+		b.Run(fmt.Sprintf("bits=%d", bitsPerKey), func(b *testing.B) {
+			filtertestutils.BenchmarkWriter(b, FilterPolicy(bitsPerKey))
+		})
+	}
+}
+
+func BenchmarkMayContain(b *testing.B) {
+	for _, bitsPerKey := range []int{8, 12, 16} {
+		b.Run(fmt.Sprintf("bits=%d", bitsPerKey), func(b *testing.B) {
+			filtertestutils.BenchmarkMayContain(b, FilterPolicy(bitsPerKey), Decoder)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package rangefilter
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/cockroachdb/crlib/crhumanize"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble/sstable/tablefilters/internal/filtersim"
+)
+
+// SimulateFPR measures the false positive rate of range filters with the given
+// bits per key, for queries spanning gapFraction times the average gap between
+// consecutive keys (see filtersim.RangeFPR).
+func SimulateFPR(
+	avgSize int, bitsPerKey int, gapFraction float64,
+) (fprMean, fprStdDev, avgBitsPerKey float64) {
+	const numRuns = 100
+	const numQueries = 100_000
+
+	var sizeSum, filterSizeSum atomic.Uint64
+	policy := FilterPolicy(bitsPerKey)
+	fprMean, fprStdDev = filtersim.SimulateFPR(numRuns, avgSize, func(size int) float64 {
+		fpr, filterSize := filtersim.RangeFPR(size, gapFraction, numQueries, func(keys [][]byte) []byte {
+			w := policy.NewWriter()
+			for _, k := range keys {
+				w.AddKey(k)
+			}
+			filter, _, ok := w.Finish()
+			if !ok {
+				panic(errors.AssertionFailedf("could not build filter (size=%d)", errors.Safe(size)))
+			}
+			return filter
+		}, mayContainRange)
+		sizeSum.Add(uint64(size))
+		filterSizeSum.Add(uint64(filterSize))
+		return fpr
+	})
+	avgBitsPerKey = float64(filterSizeSum.Load()) / float64(sizeSum.Load()) * 8
+	fmt.Printf("%d bits per key, %s size, range %.2f gaps: bpk %.1f  FPR %s\n",
+		bitsPerKey, crhumanize.Count(avgSize, crhumanize.Compact), gapFraction,
+		avgBitsPerKey,
+		filtersim.FormatFPRWithStdDev(fprMean, fprStdDev),
+	)
+	return fprMean, fprStdDev, avgBitsPerKey
+}
@@ -0,0 +1,56 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+//go:build ignore
+
+// This program generates the simulation.md file via:
+//
+//	go run simulation_gen.go
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cockroachdb/crlib/crhumanize"
+	"github.com/cockroachdb/pebble/internal/ascii"
+	"github.com/cockroachdb/pebble/sstable/tablefilters/internal/filtersim"
+	"github.com/cockroachdb/pebble/sstable/tablefilters/rangefilter"
+)
+
+func main() {
+	bitVals := []int{8, 12, 16}
+	avgSizes := []int{10_000, 100_000}
+	gapFractions := []float64{0, 0.01, 0.1}
+
+	board := ascii.Make(100, 100)
+	cur := board.At(0, 0)
+	cur = cur.WriteString("# Range filter simulation results\n\n")
+	cur = cur.WriteString("The range length is expressed as a fraction of the average gap between\n")
+	cur = cur.WriteString("consecutive keys; a length of 0 corresponds to point queries.\n\n")
+
+	cur = cur.WriteString("| Bits/key |  Keys  | Range length | Actual bits/key |            FPR             |\n")
+	cur = cur.WriteString("|---------:|:------:|:------------:|:---------------:|:--------------------------:|\n")
+
+	for _, b := range bitVals {
+		if b > bitVals[0] {
+			cur = cur.WriteString("|          |        |              |                 |                            |\n")
+		}
+		for _, avgSize := range avgSizes {
+			for _, f := range gapFractions {
+				fprMean, fprStdDev, avgBitsPerKey := rangefilter.SimulateFPR(avgSize, b, f)
+				cur = cur.Printf("| %8d |  %4s  |     %4.2f     |      %5.1f      | %-26s |\n",
+					b, crhumanize.Count(avgSize, crhumanize.Compact), f,
+					avgBitsPerKey,
+					filtersim.FormatFPRWithStdDev(fprMean, fprStdDev),
+				)
+			}
+		}
+	}
+	fmt.Println(board.String())
+	err := os.WriteFile("simulation.md", []byte(board.String()+"\n"), 0644)
+	if err != nil {
+		panic(err)
+	}
+}
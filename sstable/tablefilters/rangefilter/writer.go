@@ -0,0 +1,93 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package rangefilter
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/cockroachdb/pebble/internal/base"
+)
+
+// tableFilterWriter implements base.TableFilterWriter for range filters.
+//
+// The common prefix of the keys is only known once all the keys were added, so
+// the writer retains, for each key, the length of its common prefix with the
+// first key and the 8 bytes that follow it. This is enough to compute the
+// mapped integer of each key once the common prefix is known.
+type tableFilterWriter struct {
+	lowBits int
+	first   []byte
+	last    []byte
+	entries []entry
+}
+
+type entry struct {
+	// lcp is the length of the common prefix of the key and the first key.
+	lcp int
+	// tail holds the 8 bytes of the key following the common prefix with the
+	// first key, zero-padded.
+	tail uint64
+}
+
+func newTableFilterWriter(bitsPerKey int) *tableFilterWriter {
+	return &tableFilterWriter{lowBits: lowBitsForBitsPerKey(bitsPerKey)}
+}
+
+// AddKey implements the base.TableFilterWriter interface.
+func (w *tableFilterWriter) AddKey(key []byte) {
+	if w.first == nil {
+		w.first = append(make([]byte, 0, len(key)), key...)
+		w.last = append(w.last[:0], key...)
+		return
+	}
+	if bytes.Equal(key, w.last) {
+		return
+	}
+	w.last = append(w.last[:0], key...)
+	lcp := commonPrefixLen(w.first, key)
+	var buf [8]byte
+	copy(buf[:], key[lcp:])
+	w.entries = append(w.entries, entry{lcp: lcp, tail: binary.BigEndian.Uint64(buf[:])})
+}
+
+// Finish implements the base.TableFilterWriter interface.
+func (w *tableFilterWriter) Finish() (_ []byte, _ base.TableFilterFamily, ok bool) {
+	if w.first == nil {
+		return nil, "", false
+	}
+	prefixLen := len(w.first)
+	for _, e := range w.entries {
+		prefixLen = min(prefixLen, e.lcp)
+	}
+	prefix := w.first[:prefixLen]
+	vals := make([]uint64, 0, len(w.entries)+1)
+	vals = append(vals, keyToUint64(prefix, w.first))
+	for _, e := range w.entries {
+		// The key consists of the prefix, followed by the bytes of the first
+		// key up to e.lcp, followed by the tail.
+		n := e.lcp - prefixLen
+		if n >= 8 {
+			vals = append(vals, keyToUint64(prefix, w.first[:prefixLen+8]))
+			continue
+		}
+		vals = append(vals, keyToUint64(prefix, w.first[:e.lcp])|e.tail>>(8*n))
+	}
+	data := buildFilter(prefix, vals, w.lowBits)
+	w.first = nil
+	w.last = w.last[:0]
+	w.entries = w.entries[:0]
+	return data, Family, true
+}
+
+func commonPrefixLen(a, b []byte) int {
+	n := min(len(a), len(b))
+	for i := range n {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
@@ -8,15 +8,16 @@ import (
 	"github.com/cockroachdb/pebble/internal/base"
 	"github.com/cockroachdb/pebble/sstable/tablefilters/binaryfuse"
 	"github.com/cockroachdb/pebble/sstable/tablefilters/bloom"
+	"github.com/cockroachdb/pebble/sstable/tablefilters/rangefilter"
 )
 
-// Decoders contains the decoders for bloom and binary fuse filters.
-var Decoders = []base.TableFilterDecoder{bloom.Decoder, binaryfuse.Decoder}
+// Decoders contains the decoders for bloom, binary fuse and range filters.
+var Decoders = []base.TableFilterDecoder{bloom.Decoder, binaryfuse.Decoder, rangefilter.Decoder}
 
 // PolicyFromName returns the TableFilterPolicy corresponding to the given name,
 // or false if the name is not recognized.
 //
-// Supports bloom filters and binary fuse filters. The "none" policy is also
+// Supports bloom filters, binary fuse filters and range filters. The "none" policy is also
 // supported.
 func PolicyFromName(name string) (_ base.TableFilterPolicy, ok bool) {
 	if name == "none" {
@@ -28,5 +29,8 @@ func PolicyFromName(name string) (_ base.TableFilterPolicy, ok bool) {
 	if p, ok := binaryfuse.PolicyFromName(name); ok {
 		return p, true
 	}
+	if p, ok := rangefilter.PolicyFromName(name); ok {
+		return p, true
+	}
 	return nil, false
 }
@@ -13,6 +13,10 @@ type writeTask struct {
 	// before adding the writeTask back to the pool.
 	compressionDone chan bool
 	buf             *dataBlockBuf
+	// dataInflightSize is the uncompressed size of the data block if it was
+	// compressed by the compression workers, and 0 otherwise. It is used to
+	// decrement Writer.coordination.sizeEstimate.inflightSize.
+	dataInflightSize int
 	// If this is not nil, then this index block will be flushed.
 	flushableIndexBlock *indexBlockBuf
 	// currIndexBlock is the index block on which indexBlock.add must be called.
@@ -24,6 +28,12 @@ type writeTask struct {
 	finishedIndexProps []byte
 }
 
+// compress implements compressionJob.
+func (task *writeTask) compress(m *block.PhysicalBlockMaker) {
+	task.buf.compressAndChecksum(m)
+	task.compressionDone <- true
+}
+
 // It is not the responsibility of the writeTask to clear the
 // task.flushableIndexBlock, and task.buf.
 func (task *writeTask) clear() {
@@ -64,6 +74,9 @@ func (w *writeQueue) performWrite(task *writeTask) error {
 	if err != nil {
 		return err
 	}
+	if task.dataInflightSize > 0 {
+		w.writer.coordination.sizeEstimate.dataBlockCompressed(int(handle.Length), task.dataInflightSize)
+	}
 	bhp := block.HandleWithProperties{Handle: handle, Props: task.buf.dataBlockProps}
 	if err = w.writer.addIndexEntry(
 		task.indexEntrySep, bhp, task.buf.tmp[:], task.flushableIndexBlock, task.currIndexBlock,
@@ -30,7 +30,7 @@ func NewRawWriter(writable objstorage.Writable, o WriterOptions) RawWriter {
 func NewRawWriterWithCPUMeasurer(
 	writable objstorage.Writable, o WriterOptions, cpuMeasurer base.CPUMeasurer,
 ) RawWriter {
-	if o.TableFormat <= TableFormatPebblev4 {
+	if !o.TableFormat.BlockColumnar() {
 		// Don't bother plumbing the cpuMeasurer to the row writer since it is not
 		// the default and will be removed.
 		return newRowWriter(writable, o)
@@ -401,6 +401,13 @@ type RawWriter interface {
 	// The writer takes ownership of the filterData buffer.
 	setFilter(filerData []byte, family base.TableFilterFamily)
 
+	// setPartitionedFilter sets the pre-populated partitions of a partitioned
+	// filter. It is used by the sstable copier that can copy parts of an
+	// sstable to a new sstable, using CopySpan().
+	//
+	// The writer takes ownership of the partitions.
+	setPartitionedFilter(parts []filterPartition)
+
 	// copyProperties copies properties from the specified props, and resets others
 	// to prepare for copying data blocks from another sstable. It's specifically
 	// used by the sstable copier that can copy parts of an sstable to a new sstable,
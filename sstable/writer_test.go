@@ -10,11 +10,13 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
 	"math/rand/v2"
 	"strconv"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 	"unsafe"
 
 	"github.com/cockroachdb/crlib/crstrings"
@@ -1202,6 +1204,145 @@ func TestWriter_TableFormatCompatibility(t *testing.T) {
 	}
 }
 
+func TestWriterColumnarIndexBlocks(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	seed := uint64(time.Now().UnixNano())
+	t.Logf("seed: %d", seed)
+	rng := rand.New(rand.NewPCG(0, seed))
+
+	ks := testkeys.Alpha(4)
+	ks = testkeys.EveryN(ks, ks.Count()/2_000)
+	keys := make([][]byte, ks.Count())
+	for i := range keys {
+		keys[i] = testkeys.Key(ks, uint64(i))
+	}
+	write := func(tf TableFormat, indexBlockSize int) *Reader {
+		f := &objstorage.MemObj{}
+		w := NewWriter(f, WriterOptions{
+			Comparer:       testkeys.Comparer,
+			TableFormat:    tf,
+			BlockSize:      256,
+			IndexBlockSize: indexBlockSize,
+			BlockPropertyCollectors: []func() BlockPropertyCollector{
+				NewTestKeysBlockPropertyCollector,
+			},
+		})
+		for i, k := range keys {
+			// The suffixes increase with the keys, and every other key has two
+			// versions.
+			suffix := 1 + 4*i/len(keys)
+			if i%2 == 0 {
+				require.NoError(t, w.Set(fmt.Appendf(nil, "%s@%d", k, suffix+1), fmt.Appendf(nil, "new-%d", i)))
+			}
+			require.NoError(t, w.Set(fmt.Appendf(nil, "%s@%d", k, suffix), fmt.Appendf(nil, "old-%d", i)))
+		}
+		require.NoError(t, w.Close())
+		r, err := NewMemReader(f.Data(), ReaderOptions{Comparer: testkeys.Comparer})
+		require.NoError(t, err)
+		return r
+	}
+	iterKVs := func(iter Iterator, kv *base.InternalKV) string {
+		if kv == nil {
+			require.NoError(t, iter.Error())
+			return "."
+		}
+		v, _, err := kv.Value(nil)
+		require.NoError(t, err)
+		return fmt.Sprintf("%s:%s", kv.K, v)
+	}
+
+	for _, indexBlockSize := range []int{math.MaxInt32, 128} {
+		t.Run(fmt.Sprintf("index-block-size=%d", indexBlockSize), func(t *testing.T) {
+			expected := write(TableFormatPebblev4, indexBlockSize)
+			defer func() { require.NoError(t, expected.Close()) }()
+			r := write(TableFormatPebblev4ColumnarIndex, indexBlockSize)
+			defer func() { require.NoError(t, r.Close()) }()
+
+			require.False(t, expected.tableFormat.ColumnarIndex())
+			require.True(t, r.tableFormat.ColumnarIndex())
+			require.Equal(t, indexBlockSize == 128, r.Attributes.Has(AttributeTwoLevelIndex))
+			require.Equal(t, expected.Attributes.Has(AttributeTwoLevelIndex), r.Attributes.Has(AttributeTwoLevelIndex))
+			props, err := r.ReadPropertiesBlock(context.Background(), nil /* buffer pool */)
+			require.NoError(t, err)
+			expectedProps, err := expected.ReadPropertiesBlock(context.Background(), nil /* buffer pool */)
+			require.NoError(t, err)
+			require.Equal(t, expectedProps.NumDataBlocks, props.NumDataBlocks)
+			require.Equal(t, expectedProps.IndexPartitions, props.IndexPartitions)
+			require.Less(t, props.IndexSize, expectedProps.IndexSize)
+
+			// The data blocks are identical.
+			l, err := r.Layout()
+			require.NoError(t, err)
+			expectedLayout, err := expected.Layout()
+			require.NoError(t, err)
+			require.Equal(t, expectedLayout.Data, l.Data)
+
+			// Iterating over both tables yields the same results.
+			iter, err := r.NewIter(NoTransforms, nil /* lower */, nil /* upper */, AssertNoBlobHandles)
+			require.NoError(t, err)
+			defer func() { require.NoError(t, iter.Close()) }()
+			expectedIter, err := expected.NewIter(NoTransforms, nil /* lower */, nil /* upper */, AssertNoBlobHandles)
+			require.NoError(t, err)
+			defer func() { require.NoError(t, expectedIter.Close()) }()
+			var numKeys, numKeysSuffix5 int
+			expectedKV := expectedIter.First()
+			for kv := iter.First(); kv != nil || expectedKV != nil; kv = iter.Next() {
+				require.Equal(t, iterKVs(expectedIter, expectedKV), iterKVs(iter, kv))
+				numKeys++
+				if bytes.HasSuffix(kv.K.UserKey, []byte("@5")) {
+					numKeysSuffix5++
+				}
+				expectedKV = expectedIter.Next()
+			}
+			for range 1000 {
+				k := fmt.Appendf(nil, "%s@%d", keys[rng.IntN(len(keys))], rng.IntN(6))
+				var kv, expectedKV *base.InternalKV
+				switch rng.IntN(3) {
+				case 0:
+					expectedKV, kv = expectedIter.SeekGE(k, base.SeekGEFlagsNone), iter.SeekGE(k, base.SeekGEFlagsNone)
+				case 1:
+					expectedKV, kv = expectedIter.SeekLT(k, base.SeekLTFlagsNone), iter.SeekLT(k, base.SeekLTFlagsNone)
+				case 2:
+					prefix := k[:testkeys.Comparer.Split(k)]
+					expectedKV, kv = expectedIter.SeekPrefixGE(prefix, k, base.SeekGEFlagsNone), iter.SeekPrefixGE(prefix, k, base.SeekGEFlagsNone)
+				}
+				require.Equal(t, iterKVs(expectedIter, expectedKV), iterKVs(iter, kv))
+				if kv != nil {
+					require.Equal(t, iterKVs(expectedIter, expectedIter.Next()), iterKVs(iter, iter.Next()))
+				}
+			}
+
+			// Block properties stored in the index blocks are used to filter
+			// data blocks.
+			filterer := newBlockPropertiesFilterer([]BlockPropertyFilter{
+				NewTestKeysBlockPropertyFilter(5, 6),
+			}, nil, nil)
+			ok, err := filterer.intersectsUserPropsAndFinishInit(r.UserProperties)
+			require.NoError(t, err)
+			require.True(t, ok)
+			filteredIter, err := r.NewPointIter(context.Background(), IterOptions{
+				Transforms:           NoTransforms,
+				Filterer:             filterer,
+				FilterBlockSizeLimit: NeverUseFilterBlock,
+				Env:                  NoReadEnv,
+				ReaderProvider:       MakeTrivialReaderProvider(r),
+				BlobContext:          AssertNoBlobHandles,
+			})
+			require.NoError(t, err)
+			var numFilteredKeys, numFilteredKeysSuffix5 int
+			for kv := filteredIter.First(); kv != nil; kv = filteredIter.Next() {
+				numFilteredKeys++
+				if bytes.HasSuffix(kv.K.UserKey, []byte("@5")) {
+					numFilteredKeysSuffix5++
+				}
+			}
+			require.NoError(t, filteredIter.Close())
+			require.Equal(t, numKeysSuffix5, numFilteredKeysSuffix5)
+			require.Less(t, numFilteredKeys, numKeys/2)
+		})
+	}
+}
+
 // Tests for races, such as https://github.com/cockroachdb/cockroach/issues/77194,
 // in the Writer.
 func TestWriterRace(t *testing.T) {
@@ -1368,6 +1509,63 @@ func BenchmarkWriterWithVersions(b *testing.B) {
 	}
 }
 
+// BenchmarkWriterCompressionWorkers compares the throughput of the writer
+// (measured in uncompressed key and value bytes) with different numbers of
+// compression workers, for each compression setting.
+func BenchmarkWriterCompressionWorkers(b *testing.B) {
+	rng := rand.New(rand.NewPCG(0, 1))
+	keys := make([][]byte, 2e5)
+	values := make([][]byte, len(keys))
+	var inputBytes int64
+	for i := range keys {
+		keys[i] = fmt.Appendf(nil, "0123456789abcd-%08d", i)
+		// Values are moderately compressible.
+		values[i] = make([]byte, 100)
+		for j := range values[i] {
+			values[i][j] = byte('a' + rng.IntN(16))
+		}
+		inputBytes += int64(len(keys[i]) + len(values[i]))
+	}
+	compressions := []*block.CompressionProfile{
+		block.NoCompression,
+		block.SnappyCompression,
+		block.ZstdCompression,
+		block.MinLZCompression,
+	}
+	for _, format := range []TableFormat{TableFormatPebblev4, TableFormatMax} {
+		b.Run(fmt.Sprintf("format=%s", format.String()), func(b *testing.B) {
+			for _, comp := range compressions {
+				b.Run(fmt.Sprintf("compression=%s", comp.Name), func(b *testing.B) {
+					for _, workers := range []int{0, 1, 2, 4, 8} {
+						b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+							opts := WriterOptions{
+								BlockSize:          32 << 10,
+								Compression:        comp,
+								TableFormat:        format,
+								CompressionWorkers: workers,
+							}
+							f := &discardFile{}
+							b.SetBytes(inputBytes)
+							b.ResetTimer()
+							for i := 0; i < b.N; i++ {
+								w := NewWriter(f, opts)
+								for j := range keys {
+									if err := w.Set(keys[j], values[j]); err != nil {
+										b.Fatal(err)
+									}
+								}
+								if err := w.Close(); err != nil {
+									b.Fatal(err)
+								}
+							}
+						})
+					}
+				})
+			}
+		})
+	}
+}
+
 func runWriterBench(b *testing.B, keys [][]byte, comparer *base.Comparer, format TableFormat) {
 	compressions := []*block.CompressionProfile{
 		block.NoCompression,
@@ -0,0 +1,137 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package pebble
+
+import (
+	"math"
+
+	"github.com/cockroachdb/pebble/internal/base"
+	"github.com/cockroachdb/pebble/internal/manifest"
+	"github.com/cockroachdb/pebble/sstable"
+	"github.com/cockroachdb/pebble/sstable/tablefilters/bloom"
+)
+
+// maxAdaptiveFilterBitsPerKey is the maximum bits per key used by adaptive
+// filter sizing (unless the level's target is higher). Our blocked bloom filter
+// achieves diminishing returns above this value (see bloom.FilterPolicy).
+const maxAdaptiveFilterBitsPerKey = 20
+
+// tableFilterSizing contains the statistics used to adapt the size of the
+// Bloom filters of the tables written by a compaction; see
+// Options.TableFilterMemoryBudget.
+type tableFilterSizing struct {
+	// inputKeys is the number of keys in the compaction's input tables.
+	inputKeys float64
+	// inputLookups is the number of negative lookups (filter hits and false
+	// positives) seen by the filters of the compaction's input tables.
+	inputLookups float64
+	// inputFilterSize is the total filter size of the compaction's input
+	// tables.
+	inputFilterSize uint64
+	// totalKeys, totalLookups and totalFilterSize are the same as above, for
+	// all the open tables with filters (see
+	// sstable.FilterMetricsTracker.OpenTablesMetrics). Only the filters of
+	// open tables take up memory.
+	totalKeys       float64
+	totalLookups    float64
+	totalFilterSize uint64
+}
+
+// makeTableFilterSizing collects the statistics of the open tables and of the
+// given compaction inputs.
+func makeTableFilterSizing(
+	inputs []compactionLevel, tracker *sstable.FilterMetricsTracker,
+) tableFilterSizing {
+	var s tableFilterSizing
+	totalKeys, totalFilterSize, totalLookups := tracker.OpenTablesMetrics()
+	s.totalKeys = float64(totalKeys)
+	s.totalLookups = float64(totalLookups)
+	s.totalFilterSize = totalFilterSize
+	for _, cl := range inputs {
+		for t := range cl.files.All() {
+			keys, lookups, filterSize := tableFilterStats(t, tracker)
+			s.inputKeys += keys
+			s.inputLookups += lookups
+			s.inputFilterSize += filterSize
+		}
+	}
+	return s
+}
+
+// tableFilterStats returns the number of keys, the number of negative lookups
+// seen by the filter while the table is open (filter hits and false positives)
+// and the filter size of the given table. The values are scaled for virtual
+// tables.
+func tableFilterStats(
+	t *manifest.TableMetadata, tracker *sstable.FilterMetricsTracker,
+) (keys, lookups float64, filterSize uint64) {
+	props, ok := t.TableBacking.Properties()
+	if !ok {
+		return 0, 0, 0
+	}
+	m, _ := tracker.TableMetrics(t.TableBacking.DiskFileNum)
+	keys = t.ScaleStatisticFloat(float64(props.NumEntries))
+	lookups = t.ScaleStatisticFloat(float64(m.Hits + m.FalsePositives))
+	return keys, lookups, t.ScaleStatistic(props.FilterSize)
+}
+
+// adaptPolicy returns the filter policy to use for the output tables of the
+// compaction, given the policy that would otherwise be used. Only Bloom filter
+// policies are adapted.
+func (s *tableFilterSizing) adaptPolicy(
+	policy base.TableFilterPolicy, budget uint64,
+) base.TableFilterPolicy {
+	target, ok := bloom.BitsPerKey(policy)
+	if !ok {
+		return policy
+	}
+	bitsPerKey := s.bitsPerKey(target, budget)
+	if bitsPerKey == 0 {
+		return base.NoFilterPolicy
+	}
+	return bloom.WithBitsPerKey(policy, bitsPerKey)
+}
+
+// bitsPerKey returns the bits per key for the output tables of the
+// compaction, given the target bits per key of the level. Returns 0 if the
+// output tables should not have filters.
+func (s *tableFilterSizing) bitsPerKey(target uint32, budget uint64) uint32 {
+	if s.inputKeys == 0 {
+		// We don't know anything about the data being written.
+		return target
+	}
+	bitsPerKey := float64(target)
+	if s.totalLookups > 0 && s.totalKeys > 0 {
+		// For a fixed total filter size, the expected number of false positives
+		// across all tables is minimized when each table gets
+		// ln(lookups/keys)/ln(2)^2 bits per key, plus a constant (see
+		// DBTableFilterPolicyProgressive). We use the target bits per key for
+		// tables that see the average number of negative lookups per key. We add
+		// one to the number of lookups so that cold tables don't end up with an
+		// arbitrarily small number of bits per key.
+		rate := (s.inputLookups + 1) / s.inputKeys
+		avgRate := (s.totalLookups + 1) / s.totalKeys
+		bitsPerKey += math.Log(rate/avgRate) / (math.Ln2 * math.Ln2)
+	}
+	bitsPerKey = min(bitsPerKey, float64(max(target, maxAdaptiveFilterBitsPerKey)))
+	bitsPerKey = max(bitsPerKey, 1)
+	if budget > 0 {
+		// The output tables replace the input tables, so their filters can use
+		// whatever part of the budget is not used by the other tables.
+		// Note that the input statistics can include tables whose properties
+		// were loaded after we computed the totals.
+		var otherFilterSize uint64
+		if s.totalFilterSize > s.inputFilterSize {
+			otherFilterSize = s.totalFilterSize - s.inputFilterSize
+		}
+		if otherFilterSize >= budget {
+			return 0
+		}
+		bitsPerKey = min(bitsPerKey, float64(budget-otherFilterSize)*8/s.inputKeys)
+	}
+	// The budget can leave us with less than one bit per key, in which case we
+	// don't write a filter.
+	return uint32(bitsPerKey)
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package pebble
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/pebble/internal/base"
+	"github.com/cockroachdb/pebble/sstable/tablefilters/binaryfuse"
+	"github.com/cockroachdb/pebble/sstable/tablefilters/bloom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableFilterSizing(t *testing.T) {
+	const budget = 1 << 30
+	// The inputs hold 1% of the keys in the LSM.
+	sizing := func(inputLookups, totalLookups float64) tableFilterSizing {
+		return tableFilterSizing{
+			inputKeys:       1000,
+			inputLookups:    inputLookups,
+			inputFilterSize: 1000,
+			totalKeys:       100000,
+			totalLookups:    totalLookups,
+			totalFilterSize: 100000,
+		}
+	}
+
+	// Without lookups, the target is used.
+	s := sizing(0, 0)
+	require.Equal(t, uint32(10), s.bitsPerKey(10, budget))
+	// Inputs with an average lookup rate use the target.
+	s = sizing(999, 99999)
+	require.Equal(t, uint32(10), s.bitsPerKey(10, budget))
+	// Hot inputs get more bits per key (~1.44 bits for each doubling of the
+	// lookup rate), up to a maximum.
+	s = sizing(3999, 99999)
+	require.Equal(t, uint32(12), s.bitsPerKey(10, budget))
+	s = sizing(99999, 99999)
+	require.Equal(t, uint32(maxAdaptiveFilterBitsPerKey), s.bitsPerKey(16, budget))
+	// Cold inputs get fewer bits per key, down to one.
+	s = sizing(249, 99999)
+	require.Equal(t, uint32(7), s.bitsPerKey(10, budget))
+	s = sizing(0, 99999)
+	require.Equal(t, uint32(1), s.bitsPerKey(10, budget))
+
+	// The budget caps the bits per key: the other tables use 99000 bytes, which
+	// leaves 1000 bytes (8 bits per key) for the output.
+	s = sizing(999, 99999)
+	require.Equal(t, uint32(8), s.bitsPerKey(10, 100000))
+	// There is no room for a filter when the other tables use up the budget.
+	require.Equal(t, uint32(0), s.bitsPerKey(10, 99000))
+	require.Equal(t, base.NoFilterPolicy, s.adaptPolicy(bloom.FilterPolicy(10), 99000))
+
+	// Only bloom filter policies are adapted.
+	s = sizing(3999, 99999)
+	require.Equal(t, bloom.FilterPolicy(12), s.adaptPolicy(bloom.FilterPolicy(10), budget))
+	require.Equal(t, bloom.AdaptivePolicy(12, 1<<20), s.adaptPolicy(bloom.AdaptivePolicy(10, 1<<20), budget))
+	require.Equal(t, binaryfuse.FilterPolicy(8), s.adaptPolicy(binaryfuse.FilterPolicy(8), budget))
+	require.Equal(t, base.NoFilterPolicy, s.adaptPolicy(base.NoFilterPolicy, budget))
+}
@@ -0,0 +1,170 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package pebble
+
+import (
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble/internal/base"
+	"github.com/cockroachdb/pebble/internal/compact"
+	"github.com/cockroachdb/pebble/internal/manifest"
+	"github.com/cockroachdb/pebble/metrics"
+	"github.com/cockroachdb/pebble/objstorage"
+)
+
+// coldThreshold returns the tiering attribute below which KVs governed by the
+// given policy belong in the cold tier, as of the given time.
+func coldThreshold(now time.Time, policy base.TieringPolicy) base.TieringAttribute {
+	t := now.Add(-policy.AgeThreshold).Unix()
+	if t <= 0 {
+		return 0
+	}
+	return base.TieringAttribute(t)
+}
+
+// objectTier returns the storage tier of the given object, as recorded in its
+// placement metadata. Local objects record their tier; a shared object belongs
+// to the cold tier if it was created on Options.Tiering.ColdSharedLocator (which
+// Options.Validate requires to be distinct from CreateOnSharedLocator).
+func (d *DB) objectTier(fileType base.FileType, fileNum base.DiskFileNum) base.StorageTier {
+	meta, err := d.objProvider.Lookup(fileType, fileNum)
+	if err != nil {
+		// If an object is unknown to the provider, it must be a local object that
+		// disappeared before we reopened a store.
+		return base.HotTier
+	}
+	return d.objectTierFromMeta(meta)
+}
+
+func (d *DB) objectTierFromMeta(meta objstorage.ObjectMetadata) base.StorageTier {
+	switch {
+	case !meta.IsRemote():
+		return meta.Local.Tier
+	case meta.IsShared() && d.opts.Tiering.CreateColdOnShared &&
+		meta.Remote.Locator == d.opts.Tiering.ColdSharedLocator:
+		return base.ColdTier
+	default:
+		return base.HotTier
+	}
+}
+
+// tableTierFunc returns the function used by compactions to route KVs to hot
+// or cold output tables, or nil if the output tables of the compaction are not
+// tiered.
+//
+// Tables are only placed on the cold tier by compactions into the bottommost
+// level, where data that has aged into the cold tier settles; this avoids
+// repeatedly moving cold tables between tiers as they are compacted down the
+// LSM.
+func (d *DB) tableTierFunc(
+	outputLevel int, policy base.TieringPolicyAndExtractor,
+) compact.TierFunc {
+	if !d.opts.Tiering.CreateColdOnShared || !policy.IsSet() || outputLevel != numLevels-1 {
+		return nil
+	}
+	if _, ok := d.objProvider.CreatorID(); !ok {
+		// Cold objects can't be created on shared storage yet.
+		return nil
+	}
+	threshold := coldThreshold(d.opts.Tiering.NowFn(), policy.TieringPolicy)
+	return func(kv *base.InternalKV, meta base.KVMeta) (base.StorageTier, bool) {
+		if !meta.IsSet() {
+			// KVs written by compactions carry their tiering metadata; KVs from
+			// flushes or ingested tables might not. Extract the attribute from
+			// in-place values. Values that are stored out of place are not read,
+			// and tombstones have no attribute; neither constrains the tier of
+			// the table.
+			if policy.ExtractAttribute == nil || !kv.V.IsInPlaceValue() || !kv.K.Kind().IsSet() {
+				return 0, false
+			}
+			attr, err := policy.ExtractAttribute(kv.K.UserKey, kv.V.InPlaceValue())
+			if err != nil {
+				// KVs with extraction errors remain in the hot tier.
+				return base.HotTier, true
+			}
+			meta = base.KVMeta{TieringSpanID: policy.SpanID, TieringAttribute: attr}
+		}
+		if meta.TieringSpanID == policy.SpanID && meta.TieringAttribute < threshold {
+			return base.ColdTier, true
+		}
+		return base.HotTier, true
+	}
+}
+
+// virtualBackingsByTier returns the count and size of the backings of virtual
+// tables in each storage tier.
+//
+// d.mu must be held.
+func (d *DB) virtualBackingsByTier() metrics.CountAndSizeByTier {
+	var res metrics.CountAndSizeByTier
+	for b := range d.mu.versions.latest.virtualBackings.All() {
+		res.Ptr(d.objectTier(base.FileTypeTable, b.DiskFileNum)).Inc(b.Size)
+	}
+	return res
+}
+
+var blobFileTierAnnotatorIdx = manifest.NewBlobAnnotationIdx()
+
+// makeBlobFileTierAnnotator returns an annotator that computes the count and
+// size of blob files in each storage tier.
+func (d *DB) makeBlobFileTierAnnotator() manifest.BlobFileAnnotator[metrics.CountAndSizeByTier] {
+	return manifest.MakeBlobFileAnnotator[metrics.CountAndSizeByTier](
+		blobFileTierAnnotatorIdx,
+		manifest.BlobFileAnnotatorFuncs[metrics.CountAndSizeByTier]{
+			Merge: (*metrics.CountAndSizeByTier).Accumulate,
+			BlobFile: func(m manifest.BlobFileMetadata) (res metrics.CountAndSizeByTier, cacheOK bool) {
+				tier := d.objectTier(base.FileTypeBlob, m.Physical.FileNum)
+				res.Ptr(tier).Inc(m.Physical.Size)
+				return res, true
+			},
+		})
+}
+
+// EstimateDiskUsageByTier is like EstimateDiskUsage but breaks down the
+// estimated size by storage tier. The size of values stored in blob files is
+// attributed to the tier of the blob file; the rest of the table size is
+// attributed to the tier of the table.
+func (d *DB) EstimateDiskUsageByTier(start, end []byte) (hotSize, coldSize uint64, _ error) {
+	if err := d.closed.Load(); err != nil {
+		panic(err)
+	}
+
+	bounds := base.UserKeyBoundsInclusive(start, end)
+	if !bounds.Valid(d.cmp) {
+		return 0, 0, errors.New("invalid key-range specified (start > end)")
+	}
+
+	readState := d.loadReadState()
+	defer readState.unref()
+	vers := readState.current
+
+	var sizes [base.NumStorageTiers]uint64
+	for level := range vers.Levels {
+		for f := range vers.Overlaps(level, bounds).All() {
+			fileSize := f.Size
+			overlapFraction := 1.0
+			if !f.ContainedWithinSpan(d.cmp, bounds.Start, bounds.End.Key) {
+				var err error
+				fileSize, err = d.fileCache.estimateSize(f, bounds.Start, bounds.End.Key)
+				if err != nil {
+					return 0, 0, err
+				}
+				if f.Size > 0 {
+					overlapFraction = float64(fileSize) / float64(f.Size)
+				}
+			}
+			sizes[d.objectTier(base.FileTypeTable, f.TableBacking.DiskFileNum)] += fileSize
+			for _, ref := range f.BlobReferences {
+				tier := base.HotTier
+				if phys, ok := vers.BlobFiles.LookupPhysical(ref.FileID); ok {
+					tier = d.objectTier(base.FileTypeBlob, phys.FileNum)
+				}
+				sizes[tier] += uint64(float64(ref.EstimatedPhysicalSize) * overlapFraction)
+			}
+		}
+	}
+	return sizes[base.HotTier], sizes[base.ColdTier], nil
+}
@@ -0,0 +1,206 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package pebble
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble/internal/base"
+	"github.com/cockroachdb/pebble/internal/testutils"
+	"github.com/cockroachdb/pebble/objstorage/remote"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTieringColdBlobFiles verifies that values whose tiering attribute is
+// older than the policy's age threshold are written to blob files on the cold
+// shared storage.
+func TestTieringColdBlobFiles(t *testing.T) {
+	now := time.Unix(1_000_000, 0)
+	remoteStorage := remote.NewInMem()
+	opts := &Options{
+		FS:                 vfs.NewMem(),
+		FormatMajorVersion: FormatNewest,
+		Logger:             testutils.Logger{T: t},
+	}
+	opts.RemoteStorage = remote.MakeSimpleFactory(map[remote.Locator]remote.Storage{
+		remote.MakeLocator("cold"): remoteStorage,
+	})
+	opts.Tiering.CreateColdOnShared = true
+	opts.Tiering.ColdSharedLocator = remote.MakeLocator("cold")
+	opts.Tiering.NowFn = func() time.Time { return now }
+	opts.ValueSeparationPolicy = func() ValueSeparationPolicy {
+		return ValueSeparationPolicy{
+			Enabled:                true,
+			MinimumSize:            10,
+			MinimumMVCCGarbageSize: 10,
+			MaxBlobReferenceDepth:  5,
+		}
+	}
+	// Values are prefixed by a big-endian timestamp which is used as the
+	// tiering attribute.
+	opts.SpanPolicyFunc = func(bounds base.UserKeyBounds) (base.SpanPolicy, error) {
+		return base.SpanPolicy{
+			TieringPolicy: base.TieringPolicyAndExtractor{
+				TieringPolicy: base.TieringPolicy{SpanID: 1, AgeThreshold: time.Hour},
+				ExtractAttribute: func(userKey []byte, value []byte) (base.TieringAttribute, error) {
+					if len(value) < 8 {
+						return 0, errors.New("value too short")
+					}
+					return base.TieringAttribute(binary.BigEndian.Uint64(value)), nil
+				},
+			},
+		}, nil
+	}
+	d, err := Open("", opts)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, d.Close()) }()
+	require.NoError(t, d.SetCreatorID(1))
+
+	makeValue := func(ts time.Time, i int) []byte {
+		v := binary.BigEndian.AppendUint64(nil, uint64(ts.Unix()))
+		return append(v, strings.Repeat(fmt.Sprint(i), 20)...)
+	}
+	valueFor := func(i int) []byte {
+		if i%2 == 0 {
+			// Old enough to be cold.
+			return makeValue(now.Add(-2*time.Hour), i)
+		}
+		return makeValue(now.Add(-time.Minute), i)
+	}
+	const numKeys = 10
+	for i := 0; i < numKeys; i++ {
+		require.NoError(t, d.Set([]byte(fmt.Sprintf("k%02d", i)), valueFor(i), nil))
+	}
+	require.NoError(t, d.Flush())
+
+	m := d.Metrics()
+	require.Equal(t, uint64(1), m.BlobFiles.LiveByTier.Hot.Count)
+	require.Equal(t, uint64(1), m.BlobFiles.LiveByTier.Cold.Count)
+	require.Equal(t, uint64(1), m.BlobFiles.Live.Shared.Count)
+
+	names, err := remoteStorage.List("", "")
+	require.NoError(t, err)
+	var numBlobs int
+	for _, name := range names {
+		if strings.HasSuffix(name, ".blob") {
+			numBlobs++
+		}
+	}
+	require.Equal(t, 1, numBlobs)
+
+	for i := 0; i < numKeys; i++ {
+		v, closer, err := d.Get([]byte(fmt.Sprintf("k%02d", i)))
+		require.NoError(t, err)
+		require.True(t, bytes.Equal(valueFor(i), v))
+		require.NoError(t, closer.Close())
+	}
+
+	hot, cold, err := d.EstimateDiskUsageByTier([]byte("a"), []byte("z"))
+	require.NoError(t, err)
+	require.Greater(t, hot, uint64(0))
+	require.Greater(t, cold, uint64(0))
+	total, err := d.EstimateDiskUsage([]byte("a"), []byte("z"))
+	require.NoError(t, err)
+	require.Equal(t, total, hot+cold)
+}
+
+// TestTieringColdTables verifies that compactions into the bottommost level
+// split their outputs at tier boundaries, create the cold tables on the cold
+// shared storage, and that tables created on CreateOnSharedLocator are
+// attributed to the hot tier.
+func TestTieringColdTables(t *testing.T) {
+	now := time.Unix(1_000_000, 0)
+	coldStorage := remote.NewInMem()
+	opts := &Options{
+		FS:                 vfs.NewMem(),
+		FormatMajorVersion: FormatNewest,
+		Logger:             testutils.Logger{T: t},
+	}
+	opts.RemoteStorage = remote.MakeSimpleFactory(map[remote.Locator]remote.Storage{
+		remote.MakeLocator("hot"):  remote.NewInMem(),
+		remote.MakeLocator("cold"): coldStorage,
+	})
+	opts.CreateOnShared = remote.CreateOnSharedAll
+	opts.CreateOnSharedLocator = remote.MakeLocator("hot")
+	opts.Tiering.CreateColdOnShared = true
+	opts.Tiering.ColdSharedLocator = remote.MakeLocator("cold")
+	opts.Tiering.NowFn = func() time.Time { return now }
+	opts.SpanPolicyFunc = func(bounds base.UserKeyBounds) (base.SpanPolicy, error) {
+		return base.SpanPolicy{
+			TieringPolicy: base.TieringPolicyAndExtractor{
+				TieringPolicy: base.TieringPolicy{SpanID: 1, AgeThreshold: time.Hour},
+				ExtractAttribute: func(userKey []byte, value []byte) (base.TieringAttribute, error) {
+					if len(value) < 8 {
+						return 0, errors.New("value too short")
+					}
+					return base.TieringAttribute(binary.BigEndian.Uint64(value)), nil
+				},
+			},
+		}, nil
+	}
+	d, err := Open("", opts)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, d.Close()) }()
+	require.NoError(t, d.SetCreatorID(1))
+
+	// Keys a* are cold and keys b* are hot.
+	valueFor := func(key string) []byte {
+		ts := now.Add(-time.Minute)
+		if key[0] == 'a' {
+			ts = now.Add(-2 * time.Hour)
+		}
+		return binary.BigEndian.AppendUint64(nil, uint64(ts.Unix()))
+	}
+	var keys []string
+	for _, prefix := range []string{"a", "b"} {
+		for i := 0; i < 5; i++ {
+			keys = append(keys, fmt.Sprintf("%s%02d", prefix, i))
+		}
+	}
+	// Write the keys in two overlapping flushes so that the manual compaction
+	// rewrites them rather than moving a table.
+	for range 2 {
+		for _, key := range keys {
+			require.NoError(t, d.Set([]byte(key), valueFor(key), nil))
+		}
+		require.NoError(t, d.Flush())
+	}
+	require.NoError(t, d.Compact(context.Background(), []byte("a"), []byte("c"), false /* parallelize */))
+
+	m := d.Metrics()
+	require.Equal(t, uint64(1), m.Table.Physical.LiveByTier.Cold.Count)
+	require.Equal(t, uint64(1), m.Table.Physical.LiveByTier.Hot.Count)
+	require.Equal(t, uint64(2), m.Table.Physical.Live.Shared.Count)
+
+	names, err := coldStorage.List("", "")
+	require.NoError(t, err)
+	var numTables int
+	for _, name := range names {
+		if strings.HasSuffix(name, ".sst") {
+			numTables++
+		}
+	}
+	require.Equal(t, 1, numTables)
+
+	for _, key := range keys {
+		v, closer, err := d.Get([]byte(key))
+		require.NoError(t, err)
+		require.True(t, bytes.Equal(valueFor(key), v))
+		require.NoError(t, closer.Close())
+	}
+
+	hot, cold, err := d.EstimateDiskUsageByTier([]byte("a"), []byte("c"))
+	require.NoError(t, err)
+	require.Greater(t, hot, uint64(0))
+	require.Greater(t, cold, uint64(0))
+}
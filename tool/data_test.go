@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -20,6 +21,8 @@ import (
 	"github.com/cockroachdb/pebble/cockroachkvs"
 	"github.com/cockroachdb/pebble/internal/base"
 	"github.com/cockroachdb/pebble/internal/testkeys"
+	"github.com/cockroachdb/pebble/objstorage/objstorageprovider"
+	"github.com/cockroachdb/pebble/sstable"
 	"github.com/cockroachdb/pebble/vfs"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
@@ -93,6 +96,18 @@ func runTests(t *testing.T, path string) {
 					db.Close()
 					return ""
 				}
+				if d.Cmd == "build-zone-map-sstable" {
+					if err := buildZoneMapSSTable(fs, d.CmdArgs[0].String()); err != nil {
+						d.Fatalf(t, "%v", err)
+					}
+					return ""
+				}
+				if d.Cmd == "build-signed-sstable" {
+					if err := buildSignedSSTable(fs, d.CmdArgs[0].String(), d.HasArg("tamper")); err != nil {
+						d.Fatalf(t, "%v", err)
+					}
+					return ""
+				}
 
 				args := []string{d.Cmd}
 				for _, arg := range d.CmdArgs {
@@ -142,6 +157,7 @@ func runTests(t *testing.T, path string) {
 					OpenErrEnhancer(openErrEnhancer),
 					KeySchema(cockroachkvs.KeySchema.Name),
 					KeySchemas(&cockroachkvs.KeySchema),
+					SigningKeys(testSigningKeys),
 				)
 
 				c := &cobra.Command{}
@@ -177,3 +193,81 @@ func overrideRenderMetricsForDeterminism() func() {
 	renderMetrics = (*pebble.Metrics).StringForTests
 	return func() { renderMetrics = previousRenderMetrics }
 }
+
+// buildZoneMapSSTable writes an sstable with small blocks and a "price" zone
+// map over values of the form "<n>". The value of key kNNN is 10*NNN.
+func buildZoneMapSSTable(fs vfs.FS, path string) error {
+	f, err := fs.Create(path, vfs.WriteCategoryUnspecified)
+	if err != nil {
+		return err
+	}
+	w := sstable.NewWriter(objstorageprovider.NewFileWritable(f), sstable.WriterOptions{
+		TableFormat: sstable.TableFormatPebblev4,
+		BlockSize:   64,
+		BlockPropertyCollectors: []func() sstable.BlockPropertyCollector{
+			func() sstable.BlockPropertyCollector {
+				return sstable.NewZoneMapCollector(sstable.ZoneMapConfig{
+					Name: "price",
+					Type: sstable.ZoneMapUint64,
+					Extract: func(key sstable.InternalKey, value []byte) ([]byte, sstable.ZoneMapFieldPresence, error) {
+						if value == nil {
+							return nil, sstable.ZoneMapFieldUnknown, nil
+						}
+						n, err := strconv.ParseUint(string(value), 10, 64)
+						if err != nil {
+							return nil, sstable.ZoneMapFieldAbsent, nil
+						}
+						return sstable.EncodeZoneMapUint64(n), sstable.ZoneMapFieldPresent, nil
+					},
+				})
+			},
+		},
+	})
+	for i := 0; i < 20; i++ {
+		if err := w.Set(fmt.Appendf(nil, "k%03d", i), strconv.AppendInt(nil, int64(10*i), 10)); err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
+
+var testSigningKeys = &sstable.StaticSigningKeys{
+	SigningKeyID: 1,
+	Keys:         map[uint32][]byte{1: []byte("test-key")},
+}
+
+// buildSignedSSTable writes an sstable signed with testSigningKeys. If tamper
+// is true, a byte of the first data block is modified after the table is
+// written.
+func buildSignedSSTable(fs vfs.FS, path string, tamper bool) error {
+	f, err := fs.Create(path, vfs.WriteCategoryUnspecified)
+	if err != nil {
+		return err
+	}
+	w := sstable.NewWriter(objstorageprovider.NewFileWritable(f), sstable.WriterOptions{
+		TableFormat: sstable.TableFormatPebblev7,
+		Comparer:    &cockroachkvs.Comparer,
+		KeySchema:   &cockroachkvs.KeySchema,
+		SigningKeys: testSigningKeys,
+	})
+	for i := 0; i < 20; i++ {
+		k := cockroachkvs.EncodeMVCCKey(nil, fmt.Appendf(nil, "k%03d", i), 1, 0)
+		if err := w.Set(k, fmt.Appendf(nil, "v%d", i)); err != nil {
+			return err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if !tamper {
+		return nil
+	}
+	f, err = fs.OpenReadWrite(path, vfs.WriteCategoryUnspecified)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteAt([]byte("x"), 10); err != nil {
+		return err
+	}
+	return f.Close()
+}
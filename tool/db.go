@@ -77,6 +77,7 @@ type dbT struct {
 	verbose       bool
 	bypassPrompt  bool
 	lsmURL        bool
+	spaceByTier   bool
 	analyzeData   struct {
 		samplePercent int
 		timeout       time.Duration
@@ -213,8 +214,9 @@ not be in use by another process.
 		Short: "print filesystem space used",
 		Long: `
 Print the estimated filesystem space usage for the inclusive-inclusive range
-specified by --start and --end. Requires that the specified database not be in
-use by another process.
+specified by --start and --end. With --by-tier, the usage is broken down by
+storage tier (values in cold-tier blob files are attributed to the cold tier).
+Requires that the specified database not be in use by another process.
 `,
 		Args: cobra.ExactArgs(1),
 		Run:  d.runSpace,
@@ -298,6 +300,8 @@ Returns the filename of the current manifest file.
 		&d.start, "start", "start key for the range")
 	d.Space.Flags().Var(
 		&d.end, "end", "inclusive end key for the range")
+	d.Space.Flags().BoolVar(
+		&d.spaceByTier, "by-tier", false, "break down the space usage by storage tier")
 
 	d.Scan.Flags().Var(
 		&d.fmtKey, "key", "key formatter")
@@ -701,6 +705,16 @@ func (d *dbT) runSpace(cmd *cobra.Command, args []string) {
 	}
 	defer d.closeDB(stdout, db)
 
+	if d.spaceByTier {
+		hot, cold, err := db.EstimateDiskUsageByTier(d.start, d.end)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s\n", err)
+			return
+		}
+		fmt.Fprintf(stdout, "hot: %d\ncold: %d\n", hot, cold)
+		return
+	}
+
 	bytes, err := db.EstimateDiskUsage(d.start, d.end)
 	if err != nil {
 		fmt.Fprintf(stderr, "%s\n", err)
@@ -103,9 +103,10 @@ func (d *dbT) runAnalyzeData(cmd *cobra.Command, args []string) {
 	lastReportTime := startTime
 
 	readerOptions := sstable.ReaderOptions{
-		Comparers:  d.comparers,
-		Mergers:    d.mergers,
-		KeySchemas: d.opts.KeySchemas,
+		Comparers:   d.comparers,
+		Mergers:     d.mergers,
+		KeySchemas:  d.opts.KeySchemas,
+		SigningKeys: d.opts.SigningKeys,
 	}
 	analyzer := compressionanalyzer.NewFileAnalyzer(readLimiter, readerOptions)
 	var sampledFiles int
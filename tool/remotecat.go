@@ -5,14 +5,20 @@
 package tool
 
 import (
+	"cmp"
 	"fmt"
 	"io"
 	"slices"
+	"strings"
+	"time"
 
+	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/pebble"
 	"github.com/cockroachdb/pebble/internal/base"
 	"github.com/cockroachdb/pebble/objstorage"
+	"github.com/cockroachdb/pebble/objstorage/objstorageprovider"
 	"github.com/cockroachdb/pebble/objstorage/objstorageprovider/remoteobjcat"
+	"github.com/cockroachdb/pebble/objstorage/remote"
 	"github.com/cockroachdb/pebble/record"
 	"github.com/spf13/cobra"
 )
@@ -21,14 +27,24 @@ import (
 type remoteCatalogT struct {
 	Root *cobra.Command
 	Dump *cobra.Command
+	GC   *cobra.Command
 
 	verbose bool
 	opts    *pebble.Options
+
+	remoteStorageFn DBRemoteStorageFn
+	gc              struct {
+		creatorID   uint64
+		storage     string
+		gracePeriod time.Duration
+		delete      bool
+	}
 }
 
-func newRemoteCatalog(opts *pebble.Options) *remoteCatalogT {
+func newRemoteCatalog(opts *pebble.Options, remoteStorageFn DBRemoteStorageFn) *remoteCatalogT {
 	m := &remoteCatalogT{
-		opts: opts,
+		opts:            opts,
+		remoteStorageFn: remoteStorageFn,
 	}
 
 	m.Root = &cobra.Command{
@@ -49,6 +65,31 @@ Print the contents of the REMOTE-OBJ-CATALOG files.
 	m.Dump.Flags().BoolVarP(&m.verbose, "verbose", "v", false, "show each record in the catalog")
 	m.Root.AddCommand(m.Dump)
 
+	m.GC = &cobra.Command{
+		Use:   "gc <remote-catalog-files>",
+		Short: "find (and optionally delete) orphaned remote objects",
+		Long: `
+List the remote storage and cross-reference the objects and ref markers
+created by the given creator ID with the given REMOTE-OBJ-CATALOG files
+(which should include the catalogs of all stores that share objects with
+the creator). Objects that are not referenced by any catalog and ref
+markers that have no corresponding catalog entry are reported as orphaned.
+
+The --storage flag specifies either a local directory or a cloud storage
+URI (if supported by the tool).
+
+With --delete, orphaned objects and ref markers older than --grace-period
+are deleted.
+`,
+		Args: cobra.MinimumNArgs(1),
+		Run:  m.runGC,
+	}
+	m.GC.Flags().Uint64Var(&m.gc.creatorID, "creator-id", 0, "creator ID of the store to garbage collect")
+	m.GC.Flags().StringVar(&m.gc.storage, "storage", "", "remote storage directory or URI")
+	m.GC.Flags().DurationVar(&m.gc.gracePeriod, "grace-period", 24*time.Hour, "minimum age of deleted objects")
+	m.GC.Flags().BoolVar(&m.gc.delete, "delete", false, "delete orphaned objects and ref markers")
+	m.Root.AddCommand(m.GC)
+
 	return m
 }
 
@@ -61,16 +102,20 @@ func (m *remoteCatalogT) runDump(cmd *cobra.Command, args []string) {
 	}
 }
 
-func (m *remoteCatalogT) runDumpOne(stdout io.Writer, filename string) error {
+// readCatalogFile reads the given catalog file, calling fn for each version
+// edit before it is applied. Returns the resulting creator ID and objects.
+func (m *remoteCatalogT) readCatalogFile(
+	filename string, fn func(offset int64, editIdx int, ve *remoteobjcat.VersionEdit),
+) (objstorage.CreatorID, map[base.DiskFileNum]remoteobjcat.RemoteObjectMetadata, error) {
 	f, err := m.opts.FS.Open(filename)
 	if err != nil {
-		return err
+		return 0, nil, err
 	}
+	defer f.Close()
 
 	var creatorID objstorage.CreatorID
 	objects := make(map[base.DiskFileNum]remoteobjcat.RemoteObjectMetadata)
 
-	fmt.Fprintf(stdout, "%s\n", filename)
 	var editIdx int
 	rr := record.NewReader(f, 0 /* logNum */)
 	for {
@@ -79,15 +124,28 @@ func (m *remoteCatalogT) runDumpOne(stdout io.Writer, filename string) error {
 		if err == io.EOF {
 			break
 		} else if err != nil {
-			return err
+			return 0, nil, err
 		}
 
 		var ve remoteobjcat.VersionEdit
 		err = ve.Decode(r)
 		if err != nil {
-			return err
+			return 0, nil, err
 		}
+		if fn != nil {
+			fn(offset, editIdx, &ve)
+		}
+		editIdx++
+		if err := ve.Apply(&creatorID, objects); err != nil {
+			return 0, nil, err
+		}
+	}
+	return creatorID, objects, nil
+}
 
+func (m *remoteCatalogT) runDumpOne(stdout io.Writer, filename string) error {
+	fmt.Fprintf(stdout, "%s\n", filename)
+	creatorID, objects, err := m.readCatalogFile(filename, func(offset int64, editIdx int, ve *remoteobjcat.VersionEdit) {
 		if m.verbose {
 			fmt.Fprintf(stdout, "%d/%d\n", offset, editIdx)
 			if ve.CreatorID.IsSet() {
@@ -109,10 +167,9 @@ func (m *remoteCatalogT) runDumpOne(stdout io.Writer, filename string) error {
 				}
 			}
 		}
-		editIdx++
-		if err := ve.Apply(&creatorID, objects); err != nil {
-			return err
-		}
+	})
+	if err != nil {
+		return err
 	}
 	fmt.Fprintf(stdout, "CreatorID: %v\n", creatorID)
 	var filenums []base.DiskFileNum
@@ -130,3 +187,78 @@ func (m *remoteCatalogT) runDumpOne(stdout io.Writer, filename string) error {
 	}
 	return nil
 }
+
+func (m *remoteCatalogT) runGC(cmd *cobra.Command, args []string) {
+	stdout, stderr := cmd.OutOrStdout(), cmd.OutOrStderr()
+	if m.gc.creatorID == 0 {
+		fmt.Fprintf(stderr, "--creator-id must be specified\n")
+		return
+	}
+	if m.gc.storage == "" {
+		fmt.Fprintf(stderr, "--storage must be specified\n")
+		return
+	}
+	var storage remote.Storage
+	if strings.Contains(m.gc.storage, "://") {
+		if m.remoteStorageFn == nil {
+			fmt.Fprintf(stderr, "path looks like remote storage, but remote storage not configured.\n")
+			return
+		}
+		var err error
+		if storage, err = m.remoteStorageFn(m.gc.storage); err != nil {
+			fmt.Fprintf(stderr, "error initializing remote storage: %s\n", err)
+			return
+		}
+	} else {
+		storage = remote.NewLocalFS(m.gc.storage, m.opts.FS)
+	}
+	defer storage.Close()
+
+	opts := objstorageprovider.RemoteGCOptions{
+		CreatorID:   objstorage.CreatorID(m.gc.creatorID),
+		GracePeriod: m.gc.gracePeriod,
+		Delete:      m.gc.delete,
+		Now:         timeNow,
+	}
+	for _, arg := range args {
+		creatorID, objects, err := m.readCatalogFile(arg, nil /* fn */)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", arg, err)
+			return
+		}
+		if !creatorID.IsSet() {
+			fmt.Fprintf(stderr, "%s: %s\n", arg, errors.New("catalog has no creator ID"))
+			return
+		}
+		contents := remoteobjcat.CatalogContents{CreatorID: creatorID}
+		for _, o := range objects {
+			contents.Objects = append(contents.Objects, o)
+		}
+		slices.SortFunc(contents.Objects, func(a, b remoteobjcat.RemoteObjectMetadata) int {
+			return cmp.Compare(a.FileNum, b.FileNum)
+		})
+		opts.Catalogs = append(opts.Catalogs, contents)
+	}
+
+	res, err := objstorageprovider.FindRemoteGarbage(storage, opts)
+	printList := func(title string, names []string) {
+		if len(names) == 0 {
+			return
+		}
+		fmt.Fprintf(stdout, "%s:\n", title)
+		for _, n := range names {
+			fmt.Fprintf(stdout, "    %s\n", n)
+		}
+	}
+	printList("Orphaned objects", res.OrphanedObjects)
+	printList("Orphaned ref markers", res.OrphanedRefs)
+	printList("Deleted", res.Deleted)
+	printList("Within grace period", res.WithinGracePeriod)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s\n", err)
+		return
+	}
+	if len(res.OrphanedObjects)+len(res.OrphanedRefs) == 0 {
+		fmt.Fprintf(stdout, "no orphaned objects found\n")
+	}
+}
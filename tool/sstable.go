@@ -50,6 +50,7 @@ type sstableT struct {
 	start        key
 	end          key
 	filter       key
+	zoneMaps     zoneMapFilters
 	count        int64
 	verbose      bool
 	blobModeLoad string
@@ -73,9 +74,12 @@ func newSSTable(
 	s.Check = &cobra.Command{
 		Use:   "check <sstables>",
 		Short: "verify checksums and metadata",
-		Long:  ``,
-		Args:  cobra.MinimumNArgs(1),
-		Run:   s.runCheck,
+		Long: `
+Verify the checksums and metadata of the sstables. Signed sstables are also
+authenticated, using the signing keys configured for the tool.
+`,
+		Args: cobra.MinimumNArgs(1),
+		Run:  s.runCheck,
 	}
 	s.Layout = &cobra.Command{
 		Use:   "layout <sstables>",
@@ -104,6 +108,10 @@ properties are pretty-printed or displayed in a verbose/raw format.
 Print the records in the sstables. The sstables are scanned in command line
 order which means the records will be printed in that order. Raw range
 tombstones are displayed interleaved with point records.
+
+The --zone-map flag skips the data blocks (and sstables) whose zone maps (see
+sstable.NewZoneMapCollector) contain no values within [lower, upper). Records
+in the remaining blocks are printed even if their values are outside the range.
 `,
 		Args: cobra.MinimumNArgs(1),
 		Run:  s.runScan,
@@ -140,6 +148,9 @@ inclusive-inclusive range specified by --start and --end.
 	}
 	s.Scan.Flags().Var(
 		&s.filter, "filter", "only output records with matching prefix or overlapping range tombstones")
+	s.Scan.Flags().Var(
+		&s.zoneMaps, "zone-map", "skip blocks whose zone map excludes the range, specified as "+
+			"<name>:<type>:<lower>,<upper> (may be repeated)")
 	s.Scan.Flags().Int64Var(
 		&s.count, "count", 0, "key count for scan (0 is unlimited)")
 	s.Scan.Flags().StringVar(
@@ -181,6 +192,10 @@ func (s *sstableT) runCheck(cmd *cobra.Command, args []string) {
 		if err := r.ValidateBlockChecksums(); err != nil {
 			fmt.Fprintf(stdout, "  checksum validation failed: %s\n", err)
 		}
+		err := sstable.VerifySignature(context.Background(), r.BlockReader().Readable(), s.opts.SigningKeys)
+		if err != nil && !errors.Is(err, sstable.ErrUnsignedTable) {
+			fmt.Fprintf(stdout, "  signature verification failed: %s\n", err)
+		}
 
 		// Update the internal formatter if this comparator has one specified.
 		s.fmtKey.setForComparer(props.ComparerName, s.comparers)
@@ -377,7 +392,28 @@ func (s *sstableT) runScan(cmd *cobra.Command, args []string) {
 			}
 			blobContext = blobMappings.LoadValueBlobContext(base.PhysicalTableFileNum(r.BlockReader().FileNum()))
 		}
-		iter, err := r.NewIter(sstable.NoTransforms, nil, s.end, blobContext)
+		var filterer *sstable.BlockPropertiesFilterer
+		if len(s.zoneMaps.filters) > 0 {
+			var err error
+			filterer, err = sstable.IntersectsTable(s.zoneMaps.filters, nil, r.UserProperties, nil)
+			if err != nil {
+				fmt.Fprintf(stderr, "%s%s\n", prefix, err)
+				return
+			}
+			if filterer == nil {
+				fmt.Fprintf(stdout, "%s(excluded by zone maps)\n", prefix)
+				return
+			}
+		}
+		iter, err := r.NewPointIter(context.Background(), sstable.IterOptions{
+			Upper:                s.end,
+			Transforms:           sstable.NoTransforms,
+			Filterer:             filterer,
+			FilterBlockSizeLimit: sstable.AlwaysUseFilterBlock,
+			Env:                  sstable.NoReadEnv,
+			ReaderProvider:       sstable.MakeTrivialReaderProvider(r),
+			BlobContext:          blobContext,
+		})
 		if err != nil {
 			fmt.Fprintf(stderr, "%s%s\n", prefix, err)
 			return
@@ -107,6 +107,15 @@ func FilterDecoders(decoders ...pebble.TableFilterDecoder) Option {
 	}
 }
 
+// SigningKeys may be passed to New to configure the keys used to authenticate
+// signed sstables. The keys are also used when opening DBs (see
+// pebble.Options.SigningKeys).
+func SigningKeys(keys sstable.SigningKeyProvider) Option {
+	return func(t *T) {
+		t.opts.SigningKeys = keys
+	}
+}
+
 // OpenOptions may be passed to New to provide a set of OpenOptions that should
 // be invoked to configure the *pebble.Options before opening a database.
 func OpenOptions(openOptions ...OpenOption) Option {
@@ -184,9 +193,9 @@ func New(opts ...Option) *T {
 	t.find = newFind(&t.opts, t.comparers, t.defaultComparer, t.mergers)
 	t.lsm = newLSM(&t.opts, t.comparers)
 	t.manifest = newManifest(&t.opts, t.comparers)
-	t.remotecat = newRemoteCatalog(&t.opts)
+	t.remotecat = newRemoteCatalog(&t.opts, t.remoteStorageFn)
 	t.sstable = newSSTable(&t.opts, t.comparers, t.mergers)
-	t.wal = newWAL(&t.opts, t.comparers, t.defaultComparer)
+	t.wal = newWAL(&t.opts, t.comparers, t.defaultComparer, t.remoteStorageFn)
 	t.blob = newBlob(&t.opts)
 	t.Commands = []*cobra.Command{
 		t.db.Root,
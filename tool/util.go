@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -59,6 +60,73 @@ func (k *key) Set(v string) error {
 	return nil
 }
 
+// zoneMapFilters accumulates sstable zone map filters (see
+// sstable.ZoneMapFilter), each specified as <name>:<type>:<lower>,<upper>. The
+// bounds of a bytes zone map are parsed as keys; an empty bound is unbounded.
+type zoneMapFilters struct {
+	specs   []string
+	filters []sstable.BlockPropertyFilter
+}
+
+func (z *zoneMapFilters) String() string {
+	return strings.Join(z.specs, " ")
+}
+
+func (z *zoneMapFilters) Type() string {
+	return "zoneMapFilter"
+}
+
+func (z *zoneMapFilters) Set(v string) error {
+	parts := strings.SplitN(v, ":", 3)
+	if len(parts) != 3 {
+		return errors.Errorf("invalid zone map filter %q; expected <name>:<type>:<lower>,<upper>", v)
+	}
+	typ, err := sstable.ParseZoneMapFieldType(parts[1])
+	if err != nil {
+		return err
+	}
+	lowerStr, upperStr, ok := strings.Cut(parts[2], ",")
+	if !ok {
+		return errors.Errorf("invalid zone map filter bounds %q; expected <lower>,<upper>", parts[2])
+	}
+	parseBound := func(s string) ([]byte, error) {
+		if s == "" {
+			return nil, nil
+		}
+		switch typ {
+		case sstable.ZoneMapUint64:
+			n, err := strconv.ParseUint(s, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			return sstable.EncodeZoneMapUint64(n), nil
+		case sstable.ZoneMapInt64:
+			n, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			return sstable.EncodeZoneMapInt64(n), nil
+		default:
+			var k key
+			if err := k.Set(s); err != nil {
+				return nil, err
+			}
+			return k, nil
+		}
+	}
+	lower, err := parseBound(lowerStr)
+	if err != nil {
+		return err
+	}
+	upper, err := parseBound(upperStr)
+	if err != nil {
+		return err
+	}
+	z.specs = append(z.specs, v)
+	z.filters = append(z.filters, sstable.NewZoneMapFilter(parts[0], typ, lower, upper))
+	return nil
+}
+
 type keyFormatter struct {
 	spec      string
 	fn        base.FormatKey
@@ -6,16 +6,20 @@ package tool
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"fmt"
 	"io"
 	"os"
 	"path"
+	"slices"
+	"strings"
 
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/pebble"
 	"github.com/cockroachdb/pebble/batchrepr"
 	"github.com/cockroachdb/pebble/internal/base"
+	"github.com/cockroachdb/pebble/objstorage/remote"
 	"github.com/cockroachdb/pebble/rangekey"
 	"github.com/cockroachdb/pebble/record"
 	"github.com/cockroachdb/pebble/sstable"
@@ -37,11 +41,18 @@ type walT struct {
 	defaultComparer string
 	comparers       sstable.Comparers
 	verbose         bool
+	remoteStorageFn DBRemoteStorageFn
 }
 
-func newWAL(opts *pebble.Options, comparers sstable.Comparers, defaultComparer string) *walT {
+func newWAL(
+	opts *pebble.Options,
+	comparers sstable.Comparers,
+	defaultComparer string,
+	remoteStorageFn DBRemoteStorageFn,
+) *walT {
 	w := &walT{
-		opts: opts,
+		opts:            opts,
+		remoteStorageFn: remoteStorageFn,
 	}
 	w.fmtKey.mustSet("quoted")
 	w.fmtValue.mustSet("size")
@@ -53,20 +64,26 @@ func newWAL(opts *pebble.Options, comparers sstable.Comparers, defaultComparer s
 		Short: "WAL introspection tools",
 	}
 	w.Dump = &cobra.Command{
-		Use:   "dump <wal-files>",
+		Use:   "dump <wal-files-or-dirs>",
 		Short: "print WAL contents",
 		Long: `
-Print the contents of the WAL files.
+Print the contents of the WAL files. If an argument is a directory,
+such as a WAL archive, all the WAL files within it are printed. If
+an argument is a remote storage URI (e.g. gs://bucket/archive), all
+the WAL files archived there are printed.
 `,
 		Args: cobra.MinimumNArgs(1),
 		Run:  w.runDump,
 	}
 	w.DumpMerged = &cobra.Command{
-		Use:   "dump-merged <wal-files>",
+		Use:   "dump-merged <wal-files-or-dirs>",
 		Short: "print WAL contents",
 		Long: `
 Print the merged contents of multiple WAL segment files that
-together form a single logical WAL.
+together form a single logical WAL. If an argument is a directory,
+all the WAL segment files within it are used, so that the segments
+written to the primary and secondary directories when using WAL
+failover can be merged by passing all the directories.
 `,
 		Args: cobra.MinimumNArgs(1),
 		Run:  w.runDumpMerged,
@@ -103,64 +120,27 @@ func (w *walT) runDump(cmd *cobra.Command, args []string) {
 	}
 
 	for _, arg := range args {
-		func() {
-			// Parse the filename in order to extract the file number. This is
-			// necessary in case WAL recycling was used (which it is usually is). If
-			// we can't parse the filename or it isn't a log file, we'll plow ahead
-			// anyways (which will likely fail when we try to read the file).
-			fileName := path.Base(arg)
-			fileNum, _, ok := wal.ParseLogFilename(fileName)
-			if !ok {
-				fileNum = 0
-			}
-
-			f, err := w.opts.FS.Open(arg)
+		if strings.Contains(arg, "://") {
+			w.dumpRemoteArchive(stdout, stderr, arg, logErr)
+			continue
+		}
+		if stat, err := w.opts.FS.Stat(arg); err == nil && stat.IsDir() {
+			// A directory, such as a WAL archive (see wal.Archiver), is dumped
+			// by dumping each of the log files within it.
+			ls, err := w.opts.FS.List(arg)
 			if err != nil {
 				fmt.Fprintf(stderr, "%s\n", err)
-				return
+				continue
 			}
-			defer f.Close()
-
-			fmt.Fprintf(stdout, "%s\n", arg)
-
-			var b pebble.Batch
-			var buf bytes.Buffer
-			rr := record.NewReader(f, base.DiskFileNum(fileNum))
-			for {
-				offset := rr.Offset()
-				r, err := rr.Next()
-				if err == nil {
-					buf.Reset()
-					_, err = io.Copy(&buf, r)
-				}
-				if err != nil {
-					// It is common to encounter a zeroed or invalid chunk due to WAL
-					// preallocation and WAL recycling. We need to distinguish these
-					// errors from EOF in order to recognize that the record was
-					// truncated, but want to otherwise treat them like EOF.
-					switch {
-					case errors.Is(err, record.ErrZeroedChunk):
-						fmt.Fprintf(stdout, "EOF [%s] (may be due to WAL preallocation)\n", err)
-					case errors.Is(err, record.ErrInvalidChunk):
-						fmt.Fprintf(stdout, "EOF [%s] (may be due to WAL recycling)\n", err)
-					default:
-						fmt.Fprintf(stdout, "%s\n", err)
-					}
-					return
+			slices.Sort(ls)
+			for _, name := range ls {
+				if _, _, ok := wal.ParseLogFilename(name); ok {
+					w.dumpFile(stdout, stderr, w.opts.FS.PathJoin(arg, name), logErr)
 				}
-
-				b = pebble.Batch{}
-				if err := b.SetRepr(buf.Bytes()); err != nil {
-					fmt.Fprintf(stdout, "corrupt batch within log file %q: %v", arg, err)
-					return
-				}
-				fmt.Fprintf(stdout, "%d(%d) seq=%d count=%d, len=%d\n",
-					offset, len(b.Repr()), b.SeqNum(), b.Count(), buf.Len())
-				w.dumpBatch(stdout, &b, b.Reader(), func(err error) {
-					logErr(arg, offset, err)
-				})
 			}
-		}()
+			continue
+		}
+		w.dumpFile(stdout, stderr, arg, logErr)
 	}
 	if len(errs) > 0 {
 		fmt.Fprintln(stderr, "Errors: ")
@@ -170,12 +150,145 @@ func (w *walT) runDump(cmd *cobra.Command, args []string) {
 	}
 }
 
+// dumpFile prints the contents of the WAL file at path.
+func (w *walT) dumpFile(
+	stdout, stderr io.Writer, path string, logErr func(arg string, offset int64, err error),
+) {
+	f, err := w.opts.FS.Open(path)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s\n", err)
+		return
+	}
+	defer f.Close()
+	w.dumpLog(stdout, path, f, logErr)
+}
+
+// dumpRemoteArchive prints the contents of the WAL files archived to the
+// remote storage identified by uri (see wal.ArchiverOptions.Storage).
+func (w *walT) dumpRemoteArchive(
+	stdout, stderr io.Writer, uri string, logErr func(arg string, offset int64, err error),
+) {
+	if w.remoteStorageFn == nil {
+		fmt.Fprintf(stderr, "path looks like remote storage, but remote storage not configured.\n")
+		return
+	}
+	storage, err := w.remoteStorageFn(uri)
+	if err != nil {
+		fmt.Fprintf(stderr, "error initializing remote storage: %s\n", err)
+		return
+	}
+	defer storage.Close()
+	names, err := storage.List("", "")
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %s\n", uri, err)
+		return
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if _, _, ok := wal.ParseLogFilename(path.Base(name)); !ok {
+			continue
+		}
+		buf, err := readRemoteObject(storage, name)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", name, err)
+			continue
+		}
+		w.dumpLog(stdout, name, bytes.NewReader(buf), logErr)
+	}
+}
+
+// readRemoteObject returns the contents of the named object.
+func readRemoteObject(storage remote.Storage, name string) ([]byte, error) {
+	ctx := context.Background()
+	r, size, err := storage.ReadObject(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	buf := make([]byte, size)
+	if err := r.ReadAt(ctx, buf, 0); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// dumpLog prints the contents of the WAL read from r, which is named arg.
+func (w *walT) dumpLog(
+	stdout io.Writer, arg string, f io.Reader, logErr func(arg string, offset int64, err error),
+) {
+	// Parse the filename in order to extract the file number. This is
+	// necessary in case WAL recycling was used (which it is usually is). If
+	// we can't parse the filename or it isn't a log file, we'll plow ahead
+	// anyways (which will likely fail when we try to read the file).
+	fileName := path.Base(arg)
+	fileNum, _, ok := wal.ParseLogFilename(fileName)
+	if !ok {
+		fileNum = 0
+	}
+
+	fmt.Fprintf(stdout, "%s\n", arg)
+
+	var b pebble.Batch
+	var buf bytes.Buffer
+	rr := record.NewReader(f, base.DiskFileNum(fileNum))
+	for {
+		offset := rr.Offset()
+		r, err := rr.Next()
+		if err == nil {
+			buf.Reset()
+			_, err = io.Copy(&buf, r)
+		}
+		if err != nil {
+			// It is common to encounter a zeroed or invalid chunk due to WAL
+			// preallocation and WAL recycling. We need to distinguish these
+			// errors from EOF in order to recognize that the record was
+			// truncated, but want to otherwise treat them like EOF.
+			switch {
+			case errors.Is(err, record.ErrZeroedChunk):
+				fmt.Fprintf(stdout, "EOF [%s] (may be due to WAL preallocation)\n", err)
+			case errors.Is(err, record.ErrInvalidChunk):
+				fmt.Fprintf(stdout, "EOF [%s] (may be due to WAL recycling)\n", err)
+			default:
+				fmt.Fprintf(stdout, "%s\n", err)
+			}
+			return
+		}
+
+		b = pebble.Batch{}
+		if err := b.SetRepr(buf.Bytes()); err != nil {
+			fmt.Fprintf(stdout, "corrupt batch within log file %q: %v", arg, err)
+			return
+		}
+		fmt.Fprintf(stdout, "%d(%d) seq=%d count=%d, len=%d\n",
+			offset, len(b.Repr()), b.SeqNum(), b.Count(), buf.Len())
+		w.dumpBatch(stdout, &b, b.Reader(), func(err error) {
+			logErr(arg, offset, err)
+		})
+	}
+}
+
 func (w *walT) runDumpMerged(cmd *cobra.Command, args []string) {
 	stdout, stderr := cmd.OutOrStdout(), cmd.OutOrStderr()
 	w.fmtKey.setForComparer(w.defaultComparer, w.comparers)
 	w.fmtValue.setForComparer(w.defaultComparer, w.comparers)
 	var a wal.FileAccumulator
 	for _, arg := range args {
+		if stat, err := w.opts.FS.Stat(arg); err == nil && stat.IsDir() {
+			ls, err := w.opts.FS.List(arg)
+			if err != nil {
+				fmt.Fprintf(stderr, "%s: %s\n", arg, err)
+				os.Exit(1)
+			}
+			slices.Sort(ls)
+			for _, name := range ls {
+				filename := w.opts.FS.PathJoin(arg, name)
+				if _, err := a.MaybeAccumulate(w.opts.FS, filename); err != nil {
+					fmt.Fprintf(stderr, "%s: %s\n", filename, err)
+					os.Exit(1)
+				}
+			}
+			continue
+		}
 		isLog, err := a.MaybeAccumulate(w.opts.FS, arg)
 		if !isLog {
 			fmt.Fprintf(stderr, "%q does not parse as a log file\n", arg)
@@ -74,6 +74,8 @@ func NewSSTBlobWriter(sstHandle objstorage.Writable, opts SSTBlobWriterOptions)
 	if opts.SpanPolicy.PreferFastCompression && opts.SSTWriterOpts.Compression != block.NoCompression {
 		opts.SSTWriterOpts.Compression = block.FastestCompression
 	}
+	opts.SSTWriterOpts.FilterPolicy = opts.SpanPolicy.AdjustTableFilterPolicy(
+		opts.SSTWriterOpts.FilterPolicy, opts.SSTWriterOpts.WritingToLowestLevel)
 
 	writer.SSTWriter = sstable.NewWriter(sstHandle, opts.SSTWriterOpts)
 
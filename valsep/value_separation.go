@@ -32,6 +32,15 @@ type ValueSeparationOutputConfig struct {
 	// determine whether a value is likely MVCC garbage. If 0, all MVCC garbage
 	// values are separated.
 	MinimumMVCCGarbageSize int
+	// TieringPolicy is the tiering policy that applies to the keys of the
+	// output. If it is set (and the ValueSeparator was configured with
+	// WriteNewBlobFilesOptions.NewColdBlobObject), separated values with a
+	// tiering attribute below ColdThreshold are written to a cold-tier blob
+	// file.
+	TieringPolicy base.TieringPolicyAndExtractor
+	// ColdThreshold is the tiering attribute below which values belong in the
+	// cold tier. Only used when TieringPolicy is set.
+	ColdThreshold base.TieringAttribute
 }
 
 // ValueSeparation defines an interface for writing some values to separate blob
@@ -37,8 +37,9 @@ const (
 // new or preserve blob references when writing an sstable. FinishOutput
 // should be called when the output sstable is complete. The ValueSeparator
 // can then be reused for the next output sstable.
-// This will be extended in the future to support the writing of hot and cold
-// blob files. All blob references are currently written to the hot tier.
+// When writing new blob files with a tiering policy in effect (see
+// ValueSeparationOutputConfig.TieringPolicy), values that belong in the cold
+// tier are written to a separate cold-tier blob file.
 type ValueSeparator struct {
 	mode valueSeparationMode
 	// inputBlobPhysicalFiles holds the *PhysicalBlobFile for every unique blob
@@ -47,7 +48,10 @@ type ValueSeparator struct {
 	outputBlobReferenceDepth manifest.BlobReferenceDepth
 	comparer                 *base.Comparer
 	// newBlobObject constructs a new blob object for use in the compaction.
-	newBlobObject      func() (objstorage.Writable, objstorage.ObjectMetadata, error)
+	newBlobObject func() (objstorage.Writable, objstorage.ObjectMetadata, error)
+	// newColdBlobObject constructs a new cold-tier blob object. If nil, all
+	// values are written to the hot tier.
+	newColdBlobObject  func() (objstorage.Writable, objstorage.ObjectMetadata, error)
 	shortAttrExtractor base.ShortAttributeExtractor
 	// writerOpts is used to configure all constructed blob writers.
 	writerOpts blob.FileWriterOptions
@@ -131,6 +135,9 @@ type WriteNewBlobFilesOptions struct {
 	ShortAttrExtractor             base.ShortAttributeExtractor
 	InvalidValueCallback           func(userKey []byte, value []byte, err error)
 	DisableValueSeparationBySuffix bool
+	// NewColdBlobObject, if set, constructs blob objects on the cold storage
+	// tier. Values are only routed to the cold tier if it is set.
+	NewColdBlobObject func() (objstorage.Writable, objstorage.ObjectMetadata, error)
 }
 
 func NewWriteNewBlobFiles(
@@ -156,6 +163,7 @@ func NewWriteNewBlobFiles(
 		outputBlobReferenceDepth: 1,
 		comparer:                 comparer,
 		newBlobObject:            newBlobObject,
+		newColdBlobObject:        opts.NewColdBlobObject,
 		shortAttrExtractor:       opts.ShortAttrExtractor,
 		writerOpts:               writerOpts,
 		globalConfig:             config,
@@ -303,7 +311,9 @@ func (vs *ValueSeparator) separateValue(
 		}
 	}
 
-	wnm, err := vs.getWriter(base.HotTier)
+	var tier base.StorageTier
+	tier, meta = vs.valueTier(kv.K.UserKey, rawValue, meta)
+	wnm, err := vs.getWriter(tier)
 	if err != nil {
 		return err
 	}
@@ -325,6 +335,33 @@ func (vs *ValueSeparator) separateValue(
 	return tw.AddWithBlobHandle(kv.K, inlineHandle, shortAttr, forceObsolete, meta)
 }
 
+// valueTier returns the storage tier that a separated value belongs to,
+// according to the current output's tiering policy. The tiering attribute is
+// extracted if the KV's metadata does not contain it already; the returned
+// metadata contains the attribute.
+func (vs *ValueSeparator) valueTier(
+	userKey []byte, rawValue []byte, meta base.KVMeta,
+) (base.StorageTier, base.KVMeta) {
+	policy := vs.currentConfig.TieringPolicy
+	if vs.newColdBlobObject == nil || !policy.IsSet() {
+		return base.HotTier, meta
+	}
+	if !meta.IsSet() && policy.ExtractAttribute != nil {
+		// An extraction error is represented by a zero attribute with a non-zero
+		// span ID; such values stay in the hot tier.
+		attr, err := policy.ExtractAttribute(userKey, rawValue)
+		if err != nil {
+			attr = 0
+		}
+		meta = base.KVMeta{TieringSpanID: policy.SpanID, TieringAttribute: attr}
+	}
+	if meta.TieringSpanID == policy.SpanID && meta.IsSet() &&
+		meta.TieringAttribute < vs.currentConfig.ColdThreshold {
+		return base.ColdTier, meta
+	}
+	return base.HotTier, meta
+}
+
 // preserveBlobReference preserves an existing blob reference by copying it
 // into the output sstable. The provided kv must have a value that is an
 // existing blob handle.
@@ -416,7 +453,11 @@ func (vs *ValueSeparator) getWriter(blobTier base.StorageTier) (*blobWriterAndMe
 	if wnm.fileWriter != nil {
 		return wnm, nil
 	}
-	writable, objMeta, err := vs.newBlobObject()
+	newBlobObject := vs.newBlobObject
+	if blobTier == base.ColdTier {
+		newBlobObject = vs.newColdBlobObject
+	}
+	writable, objMeta, err := newBlobObject()
 	if err != nil {
 		return nil, err
 	}
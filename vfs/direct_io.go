@@ -0,0 +1,30 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package vfs
+
+import (
+	"unsafe"
+
+	"github.com/cockroachdb/errors"
+)
+
+// DirectIOAlignment is the alignment of the file offsets, lengths and memory
+// buffers of reads and writes to a file in direct I/O mode (see SetDirectIO).
+const DirectIOAlignment = 4096
+
+// ErrDirectIOUnsupported is returned by SetDirectIO when the file does not
+// support direct I/O.
+var ErrDirectIOUnsupported = errors.New("vfs: direct I/O is not supported")
+
+// AlignedBuffer returns a buffer of the given size whose address is a
+// multiple of DirectIOAlignment.
+func AlignedBuffer(size int) []byte {
+	buf := make([]byte, size+DirectIOAlignment)
+	off := 0
+	if rem := int(uintptr(unsafe.Pointer(&buf[0])) & (DirectIOAlignment - 1)); rem != 0 {
+		off = DirectIOAlignment - rem
+	}
+	return buf[off : off+size : off+size]
+}
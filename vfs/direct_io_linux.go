@@ -0,0 +1,76 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+//go:build linux
+
+package vfs
+
+import (
+	"unsafe"
+
+	"github.com/cockroachdb/errors"
+	"golang.org/x/sys/unix"
+)
+
+// SetDirectIO enables or disables direct I/O (O_DIRECT) for the file. In
+// direct I/O mode, reads and writes bypass the OS page cache and must use
+// offsets, lengths and buffers aligned to DirectIOAlignment. It returns
+// ErrDirectIOUnsupported if the file is not backed by a file descriptor or its
+// file system (e.g. tmpfs) does not support direct I/O.
+func SetDirectIO(f File, enabled bool) error {
+	fd := f.Fd()
+	if fd == InvalidFd {
+		return ErrDirectIOUnsupported
+	}
+	flags, err := unix.FcntlInt(fd, unix.F_GETFL, 0)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if enabled {
+		flags |= unix.O_DIRECT
+	} else {
+		flags &^= unix.O_DIRECT
+	}
+	if _, err := unix.FcntlInt(fd, unix.F_SETFL, flags); err != nil {
+		if err == unix.EINVAL {
+			return ErrDirectIOUnsupported
+		}
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// PageCacheResidency returns the number of bytes of the file that are
+// resident in the OS page cache, along with the size of the file.
+func PageCacheResidency(f File) (resident, size int64, err error) {
+	fd := f.Fd()
+	if fd == InvalidFd {
+		return 0, 0, ErrDirectIOUnsupported
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return 0, 0, err
+	}
+	size = info.Size()
+	if size == 0 {
+		return 0, 0, nil
+	}
+	data, err := unix.Mmap(int(fd), 0, int(size), unix.PROT_READ, unix.MAP_SHARED)
+	if err != nil {
+		return 0, 0, errors.WithStack(err)
+	}
+	defer func() { _ = unix.Munmap(data) }()
+	pageSize := int64(unix.Getpagesize())
+	vec := make([]byte, (size+pageSize-1)/pageSize)
+	if _, _, errno := unix.Syscall(unix.SYS_MINCORE, uintptr(unsafe.Pointer(&data[0])),
+		uintptr(len(data)), uintptr(unsafe.Pointer(&vec[0]))); errno != 0 {
+		return 0, 0, errors.WithStack(errno)
+	}
+	for i, v := range vec {
+		if v&1 != 0 {
+			resident += min(pageSize, size-int64(i)*pageSize)
+		}
+	}
+	return resident, size, nil
+}
@@ -0,0 +1,19 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+//go:build !linux
+
+package vfs
+
+// SetDirectIO returns ErrDirectIOUnsupported; direct I/O is only supported on
+// Linux.
+func SetDirectIO(f File, enabled bool) error {
+	return ErrDirectIOUnsupported
+}
+
+// PageCacheResidency returns ErrDirectIOUnsupported; it is only supported on
+// Linux.
+func PageCacheResidency(f File) (resident, size int64, err error) {
+	return 0, 0, ErrDirectIOUnsupported
+}
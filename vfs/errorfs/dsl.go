@@ -36,6 +36,13 @@ func PathMatch(pattern string) Predicate {
 	return &pathMatch{pattern: pattern}
 }
 
+// BaseMatch returns a predicate that returns true if the last element of an
+// operation's file path matches the provided pattern according to
+// filepath.Match. Unlike PathMatch, it matches files in any directory.
+func BaseMatch(pattern string) Predicate {
+	return &baseMatch{pattern: pattern}
+}
+
 // CallStackIncludes returns a Predicate that evaluates to true if the call
 // stack includes a function whose fully-qualified name contains the provided
 // substring.
@@ -64,6 +71,24 @@ func (pm *pathMatch) Evaluate(op Op) bool {
 	return matched
 }
 
+type baseMatch struct {
+	pattern string
+}
+
+func (bm *baseMatch) String() string {
+	return fmt.Sprintf("(BaseMatch %q)", bm.pattern)
+}
+
+func (bm *baseMatch) Evaluate(op Op) bool {
+	matched, err := filepath.Match(bm.pattern, filepath.Base(op.Path))
+	if err != nil {
+		// Only possible error is ErrBadPattern, indicating an issue with
+		// the test itself.
+		panic(err)
+	}
+	return matched
+}
+
 var (
 	// Reads is a predicate that returns true iff an operation is a read
 	// operation.
@@ -162,6 +187,8 @@ var defaultParser = NewParser()
 //     a write operation (eg, Create, Rename, Write, WriteAt, etc).
 //   - (PathMatch <STRING>) is a predicate that evalutes to true iff the
 //     operation's file path matches the provided shell pattern.
+//   - (BaseMatch <STRING>) is a predicate that evalutes to true iff the last
+//     element of the operation's file path matches the provided shell pattern.
 //   - (OnIndex <INTEGER>) is a predicate that evaluates to true only on the n-th
 //     invocation.
 //   - (And <PREDICATE> [PREDICATE]...) is a predicate that evaluates to true
@@ -176,12 +203,36 @@ var defaultParser = NewParser()
 //     to true. The probability of evaluating to true is determined by the
 //     required float argument (must be ≤1). The optional second parameter is a
 //     pseudorandom seed, for adjusting the deterministic randomness.
+//   - <OPKIND> (eg, OpFileSync, OpCreate) is a constant predicate that
+//     evaluates to true iff the operation is of the named kind (see OpKind).
 //   - Operation-specific:
 //     (OpFileReadAt <INTEGER>) is a predicate that evaluates to true iff
 //     an operation is a file ReadAt call with an offset that's exactly equal.
 //
+// Latency injectors (durations are quoted strings parsed by
+// time.ParseDuration; the predicate is optional in all of them):
+//   - (Latency <DURATION> [PREDICATE]) injects a fixed latency.
+//   - (UniformLatency <DURATION> <DURATION> <INTEGER> [PREDICATE]) injects a
+//     latency uniformly distributed between the two durations, using the
+//     integer as a pseudorandom seed.
+//   - (RandomLatency <DURATION> <INTEGER> [PREDICATE]) injects an
+//     exponentially distributed latency with the given mean, using the integer
+//     as a pseudorandom seed.
+//   - (Stall <DURATION> <DURATION> [PREDICATE]) blocks operations performed
+//     during a window that starts after the first duration (measured from when
+//     the DSL is parsed) and lasts for the second duration, until the end of
+//     the window.
+//   - (Hang <STRING> [PREDICATE]) blocks operations until released; the Hang
+//     may be retrieved by name through FindHang.
+//   - (Any <INJECTOR> [INJECTOR]...) combines injectors; see Any.
+//
 // Example: (ErrInjected (And (PathMatch "*.sst") (OnIndex 5))) is a rule set
 // that will inject an error on the 5-th I/O operation involving an sstable.
+//
+// Example: (Any (Stall "10s" "5s" (PathMatch "/mnt/wal/*")) (Latency "1ms"
+// (PathMatch "*.sst"))) stalls all operations on files within /mnt/wal for 5
+// seconds starting 10 seconds after parsing, and slows down all sstable
+// operations.
 func NewParser() *Parser {
 	p := &Parser{
 		predicates: dsl.NewPredicateParser[Op](),
@@ -189,13 +240,23 @@ func NewParser() *Parser {
 	}
 	p.predicates.DefineConstant("Reads", func() dsl.Predicate[Op] { return Reads })
 	p.predicates.DefineConstant("Writes", func() dsl.Predicate[Op] { return Writes })
-	p.predicates.DefineConstant("OpFileWrite", func() dsl.Predicate[Op] { return OpKindIn("OpFileWrite", MakeOpKinds(OpFileWrite)) })
+	for kind := OpKind(0); kind < numOpKinds; kind++ {
+		p.predicates.DefineConstant(kind.String(), func() dsl.Predicate[Op] {
+			return OpKindIn(kind.String(), MakeOpKinds(kind))
+		})
+	}
 	p.predicates.DefineFunc("PathMatch",
 		func(p *dsl.Parser[dsl.Predicate[Op]], s *dsl.Scanner) dsl.Predicate[Op] {
 			pattern := s.ConsumeString()
 			s.Consume(token.RPAREN)
 			return PathMatch(pattern)
 		})
+	p.predicates.DefineFunc("BaseMatch",
+		func(p *dsl.Parser[dsl.Predicate[Op]], s *dsl.Scanner) dsl.Predicate[Op] {
+			pattern := s.ConsumeString()
+			s.Consume(token.RPAREN)
+			return BaseMatch(pattern)
+		})
 	p.predicates.DefineFunc("OpFileReadAt",
 		func(p *dsl.Parser[dsl.Predicate[Op]], s *dsl.Scanner) dsl.Predicate[Op] {
 			return parseFileReadAtOp(s)
@@ -209,6 +270,33 @@ func NewParser() *Parser {
 		func(_ *dsl.Parser[Injector], s *dsl.Scanner) Injector {
 			return parseRandomLatency(p, s)
 		})
+	p.injectors.DefineFunc("Latency",
+		func(_ *dsl.Parser[Injector], s *dsl.Scanner) Injector {
+			return parseLatency(p, s)
+		})
+	p.injectors.DefineFunc("UniformLatency",
+		func(_ *dsl.Parser[Injector], s *dsl.Scanner) Injector {
+			return parseUniformLatency(p, s)
+		})
+	p.injectors.DefineFunc("Stall",
+		func(_ *dsl.Parser[Injector], s *dsl.Scanner) Injector {
+			return parseStall(p, s)
+		})
+	p.injectors.DefineFunc("Hang",
+		func(_ *dsl.Parser[Injector], s *dsl.Scanner) Injector {
+			return parseHang(p, s)
+		})
+	p.injectors.DefineFunc("Any",
+		func(ip *dsl.Parser[Injector], s *dsl.Scanner) Injector {
+			var injs []Injector
+			for tok := s.Scan(); tok.Kind != token.RPAREN; tok = s.Scan() {
+				injs = append(injs, ip.ParseFromPos(s, tok))
+			}
+			if len(injs) == 0 {
+				panic(errors.Newf("errorfs: Any requires at least one injector"))
+			}
+			return Any(injs...)
+		})
 	return p
 }
 
@@ -224,6 +312,21 @@ func (p *Parser) Parse(s string) (Injector, error) {
 	return p.injectors.Parse(s)
 }
 
+// parseOptionalPredicate parses an optional predicate followed by the closing
+// parenthesis of a func. It returns nil if there is no predicate.
+func (p *Parser) parseOptionalPredicate(s *dsl.Scanner) Predicate {
+	var pred Predicate
+	tok := s.Scan()
+	if tok.Kind == token.LPAREN || tok.Kind == token.IDENT {
+		pred = p.predicates.ParseFromPos(s, tok)
+		tok = s.Scan()
+	}
+	if tok.Kind != token.RPAREN {
+		panic(errors.Errorf("errorfs: unexpected token %s; expected %s", tok.String(), token.RPAREN))
+	}
+	return pred
+}
+
 // AddError defines a new error that may be used within the DSL parsed by
 // Parse and will inject the provided error.
 func (p *Parser) AddError(le LabelledError) {
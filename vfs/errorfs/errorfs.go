@@ -90,6 +90,41 @@ const (
 	numOpKinds
 )
 
+var opKindNames = [numOpKinds]string{
+	OpCreate:          "OpCreate",
+	OpLink:            "OpLink",
+	OpOpen:            "OpOpen",
+	OpOpenDir:         "OpOpenDir",
+	OpRemove:          "OpRemove",
+	OpRemoveAll:       "OpRemoveAll",
+	OpRename:          "OpRename",
+	OpReuseForWrite:   "OpReuseForWrite",
+	OpMkdirAll:        "OpMkdirAll",
+	OpLock:            "OpLock",
+	OpList:            "OpList",
+	OpFilePreallocate: "OpFilePreallocate",
+	OpStat:            "OpStat",
+	OpGetDiskUsage:    "OpGetDiskUsage",
+	OpFileClose:       "OpFileClose",
+	OpFileRead:        "OpFileRead",
+	OpFileReadAt:      "OpFileReadAt",
+	OpFileWrite:       "OpFileWrite",
+	OpFileWriteAt:     "OpFileWriteAt",
+	OpFileStat:        "OpFileStat",
+	OpFileSync:        "OpFileSync",
+	OpFileSyncData:    "OpFileSyncData",
+	OpFileSyncTo:      "OpFileSyncTo",
+	OpFileFlush:       "OpFileFlush",
+}
+
+// String implements fmt.Stringer.
+func (o OpKind) String() string {
+	if o < 0 || o >= numOpKinds {
+		return fmt.Sprintf("OpKind(%d)", int(o))
+	}
+	return opKindNames[o]
+}
+
 func (o OpKind) IsRead() bool {
 	if o < 0 || o >= numOpKinds {
 		panic(errors.AssertionFailedf("invalid op kind: %d", o))
@@ -8,9 +8,12 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/crlib/crstrings"
 	"github.com/cockroachdb/datadriven"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/stretchr/testify/require"
 )
 
 func TestErrorFS(t *testing.T) {
@@ -33,3 +36,58 @@ func TestErrorFS(t *testing.T) {
 		}
 	})
 }
+
+func TestStallAndHang(t *testing.T) {
+	inj, err := ParseDSL(`(Any (Stall "0s" "200ms" (PathMatch "primary/*")) (Hang "sync" (And OpFileSync (BaseMatch "*.log"))))`)
+	require.NoError(t, err)
+	h := FindHang(inj, "sync")
+	require.NotNil(t, h)
+	require.Nil(t, FindHang(inj, "other"))
+
+	fs := Wrap(vfs.NewMem(), inj)
+	timed := func(fn func()) time.Duration {
+		start := time.Now()
+		fn()
+		return time.Since(start)
+	}
+	create := func(name string) vfs.File {
+		f, err := fs.Create(name, vfs.WriteCategoryUnspecified)
+		require.NoError(t, err)
+		return f
+	}
+	require.NoError(t, fs.MkdirAll("primary", 0755))
+	require.NoError(t, fs.MkdirAll("secondary", 0755))
+
+	// Operations on the primary directory stall until the end of the window;
+	// other operations proceed.
+	var secondary vfs.File
+	require.Less(t, timed(func() { secondary = create("secondary/000001.log") }), 100*time.Millisecond)
+	var primary vfs.File
+	require.GreaterOrEqual(t, timed(func() { primary = create("primary/000001.log") }), 100*time.Millisecond)
+	require.Less(t, timed(func() { _, err = primary.Write([]byte("foo")) }), 100*time.Millisecond)
+	require.NoError(t, err)
+
+	// Syncs of log files hang until released.
+	done := make(chan error)
+	go func() { done <- secondary.Sync() }()
+	require.Eventually(t, func() bool { return h.Blocked() == 1 }, 10*time.Second, time.Millisecond)
+	select {
+	case <-done:
+		t.Fatal("sync was not blocked")
+	case <-time.After(10 * time.Millisecond):
+	}
+	h.Release()
+	require.NoError(t, <-done)
+	require.Zero(t, h.Blocked())
+	require.NoError(t, primary.Sync())
+
+	// Once rearmed, the Hang blocks again.
+	h.Rearm()
+	go func() { done <- primary.Sync() }()
+	require.Eventually(t, func() bool { return h.Blocked() == 1 }, 10*time.Second, time.Millisecond)
+	ReleaseHangs(inj)
+	require.NoError(t, <-done)
+
+	require.NoError(t, primary.Close())
+	require.NoError(t, secondary.Close())
+}
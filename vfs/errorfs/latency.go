@@ -34,25 +34,106 @@ func RandomLatency(pred Predicate, mean time.Duration, seed int64, limit time.Du
 }
 
 func parseRandomLatency(p *Parser, s *dsl.Scanner) Injector {
-	dur, err := time.ParseDuration(s.ConsumeString())
-	if err != nil {
-		panic(errors.Newf("parsing RandomLatency: %s", err))
-	}
+	dur := parseDuration(s, "RandomLatency")
 	lit := s.Consume(token.INT).Lit
 	seed, err := strconv.ParseInt(lit, 10, 64)
 	if err != nil {
 		panic(err)
 	}
-	var pred Predicate
-	tok := s.Scan()
-	if tok.Kind == token.LPAREN || tok.Kind == token.IDENT {
-		pred = p.predicates.ParseFromPos(s, tok)
-		tok = s.Scan()
+	return RandomLatency(p.parseOptionalPredicate(s), dur, seed, 0 /* no limit */)
+}
+
+// Latency constructs an Injector that does not inject errors but instead
+// injects a fixed latency into operations that match the provided predicate.
+func Latency(pred Predicate, dur time.Duration) Injector {
+	return &fixedLatency{predicate: pred, dur: dur}
+}
+
+func parseLatency(p *Parser, s *dsl.Scanner) Injector {
+	dur := parseDuration(s, "Latency")
+	return Latency(p.parseOptionalPredicate(s), dur)
+}
+
+type fixedLatency struct {
+	predicate Predicate
+	dur       time.Duration
+}
+
+func (l *fixedLatency) String() string {
+	if l.predicate == nil {
+		return fmt.Sprintf("(Latency %q)", l.dur)
+	}
+	return fmt.Sprintf("(Latency %q %s)", l.dur, l.predicate)
+}
+
+func (l *fixedLatency) MaybeError(op Op) error {
+	if l.predicate == nil || l.predicate.Evaluate(op) {
+		time.Sleep(l.dur)
+	}
+	return nil
+}
+
+// UniformLatency constructs an Injector that does not inject errors but
+// instead injects random latency into operations that match the provided
+// predicate. The amount of latency injected is uniformly distributed in
+// [lo, hi). Like RandomLatency, the latency injected is derived from the
+// provided seed and is deterministic with respect to each file's path.
+func UniformLatency(pred Predicate, lo, hi time.Duration, seed int64) Injector {
+	if hi < lo {
+		panic(errors.AssertionFailedf("errorfs: UniformLatency bounds [%s, %s) are invalid", lo, hi))
+	}
+	ul := &uniformLatency{predicate: pred, lo: lo, hi: hi}
+	ul.keyedPrng.init(seed)
+	return ul
+}
+
+func parseUniformLatency(p *Parser, s *dsl.Scanner) Injector {
+	lo := parseDuration(s, "UniformLatency")
+	hi := parseDuration(s, "UniformLatency")
+	if hi < lo {
+		panic(errors.Newf("parsing UniformLatency: %q is less than %q", hi, lo))
 	}
-	if tok.Kind != token.RPAREN {
-		panic(errors.Errorf("errorfs: unexpected token %s; expected %s", tok.String(), token.RPAREN))
+	seed, err := strconv.ParseInt(s.Consume(token.INT).Lit, 10, 64)
+	if err != nil {
+		panic(err)
+	}
+	return UniformLatency(p.parseOptionalPredicate(s), lo, hi, seed)
+}
+
+type uniformLatency struct {
+	predicate Predicate
+	lo, hi    time.Duration
+	keyedPrng
+}
+
+func (ul *uniformLatency) String() string {
+	if ul.predicate == nil {
+		return fmt.Sprintf("(UniformLatency %q %q %d)", ul.lo, ul.hi, ul.rootSeed)
+	}
+	return fmt.Sprintf("(UniformLatency %q %q %d %s)", ul.lo, ul.hi, ul.rootSeed, ul.predicate)
+}
+
+func (ul *uniformLatency) MaybeError(op Op) error {
+	if ul.predicate != nil && !ul.predicate.Evaluate(op) {
+		return nil
+	}
+	dur := ul.lo
+	if ul.hi > ul.lo {
+		ul.keyedPrng.withKey(op.Path, func(prng *rand.Rand) {
+			dur += time.Duration(prng.Int64N(int64(ul.hi - ul.lo)))
+		})
+	}
+	time.Sleep(dur)
+	return nil
+}
+
+// parseDuration consumes a quoted duration.
+func parseDuration(s *dsl.Scanner, name string) time.Duration {
+	dur, err := time.ParseDuration(s.ConsumeString())
+	if err != nil {
+		panic(errors.Newf("parsing %s: %s", name, err))
 	}
-	return RandomLatency(pred, dur, seed, 0 /* no limit */)
+	return dur
 }
 
 type randomLatency struct {
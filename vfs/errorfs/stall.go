@@ -0,0 +1,184 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package errorfs
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/pebble/internal/dsl"
+)
+
+// Stall constructs an Injector that does not inject errors but instead stalls
+// operations that match the provided predicate during a window of time. The
+// window begins start after the Injector is constructed and lasts for dur. An
+// operation performed during the window blocks until the end of the window;
+// operations performed outside the window proceed without delay.
+//
+// Stall is useful for modelling a disk that stops responding for a period of
+// time, for example to exercise disk slowness detection or WAL failover.
+func Stall(pred Predicate, start, dur time.Duration) Injector {
+	return &stall{
+		predicate: pred,
+		start:     start,
+		dur:       dur,
+		origin:    time.Now(),
+	}
+}
+
+func parseStall(p *Parser, s *dsl.Scanner) Injector {
+	start := parseDuration(s, "Stall")
+	dur := parseDuration(s, "Stall")
+	return Stall(p.parseOptionalPredicate(s), start, dur)
+}
+
+type stall struct {
+	predicate  Predicate
+	start, dur time.Duration
+	// origin is the time from which the window is measured.
+	origin time.Time
+}
+
+func (s *stall) String() string {
+	if s.predicate == nil {
+		return fmt.Sprintf("(Stall %q %q)", s.start, s.dur)
+	}
+	return fmt.Sprintf("(Stall %q %q %s)", s.start, s.dur, s.predicate)
+}
+
+func (s *stall) MaybeError(op Op) error {
+	elapsed := time.Since(s.origin)
+	if elapsed < s.start || elapsed >= s.start+s.dur {
+		return nil
+	}
+	if s.predicate != nil && !s.predicate.Evaluate(op) {
+		return nil
+	}
+	time.Sleep(s.start + s.dur - elapsed)
+	return nil
+}
+
+// NewHang constructs a Hang with the given name that blocks operations that
+// match the provided predicate until it is released.
+func NewHang(name string, pred Predicate) *Hang {
+	h := &Hang{name: name, predicate: pred}
+	h.mu.released = make(chan struct{})
+	return h
+}
+
+func parseHang(p *Parser, s *dsl.Scanner) Injector {
+	name := s.ConsumeString()
+	return NewHang(name, p.parseOptionalPredicate(s))
+}
+
+// Hang implements Injector, blocking operations that match its predicate
+// until Release is called. A Hang constructed through the DSL may be
+// retrieved from the parsed Injector with FindHang.
+type Hang struct {
+	name      string
+	predicate Predicate
+	mu        struct {
+		sync.Mutex
+		// released is closed when the Hang is released.
+		released chan struct{}
+	}
+	// blocked is the number of operations currently blocked.
+	blocked atomic.Int32
+}
+
+// String implements fmt.Stringer.
+func (h *Hang) String() string {
+	if h.predicate == nil {
+		return fmt.Sprintf("(Hang %q)", h.name)
+	}
+	return fmt.Sprintf("(Hang %q %s)", h.name, h.predicate)
+}
+
+// Name returns the name of the Hang.
+func (h *Hang) Name() string {
+	return h.name
+}
+
+// MaybeError implements Injector.
+func (h *Hang) MaybeError(op Op) error {
+	if h.predicate != nil && !h.predicate.Evaluate(op) {
+		return nil
+	}
+	h.mu.Lock()
+	released := h.mu.released
+	h.mu.Unlock()
+	select {
+	case <-released:
+		return nil
+	default:
+	}
+	h.blocked.Add(1)
+	defer h.blocked.Add(-1)
+	<-released
+	return nil
+}
+
+// Release unblocks all blocked operations. Subsequent operations are not
+// blocked until the Hang is rearmed.
+func (h *Hang) Release() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	select {
+	case <-h.mu.released:
+	default:
+		close(h.mu.released)
+	}
+}
+
+// Rearm makes a released Hang block matching operations again.
+func (h *Hang) Rearm() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	select {
+	case <-h.mu.released:
+		h.mu.released = make(chan struct{})
+	default:
+	}
+}
+
+// Blocked returns the number of operations currently blocked by the Hang.
+func (h *Hang) Blocked() int {
+	return int(h.blocked.Load())
+}
+
+// FindHang returns the Hang with the given name within the provided Injector,
+// looking through the Injectors combined by Any, Counter and Toggle. It returns
+// nil if there is no such Hang.
+func FindHang(inj Injector, name string) *Hang {
+	var found *Hang
+	walkHangs(inj, func(h *Hang) {
+		if found == nil && h.name == name {
+			found = h
+		}
+	})
+	return found
+}
+
+// ReleaseHangs releases all Hangs within the provided Injector (see FindHang).
+func ReleaseHangs(inj Injector) {
+	walkHangs(inj, (*Hang).Release)
+}
+
+func walkHangs(inj Injector, fn func(*Hang)) {
+	switch inj := inj.(type) {
+	case *Hang:
+		fn(inj)
+	case anyInjector:
+		for _, i := range inj {
+			walkHangs(i, fn)
+		}
+	case *Counter:
+		walkHangs(inj.Injector, fn)
+	case *Toggle:
+		walkHangs(inj.Injector, fn)
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package vfs
+
+// IOUringOptions configures the FS returned by NewIOUringFS.
+type IOUringOptions struct {
+	// Entries is the size of the submission queue, which bounds the number of
+	// operations in flight at once. It is rounded up to a power of two by the
+	// kernel. Defaults to 256.
+	Entries uint32
+}
+
+func (o *IOUringOptions) ensureDefaults() {
+	if o.Entries == 0 {
+		o.Entries = 256
+	}
+}
@@ -0,0 +1,747 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+//go:build linux
+
+package vfs
+
+import (
+	"io"
+	"math"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+
+	"github.com/cockroachdb/errors"
+	"golang.org/x/sys/unix"
+)
+
+// NewIOUringFS returns an FS that issues file reads, writes and syncs through
+// a shared io_uring instance instead of blocking system calls. Operations
+// submitted concurrently (e.g. reads from concurrent iterators) are batched
+// into a single io_uring_enter call, and goroutines waiting on a read or sync
+// park instead of occupying an OS thread. Prefetch is submitted as an
+// asynchronous fadvise and does not wait for completion.
+//
+// All other operations are served by Default. Individual operations the
+// kernel does not support fall back to the equivalent system call. If the
+// kernel does not support io_uring at all (or it is disallowed, e.g. by a
+// seccomp policy), NewIOUringFS returns Default.
+//
+// The returned Closer must be closed once all files opened through the FS
+// are closed. Operations on files issued after Close fall back to system
+// calls.
+func NewIOUringFS(opts IOUringOptions) (FS, io.Closer) {
+	opts.ensureDefaults()
+	r, err := newIOUring(opts.Entries)
+	if err != nil {
+		return Default, noopCloser{}
+	}
+	return &ioUringFS{FS: Default, ring: r}, r
+}
+
+var ioUringSupport struct {
+	once      sync.Once
+	supported bool
+}
+
+// IOUringSupported returns true if NewIOUringFS would return an io_uring
+// backed FS.
+func IOUringSupported() bool {
+	ioUringSupport.once.Do(func() {
+		r, err := newIOUring(1)
+		if err == nil {
+			ioUringSupport.supported = true
+			_ = r.Close()
+		}
+	})
+	return ioUringSupport.supported
+}
+
+type ioUringFS struct {
+	FS
+	ring *ioUring
+}
+
+var _ FS = (*ioUringFS)(nil)
+
+func (fs *ioUringFS) wrap(f File, err error) (File, error) {
+	if err != nil {
+		return f, err
+	}
+	if lf, ok := f.(*linuxFile); ok {
+		return &ioUringFile{linuxFile: lf, ring: fs.ring}, nil
+	}
+	return f, nil
+}
+
+func (fs *ioUringFS) Create(name string, category DiskWriteCategory) (File, error) {
+	return fs.wrap(fs.FS.Create(name, category))
+}
+
+func (fs *ioUringFS) Open(name string, opts ...OpenOption) (File, error) {
+	return fs.wrap(fs.FS.Open(name, opts...))
+}
+
+func (fs *ioUringFS) OpenReadWrite(
+	name string, category DiskWriteCategory, opts ...OpenOption,
+) (File, error) {
+	return fs.wrap(fs.FS.OpenReadWrite(name, category, opts...))
+}
+
+func (fs *ioUringFS) ReuseForWrite(
+	oldname, newname string, category DiskWriteCategory,
+) (File, error) {
+	return fs.wrap(fs.FS.ReuseForWrite(oldname, newname, category))
+}
+
+func (fs *ioUringFS) Unwrap() FS { return fs.FS }
+
+// ioUringFile is a linuxFile whose reads, writes and syncs go through an
+// io_uring.
+type ioUringFile struct {
+	*linuxFile
+	ring *ioUring
+	// async tracks asynchronous operations (prefetches) that must complete
+	// before the file descriptor is closed.
+	async sync.WaitGroup
+}
+
+var _ File = (*ioUringFile)(nil)
+
+func (f *ioUringFile) ReadAt(p []byte, off int64) (n int, err error) {
+	if !f.ring.supports(ioringOpRead) {
+		return f.linuxFile.ReadAt(p, off)
+	}
+	for n < len(p) {
+		chunk := p[n:]
+		if len(chunk) > math.MaxInt32 {
+			chunk = chunk[:math.MaxInt32]
+		}
+		res, ok := f.ring.do(ioUringSQE{
+			opcode: ioringOpRead,
+			fd:     int32(f.fd),
+			off:    uint64(off) + uint64(n),
+			len:    uint32(len(chunk)),
+		}, chunk)
+		if !ok {
+			m, err := f.linuxFile.ReadAt(p[n:], off+int64(n))
+			return n + m, err
+		}
+		if res < 0 {
+			if syscall.Errno(-res) == unix.EINTR || syscall.Errno(-res) == unix.EAGAIN {
+				continue
+			}
+			return n, &os.PathError{Op: "read", Path: f.Name(), Err: syscall.Errno(-res)}
+		}
+		if res == 0 {
+			return n, io.EOF
+		}
+		n += int(res)
+	}
+	return n, nil
+}
+
+func (f *ioUringFile) Write(p []byte) (n int, err error) {
+	if !f.ring.supports(ioringOpWrite) || f.ring.features&ioringFeatRWCurPos == 0 {
+		return f.linuxFile.Write(p)
+	}
+	// An offset of -1 writes at (and advances) the file position.
+	return f.write(p, -1)
+}
+
+func (f *ioUringFile) WriteAt(p []byte, off int64) (n int, err error) {
+	if !f.ring.supports(ioringOpWrite) {
+		return f.linuxFile.WriteAt(p, off)
+	}
+	if off < 0 {
+		return 0, &os.PathError{Op: "writeat", Path: f.Name(), Err: errors.New("negative offset")}
+	}
+	return f.write(p, off)
+}
+
+// write writes p at off, or at the file position if off is -1.
+func (f *ioUringFile) write(p []byte, off int64) (n int, err error) {
+	for n < len(p) {
+		chunk := p[n:]
+		if len(chunk) > math.MaxInt32 {
+			chunk = chunk[:math.MaxInt32]
+		}
+		sqe := ioUringSQE{
+			opcode: ioringOpWrite,
+			fd:     int32(f.fd),
+			off:    math.MaxUint64,
+			len:    uint32(len(chunk)),
+		}
+		if off >= 0 {
+			sqe.off = uint64(off) + uint64(n)
+		}
+		res, ok := f.ring.do(sqe, chunk)
+		if !ok {
+			var m int
+			if off >= 0 {
+				m, err = f.linuxFile.WriteAt(p[n:], off+int64(n))
+			} else {
+				m, err = f.linuxFile.Write(p[n:])
+			}
+			return n + m, err
+		}
+		if res < 0 {
+			if syscall.Errno(-res) == unix.EINTR || syscall.Errno(-res) == unix.EAGAIN {
+				continue
+			}
+			return n, &os.PathError{Op: "write", Path: f.Name(), Err: syscall.Errno(-res)}
+		}
+		if res == 0 {
+			return n, io.ErrShortWrite
+		}
+		n += int(res)
+	}
+	return n, nil
+}
+
+func (f *ioUringFile) fsync(flags uint32) (ok bool, err error) {
+	if !f.ring.supports(ioringOpFsync) {
+		return false, nil
+	}
+	for {
+		res, ok := f.ring.do(ioUringSQE{opcode: ioringOpFsync, fd: int32(f.fd), opFlags: flags}, nil)
+		if !ok {
+			return false, nil
+		}
+		if res == -int32(unix.EINTR) {
+			continue
+		}
+		if res < 0 {
+			return true, &os.PathError{Op: "sync", Path: f.Name(), Err: syscall.Errno(-res)}
+		}
+		return true, nil
+	}
+}
+
+func (f *ioUringFile) Sync() error {
+	if ok, err := f.fsync(0); ok {
+		return err
+	}
+	return f.linuxFile.Sync()
+}
+
+func (f *ioUringFile) SyncData() error {
+	if ok, err := f.fsync(ioringFsyncDatasync); ok {
+		return err
+	}
+	return f.linuxFile.SyncData()
+}
+
+func (f *ioUringFile) SyncTo(offset int64) (fullSync bool, err error) {
+	if !f.useSyncRange {
+		// See linuxFile.SyncTo.
+		if ok, err := f.fsync(ioringFsyncDatasync); ok {
+			return err == nil, err
+		}
+		return f.linuxFile.SyncTo(offset)
+	}
+	if !f.ring.supports(ioringOpSyncFileRange) || offset > math.MaxUint32 {
+		return f.linuxFile.SyncTo(offset)
+	}
+	const (
+		waitBefore = 0x1
+		write      = 0x2
+	)
+	for {
+		res, ok := f.ring.do(ioUringSQE{
+			opcode:  ioringOpSyncFileRange,
+			fd:      int32(f.fd),
+			len:     uint32(offset),
+			opFlags: write | waitBefore,
+		}, nil)
+		if !ok {
+			return f.linuxFile.SyncTo(offset)
+		}
+		if res == -int32(unix.EINTR) {
+			continue
+		}
+		if res < 0 {
+			return false, syscall.Errno(-res)
+		}
+		return false, nil
+	}
+}
+
+func (f *ioUringFile) Prefetch(offset int64, length int64) error {
+	if !f.ring.supports(ioringOpFadvise) || length > math.MaxUint32 {
+		return f.linuxFile.Prefetch(offset, length)
+	}
+	f.async.Add(1)
+	if !f.ring.doAsync(ioUringSQE{
+		opcode:  ioringOpFadvise,
+		fd:      int32(f.fd),
+		off:     uint64(offset),
+		len:     uint32(length),
+		opFlags: unix.FADV_WILLNEED,
+	}, f.async.Done) {
+		f.async.Done()
+		return f.linuxFile.Prefetch(offset, length)
+	}
+	return nil
+}
+
+func (f *ioUringFile) Close() error {
+	// Wait for outstanding prefetches so that they don't apply to a reused
+	// file descriptor.
+	f.async.Wait()
+	return f.linuxFile.Close()
+}
+
+// io_uring constants, from include/uapi/linux/io_uring.h.
+const (
+	ioringOpNop           = 0
+	ioringOpFsync         = 3
+	ioringOpSyncFileRange = 8
+	ioringOpRead          = 22
+	ioringOpWrite         = 23
+	ioringOpFadvise       = 24
+	ioringOpLast          = 64
+
+	ioringFsyncDatasync = 1 << 0
+
+	ioringEnterGetEvents = 1 << 0
+
+	ioringFeatSingleMmap = 1 << 0
+	ioringFeatRWCurPos   = 1 << 3
+
+	ioringOffSQRing = 0
+	ioringOffCQRing = 0x8000000
+	ioringOffSQEs   = 0x10000000
+
+	ioringRegisterProbe = 8
+	ioUringOpSupported  = 1 << 0
+)
+
+type ioUringSQRingOffsets struct {
+	head, tail, ringMask, ringEntries, flags, dropped, array, resv1 uint32
+	userAddr                                                        uint64
+}
+
+type ioUringCQRingOffsets struct {
+	head, tail, ringMask, ringEntries, overflow, cqes, flags, resv1 uint32
+	userAddr                                                        uint64
+}
+
+type ioUringParams struct {
+	sqEntries, cqEntries, flags, sqThreadCPU, sqThreadIdle, features, wqFd uint32
+	resv                                                                   [3]uint32
+	sqOff                                                                  ioUringSQRingOffsets
+	cqOff                                                                  ioUringCQRingOffsets
+}
+
+// ioUringSQE is a submission queue entry (struct io_uring_sqe).
+type ioUringSQE struct {
+	opcode      uint8
+	flags       uint8
+	ioprio      uint16
+	fd          int32
+	off         uint64
+	addr        uint64
+	len         uint32
+	opFlags     uint32
+	userData    uint64
+	bufIndex    uint16
+	personality uint16
+	spliceFdIn  int32
+	addr3       uint64
+	_           uint64
+}
+
+// ioUringCQE is a completion queue entry (struct io_uring_cqe).
+type ioUringCQE struct {
+	userData uint64
+	res      int32
+	flags    uint32
+}
+
+type ioUringProbe struct {
+	lastOp uint8
+	opsLen uint8
+	resv   uint16
+	resv2  [3]uint32
+	ops    [ioringOpLast]struct {
+		op    uint8
+		resv  uint8
+		flags uint16
+		resv2 uint32
+	}
+}
+
+// ioUringStopID is the user data of the NOP that stops the completion loop.
+const ioUringStopID = math.MaxUint64
+
+// ioUring is an io_uring instance shared by all the files of an ioUringFS.
+//
+// Submissions are serialized by mu; io_uring_enter calls to submit are
+// serialized by submitMu so that whichever goroutine enters the kernel
+// submits every entry queued so far, batching concurrent operations. A
+// dedicated goroutine reaps completions and hands results to waiters.
+type ioUring struct {
+	fd        int
+	features  uint32
+	supported [ioringOpLast]bool
+
+	sqRing, cqRing, sqesMem []byte
+	sqHead, sqTail          *atomic.Uint32
+	sqMask                  uint32
+	sqes                    []ioUringSQE
+	cqHead, cqTail          *atomic.Uint32
+	cqMask                  uint32
+	cqes                    []ioUringCQE
+
+	// slots holds the IDs of free entries in reqs. Bounding the number of
+	// operations in flight to the submission queue size guarantees that
+	// neither queue overflows.
+	slots chan uint64
+	reqs  []ioUringReq
+
+	mu struct {
+		sync.Mutex
+		// pending is the number of queued entries not yet submitted.
+		pending uint32
+		// active[id] is set while the operation with the given ID is in
+		// flight.
+		active []bool
+	}
+	submitMu sync.Mutex
+	// failed is set when an io_uring_enter call fails with a non-transient
+	// error. The operations that were affected are completed with that error,
+	// and the ring is not used for new operations.
+	failed atomic.Pointer[error]
+
+	closeMu struct {
+		sync.RWMutex
+		closed bool
+	}
+	inflight sync.WaitGroup
+	reaped   chan struct{}
+}
+
+type ioUringReq struct {
+	done   chan int32
+	pinner runtime.Pinner
+	// onDone is set for asynchronous operations and is called by the
+	// completion loop. It is atomic because the completion loop only
+	// synchronizes with the submitter through the kernel, which the Go memory
+	// model can't see.
+	onDone atomic.Pointer[func()]
+}
+
+func newIOUring(entries uint32) (_ *ioUring, err error) {
+	var p ioUringParams
+	fd, _, errno := unix.Syscall(unix.SYS_IO_URING_SETUP, uintptr(entries), uintptr(unsafe.Pointer(&p)), 0)
+	if errno != 0 {
+		return nil, errors.Wrap(errno, "io_uring_setup")
+	}
+	r := &ioUring{fd: int(fd), features: p.features}
+	defer func() {
+		if err != nil {
+			r.release()
+		}
+	}()
+
+	// Only use io_uring if the kernel supports probing, which implies the
+	// basic read and write operations are available too.
+	var probe ioUringProbe
+	if _, _, errno := unix.Syscall6(unix.SYS_IO_URING_REGISTER, uintptr(r.fd), ioringRegisterProbe,
+		uintptr(unsafe.Pointer(&probe)), ioringOpLast, 0, 0); errno != 0 {
+		return nil, errors.Wrap(errno, "io_uring_register")
+	}
+	for i := 0; i < int(probe.opsLen) && i < ioringOpLast; i++ {
+		r.supported[probe.ops[i].op] = probe.ops[i].flags&ioUringOpSupported != 0
+	}
+	if !r.supported[ioringOpNop] || !r.supported[ioringOpRead] || !r.supported[ioringOpWrite] {
+		return nil, errors.New("io_uring: read and write operations are not supported")
+	}
+
+	sqRingSize := int(p.sqOff.array + p.sqEntries*4)
+	cqRingSize := int(p.cqOff.cqes + p.cqEntries*uint32(unsafe.Sizeof(ioUringCQE{})))
+	if p.features&ioringFeatSingleMmap != 0 {
+		sqRingSize = max(sqRingSize, cqRingSize)
+	}
+	const prot, flags = unix.PROT_READ | unix.PROT_WRITE, unix.MAP_SHARED | unix.MAP_POPULATE
+	if r.sqRing, err = unix.Mmap(r.fd, ioringOffSQRing, sqRingSize, prot, flags); err != nil {
+		return nil, errors.Wrap(err, "io_uring: mmap")
+	}
+	if p.features&ioringFeatSingleMmap != 0 {
+		r.cqRing = r.sqRing
+	} else if r.cqRing, err = unix.Mmap(r.fd, ioringOffCQRing, cqRingSize, prot, flags); err != nil {
+		return nil, errors.Wrap(err, "io_uring: mmap")
+	}
+	sqesSize := int(p.sqEntries) * int(unsafe.Sizeof(ioUringSQE{}))
+	if r.sqesMem, err = unix.Mmap(r.fd, ioringOffSQEs, sqesSize, prot, flags); err != nil {
+		return nil, errors.Wrap(err, "io_uring: mmap")
+	}
+
+	r.sqHead = (*atomic.Uint32)(unsafe.Pointer(&r.sqRing[p.sqOff.head]))
+	r.sqTail = (*atomic.Uint32)(unsafe.Pointer(&r.sqRing[p.sqOff.tail]))
+	r.sqMask = *(*uint32)(unsafe.Pointer(&r.sqRing[p.sqOff.ringMask]))
+	r.sqes = unsafe.Slice((*ioUringSQE)(unsafe.Pointer(&r.sqesMem[0])), p.sqEntries)
+	// Entries are always placed in the slot of the same index, so the
+	// indirection array is the identity mapping.
+	array := unsafe.Slice((*uint32)(unsafe.Pointer(&r.sqRing[p.sqOff.array])), p.sqEntries)
+	for i := range array {
+		array[i] = uint32(i)
+	}
+	r.cqHead = (*atomic.Uint32)(unsafe.Pointer(&r.cqRing[p.cqOff.head]))
+	r.cqTail = (*atomic.Uint32)(unsafe.Pointer(&r.cqRing[p.cqOff.tail]))
+	r.cqMask = *(*uint32)(unsafe.Pointer(&r.cqRing[p.cqOff.ringMask]))
+	r.cqes = unsafe.Slice((*ioUringCQE)(unsafe.Pointer(&r.cqRing[p.cqOff.cqes])), p.cqEntries)
+
+	// Keep one submission entry for the stop NOP.
+	n := p.sqEntries - 1
+	if n == 0 {
+		n = 1
+	}
+	r.slots = make(chan uint64, n)
+	r.reqs = make([]ioUringReq, n)
+	r.mu.active = make([]bool, n)
+	for i := range r.reqs {
+		r.reqs[i].done = make(chan int32, 1)
+		r.slots <- uint64(i)
+	}
+	r.reaped = make(chan struct{})
+	go r.reap()
+	return r, nil
+}
+
+func (r *ioUring) supports(op uint8) bool {
+	return r.supported[op]
+}
+
+// do submits sqe and waits for its completion, returning the result. buf, if
+// non-empty, is the buffer the operation reads into or writes from; it is
+// pinned for the duration of the operation. It returns ok=false if the ring
+// is closed or failed, in which case the caller must fall back to a system
+// call. If the ring fails while the operation is in flight, the result is the
+// negated errno of the failure.
+func (r *ioUring) do(sqe ioUringSQE, buf []byte) (res int32, ok bool) {
+	id, ok := r.start(&sqe, buf, nil)
+	if !ok {
+		return 0, false
+	}
+	req := &r.reqs[id]
+	res = <-req.done
+	r.finish(id)
+	return res, true
+}
+
+// doAsync submits sqe without waiting for its completion; onDone is called
+// from the completion loop (or by the submitter, if the submission fails). It
+// returns false if the ring is closed or failed, in which case onDone is not
+// called.
+func (r *ioUring) doAsync(sqe ioUringSQE, onDone func()) bool {
+	_, ok := r.start(&sqe, nil, onDone)
+	return ok
+}
+
+func (r *ioUring) start(sqe *ioUringSQE, buf []byte, onDone func()) (uint64, bool) {
+	r.closeMu.RLock()
+	defer r.closeMu.RUnlock()
+	if r.closeMu.closed || r.failed.Load() != nil {
+		return 0, false
+	}
+	r.inflight.Add(1)
+	id := <-r.slots
+	req := &r.reqs[id]
+	if onDone != nil {
+		req.onDone.Store(&onDone)
+	} else {
+		req.onDone.Store(nil)
+	}
+	if len(buf) > 0 {
+		req.pinner.Pin(&buf[0])
+		sqe.addr = uint64(uintptr(unsafe.Pointer(&buf[0])))
+	}
+	sqe.userData = id
+	// A submission failure is reported through the operation's result.
+	_ = r.enqueue(sqe)
+	return id, true
+}
+
+func (r *ioUring) finish(id uint64) {
+	r.reqs[id].pinner.Unpin()
+	r.slots <- id
+	r.inflight.Done()
+}
+
+// enqueue queues sqe and submits all the queued entries. If the submission
+// fails, the ring is marked as failed, the operations of the entries that
+// were not submitted are completed with the error, and the error is returned.
+func (r *ioUring) enqueue(sqe *ioUringSQE) error {
+	r.mu.Lock()
+	tail := r.sqTail.Load()
+	r.sqes[tail&r.sqMask] = *sqe
+	r.sqTail.Store(tail + 1)
+	r.mu.pending++
+	if sqe.userData != ioUringStopID {
+		r.mu.active[sqe.userData] = true
+	}
+	r.mu.Unlock()
+
+	r.submitMu.Lock()
+	defer r.submitMu.Unlock()
+	for {
+		r.mu.Lock()
+		n := r.mu.pending
+		r.mu.pending = 0
+		r.mu.Unlock()
+		if n == 0 {
+			return nil
+		}
+		for n > 0 {
+			submitted, _, errno := unix.Syscall6(unix.SYS_IO_URING_ENTER, uintptr(r.fd), uintptr(n), 0, 0, 0, 0)
+			if errno != 0 {
+				if isTransientIOUringErrno(errno) {
+					continue
+				}
+				return r.failUnsubmitted(errno)
+			}
+			n -= uint32(submitted)
+		}
+	}
+}
+
+func isTransientIOUringErrno(errno syscall.Errno) bool {
+	return errno == unix.EINTR || errno == unix.EAGAIN || errno == unix.EBUSY
+}
+
+// fail marks the ring as failed and returns the error corresponding to errno.
+func (r *ioUring) fail(errno syscall.Errno) error {
+	err := errors.Wrap(errno, "io_uring_enter")
+	r.failed.CompareAndSwap(nil, &err)
+	return err
+}
+
+// failUnsubmitted marks the ring as failed and completes the operations of
+// the entries that the kernel hasn't consumed with the error. The entries are
+// removed from the submission queue so that they are never submitted.
+//
+// submitMu must be held.
+func (r *ioUring) failUnsubmitted(errno syscall.Errno) error {
+	err := r.fail(errno)
+	r.mu.Lock()
+	head, tail := r.sqHead.Load(), r.sqTail.Load()
+	var ids []uint64
+	for i := head; i != tail; i++ {
+		if id := r.sqes[i&r.sqMask].userData; id != ioUringStopID {
+			ids = append(ids, id)
+		}
+	}
+	r.sqTail.Store(head)
+	r.mu.pending = 0
+	r.mu.Unlock()
+	for _, id := range ids {
+		r.complete(id, -int32(errno))
+	}
+	return err
+}
+
+// complete delivers the result of the operation with the given ID.
+func (r *ioUring) complete(id uint64, res int32) {
+	r.mu.Lock()
+	r.mu.active[id] = false
+	r.mu.Unlock()
+	req := &r.reqs[id]
+	if onDone := req.onDone.Load(); onDone != nil {
+		r.finish(id)
+		(*onDone)()
+	} else {
+		req.done <- res
+	}
+}
+
+// reap is the completion loop. If waiting for completions fails, the ring is
+// marked as failed and all the operations in flight are completed with the
+// error.
+func (r *ioUring) reap() {
+	defer close(r.reaped)
+	for {
+		head := r.cqHead.Load()
+		tail := r.cqTail.Load()
+		if head == tail {
+			_, _, errno := unix.Syscall6(unix.SYS_IO_URING_ENTER, uintptr(r.fd), 0, 1, ioringEnterGetEvents, 0, 0)
+			if errno != 0 && !isTransientIOUringErrno(errno) {
+				r.fail(errno)
+				r.mu.Lock()
+				var ids []uint64
+				for id, active := range r.mu.active {
+					if active {
+						ids = append(ids, uint64(id))
+					}
+				}
+				r.mu.Unlock()
+				for _, id := range ids {
+					r.complete(id, -int32(errno))
+				}
+				return
+			}
+			continue
+		}
+		stop := false
+		for ; head != tail; head++ {
+			cqe := r.cqes[head&r.cqMask]
+			if cqe.userData == ioUringStopID {
+				stop = true
+				continue
+			}
+			r.complete(cqe.userData, cqe.res)
+		}
+		r.cqHead.Store(head)
+		if stop {
+			return
+		}
+	}
+}
+
+// Close waits for in-flight operations, stops the completion loop and
+// releases the ring. Subsequent operations fall back to system calls.
+func (r *ioUring) Close() error {
+	r.closeMu.Lock()
+	if r.closeMu.closed {
+		r.closeMu.Unlock()
+		return nil
+	}
+	r.closeMu.closed = true
+	r.closeMu.Unlock()
+	r.inflight.Wait()
+	if err := r.enqueue(&ioUringSQE{opcode: ioringOpNop, userData: ioUringStopID}); err != nil {
+		select {
+		case <-r.reaped:
+		default:
+			// The completion loop can't be stopped, so the ring can't be
+			// released.
+			return err
+		}
+	} else {
+		<-r.reaped
+	}
+	r.release()
+	if err := r.failed.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+func (r *ioUring) release() {
+	if r.sqesMem != nil {
+		_ = unix.Munmap(r.sqesMem)
+	}
+	if r.cqRing != nil && &r.cqRing[0] != &r.sqRing[0] {
+		_ = unix.Munmap(r.cqRing)
+	}
+	if r.sqRing != nil {
+		_ = unix.Munmap(r.sqRing)
+	}
+	_ = unix.Close(r.fd)
+}
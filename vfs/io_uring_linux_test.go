@@ -0,0 +1,85 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+//go:build linux
+
+package vfs
+
+import (
+	"bytes"
+	"io"
+	"math/rand/v2"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIOUringFS(t *testing.T) {
+	if !IOUringSupported() {
+		t.Skip("io_uring is not supported")
+	}
+	fs, closer := NewIOUringFS(IOUringOptions{Entries: 8})
+	require.IsType(t, (*ioUringFS)(nil), fs)
+	dir := t.TempDir()
+	path := fs.PathJoin(dir, "foo")
+
+	data := make([]byte, 1<<20)
+	for i := range data {
+		data[i] = byte(rand.Uint32())
+	}
+	f, err := fs.Create(path, WriteCategoryUnspecified)
+	require.NoError(t, err)
+	require.IsType(t, (*ioUringFile)(nil), f)
+	for i := 0; i < len(data); i += 64 << 10 {
+		n, err := f.Write(data[i : i+64<<10])
+		require.NoError(t, err)
+		require.Equal(t, 64<<10, n)
+		_, err = f.SyncTo(int64(i))
+		require.NoError(t, err)
+	}
+	// Overwrite a range in place.
+	copy(data[100:], "hello world")
+	_, err = f.WriteAt([]byte("hello world"), 100)
+	require.NoError(t, err)
+	require.NoError(t, f.SyncData())
+	require.NoError(t, f.Sync())
+	require.NoError(t, f.Close())
+
+	f, err = fs.Open(path, RandomReadsOption)
+	require.NoError(t, err)
+	require.NoError(t, f.Prefetch(0, 1<<20))
+	// Issue more concurrent reads than there are submission queue entries.
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Go(func() {
+			for j := 0; j < 100; j++ {
+				off := rand.IntN(len(data) - 4096)
+				buf := make([]byte, 1+rand.IntN(4096))
+				n, err := f.ReadAt(buf, int64(off))
+				if err != nil || n != len(buf) || !bytes.Equal(buf, data[off:off+n]) {
+					t.Errorf("ReadAt(%d, %d) = %d, %v", len(buf), off, n, err)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+	// A read past the end of the file is short.
+	buf := make([]byte, 100)
+	n, err := f.ReadAt(buf, int64(len(data)-10))
+	require.Equal(t, io.EOF, err)
+	require.Equal(t, 10, n)
+	require.Equal(t, data[len(data)-10:], buf[:n])
+	require.NoError(t, f.Close())
+
+	// Files keep working, through system calls, once the ring is closed.
+	f, err = fs.Open(path)
+	require.NoError(t, err)
+	require.NoError(t, closer.Close())
+	n, err = f.ReadAt(buf, 100)
+	require.NoError(t, err)
+	require.Equal(t, data[100:200], buf[:n])
+	require.NoError(t, f.Close())
+}
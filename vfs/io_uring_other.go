@@ -0,0 +1,19 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+//go:build !linux
+
+package vfs
+
+import "io"
+
+// NewIOUringFS returns Default; io_uring is only available on Linux.
+func NewIOUringFS(opts IOUringOptions) (FS, io.Closer) {
+	return Default, noopCloser{}
+}
+
+// IOUringSupported returns false; io_uring is only available on Linux.
+func IOUringSupported() bool {
+	return false
+}
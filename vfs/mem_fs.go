@@ -40,6 +40,9 @@ func NewMem() *MemFS {
 // there (with no guarantees one way or the other about more recently written
 // data).
 //
+// The CrashStates() method can be used to enumerate or randomly sample the
+// possible states of the FS after a simulated crash.
+//
 // Note: when CrashClone() is not necessary, NewMem() is faster and should be
 // preferred.
 //
@@ -243,6 +246,7 @@ func (y *MemFS) create(fullname string, category DiskWriteCategory) (File, error
 			}
 			n := &memNode{}
 			dir.children[frag] = n
+			y.recordDirOp(dir, memDirEntry{name: frag, n: n})
 			ret = &memFile{
 				name:  frag,
 				n:     n,
@@ -301,6 +305,7 @@ func (y *MemFS) Link(oldname, newname string) error {
 				}
 			}
 			dir.children[frag] = n
+			y.recordDirOp(dir, memDirEntry{name: frag, n: n})
 		}
 		return nil
 	})
@@ -394,6 +399,7 @@ func (y *MemFS) Remove(fullname string) error {
 				return errNotEmpty
 			}
 			delete(dir.children, frag)
+			y.recordDirOp(dir, memDirEntry{name: frag})
 		}
 		return nil
 	})
@@ -415,6 +421,7 @@ func (y *MemFS) RemoveAll(fullname string) error {
 				return nil
 			}
 			delete(dir.children, frag)
+			y.recordDirOp(dir, memDirEntry{name: frag})
 		}
 		return nil
 	})
@@ -438,7 +445,8 @@ func (y *MemFS) Rename(oldname, newname string) error {
 // rename is the lock-free internal implementation of Rename. The caller must
 // hold cloneMu when crashable is true.
 func (y *MemFS) rename(oldname, newname string) error {
-	var n *memNode
+	var n, oldDir *memNode
+	var oldFrag string
 	err := y.walk(oldname, func(dir *memNode, frag string, final bool) error {
 		if final {
 			if frag == "" {
@@ -446,6 +454,9 @@ func (y *MemFS) rename(oldname, newname string) error {
 			}
 			n = dir.children[frag]
 			delete(dir.children, frag)
+			if n != nil {
+				oldDir, oldFrag = dir, frag
+			}
 		}
 		return nil
 	})
@@ -465,6 +476,13 @@ func (y *MemFS) rename(oldname, newname string) error {
 				return errors.New("pebble/vfs: empty file name")
 			}
 			dir.children[frag] = n
+			if dir == oldDir {
+				// A rename within a directory is atomic.
+				y.recordDirOp(dir, memDirEntry{name: oldFrag}, memDirEntry{name: frag, n: n})
+			} else {
+				y.recordDirOp(oldDir, memDirEntry{name: oldFrag})
+				y.recordDirOp(dir, memDirEntry{name: frag, n: n})
+			}
 		}
 		return nil
 	})
@@ -507,10 +525,12 @@ func (y *MemFS) MkdirAll(dirname string, perm os.FileMode) error {
 		}
 		child := dir.children[frag]
 		if child == nil {
-			dir.children[frag] = &memNode{
+			child = &memNode{
 				children: make(map[string]*memNode),
 				isDir:    true,
 			}
+			dir.children[frag] = child
+			y.recordDirOp(dir, memDirEntry{name: frag, n: child})
 			return nil
 		}
 		if !child.isDir {
@@ -664,10 +684,18 @@ type memNode struct {
 		data       []byte
 		syncedData []byte
 		modTime    time.Time
+		// pendingWrites are the writes since the last sync, in order; only
+		// maintained for a crashable MemFS. Applying them to syncedData yields
+		// data.
+		pendingWrites []memWrite
 	}
 
 	children       map[string]*memNode
 	syncedChildren map[string]*memNode // may be nil if never synced
+	// pendingOps are the changes to children since the last sync, in order;
+	// only maintained for a crashable MemFS. Applying them to syncedChildren
+	// yields children.
+	pendingOps []memDirOp
 }
 
 func newRootMemNode() *memNode {
@@ -827,6 +855,7 @@ func (f *memFile) Write(p []byte) (int, error) {
 		}
 		f.n.mu.data = append(f.n.mu.data[:f.pos], p...)
 	}
+	f.fs.recordWrite(f.n, f.pos, len(p))
 	f.pos += len(p)
 
 	if invariants.Enabled {
@@ -862,6 +891,7 @@ func (f *memFile) WriteAt(p []byte, ofs int64) (int, error) {
 	if n != len(p) {
 		panic(errors.AssertionFailedf("stuff"))
 	}
+	f.fs.recordWrite(f.n, int(ofs), len(p))
 
 	return len(p), nil
 }
@@ -890,9 +920,11 @@ func (f *memFile) Sync() error {
 	defer f.fs.mu.Unlock()
 	if f.n.isDir {
 		f.n.syncedChildren = maps.Clone(f.n.children)
+		f.n.pendingOps = nil
 	} else {
 		f.n.mu.Lock()
 		f.n.mu.syncedData = append(f.n.mu.syncedData[:0], f.n.mu.data...)
+		f.n.mu.pendingWrites = nil
 		f.n.mu.Unlock()
 	}
 	return nil
@@ -0,0 +1,270 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package vfs
+
+import (
+	"maps"
+	"math"
+	"math/bits"
+	"math/rand/v2"
+	"slices"
+	"sort"
+
+	"github.com/cockroachdb/errors"
+)
+
+// memWrite is a write to a file of a crashable MemFS that has not been synced.
+type memWrite struct {
+	off  int
+	data []byte
+}
+
+// memDirEntry is a change to a directory entry: the entry is set to n, or
+// removed if n is nil.
+type memDirEntry struct {
+	name string
+	n    *memNode
+}
+
+// memDirOp is a change to the entries of a directory of a crashable MemFS that
+// has not been synced. The entries of an op are changed atomically.
+type memDirOp []memDirEntry
+
+// maxPendingChanges is the maximum number of unsynced writes (or directory
+// changes) tracked for a file (or directory) of a crashable MemFS. Beyond it, a
+// new write or change is merged into the previous one. This reduces the number
+// of post-crash states without making any of them invalid (a merged write is
+// just one that happens to be persisted atomically), and bounds the state kept
+// until the next sync and captured by CrashStates.
+const maxPendingChanges = 64
+
+// recordDirOp records a change to the entries of dir, which must already be
+// applied to dir.children. MemFS.mu must be held.
+func (y *MemFS) recordDirOp(dir *memNode, entries ...memDirEntry) {
+	if !y.crashable {
+		return
+	}
+	if n := len(dir.pendingOps); n >= maxPendingChanges {
+		// CrashStates copies the entries of the ops it captures, so the last op
+		// can be extended in place.
+		dir.pendingOps[n-1] = append(dir.pendingOps[n-1], entries...)
+		return
+	}
+	dir.pendingOps = append(dir.pendingOps, memDirOp(entries))
+}
+
+// recordWrite records a write of n bytes at offset off to the file f, which
+// must already be applied to f.mu.data. f.mu must be held.
+func (y *MemFS) recordWrite(f *memNode, off, n int) {
+	if !y.crashable {
+		return
+	}
+	w := f.mu.pendingWrites
+	if len(w) < maxPendingChanges {
+		f.mu.pendingWrites = append(w, memWrite{off: off, data: slices.Clone(f.mu.data[off : off+n])})
+		return
+	}
+	last := &w[len(w)-1]
+	if off == last.off+len(last.data) {
+		// The common case of an append. Captured writes never look past their
+		// length, so the data can be extended in place.
+		last.data = append(last.data, f.mu.data[off:off+n]...)
+		return
+	}
+	lo := min(last.off, off)
+	hi := max(last.off+len(last.data), off+n)
+	*last = memWrite{off: lo, data: slices.Clone(f.mu.data[lo:hi])}
+}
+
+// CrashStates captures the state of a crashable MemFS, from which the possible
+// states of the filesystem after a crash at that moment can be produced.
+//
+// Durability is tracked per file and per directory. A file's data is durable
+// up to its last Sync or SyncData; SyncTo provides no durability guarantees.
+// A directory's entries (as changed by Create, Link, Remove, Rename,
+// ReuseForWrite and MkdirAll) are durable up to the directory's last Sync. A
+// rename within a directory is atomic; a rename across directories is a
+// removal from the old directory and an addition to the new one.
+//
+// After a crash, each file contains its durable data followed by a prefix of
+// the writes that were not synced (applied in order), and each directory
+// contains its durable entries changed by a prefix of the changes that were
+// not synced. The prefixes are chosen independently for each file and
+// directory. This is more permissive than most filesystems, which order some
+// operations with respect to each other, so any state that Pebble fails to
+// recover from is a state Pebble must not rely on not happening.
+type CrashStates struct {
+	root *crashNode
+	// pending contains the nodes with writes or changes that have not been
+	// synced, in a deterministic order.
+	pending          []*crashNode
+	windowsSemantics bool
+}
+
+// crashNode is an immutable copy of the durability state of a memNode.
+type crashNode struct {
+	isDir bool
+	// For files.
+	syncedData    []byte
+	pendingWrites []memWrite
+	// For directories.
+	syncedChildren map[string]*crashNode
+	pendingOps     [][]crashDirEntry
+
+	// pendingIdx is the index of the node in CrashStates.pending, or -1.
+	pendingIdx int
+}
+
+type crashDirEntry struct {
+	name string
+	n    *crashNode
+}
+
+// numChoices returns the number of possible post-crash states of the node.
+func (n *crashNode) numChoices() int {
+	return len(n.pendingWrites) + len(n.pendingOps) + 1
+}
+
+// CrashStates captures the current state of the filesystem for the purpose of
+// producing post-crash states; see CrashStates.
+func (y *MemFS) CrashStates() *CrashStates {
+	if !y.crashable {
+		panic(errors.AssertionFailedf("not a crashable MemFS"))
+	}
+	// Block all modification operations while we capture the state.
+	y.cloneMu.Lock()
+	defer y.cloneMu.Unlock()
+	s := &CrashStates{windowsSemantics: y.windowsSemantics}
+	s.root = s.capture(y.root, make(map[*memNode]*crashNode))
+	return s
+}
+
+func (s *CrashStates) capture(n *memNode, seen map[*memNode]*crashNode) *crashNode {
+	if c, ok := seen[n]; ok {
+		return c
+	}
+	c := &crashNode{isDir: n.isDir, pendingIdx: -1}
+	seen[n] = c
+	if !n.isDir {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		c.syncedData = slices.Clone(n.mu.syncedData)
+		// The writes themselves are never modified.
+		c.pendingWrites = slices.Clone(n.mu.pendingWrites)
+		if len(c.pendingWrites) > 0 {
+			c.pendingIdx = len(s.pending)
+			s.pending = append(s.pending, c)
+		}
+		return c
+	}
+	if len(n.pendingOps) > 0 {
+		c.pendingIdx = len(s.pending)
+		s.pending = append(s.pending, c)
+	}
+	// Visit the children in a deterministic order.
+	c.syncedChildren = make(map[string]*crashNode, len(n.syncedChildren))
+	names := slices.Collect(maps.Keys(n.syncedChildren))
+	sort.Strings(names)
+	for _, name := range names {
+		c.syncedChildren[name] = s.capture(n.syncedChildren[name], seen)
+	}
+	c.pendingOps = make([][]crashDirEntry, len(n.pendingOps))
+	for i, op := range n.pendingOps {
+		c.pendingOps[i] = make([]crashDirEntry, len(op))
+		for j, e := range op {
+			c.pendingOps[i][j].name = e.name
+			if e.n != nil {
+				c.pendingOps[i][j].n = s.capture(e.n, seen)
+			}
+		}
+	}
+	return c
+}
+
+// Count returns the number of post-crash states that can be produced with
+// State, or math.MaxUint64 if there are more. Some of these states may be
+// identical.
+func (s *CrashStates) Count() uint64 {
+	count := uint64(1)
+	for _, n := range s.pending {
+		hi, lo := bits.Mul64(count, uint64(n.numChoices()))
+		if hi != 0 {
+			return math.MaxUint64
+		}
+		count = lo
+	}
+	return count
+}
+
+// State returns a new crashable MemFS with the i-th possible post-crash state,
+// for 0 <= i < Count(). State(0) contains only the data that was synced, and
+// State(Count()-1) contains all the data.
+func (s *CrashStates) State(i uint64) *MemFS {
+	choices := make([]int, len(s.pending))
+	for j, n := range s.pending {
+		k := uint64(n.numChoices())
+		choices[j] = int(i % k)
+		i /= k
+	}
+	return s.build(choices)
+}
+
+// Random returns a new crashable MemFS with a randomly chosen possible
+// post-crash state.
+func (s *CrashStates) Random(rng *rand.Rand) *MemFS {
+	choices := make([]int, len(s.pending))
+	for j, n := range s.pending {
+		choices[j] = rng.IntN(n.numChoices())
+	}
+	return s.build(choices)
+}
+
+func (s *CrashStates) build(choices []int) *MemFS {
+	fs := &MemFS{crashable: true, windowsSemantics: s.windowsSemantics}
+	fs.root = s.buildNode(s.root, choices, make(map[*crashNode]*memNode))
+	return fs
+}
+
+func (s *CrashStates) buildNode(
+	c *crashNode, choices []int, built map[*crashNode]*memNode,
+) *memNode {
+	if n, ok := built[c]; ok {
+		return n
+	}
+	n := &memNode{isDir: c.isDir}
+	built[c] = n
+	var k int
+	if c.pendingIdx >= 0 {
+		k = choices[c.pendingIdx]
+	}
+	if !c.isDir {
+		data := slices.Clone(c.syncedData)
+		for _, w := range c.pendingWrites[:k] {
+			if grow := w.off + len(w.data) - len(data); grow > 0 {
+				data = append(data, make([]byte, grow)...)
+			}
+			copy(data[w.off:], w.data)
+		}
+		n.mu.data = data
+		n.mu.syncedData = slices.Clone(data)
+		return n
+	}
+	entries := maps.Clone(c.syncedChildren)
+	for _, op := range c.pendingOps[:k] {
+		for _, e := range op {
+			if e.n == nil {
+				delete(entries, e.name)
+			} else {
+				entries[e.name] = e.n
+			}
+		}
+	}
+	n.children = make(map[string]*memNode, len(entries))
+	for name, child := range entries {
+		n.children[name] = s.buildNode(child, choices, built)
+	}
+	n.syncedChildren = maps.Clone(n.children)
+	return n
+}
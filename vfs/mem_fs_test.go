@@ -23,6 +23,7 @@ import (
 func runMemFSDataDriven(t *testing.T, path string, fs *MemFS) {
 	fsMap := map[string]*MemFS{"initial": fs}
 	var f File
+	var states *CrashStates
 	rng := rand.New(rand.NewPCG(0, 0))
 	datadriven.RunTest(t, path, func(t *testing.T, td *datadriven.TestData) string {
 		var err error
@@ -50,6 +51,23 @@ func runMemFSDataDriven(t *testing.T, path string, fs *MemFS) {
 			fsName := td.CmdArgs[1].String()
 			newFs := fs.CrashClone(CrashCloneCfg{UnsyncedDataPercent: p, RNG: rng})
 			fsMap[fsName] = newFs
+		case "crash-states":
+			states = fs.CrashStates()
+			return fmt.Sprint(states.Count())
+		case "crash-state":
+			i, _ := strconv.ParseUint(td.CmdArgs[0].String(), 10, 64)
+			fsMap[td.CmdArgs[1].String()] = states.State(i)
+		case "crash-states-dump":
+			// Print the distinct post-crash states.
+			var buf strings.Builder
+			seen := make(map[string]bool)
+			for i := uint64(0); i < states.Count(); i++ {
+				if s := states.State(i).String(); !seen[s] {
+					seen[s] = true
+					fmt.Fprintf(&buf, "state %d:\n%s", i, s)
+				}
+			}
+			return buf.String()
 		case "switch-fs":
 			fsName := td.CmdArgs[0].String()
 			fs = fsMap[fsName]
@@ -131,6 +149,53 @@ func TestMemFSCrashable(t *testing.T) {
 	runMemFSDataDriven(t, "testdata/memfs_crashable", NewCrashableMem())
 }
 
+func TestMemFSCrashStates(t *testing.T) {
+	runMemFSDataDriven(t, "testdata/memfs_crash_states", NewCrashableMem())
+}
+
+func TestMemFSCrashStatesMaxPendingChanges(t *testing.T) {
+	fs := NewCrashableMem()
+	f, err := fs.Create("foo", WriteCategoryUnspecified)
+	require.NoError(t, err)
+	var data []byte
+	for i := 0; i < 10*maxPendingChanges; i++ {
+		b := []byte{byte(i)}
+		data = append(data, b...)
+		_, err := f.Write(b)
+		require.NoError(t, err)
+	}
+	// An overwrite that is not contiguous with the previous write.
+	_, err = f.(io.WriterAt).WriteAt([]byte("xyz"), 10)
+	require.NoError(t, err)
+
+	// The first maxPendingChanges-1 writes are tracked individually; all the
+	// following writes are merged into one.
+	want := map[string]bool{}
+	for k := 0; k < maxPendingChanges; k++ {
+		want[string(data[:k])] = true
+	}
+	copy(data[10:], "xyz")
+	want[string(data)] = true
+
+	states := fs.CrashStates()
+	// The file's writes and the creation of the file in the root directory.
+	require.Equal(t, uint64((maxPendingChanges+1)*2), states.Count())
+	got := map[string]bool{}
+	for i := uint64(0); i < states.Count(); i++ {
+		crashFS := states.State(i)
+		cf, err := crashFS.Open("foo")
+		if err != nil {
+			continue
+		}
+		b, err := io.ReadAll(cf)
+		require.NoError(t, err)
+		require.NoError(t, cf.Close())
+		got[string(b)] = true
+	}
+	require.Equal(t, want, got)
+	require.NoError(t, f.Close())
+}
+
 func TestMemFile(t *testing.T) {
 	want := "foo"
 	f := NewMemFile([]byte(want))
@@ -0,0 +1,291 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package vfs
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/pebble/internal/rate"
+)
+
+// WriteCategoryWAL is the DiskWriteCategory used for WAL writes. It is a
+// high-priority category by default (see RateLimiter).
+const WriteCategoryWAL DiskWriteCategory = "pebble-wal"
+
+// ReadCategory identifies a category of reads for rate limiting. Pebble uses
+// the names of the sstable block read categories (block.Category).
+type ReadCategory string
+
+// RateLimiter enforces token-bucket bandwidth limits on reads and writes, per
+// category. Limits can be adjusted at any time and take effect for subsequent
+// operations, including those on files that are already open. Categories
+// without a limit are not throttled.
+//
+// Writes are limited through an FS wrapper (see WithRateLimiting), which knows
+// the write category of each file. Reads are limited by the caller through
+// WaitRead: the category of a read is not visible at the FS layer, so Pebble
+// charges reads of sstable and blob file blocks when they are loaded.
+//
+// In addition to the per-category limits, there can be a limit on the total
+// write bandwidth (see SetTotalWriteLimit). Writes in high-priority categories
+// (by default, WAL writes) are never throttled by the total limit; instead,
+// the bandwidth they use is deducted from it, delaying writes in other
+// categories. This ensures that WAL writes never queue behind compaction
+// writes.
+//
+// RateLimiter is safe for concurrent use.
+type RateLimiter struct {
+	mu struct {
+		sync.RWMutex
+		write map[DiskWriteCategory]*categoryRateLimit
+		read  map[ReadCategory]*categoryRateLimit
+	}
+	totalWrite categoryRateLimit
+}
+
+// NewRateLimiter returns a RateLimiter without any limits, in which
+// WriteCategoryWAL is a high-priority category.
+func NewRateLimiter() *RateLimiter {
+	l := &RateLimiter{}
+	l.mu.write = make(map[DiskWriteCategory]*categoryRateLimit)
+	l.mu.read = make(map[ReadCategory]*categoryRateLimit)
+	l.SetHighPriority(WriteCategoryWAL, true)
+	return l
+}
+
+// SetWriteLimit sets the bandwidth limit for writes in the given category. A
+// limit of zero removes the limit.
+func (l *RateLimiter) SetWriteLimit(category DiskWriteCategory, bytesPerSecond int64) {
+	l.writeLimit(category).setLimit(bytesPerSecond)
+}
+
+// SetReadLimit sets the bandwidth limit for reads in the given category. A
+// limit of zero removes the limit.
+func (l *RateLimiter) SetReadLimit(category ReadCategory, bytesPerSecond int64) {
+	l.readLimit(category).setLimit(bytesPerSecond)
+}
+
+// SetTotalWriteLimit sets the bandwidth limit for writes across all
+// categories. A limit of zero removes the limit.
+func (l *RateLimiter) SetTotalWriteLimit(bytesPerSecond int64) {
+	l.totalWrite.setLimit(bytesPerSecond)
+}
+
+// SetHighPriority sets whether writes in the given category are high
+// priority, i.e. not throttled by the total write limit.
+func (l *RateLimiter) SetHighPriority(category DiskWriteCategory, highPriority bool) {
+	l.writeLimit(category).highPriority.Store(highPriority)
+}
+
+// WriteLimit returns the bandwidth limit for writes in the given category, or
+// zero if there is no limit.
+func (l *RateLimiter) WriteLimit(category DiskWriteCategory) int64 {
+	return l.writeLimit(category).bytesPerSecond.Load()
+}
+
+// ReadLimit returns the bandwidth limit for reads in the given category, or
+// zero if there is no limit.
+func (l *RateLimiter) ReadLimit(category ReadCategory) int64 {
+	return l.readLimit(category).bytesPerSecond.Load()
+}
+
+// TotalWriteLimit returns the bandwidth limit for writes across all
+// categories, or zero if there is no limit.
+func (l *RateLimiter) TotalWriteLimit() int64 {
+	return l.totalWrite.bytesPerSecond.Load()
+}
+
+// WaitWrite blocks until n bytes can be written in the given category.
+func (l *RateLimiter) WaitWrite(category DiskWriteCategory, n int) {
+	l.waitWrite(l.writeLimit(category), n)
+}
+
+// WaitRead blocks until n bytes can be read in the given category.
+func (l *RateLimiter) WaitRead(category ReadCategory, n int) {
+	c := l.readLimit(category)
+	if c.limiter.Load() == nil {
+		return
+	}
+	start := time.Now()
+	c.wait(n)
+	c.recordWait(time.Since(start))
+}
+
+// ThrottledWrites returns the total time writes in the given category spent
+// waiting for bandwidth.
+func (l *RateLimiter) ThrottledWrites(category DiskWriteCategory) time.Duration {
+	return time.Duration(l.writeLimit(category).waitNanos.Load())
+}
+
+// ThrottledReads returns the total time reads in the given category spent
+// waiting for bandwidth.
+func (l *RateLimiter) ThrottledReads(category ReadCategory) time.Duration {
+	return time.Duration(l.readLimit(category).waitNanos.Load())
+}
+
+func (l *RateLimiter) waitWrite(c *categoryRateLimit, n int) {
+	start := time.Now()
+	c.wait(n)
+	if c.highPriority.Load() {
+		l.totalWrite.remove(n)
+	} else {
+		l.totalWrite.wait(n)
+	}
+	c.recordWait(time.Since(start))
+}
+
+func (l *RateLimiter) writeLimit(category DiskWriteCategory) *categoryRateLimit {
+	return lookupOrCreate(l, l.mu.write, category)
+}
+
+func (l *RateLimiter) readLimit(category ReadCategory) *categoryRateLimit {
+	return lookupOrCreate(l, l.mu.read, category)
+}
+
+func lookupOrCreate[K comparable](
+	l *RateLimiter, m map[K]*categoryRateLimit, k K,
+) *categoryRateLimit {
+	l.mu.RLock()
+	c, ok := m[k]
+	l.mu.RUnlock()
+	if ok {
+		return c
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if c, ok = m[k]; !ok {
+		c = &categoryRateLimit{}
+		m[k] = c
+	}
+	return c
+}
+
+// categoryRateLimit is the token bucket for a category.
+type categoryRateLimit struct {
+	bytesPerSecond atomic.Int64
+	// limiter is nil if there is no limit.
+	limiter      atomic.Pointer[rate.Limiter]
+	highPriority atomic.Bool
+	// waitNanos is the total time spent waiting for bandwidth.
+	waitNanos atomic.Int64
+	// mu serializes changes to the limit.
+	mu sync.Mutex
+}
+
+func (c *categoryRateLimit) setLimit(bytesPerSecond int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.bytesPerSecond.Store(bytesPerSecond)
+	if bytesPerSecond <= 0 {
+		c.limiter.Store(nil)
+		return
+	}
+	// Allow bursts of up to a second's worth of bandwidth.
+	r := float64(bytesPerSecond)
+	if lim := c.limiter.Load(); lim != nil {
+		lim.SetRateAndBurst(r, r)
+	} else {
+		c.limiter.Store(rate.NewLimiter(r, r))
+	}
+}
+
+func (c *categoryRateLimit) wait(n int) {
+	if lim := c.limiter.Load(); lim != nil {
+		lim.Wait(float64(n))
+	}
+}
+
+// recordWait records the time an operation spent waiting; short durations are
+// ignored as they are not due to throttling.
+func (c *categoryRateLimit) recordWait(d time.Duration) {
+	if d >= time.Millisecond {
+		c.waitNanos.Add(int64(d))
+	}
+}
+
+func (c *categoryRateLimit) remove(n int) {
+	if lim := c.limiter.Load(); lim != nil {
+		lim.Remove(float64(n))
+	}
+}
+
+// WithRateLimiting wraps the FS so that writes to files are throttled
+// according to the limits of their write category in the given RateLimiter.
+func WithRateLimiting(fs FS, l *RateLimiter) FS {
+	return &rateLimitedFS{FS: fs, limiter: l}
+}
+
+type rateLimitedFS struct {
+	FS
+	limiter *RateLimiter
+}
+
+var _ FS = (*rateLimitedFS)(nil)
+
+func (fs *rateLimitedFS) wrap(f File, category DiskWriteCategory) File {
+	return &rateLimitedFile{
+		File:     f,
+		limiter:  fs.limiter,
+		category: fs.limiter.writeLimit(category),
+	}
+}
+
+// Create is part of the FS interface.
+func (fs *rateLimitedFS) Create(name string, category DiskWriteCategory) (File, error) {
+	f, err := fs.FS.Create(name, category)
+	if err != nil {
+		return nil, err
+	}
+	return fs.wrap(f, category), nil
+}
+
+// OpenReadWrite is part of the FS interface.
+func (fs *rateLimitedFS) OpenReadWrite(
+	name string, category DiskWriteCategory, opts ...OpenOption,
+) (File, error) {
+	f, err := fs.FS.OpenReadWrite(name, category, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return fs.wrap(f, category), nil
+}
+
+// ReuseForWrite is part of the FS interface.
+func (fs *rateLimitedFS) ReuseForWrite(
+	oldname, newname string, category DiskWriteCategory,
+) (File, error) {
+	f, err := fs.FS.ReuseForWrite(oldname, newname, category)
+	if err != nil {
+		return nil, err
+	}
+	return fs.wrap(f, category), nil
+}
+
+// Unwrap is part of the FS interface.
+func (fs *rateLimitedFS) Unwrap() FS {
+	return fs.FS
+}
+
+type rateLimitedFile struct {
+	File
+	limiter  *RateLimiter
+	category *categoryRateLimit
+}
+
+var _ File = (*rateLimitedFile)(nil)
+
+// Write is part of the File interface.
+func (f *rateLimitedFile) Write(p []byte) (int, error) {
+	f.limiter.waitWrite(f.category, len(p))
+	return f.File.Write(p)
+}
+
+// WriteAt is part of the File interface.
+func (f *rateLimitedFile) WriteAt(p []byte, off int64) (int, error) {
+	f.limiter.waitWrite(f.category, len(p))
+	return f.File.WriteAt(p, off)
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package vfs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiting(t *testing.T) {
+	const mb = 1 << 20
+	const compaction DiskWriteCategory = "compaction"
+	const other DiskWriteCategory = "other"
+
+	l := NewRateLimiter()
+	fs := WithRateLimiting(NewMem(), l)
+	create := func(name string, category DiskWriteCategory) File {
+		f, err := fs.Create(name, category)
+		require.NoError(t, err)
+		return f
+	}
+	write := func(f File, n int) time.Duration {
+		start := time.Now()
+		_, err := f.Write(make([]byte, n))
+		require.NoError(t, err)
+		return time.Since(start)
+	}
+
+	// The bucket starts full, allowing a second's worth of writes; beyond that,
+	// writes are throttled.
+	l.SetWriteLimit(compaction, mb)
+	require.Equal(t, int64(mb), l.WriteLimit(compaction))
+	c := create("compaction", compaction)
+	write(c, mb)
+	require.GreaterOrEqual(t, write(c, mb/4), 150*time.Millisecond)
+	require.NotZero(t, l.ThrottledWrites(compaction))
+
+	// Other categories are not limited.
+	o := create("other", other)
+	for i := 0; i < 4; i++ {
+		write(o, mb)
+	}
+	require.Zero(t, l.ThrottledWrites(other))
+
+	// Limits can be changed while files are open.
+	l.SetWriteLimit(compaction, 0)
+	require.Less(t, write(c, 4*mb), 100*time.Millisecond)
+
+	// WAL writes aren't throttled by the total limit, but use up its bandwidth.
+	l.SetTotalWriteLimit(mb)
+	require.Equal(t, int64(mb), l.TotalWriteLimit())
+	w := create("wal", WriteCategoryWAL)
+	write(w, mb+mb/4)
+	require.Zero(t, l.ThrottledWrites(WriteCategoryWAL))
+	before := l.ThrottledWrites(compaction)
+	require.GreaterOrEqual(t, write(c, 1), 150*time.Millisecond)
+	require.Greater(t, l.ThrottledWrites(compaction), before)
+	l.SetTotalWriteLimit(0)
+
+	// Reads are limited per read category.
+	l.SetReadLimit("scan", mb)
+	require.Equal(t, int64(mb), l.ReadLimit("scan"))
+	l.WaitRead("scan", mb)
+	start := time.Now()
+	l.WaitRead("scan", mb/4)
+	require.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+	require.NotZero(t, l.ThrottledReads("scan"))
+	l.WaitRead("get", 4*mb)
+	require.Zero(t, l.ThrottledReads("get"))
+
+	for _, f := range []File{c, o, w} {
+		require.NoError(t, f.Close())
+	}
+}
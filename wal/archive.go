@@ -0,0 +1,328 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package wal
+
+import (
+	"cmp"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/errors/oserror"
+	"github.com/cockroachdb/pebble/internal/base"
+	"github.com/cockroachdb/pebble/objstorage/remote"
+	"github.com/cockroachdb/pebble/vfs"
+)
+
+// ArchiverOptions configures an Archiver.
+type ArchiverOptions struct {
+	// Dir is a local directory that obsolete WALs are moved into. Exactly one
+	// of Dir and Storage must be set.
+	Dir Dir
+	// Storage is a remote.Storage that obsolete WALs are uploaded to, using
+	// object names formed by appending the log filename to Prefix. Exactly one
+	// of Dir and Storage must be set.
+	Storage remote.Storage
+	Prefix  string
+
+	// MaxAge is the age after which an archived WAL is deleted. Zero means
+	// archived WALs are not deleted because of their age.
+	MaxAge time.Duration
+	// MaxSize is the total size of archived WALs above which the oldest
+	// archived WALs are deleted. Zero means archived WALs are not deleted
+	// because of their size.
+	MaxSize int64
+
+	// Cleaner cleans the obsolete files that are not WALs. Defaults to
+	// base.DeleteCleaner.
+	Cleaner base.Cleaner
+	// Logger is used to report failures to enforce the retention limits.
+	Logger base.Logger
+}
+
+// ArchivedLog describes a WAL file held by an Archiver.
+type ArchivedLog struct {
+	Num   NumWAL
+	Index LogNameIndex
+	// Name is the filename of the log file, or the name of the object holding
+	// it when archiving to a remote.Storage.
+	Name string
+	Size int64
+	// ArchivedAt is the time the log file was archived. For the files present
+	// when the Archiver was created, it is the modification time of the file,
+	// or, for a remote.Storage that does not report modification times, the
+	// time the Archiver was created.
+	ArchivedAt time.Time
+}
+
+// Archiver is a base.Cleaner that archives obsolete WAL files instead of
+// deleting them, so that they remain available for auditing and point-in-time
+// recovery. The archived files are retained until they exceed the MaxAge or
+// MaxSize limits, oldest first. Obsolete files that are not WALs are passed
+// on to ArchiverOptions.Cleaner.
+//
+// An Archiver is configured as the Options.Cleaner of a DB, and is invoked by
+// the delete pacer. WAL recycling is disabled when an Archiver is configured,
+// since a recycled WAL would never be archived.
+//
+// Archived WALs are ordinary log files, and can be read with `pebble wal
+// dump`.
+type Archiver struct {
+	opts    ArchiverOptions
+	timeNow func() time.Time
+
+	mu struct {
+		sync.Mutex
+		// logs holds the archived WALs, in increasing ArchivedAt order.
+		logs []ArchivedLog
+		size int64
+	}
+}
+
+var _ base.Cleaner = (*Archiver)(nil)
+
+// NewArchiver returns a new Archiver. The archived WALs found in the archive
+// location are retained as though they were archived by the Archiver.
+func NewArchiver(opts ArchiverOptions) (*Archiver, error) {
+	if (opts.Dir.FS == nil) == (opts.Storage == nil) {
+		return nil, errors.New("wal: exactly one of ArchiverOptions.Dir and ArchiverOptions.Storage must be set")
+	}
+	if opts.MaxAge < 0 || opts.MaxSize < 0 {
+		return nil, errors.New("wal: archive retention limits must not be negative")
+	}
+	if opts.Cleaner == nil {
+		opts.Cleaner = base.DeleteCleaner{}
+	}
+	if opts.Logger == nil {
+		opts.Logger = base.DefaultLogger
+	}
+	a := &Archiver{opts: opts, timeNow: time.Now}
+	var err error
+	if opts.Storage == nil {
+		err = a.loadDir()
+	} else {
+		err = a.loadStorage()
+	}
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(a.mu.logs, func(x, y ArchivedLog) int {
+		return cmp.Or(x.ArchivedAt.Compare(y.ArchivedAt), cmp.Compare(x.Num, y.Num), cmp.Compare(x.Index, y.Index))
+	})
+	a.enforceRetention()
+	return a, nil
+}
+
+func (a *Archiver) loadDir() error {
+	d := a.opts.Dir
+	if err := d.FS.MkdirAll(d.Dirname, 0755); err != nil {
+		return err
+	}
+	ls, err := d.FS.List(d.Dirname)
+	if err != nil {
+		return errors.Wrapf(err, "listing WAL archive %q", d.Dirname)
+	}
+	for _, name := range ls {
+		wn, index, ok := ParseLogFilename(name)
+		if !ok {
+			continue
+		}
+		stat, err := d.FS.Stat(d.FS.PathJoin(d.Dirname, name))
+		if err != nil {
+			return err
+		}
+		a.addLocked(ArchivedLog{Num: wn, Index: index, Name: name, Size: stat.Size(), ArchivedAt: stat.ModTime()})
+	}
+	return nil
+}
+
+func (a *Archiver) loadStorage() error {
+	names, err := a.opts.Storage.List(a.opts.Prefix, "")
+	if err != nil {
+		return errors.Wrapf(err, "listing WAL archive %q", a.opts.Prefix)
+	}
+	withModTime, _ := a.opts.Storage.(remote.StorageWithModTime)
+	now := a.timeNow()
+	for _, objName := range names {
+		wn, index, ok := ParseLogFilename(strings.TrimPrefix(objName, a.opts.Prefix))
+		if !ok {
+			continue
+		}
+		size, err := a.opts.Storage.Size(objName)
+		if err != nil {
+			return err
+		}
+		archivedAt := now
+		if withModTime != nil {
+			if archivedAt, err = withModTime.ModTime(objName); err != nil {
+				return err
+			}
+		}
+		a.addLocked(ArchivedLog{Num: wn, Index: index, Name: objName, Size: size, ArchivedAt: archivedAt})
+	}
+	return nil
+}
+
+// Clean implements base.Cleaner. Obsolete WAL files are archived, and other
+// obsolete files are passed on to ArchiverOptions.Cleaner.
+func (a *Archiver) Clean(fs vfs.FS, fileType base.FileType, path string) error {
+	if fileType != base.FileTypeLog {
+		return a.opts.Cleaner.Clean(fs, fileType, path)
+	}
+	filename := fs.PathBase(path)
+	wn, index, ok := ParseLogFilename(filename)
+	if !ok {
+		return a.opts.Cleaner.Clean(fs, fileType, path)
+	}
+	name := a.opts.Prefix + filename
+	if a.opts.Storage == nil {
+		name = filename
+	}
+	a.mu.Lock()
+	archived := slices.ContainsFunc(a.mu.logs, func(l ArchivedLog) bool { return l.Name == name })
+	a.mu.Unlock()
+	if archived {
+		// Another copy of the same log file was already archived. This happens
+		// when a WAL is replicated to multiple directories.
+		return fs.Remove(path)
+	}
+
+	var size int64
+	var err error
+	if a.opts.Storage == nil {
+		size, err = a.archiveToDir(fs, path, name)
+	} else {
+		size, err = a.archiveToStorage(fs, path, name)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "archiving WAL %q", path)
+	}
+	a.mu.Lock()
+	a.addLocked(ArchivedLog{Num: wn, Index: index, Name: name, Size: size, ArchivedAt: a.timeNow()})
+	a.mu.Unlock()
+	a.enforceRetention()
+	return nil
+}
+
+// archiveToDir moves the log file at path into the archive directory. The
+// file is renamed if it is on the same filesystem, and copied otherwise. The
+// filesystems are compared after unwrapping, so that a log file cleaned
+// through a wrapped FS (e.g. a rate limited one) is still renamed.
+func (a *Archiver) archiveToDir(fs vfs.FS, path, name string) (int64, error) {
+	d := a.opts.Dir
+	dst := d.FS.PathJoin(d.Dirname, name)
+	renamed := false
+	if vfs.Root(fs) == vfs.Root(d.FS) {
+		renamed = fs.Rename(path, dst) == nil
+	}
+	if !renamed {
+		// The rename can fail because the archive directory is on a different
+		// device; fall back to copying.
+		if err := vfs.CopyAcrossFS(fs, path, d.FS, dst); err != nil {
+			return 0, err
+		}
+	}
+	dir, err := d.FS.OpenDir(d.Dirname)
+	if err != nil {
+		return 0, err
+	}
+	err = errors.CombineErrors(dir.Sync(), dir.Close())
+	if err != nil {
+		return 0, err
+	}
+	if !renamed {
+		if err := fs.Remove(path); err != nil {
+			return 0, err
+		}
+	}
+	stat, err := d.FS.Stat(dst)
+	if err != nil {
+		return 0, err
+	}
+	return stat.Size(), nil
+}
+
+// archiveToStorage uploads the log file at path to the remote.Storage, and
+// then removes it.
+func (a *Archiver) archiveToStorage(fs vfs.FS, path, name string) (int64, error) {
+	f, err := fs.Open(path, vfs.SequentialReadsOption)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	w, err := a.opts.Storage.CreateObject(name)
+	if err != nil {
+		return 0, err
+	}
+	size, err := io.Copy(w, f)
+	if err = errors.CombineErrors(err, w.Close()); err != nil {
+		return 0, err
+	}
+	return size, fs.Remove(path)
+}
+
+// addLocked adds an archived log. a.mu must be held, unless the Archiver is
+// being constructed.
+func (a *Archiver) addLocked(l ArchivedLog) {
+	a.mu.logs = append(a.mu.logs, l)
+	a.mu.size += l.Size
+}
+
+// enforceRetention deletes the oldest archived logs until the retention limits
+// are satisfied. Failures are logged, and the logs that could not be deleted
+// are forgotten.
+func (a *Archiver) enforceRetention() {
+	var expired []ArchivedLog
+	func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		now := a.timeNow()
+		i := 0
+		for ; i < len(a.mu.logs); i++ {
+			l := a.mu.logs[i]
+			tooOld := a.opts.MaxAge > 0 && now.Sub(l.ArchivedAt) > a.opts.MaxAge
+			tooBig := a.opts.MaxSize > 0 && a.mu.size > a.opts.MaxSize
+			if !tooOld && !tooBig {
+				break
+			}
+			a.mu.size -= l.Size
+		}
+		expired = slices.Clone(a.mu.logs[:i])
+		a.mu.logs = slices.Delete(a.mu.logs, 0, i)
+	}()
+	for _, l := range expired {
+		var err error
+		if a.opts.Storage == nil {
+			err = a.opts.Dir.FS.Remove(a.opts.Dir.FS.PathJoin(a.opts.Dir.Dirname, l.Name))
+			if oserror.IsNotExist(err) {
+				err = nil
+			}
+		} else if err = a.opts.Storage.Delete(l.Name); err != nil && a.opts.Storage.IsNotExistError(err) {
+			err = nil
+		}
+		if err != nil {
+			a.opts.Logger.Errorf("wal: deleting archived WAL %q: %v", l.Name, err)
+		}
+	}
+}
+
+// List returns the archived logs, in the order they were archived.
+func (a *Archiver) List() []ArchivedLog {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return slices.Clone(a.mu.logs)
+}
+
+// Opts returns the options the Archiver was created with.
+func (a *Archiver) Opts() ArchiverOptions {
+	return a.opts
+}
+
+func (a *Archiver) String() string {
+	return "wal-archive"
+}
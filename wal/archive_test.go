@@ -0,0 +1,171 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package wal
+
+import (
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble/internal/base"
+	"github.com/cockroachdb/pebble/internal/testutils"
+	"github.com/cockroachdb/pebble/objstorage/remote"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/stretchr/testify/require"
+)
+
+// createLogFile creates a log file of the given size.
+func createLogFile(t *testing.T, fs vfs.FS, path string, size int) {
+	f, err := fs.Create(path, vfs.WriteCategoryUnspecified)
+	require.NoError(t, err)
+	_, err = f.Write(make([]byte, size))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+}
+
+func archivedNames(a *Archiver) []string {
+	var names []string
+	for _, l := range a.List() {
+		names = append(names, l.Name)
+	}
+	return names
+}
+
+// noOpenFS wraps a vfs.FS and fails every Open, which prevents copying files
+// out of it.
+type noOpenFS struct {
+	vfs.FS
+}
+
+func (fs noOpenFS) Open(name string, opts ...vfs.OpenOption) (vfs.File, error) {
+	return nil, errors.Newf("open %q not permitted", name)
+}
+
+func (fs noOpenFS) Unwrap() vfs.FS { return fs.FS }
+
+func TestArchiver(t *testing.T) {
+	memFS := vfs.NewMem()
+	for _, dir := range []string{"pri", "rep"} {
+		require.NoError(t, memFS.MkdirAll(dir, os.ModePerm))
+	}
+	a, err := NewArchiver(ArchiverOptions{
+		Dir:     Dir{FS: memFS, Dirname: "archive"},
+		MaxSize: 250,
+		MaxAge:  time.Hour,
+		Logger:  testutils.Logger{T: t},
+	})
+	require.NoError(t, err)
+	now := time.Unix(1000, 0)
+	a.timeNow = func() time.Time { return now }
+
+	// Log files are moved into the archive.
+	for _, name := range []string{"000001.log", "000002-001.log"} {
+		createLogFile(t, memFS, memFS.PathJoin("pri", name), 100)
+		require.NoError(t, a.Clean(memFS, base.FileTypeLog, memFS.PathJoin("pri", name)))
+		_, err := memFS.Stat(memFS.PathJoin("pri", name))
+		require.True(t, os.IsNotExist(err))
+	}
+	require.Equal(t, []string{"000001.log", "000002-001.log"}, archivedNames(a))
+	ls, err := memFS.List("archive")
+	require.NoError(t, err)
+	slices.Sort(ls)
+	require.Equal(t, []string{"000001.log", "000002-001.log"}, ls)
+
+	// A second copy of an archived log file is deleted.
+	createLogFile(t, memFS, "rep/000001.log", 100)
+	require.NoError(t, a.Clean(memFS, base.FileTypeLog, "rep/000001.log"))
+	_, err = memFS.Stat("rep/000001.log")
+	require.True(t, os.IsNotExist(err))
+	require.Len(t, a.List(), 2)
+
+	// Other files are deleted.
+	createLogFile(t, memFS, "pri/000003.sst", 100)
+	require.NoError(t, a.Clean(memFS, base.FileTypeTable, "pri/000003.sst"))
+	_, err = memFS.Stat("pri/000003.sst")
+	require.True(t, os.IsNotExist(err))
+
+	// Exceeding MaxSize deletes the oldest archived log.
+	now = now.Add(time.Minute)
+	createLogFile(t, memFS, "pri/000004.log", 100)
+	require.NoError(t, a.Clean(memFS, base.FileTypeLog, "pri/000004.log"))
+	require.Equal(t, []string{"000002-001.log", "000004.log"}, archivedNames(a))
+	_, err = memFS.Stat("archive/000001.log")
+	require.True(t, os.IsNotExist(err))
+
+	// Exceeding MaxAge deletes the logs archived before the last one.
+	now = now.Add(time.Hour + time.Second)
+	createLogFile(t, memFS, "pri/000005.log", 10)
+	require.NoError(t, a.Clean(memFS, base.FileTypeLog, "pri/000005.log"))
+	require.Equal(t, []string{"000005.log"}, archivedNames(a))
+
+	// A new Archiver picks up the archived logs.
+	a, err = NewArchiver(ArchiverOptions{Dir: Dir{FS: memFS, Dirname: "archive"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"000005.log"}, archivedNames(a))
+	logs, err := Scan(a.Opts().Dir)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	require.Equal(t, NumWAL(5), logs[0].Num)
+}
+
+// TestArchiver_WrappedFS tests that a log file cleaned through a wrapper of
+// the archive's FS is renamed rather than copied.
+func TestArchiver_WrappedFS(t *testing.T) {
+	memFS := vfs.NewMem()
+	require.NoError(t, memFS.MkdirAll("pri", os.ModePerm))
+	a, err := NewArchiver(ArchiverOptions{
+		Dir:    Dir{FS: memFS, Dirname: "archive"},
+		Logger: testutils.Logger{T: t},
+	})
+	require.NoError(t, err)
+
+	createLogFile(t, memFS, "pri/000001.log", 100)
+	require.NoError(t, a.Clean(noOpenFS{memFS}, base.FileTypeLog, "pri/000001.log"))
+	_, err = memFS.Stat("pri/000001.log")
+	require.True(t, os.IsNotExist(err))
+	require.Equal(t, []string{"000001.log"}, archivedNames(a))
+	stat, err := memFS.Stat("archive/000001.log")
+	require.NoError(t, err)
+	require.Equal(t, int64(100), stat.Size())
+}
+
+func TestArchiver_Storage(t *testing.T) {
+	memFS := vfs.NewMem()
+	require.NoError(t, memFS.MkdirAll("pri", os.ModePerm))
+	storage := remote.NewInMem()
+	opts := ArchiverOptions{
+		Storage: storage,
+		Prefix:  "wals/",
+		MaxSize: 150,
+		Logger:  testutils.Logger{T: t},
+	}
+	a, err := NewArchiver(opts)
+	require.NoError(t, err)
+
+	for _, name := range []string{"000001.log", "000002.log"} {
+		createLogFile(t, memFS, memFS.PathJoin("pri", name), 100)
+		require.NoError(t, a.Clean(memFS, base.FileTypeLog, memFS.PathJoin("pri", name)))
+		_, err := memFS.Stat(memFS.PathJoin("pri", name))
+		require.True(t, os.IsNotExist(err))
+	}
+	// The first log was deleted to respect MaxSize.
+	require.Equal(t, []string{"wals/000002.log"}, archivedNames(a))
+	names, err := storage.List("", "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"wals/000002.log"}, names)
+	size, err := storage.Size("wals/000002.log")
+	require.NoError(t, err)
+	require.Equal(t, int64(100), size)
+
+	// A new Archiver picks up the archived logs.
+	a, err = NewArchiver(opts)
+	require.NoError(t, err)
+	require.Equal(t, []string{"wals/000002.log"}, archivedNames(a))
+
+	_, err = NewArchiver(ArchiverOptions{})
+	require.Error(t, err)
+}
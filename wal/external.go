@@ -0,0 +1,150 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package wal
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble/batchrepr"
+	"github.com/cockroachdb/pebble/internal/base"
+)
+
+// ExternalLog is a WAL backend provided by the user of Pebble, such as a
+// replicated (e.g. Raft) log or a network log service. When configured (see
+// Options.External), the records of every WAL are handed to the ExternalLog
+// instead of being written to log files, and the WALs are read back from it
+// during recovery.
+//
+// An ExternalLog holds a sequence of logs, each identified by its NumWAL, and
+// each containing a sequence of records.
+type ExternalLog interface {
+	// Create creates the log numbered wn, and returns a writer for it. The
+	// NumWALs passed to successive Create calls are monotonically increasing,
+	// and the writer of the previous log is closed before Create is called.
+	Create(wn NumWAL) (ExternalLogWriter, error)
+	// List returns the numbers of the logs held by the ExternalLog, in
+	// increasing order. It is only called during recovery.
+	List() ([]NumWAL, error)
+	// OpenForRead returns a reader for the records of the log numbered wn. It
+	// is called during recovery, and to copy a log into a checkpoint, in which
+	// case the log may be concurrently appended to.
+	OpenForRead(wn NumWAL) (ExternalLogReader, error)
+	// Truncate informs the ExternalLog that the logs with numbers less than
+	// minUnflushedNum are no longer needed, and can be discarded. Truncate is
+	// called while holding the DB mutex, so it must not block on I/O. Any
+	// discarding should happen asynchronously.
+	Truncate(minUnflushedNum NumWAL) error
+}
+
+// ExternalLogWriter appends records to a log held by an ExternalLog. Calls to
+// Append and Close are serialized.
+type ExternalLogWriter interface {
+	// Append appends a record to the log. The record is a batch (see the
+	// batchrepr package), and seqNum is the sequence number of its first
+	// operation.
+	//
+	// If done is non-nil, the ExternalLogWriter must call it exactly once,
+	// after the record and all the records appended before it are durable, or
+	// with the error that prevented durability. done may be called from any
+	// goroutine, including from within Append. The calls to done must be in
+	// the order of the corresponding Append calls.
+	//
+	// The ExternalLogWriter must not retain rec after Append returns. An error
+	// returned by Append is treated as fatal.
+	Append(rec []byte, seqNum base.SeqNum, done func(error)) error
+	// Close closes the writer, after the records that were appended are
+	// durable. All the done functions passed to Append must have been called
+	// before Close returns.
+	Close() error
+}
+
+// ExternalLogReader reads the records of a log held by an ExternalLog.
+type ExternalLogReader interface {
+	// Next returns the next record, or io.EOF if there are no more records. The
+	// returned slice is only valid until the next call to Next.
+	Next() ([]byte, error)
+	// Close closes the reader.
+	Close() error
+}
+
+// ScanExternal returns the logs held by the provided ExternalLog, in
+// increasing NumWAL order.
+func ScanExternal(el ExternalLog) (Logs, error) {
+	nums, err := el.List()
+	if err != nil {
+		return nil, errors.Wrap(err, "listing external WALs")
+	}
+	logs := make(Logs, len(nums))
+	for i, wn := range nums {
+		if i > 0 && wn <= nums[i-1] {
+			return nil, errors.Errorf("wal: external WALs are not in increasing order: %s, %s",
+				nums[i-1], wn)
+		}
+		logs[i] = LogicalLog{Num: wn, external: el}
+	}
+	return logs, nil
+}
+
+// externalReader implements Reader for a log held by an ExternalLog.
+type externalReader struct {
+	num NumWAL
+	r   ExternalLogReader
+	err error
+	// off is the sum of the lengths of the records returned so far.
+	off int64
+	buf bytes.Reader
+}
+
+var _ Reader = (*externalReader)(nil)
+
+func newExternalReader(el ExternalLog, num NumWAL) *externalReader {
+	r := &externalReader{num: num}
+	r.r, r.err = el.OpenForRead(num)
+	return r
+}
+
+// NextRecord implements Reader.
+func (r *externalReader) NextRecord() (io.Reader, Offset, error) {
+	rec, _, off, err := r.nextRecord()
+	return rec, off, err
+}
+
+// nextRecord returns the next record along with its batch header, like
+// virtualWALReader.nextRecord.
+func (r *externalReader) nextRecord() (io.Reader, batchrepr.Header, Offset, error) {
+	off := Offset{PhysicalFile: externalPath(r.num), Physical: r.off}
+	if r.err != nil {
+		return nil, batchrepr.Header{}, off, r.err
+	}
+	rec, err := r.r.Next()
+	if err != nil {
+		return nil, batchrepr.Header{}, off, err
+	}
+	h, ok := batchrepr.ReadHeader(rec)
+	if !ok {
+		return nil, batchrepr.Header{}, off, errors.Errorf("wal: external WAL %s contains a record of %d bytes, shorter than a batch header",
+			r.num, len(rec))
+	}
+	r.off += int64(len(rec))
+	r.buf.Reset(rec)
+	return &r.buf, h, off, nil
+}
+
+// Close implements Reader.
+func (r *externalReader) Close() error {
+	if r.r == nil {
+		return nil
+	}
+	return r.r.Close()
+}
+
+// externalPath returns the name used in place of a file path for a log held
+// by an ExternalLog.
+func externalPath(wn NumWAL) string {
+	return fmt.Sprintf("external:%s", wn)
+}
@@ -0,0 +1,256 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package wal
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/cockroachdb/crlib/crtime"
+	"github.com/cockroachdb/pebble/batchrepr"
+	"github.com/cockroachdb/pebble/internal/base"
+	"github.com/cockroachdb/pebble/record"
+)
+
+// externalManager implements Manager by handing the records of every WAL to
+// an ExternalLog (see Options.External).
+//
+// No log files are written. Log files found in the dirs at startup, written
+// before the ExternalLog was configured, are deleted when obsolete.
+type externalManager struct {
+	o Options
+	// recycler is never used for recycling, and is only present to implement
+	// RecyclerForTesting.
+	recycler LogRecycler
+	// initialObsolete holds the set of DeletableLogs for the log files passed
+	// into Init. The logs held by the ExternalLog have no DeletableLogs, and
+	// are discarded via ExternalLog.Truncate.
+	initialObsolete []DeletableLog
+
+	// External synchronization is relied on when accessing w in Manager.Create,
+	// Writer.{WriteRecord,Close}.
+	w *externalWriter
+
+	mu struct {
+		sync.Mutex
+		// The queue of WALs created by the manager, containing both flushed and
+		// unflushed WALs. The flushed logs are a prefix, the unflushed logs a
+		// suffix. If w != nil, the last entry here is that active WAL.
+		queue []base.FileInfo
+		// truncatedNum is the minUnflushedNum last passed to
+		// ExternalLog.Truncate.
+		truncatedNum NumWAL
+	}
+}
+
+var _ Manager = &externalManager{}
+
+// init implements Manager.
+func (m *externalManager) init(o Options, initial Logs) error {
+	*m = externalManager{o: o}
+	m.recycler.Init(0)
+	for _, ll := range initial {
+		var err error
+		m.initialObsolete, err = appendDeletableLogs(m.initialObsolete, ll)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List implements Manager.
+func (m *externalManager) List() Logs {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	wals := make(Logs, len(m.mu.queue))
+	for i := range m.mu.queue {
+		wals[i] = LogicalLog{Num: NumWAL(m.mu.queue[i].FileNum), external: m.o.External}
+	}
+	return wals
+}
+
+// Obsolete implements Manager.
+func (m *externalManager) Obsolete(
+	minUnflushedNum NumWAL, noRecycle bool,
+) (toDelete []DeletableLog, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.initialObsolete = slices.DeleteFunc(m.initialObsolete, func(dl DeletableLog) bool {
+		if dl.NumWAL >= minUnflushedNum {
+			return false
+		}
+		toDelete = append(toDelete, dl)
+		return true
+	})
+
+	i := 0
+	for ; i < len(m.mu.queue); i++ {
+		if NumWAL(m.mu.queue[i].FileNum) >= minUnflushedNum {
+			break
+		}
+	}
+	m.mu.queue = m.mu.queue[i:]
+	if minUnflushedNum > m.mu.truncatedNum {
+		if err := m.o.External.Truncate(minUnflushedNum); err != nil {
+			return toDelete, err
+		}
+		m.mu.truncatedNum = minUnflushedNum
+	}
+	return toDelete, nil
+}
+
+// Create implements Manager.
+func (m *externalManager) Create(wn NumWAL, jobID int) (Writer, error) {
+	createStart := crtime.NowMono()
+	ew, err := m.o.External.Create(wn)
+	if m.o.PrimaryFileOpHistogram != nil {
+		m.o.PrimaryFileOpHistogram.Observe(float64(createStart.Elapsed()))
+	}
+	if m.o.EventListener != nil {
+		m.o.EventListener.LogCreated(CreateInfo{
+			JobID: jobID,
+			Path:  externalPath(wn),
+			Num:   wn,
+			Err:   err,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	w := &externalWriter{m: m, ew: ew}
+	w.q.init(nil /* failoverWriteAndSyncLatency */)
+	m.w = w
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mu.queue = append(m.mu.queue, base.FileInfo{FileNum: base.DiskFileNum(wn)})
+	return w, nil
+}
+
+// ElevateWriteStallThresholdForFailover implements Manager.
+func (m *externalManager) ElevateWriteStallThresholdForFailover() bool {
+	return false
+}
+
+// Stats implements Manager.
+func (m *externalManager) Stats() Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var stats Stats
+	for _, fi := range m.mu.queue {
+		stats.LiveFileCount++
+		stats.LiveFileSize += fi.FileSize
+	}
+	for _, dl := range m.initialObsolete {
+		stats.ObsoleteFileCount++
+		stats.ObsoleteFileSize += dl.ApproxFileSize
+	}
+	return stats
+}
+
+// Close implements Manager.
+func (m *externalManager) Close() error {
+	if m.w != nil {
+		_, err := m.w.Close()
+		return err
+	}
+	return nil
+}
+
+// Opts implements Manager.
+func (m *externalManager) Opts() Options {
+	return m.o
+}
+
+// RecyclerForTesting implements Manager.
+func (m *externalManager) RecyclerForTesting() *LogRecycler {
+	return &m.recycler
+}
+
+// externalWriter implements Writer by appending records to an
+// ExternalLogWriter. The records are queued in a recordQueue until the
+// ExternalLogWriter confirms their durability.
+type externalWriter struct {
+	m  *externalManager
+	ew ExternalLogWriter
+	q  recordQueue
+
+	// Only accessed in WriteRecord and Close, which are externally
+	// synchronized.
+	logicalOffset int64
+	closed        bool
+	// metrics.WriteThroughput accounts for the time spent in Append.
+	metrics record.LogWriterMetrics
+}
+
+var _ Writer = &externalWriter{}
+
+// WriteRecord implements Writer.
+func (w *externalWriter) WriteRecord(
+	p []byte, opts SyncOptions, _ RefCount,
+) (logicalOffset int64, err error) {
+	var writeStart crtime.Mono
+	if opts.Done != nil {
+		writeStart = crtime.NowMono()
+	}
+	// The records are not retained by the queue, since the ExternalLogWriter
+	// does not retain them.
+	index, _, _ := w.q.push(nil, opts, nil, writeStart, 0, nil)
+	var done func(error)
+	if opts.Done != nil {
+		done = func(err error) {
+			w.doneSync(index, err)
+		}
+	}
+	var seqNum base.SeqNum
+	if h, ok := batchrepr.ReadHeader(p); ok {
+		seqNum = h.SeqNum
+	}
+	appendStart := crtime.NowMono()
+	err = w.ew.Append(p, seqNum, done)
+	w.metrics.WriteThroughput.WorkDuration += appendStart.Elapsed()
+	if err != nil {
+		return w.logicalOffset, err
+	}
+	w.metrics.WriteThroughput.Bytes += int64(len(p))
+	w.logicalOffset += int64(len(p))
+	return w.logicalOffset, nil
+}
+
+// doneSync is called when the record at index, and all the records before
+// it, are durable.
+func (w *externalWriter) doneSync(index uint32, err error) {
+	numSyncsPopped := w.q.pop(index, err)
+	if w.m.o.QueueSemChan != nil {
+		for range numSyncsPopped {
+			<-w.m.o.QueueSemChan
+		}
+	}
+}
+
+// Close implements Writer.
+func (w *externalWriter) Close() (logicalOffset int64, err error) {
+	if w.closed {
+		return w.logicalOffset, nil
+	}
+	w.closed = true
+	err = w.ew.Close()
+	// All the syncs have been acknowledged via doneSync, so this only pops
+	// records that did not request a sync.
+	w.q.popAll(err)
+
+	w.m.mu.Lock()
+	defer w.m.mu.Unlock()
+	i := len(w.m.mu.queue) - 1
+	w.m.mu.queue[i].FileSize = uint64(w.logicalOffset)
+	w.m.w = nil
+	return w.logicalOffset, err
+}
+
+// Metrics implements Writer.
+func (w *externalWriter) Metrics() record.LogWriterMetrics {
+	return w.metrics
+}
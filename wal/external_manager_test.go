@@ -0,0 +1,103 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package wal
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/cockroachdb/pebble/batchrepr"
+	"github.com/cockroachdb/pebble/internal/base"
+	"github.com/cockroachdb/pebble/internal/testutils"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalManager(t *testing.T) {
+	memFS := vfs.NewMem()
+	require.NoError(t, memFS.MkdirAll("pri", os.ModePerm))
+	// A log file written before the external log was configured.
+	f, err := memFS.Create("pri/000001.log", vfs.WriteCategoryUnspecified)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	initial, err := Scan(Dir{FS: memFS, Dirname: "pri"})
+	require.NoError(t, err)
+
+	el := NewInProcessLog()
+	queueSemChan := make(chan struct{}, 10)
+	m, err := Init(Options{
+		Primary:      Dir{FS: memFS, Dirname: "pri"},
+		External:     el,
+		QueueSemChan: queueSemChan,
+		Logger:       testutils.Logger{T: t},
+	}, initial)
+	require.NoError(t, err)
+
+	makeBatch := func(seqNum int) []byte {
+		repr := make([]byte, batchrepr.HeaderLen)
+		batchrepr.SetSeqNum(repr, base.SeqNum(seqNum))
+		batchrepr.SetCount(repr, 1)
+		return fmt.Appendf(repr, "record %d", seqNum)
+	}
+	for wn := NumWAL(2); wn <= 3; wn++ {
+		w, err := m.Create(wn, int(wn))
+		require.NoError(t, err)
+		var wg sync.WaitGroup
+		var syncErr error
+		for i := range 4 {
+			seqNum := 4*int(wn-2) + i + 1
+			opts := SyncOptions{}
+			if i%2 == 1 {
+				queueSemChan <- struct{}{}
+				wg.Add(1)
+				opts = SyncOptions{Done: &wg, Err: &syncErr}
+			}
+			_, err = w.WriteRecord(makeBatch(seqNum), opts, nil)
+			require.NoError(t, err)
+		}
+		wg.Wait()
+		require.NoError(t, syncErr)
+		// The semaphore is released for every acknowledged sync.
+		require.Len(t, queueSemChan, 0)
+		offset, err := w.Close()
+		require.NoError(t, err)
+		require.Greater(t, offset, int64(0))
+		require.Equal(t, 4, el.NumRecords(wn))
+	}
+	require.Equal(t, 2, m.Stats().LiveFileCount)
+	require.Equal(t, 1, m.Stats().ObsoleteFileCount)
+
+	// The logs are read back from the external log.
+	logs, err := ScanExternal(el)
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	require.Equal(t, "000002: {external}", logs[0].String())
+	r := logs[0].OpenForRead()
+	for i := range 4 {
+		rr, off, err := r.NextRecord()
+		require.NoError(t, err)
+		require.Equal(t, "external:000002", off.PhysicalFile)
+		b, err := io.ReadAll(rr)
+		require.NoError(t, err)
+		require.Equal(t, makeBatch(i+1), b)
+	}
+	_, _, err = r.NextRecord()
+	require.Equal(t, io.EOF, err)
+	require.NoError(t, r.Close())
+
+	// The obsolete log file is returned for deletion, and the obsolete
+	// external log is truncated.
+	toDelete, err := m.Obsolete(3, false /* noRecycle */)
+	require.NoError(t, err)
+	require.Len(t, toDelete, 1)
+	require.Equal(t, "pri/000001.log", toDelete[0].Path)
+	nums, err := el.List()
+	require.NoError(t, err)
+	require.Equal(t, []NumWAL{3}, nums)
+	require.NoError(t, m.Close())
+}
@@ -25,8 +25,9 @@ import (
 )
 
 // dirProber probes the primary dir, until it is confirmed to be healthy. If
-// it doesn't have enough samples, it is deemed to be unhealthy. It is only
-// used for failback to the primary.
+// it doesn't have enough samples, it is deemed to be unhealthy. It is used
+// for failback to the primary and, when there is more than one secondary, for
+// choosing the dir to fail over to.
 type dirProber struct {
 	fs vfs.FS
 	// The full path of the file to use for the probe. The probe is destructive
@@ -47,6 +48,9 @@ type dirProber struct {
 		// The history is in [firstProbeIndex, nextProbeIndex).
 		firstProbeIndex int
 		nextProbeIndex  int
+		// ongoingStart is the start time of the probe in progress, if any. It is
+		// used to avoid considering a dir with a stalled probe as healthy.
+		ongoingStart time.Time
 	}
 	iterationForTesting chan<- struct{}
 }
@@ -106,7 +110,7 @@ func readSecondaryIdentifier(fs vfs.FS, identifierFile string) (string, error) {
 
 // writeSecondaryIdentifier writes the identifier to the secondary directory.
 func writeSecondaryIdentifier(fs vfs.FS, identifierFile string, identifier string) error {
-	f, err := fs.Create(identifierFile, "pebble-wal")
+	f, err := fs.Create(identifierFile, vfs.WriteCategoryWAL)
 	if err != nil {
 		return err
 	}
@@ -183,8 +187,11 @@ func (p *dirProber) probeLoop() {
 			probeDur := func() time.Duration {
 				// Delete, create, write, sync.
 				start := p.timeSource.now()
+				p.mu.Lock()
+				p.mu.ongoingStart = start
+				p.mu.Unlock()
 				_ = p.fs.Remove(p.filename)
-				f, err := p.fs.Create(p.filename, "pebble-wal")
+				f, err := p.fs.Create(p.filename, vfs.WriteCategoryWAL)
 				if err != nil {
 					return failedProbeDuration
 				}
@@ -203,6 +210,7 @@ func (p *dirProber) probeLoop() {
 				return p.timeSource.now().Sub(start)
 			}()
 			p.mu.Lock()
+			p.mu.ongoingStart = time.Time{}
 			nextIndex := p.mu.nextProbeIndex % probeHistoryLength
 			p.mu.history[nextIndex] = probeDur
 			p.mu.nextProbeIndex++
@@ -269,12 +277,32 @@ func (p *dirProber) getMeanMax(interval time.Duration) (time.Duration, time.Dura
 	return mean, max
 }
 
+// latestProbe returns the duration of the latest probe. If a probe is in
+// progress and has taken longer than the latest completed probe, its elapsed
+// duration is returned instead. It returns false if there is no probe.
+func (p *dirProber) latestProbe() (time.Duration, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var dur time.Duration
+	ok := p.mu.nextProbeIndex > p.mu.firstProbeIndex
+	if ok {
+		dur = p.mu.history[(p.mu.nextProbeIndex-1)%probeHistoryLength]
+	}
+	if !p.mu.ongoingStart.IsZero() {
+		if ongoing := p.timeSource.now().Sub(p.mu.ongoingStart); !ok || ongoing > dur {
+			dur, ok = ongoing, true
+		}
+	}
+	return dur, ok
+}
+
+// dirIndex is an index into the dirs used by the failoverMonitor. The primary
+// is at index 0, followed by the secondaries in order of preference.
 type dirIndex int
 
 const (
 	primaryDirIndex dirIndex = iota
 	secondaryDirIndex
-	numDirIndices
 )
 
 type dirAndFileHandle struct {
@@ -289,8 +317,9 @@ type switchableWriter interface {
 }
 
 type failoverMonitorOptions struct {
-	// The primary and secondary dir.
-	dirs [numDirIndices]dirAndFileHandle
+	// The primary dir followed by the secondary dirs. There are at least two
+	// dirs.
+	dirs []dirAndFileHandle
 
 	FailoverOptions
 	stopper *stopper
@@ -300,9 +329,14 @@ type failoverMonitorOptions struct {
 // switchableWriter, and does failover by switching the dir. It also monitors
 // the primary dir for failback.
 type failoverMonitor struct {
-	opts   failoverMonitorOptions
+	opts failoverMonitorOptions
+	// prober probes the primary dir.
 	prober dirProber
-	mu     struct {
+	// secondaryProbers probe the secondary dirs, and are only used when there
+	// is more than one secondary, to choose the dir to fail over to. They are
+	// indexed by dirIndex, so the first element is nil.
+	secondaryProbers []*dirProber
+	mu               struct {
 		sync.Mutex
 		// dirIndex and lastFailbackTime are only modified by monitorLoop. They
 		// are protected by the mutex for concurrent reads.
@@ -332,6 +366,15 @@ func newFailoverMonitor(opts failoverMonitorOptions) *failoverMonitor {
 	m.prober.init(opts.dirs[primaryDirIndex].FS,
 		opts.dirs[primaryDirIndex].FS.PathJoin(opts.dirs[primaryDirIndex].Dirname, "probe-file"),
 		opts.PrimaryDirProbeInterval, opts.stopper, opts.timeSource, opts.proberIterationForTesting)
+	if len(opts.dirs) > 2 {
+		m.secondaryProbers = make([]*dirProber, len(opts.dirs))
+		for i := secondaryDirIndex; int(i) < len(opts.dirs); i++ {
+			dir := opts.dirs[i]
+			m.secondaryProbers[i] = &dirProber{}
+			m.secondaryProbers[i].init(dir.FS, dir.FS.PathJoin(dir.Dirname, "probe-file"),
+				opts.PrimaryDirProbeInterval, opts.stopper, opts.timeSource, nil)
+		}
+	}
 	opts.stopper.runAsync(func() {
 		m.monitorLoop(opts.stopper.shouldQuiesce())
 	})
@@ -361,7 +404,7 @@ func (m *failoverMonitor) newWriter(writerCreateFunc func(dir dirAndFileHandle)
 func (m *failoverMonitor) elevateWriteStallThresholdForFailover() bool {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	if m.mu.dirIndex == secondaryDirIndex {
+	if m.mu.dirIndex != primaryDirIndex {
 		return true
 	}
 	intervalSinceFailedback := m.opts.timeSource.now().Sub(m.mu.lastFailBackTime)
@@ -399,7 +442,86 @@ type lastWriterInfo struct {
 	writer                 switchableWriter
 	numSwitches            int
 	ongoingLatencyAtSwitch time.Duration
-	errorCounts            [numDirIndices]int
+	// errorCounts is indexed by dirIndex.
+	errorCounts []int
+}
+
+// Arbitrary value.
+const highSecondaryErrorCountThreshold = 2
+
+// proberFor returns the prober for the given dir, or nil if the dir is not
+// probed.
+func (m *failoverMonitor) proberFor(i dirIndex) *dirProber {
+	if i == primaryDirIndex {
+		return &m.prober
+	}
+	if m.secondaryProbers == nil {
+		return nil
+	}
+	return m.secondaryProbers[i]
+}
+
+// updateProbing enables or disables the probers given that the writer is
+// using dir cur. The primary is probed when it is not in use, for failback.
+// The secondaries are always probed, including while the primary is healthy,
+// so that a healthy dir can be chosen when the writer first fails over. They
+// are not disabled while in use since a prober only receives on its enabled
+// channel between probes, and a probe of a stalled secondary would block the
+// monitor. enabled holds the current state of each prober and is updated.
+func (m *failoverMonitor) updateProbing(cur dirIndex, enabled []bool) {
+	for i := range m.opts.dirs {
+		p := m.proberFor(dirIndex(i))
+		if p == nil {
+			continue
+		}
+		enable := dirIndex(i) != primaryDirIndex || cur != primaryDirIndex
+		if enable == enabled[i] {
+			continue
+		}
+		enabled[i] = enable
+		if enable {
+			p.enableProbing()
+		} else {
+			p.disableProbing()
+		}
+	}
+}
+
+// chooseFailoverDir returns the dir to switch to when the writer using dir
+// from is unhealthy. With a single secondary, the choice is the other dir.
+// Otherwise, it is the first dir in order of preference, excluding from and
+// dirs with high error counts, whose latest probe is healthy. If there is no
+// such dir, the dirs are tried cyclically, so the next dir after from is
+// returned.
+func (m *failoverMonitor) chooseFailoverDir(from dirIndex, errorCounts []int) dirIndex {
+	if len(m.opts.dirs) == 2 {
+		return 1 - from
+	}
+	for i := range m.opts.dirs {
+		d := dirIndex(i)
+		if d == from || errorCounts[d] >= highSecondaryErrorCountThreshold {
+			continue
+		}
+		p := m.proberFor(d)
+		if p == nil {
+			continue
+		}
+		if dur, ok := p.latestProbe(); ok && dur < m.opts.HealthyProbeLatencyThreshold {
+			return d
+		}
+	}
+	return dirIndex((int(from) + 1) % len(m.opts.dirs))
+}
+
+// secondariesHaveHighErrors returns true if all the secondaries have high
+// error counts.
+func secondariesHaveHighErrors(errorCounts []int) bool {
+	for _, c := range errorCounts[secondaryDirIndex:] {
+		if c < highSecondaryErrorCountThreshold {
+			return false
+		}
+	}
+	return true
 }
 
 func (m *failoverMonitor) monitorLoop(shouldQuiesce <-chan struct{}) {
@@ -409,19 +531,29 @@ func (m *failoverMonitor) monitorLoop(shouldQuiesce <-chan struct{}) {
 	}
 	tickerCh := ticker.ch()
 	dirIndex := primaryDirIndex
-	var lastWriter lastWriterInfo
+	lastWriter := lastWriterInfo{errorCounts: make([]int, len(m.opts.dirs))}
+	probingEnabled := make([]bool, len(m.opts.dirs))
+	m.updateProbing(dirIndex, probingEnabled)
 	for {
 		select {
 		case <-shouldQuiesce:
 			ticker.stop()
 			m.prober.stop()
+			for _, p := range m.secondaryProbers {
+				if p != nil {
+					p.stop()
+				}
+			}
 			return
 		case <-tickerCh:
 			writerOngoingLatency, writerErr := func() (time.Duration, error) {
 				m.mu.Lock()
 				defer m.mu.Unlock()
 				if m.mu.writer != lastWriter.writer {
-					lastWriter = lastWriterInfo{writer: m.mu.writer}
+					lastWriter = lastWriterInfo{
+						writer:      m.mu.writer,
+						errorCounts: make([]int, len(m.opts.dirs)),
+					}
 				}
 				if lastWriter.writer == nil {
 					return 0, nil
@@ -429,16 +561,14 @@ func (m *failoverMonitor) monitorLoop(shouldQuiesce <-chan struct{}) {
 				return lastWriter.writer.ongoingLatencyOrErrorForCurDir()
 			}()
 			switchDir := false
-			// Arbitrary value.
-			const highSecondaryErrorCountThreshold = 2
 			// We don't consider a switch if currently using the primary dir and the
-			// secondary dir has high enough errors. It is more likely that someone
+			// secondary dirs have high enough errors. It is more likely that someone
 			// has misconfigured a secondary e.g. wrong permissions or not enough
 			// disk space. We only remember the error history in the context of the
 			// lastWriter since an operator can fix the underlying misconfiguration.
 			unhealthyThreshold, failoverEnabled := m.opts.UnhealthyOperationLatencyThreshold()
 
-			if !(lastWriter.errorCounts[secondaryDirIndex] >= highSecondaryErrorCountThreshold &&
+			if !(secondariesHaveHighErrors(lastWriter.errorCounts) &&
 				dirIndex == primaryDirIndex) && failoverEnabled {
 				// Switching heuristics. Subject to change based on real world experience.
 				if writerErr != nil {
@@ -460,8 +590,8 @@ func (m *failoverMonitor) monitorLoop(shouldQuiesce <-chan struct{}) {
 						lastWriter.ongoingLatencyAtSwitch = writerOngoingLatency
 					}
 					// Else high latency, but not high enough yet to motivate switch.
-				} else if dirIndex == secondaryDirIndex {
-					// The writer looks healthy. We can still switch if the writer is using the
+				} else if dirIndex != primaryDirIndex {
+					// The writer looks healthy. We can still switch if the writer is using a
 					// secondary dir and the primary is healthy again.
 					primaryMean, primaryMax := m.prober.getMeanMax(m.opts.HealthyInterval)
 					if primaryMean < m.opts.HealthyProbeLatencyThreshold &&
@@ -472,15 +602,16 @@ func (m *failoverMonitor) monitorLoop(shouldQuiesce <-chan struct{}) {
 			}
 			if switchDir {
 				lastWriter.numSwitches++
-				if dirIndex == secondaryDirIndex {
-					// Switching back to primary, so don't need to probe to see if
-					// primary is healthy.
-					m.prober.disableProbing()
+				if dirIndex != primaryDirIndex && writerErr == nil &&
+					writerOngoingLatency <= unhealthyThreshold {
+					// Failing back to the primary since it is healthy again.
 					dirIndex = primaryDirIndex
 				} else {
-					m.prober.enableProbing()
-					dirIndex = secondaryDirIndex
+					dirIndex = m.chooseFailoverDir(dirIndex, lastWriter.errorCounts)
 				}
+				// When switching back to primary, we don't need to probe to see if
+				// the primary is healthy.
+				m.updateProbing(dirIndex, probingEnabled)
 				dir := m.opts.dirs[dirIndex]
 				m.mu.Lock()
 				now := m.opts.timeSource.now()
@@ -532,7 +663,7 @@ type failoverManager struct {
 
 	// TODO(jackson/sumeer): read-path etc.
 
-	dirHandles [numDirIndices]vfs.File
+	dirHandles []vfs.File
 	stopper    *stopper
 	monitor    *failoverMonitor
 	mu         struct {
@@ -560,29 +691,30 @@ func (wm *failoverManager) init(o Options, initial Logs) error {
 	}
 	o.FailoverOptions.EnsureDefaults()
 
-	// Synchronously ensure that we're able to write to the secondary before we
-	// proceed. An operator doesn't want to encounter an issue writing to the
+	// Synchronously ensure that we're able to write to the secondaries before
+	// we proceed. An operator doesn't want to encounter an issue writing to a
 	// secondary the first time there's a need to failover. We write a bit of
-	// metadata to a file in the secondary's directory.
-	f, err := o.Secondary.FS.Create(o.Secondary.FS.PathJoin(o.Secondary.Dirname, "failover_source"), "pebble-wal")
-	if err != nil {
-		return errors.Newf("failed to write to WAL secondary dir: %v", err)
-	}
-	if _, err := io.WriteString(f, fmt.Sprintf("primary: %s\nprocess start: %s\n",
-		o.Primary.Dirname,
-		time.Now(),
-	)); err != nil {
-		return errors.Newf("failed to write metadata to WAL secondary dir: %v", err)
-	}
-	if err := errors.CombineErrors(f.Sync(), f.Close()); err != nil {
-		return err
+	// metadata to a file in each secondary's directory.
+	for _, secondary := range append([]Dir{o.Secondary}, o.AdditionalSecondaries...) {
+		f, err := secondary.FS.Create(secondary.FS.PathJoin(secondary.Dirname, "failover_source"), vfs.WriteCategoryWAL)
+		if err != nil {
+			return errors.Newf("failed to write to WAL secondary dir: %v", err)
+		}
+		if _, err := io.WriteString(f, fmt.Sprintf("primary: %s\nprocess start: %s\n",
+			o.Primary.Dirname,
+			time.Now(),
+		)); err != nil {
+			return errors.Newf("failed to write metadata to WAL secondary dir: %v", err)
+		}
+		if err := errors.CombineErrors(f.Sync(), f.Close()); err != nil {
+			return err
+		}
 	}
 
 	stopper := newStopper()
-	var dirs [numDirIndices]dirAndFileHandle
-	for i, dir := range []Dir{o.Primary, o.Secondary} {
-		dirs[i].Dir = dir
-
+	dirs := make([]dirAndFileHandle, 0, 2+len(o.AdditionalSecondaries))
+	dirHandles := make([]vfs.File, 0, cap(dirs))
+	for i, dir := range o.Dirs() {
 		// measure directory open operation
 		openStart := crtime.NowMono()
 		f, err := dir.FS.OpenDir(dir.Dirname)
@@ -592,11 +724,12 @@ func (wm *failoverManager) init(o Options, initial Logs) error {
 		openLatency := openStart.Elapsed()
 		if dirIndex(i) == primaryDirIndex && o.PrimaryFileOpHistogram != nil {
 			o.PrimaryFileOpHistogram.Observe(float64(openLatency))
-		} else if dirIndex(i) == secondaryDirIndex && o.SecondaryFileOpHistogram != nil {
+		} else if dirIndex(i) != primaryDirIndex && o.SecondaryFileOpHistogram != nil {
 			o.SecondaryFileOpHistogram.Observe(float64(openLatency))
 		}
 
-		dirs[i].File = f
+		dirs = append(dirs, dirAndFileHandle{Dir: dir, File: f})
+		dirHandles = append(dirHandles, f)
 	}
 	fmOpts := failoverMonitorOptions{
 		dirs:            dirs,
@@ -606,7 +739,7 @@ func (wm *failoverManager) init(o Options, initial Logs) error {
 	monitor := newFailoverMonitor(fmOpts)
 	*wm = failoverManager{
 		opts:       o,
-		dirHandles: [numDirIndices]vfs.File{dirs[primaryDirIndex].File, dirs[secondaryDirIndex].File},
+		dirHandles: dirHandles,
 		stopper:    stopper,
 		monitor:    monitor,
 	}
@@ -760,7 +893,7 @@ func (wm *failoverManager) Create(wn NumWAL, jobID int) (Writer, error) {
 		preallocateSize:             wm.opts.PreallocateSize,
 		minSyncInterval:             wm.opts.MinSyncInterval,
 		primaryDir:                  wm.opts.Primary,
-		secondaryDir:                wm.opts.Secondary,
+		secondaryDirs:               wm.opts.Dirs()[secondaryDirIndex:],
 		primaryFileOpHistogram:      wm.opts.PrimaryFileOpHistogram,
 		secondaryFileOpHistogram:    wm.opts.SecondaryFileOpHistogram,
 		queueSemChan:                wm.opts.QueueSemChan,
@@ -928,7 +1061,7 @@ func (wm *failoverManager) logCreator(
 			recycleLogName := dir.FS.PathJoin(dir.Dirname, makeLogFilename(NumWAL(recycleLog.FileNum), 0))
 			r.writeStart()
 			reuseStart := crtime.NowMono()
-			logFile, err = dir.FS.ReuseForWrite(recycleLogName, logFilename, "pebble-wal")
+			logFile, err = dir.FS.ReuseForWrite(recycleLogName, logFilename, vfs.WriteCategoryWAL)
 			reuseLatency := reuseStart.Elapsed()
 			if isPrimary && wm.opts.PrimaryFileOpHistogram != nil {
 				wm.opts.PrimaryFileOpHistogram.Observe(float64(reuseLatency))
@@ -977,7 +1110,7 @@ func (wm *failoverManager) logCreator(
 	// Create file.
 	r.writeStart()
 	createStart := crtime.NowMono()
-	logFile, err = dir.FS.Create(logFilename, "pebble-wal")
+	logFile, err = dir.FS.Create(logFilename, vfs.WriteCategoryWAL)
 	createLatency := createStart.Elapsed()
 	if isPrimary && wm.opts.PrimaryFileOpHistogram != nil {
 		wm.opts.PrimaryFileOpHistogram.Observe(float64(createLatency))
@@ -302,7 +302,7 @@ func TestManagerFailover(t *testing.T) {
 	var fm *failoverManager
 	var fw *failoverWriter
 	var allowFailover bool
-	dirs := [numDirIndices]string{"pri", "sec"}
+	dirs := [2]string{"pri", "sec"}
 	datadriven.RunTest(t, "testdata/manager_failover",
 		func(t *testing.T, td *datadriven.TestData) string {
 			switch td.Cmd {
@@ -620,6 +620,165 @@ func TestFailoverManager_SecondaryIsWritable(t *testing.T) {
 	}, nil /* initial  logs */), "failed to write to WAL secondary dir: injected error")
 }
 
+func TestFailoverMonitor_ChooseFailoverDir(t *testing.T) {
+	ts := newManualTime(time.UnixMilli(0))
+	m := &failoverMonitor{
+		opts: failoverMonitorOptions{
+			dirs: make([]dirAndFileHandle, 4),
+			FailoverOptions: FailoverOptions{
+				HealthyProbeLatencyThreshold: 25 * time.Millisecond,
+			},
+		},
+		secondaryProbers: []*dirProber{nil, {}, {}, {}},
+	}
+	// setSamples sets the probe history of each dir.
+	setSamples := func(samples ...[]time.Duration) {
+		for i := range samples {
+			p := m.proberFor(dirIndex(i))
+			p.timeSource = ts
+			p.interval = time.Millisecond
+			p.mu.firstProbeIndex = 0
+			p.mu.nextProbeIndex = 0
+			for _, s := range samples[i] {
+				p.mu.history[p.mu.nextProbeIndex] = s
+				p.mu.nextProbeIndex++
+			}
+		}
+	}
+	ms := time.Millisecond
+	errorCounts := make([]int, 4)
+
+	// No probe samples, so the dirs are tried cyclically.
+	setSamples(nil, nil, nil, nil)
+	require.Equal(t, dirIndex(1), m.chooseFailoverDir(0, errorCounts))
+	require.Equal(t, dirIndex(2), m.chooseFailoverDir(1, errorCounts))
+	require.Equal(t, dirIndex(0), m.chooseFailoverDir(3, errorCounts))
+
+	// The first healthy dir in order of preference is chosen, based on the
+	// latest sample.
+	setSamples([]time.Duration{ms, time.Second}, nil,
+		[]time.Duration{time.Second, ms}, []time.Duration{ms})
+	require.Equal(t, dirIndex(2), m.chooseFailoverDir(1, errorCounts))
+	require.Equal(t, dirIndex(3), m.chooseFailoverDir(2, errorCounts))
+
+	// Dirs with high error counts are not chosen, unless tried cyclically.
+	errorCounts[2] = highSecondaryErrorCountThreshold
+	require.Equal(t, dirIndex(3), m.chooseFailoverDir(1, errorCounts))
+	errorCounts[3] = highSecondaryErrorCountThreshold
+	require.Equal(t, dirIndex(2), m.chooseFailoverDir(1, errorCounts))
+	errorCounts[2], errorCounts[3] = 0, 0
+
+	// An ongoing probe that is taking long makes the dir unhealthy.
+	m.secondaryProbers[2].mu.ongoingStart = ts.now()
+	ts.advance(time.Second)
+	require.Equal(t, dirIndex(3), m.chooseFailoverDir(1, errorCounts))
+
+	// With a single secondary, the other dir is chosen.
+	m.opts.dirs = m.opts.dirs[:2]
+	require.Equal(t, dirIndex(1), m.chooseFailoverDir(0, errorCounts))
+	require.Equal(t, dirIndex(0), m.chooseFailoverDir(1, errorCounts))
+}
+
+func TestFailoverMonitor_UpdateProbing(t *testing.T) {
+	m := &failoverMonitor{
+		opts:             failoverMonitorOptions{dirs: make([]dirAndFileHandle, 3)},
+		secondaryProbers: []*dirProber{nil, {}, {}},
+	}
+	for i := range m.opts.dirs {
+		m.proberFor(dirIndex(i)).enabled = make(chan bool, 1)
+	}
+	enabled := make([]bool, len(m.opts.dirs))
+	update := func(cur dirIndex) []bool {
+		m.updateProbing(cur, enabled)
+		// Check that the probers were told about the changes.
+		for i := range m.opts.dirs {
+			select {
+			case e := <-m.proberFor(dirIndex(i)).enabled:
+				require.Equal(t, enabled[i], e)
+			default:
+			}
+		}
+		return slices.Clone(enabled)
+	}
+	// The secondaries are probed while the primary is in use, so that there
+	// are probe samples when the writer first fails over.
+	require.Equal(t, []bool{false, true, true}, update(primaryDirIndex))
+	require.Equal(t, []bool{true, true, true}, update(2))
+	require.Equal(t, []bool{true, true, true}, update(1))
+	require.Equal(t, []bool{false, true, true}, update(primaryDirIndex))
+}
+
+// TestFailoverManager_AdditionalSecondaries tests that writes proceed when
+// both the primary and the first secondary are stalled, by failing over to an
+// additional secondary.
+func TestFailoverManager_AdditionalSecondaries(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	memFS := vfs.NewMem()
+	for _, dir := range []string{"pri", "sec", "sec2"} {
+		require.NoError(t, memFS.MkdirAll(dir, os.ModePerm))
+	}
+	priHang := errorfs.NewHang("pri", errorfs.PathMatch("pri/*"))
+	secHang := errorfs.NewHang("sec", errorfs.PathMatch("sec/*"))
+	// Initialization writes to the secondaries, so only stall after it.
+	priHang.Release()
+	secHang.Release()
+	fs := errorfs.Wrap(memFS, errorfs.Any(priHang, secHang))
+
+	var m failoverManager
+	require.NoError(t, m.init(Options{
+		Primary:   Dir{FS: fs, Dirname: "pri"},
+		Secondary: Dir{FS: fs, Dirname: "sec"},
+		AdditionalSecondaries: []Dir{
+			{FS: fs, Dirname: "sec2"},
+		},
+		Logger:               testutils.Logger{T: t},
+		MaxNumRecyclableLogs: 0,
+		PreallocateSize:      func() int { return 4 },
+		FailoverOptions: FailoverOptions{
+			PrimaryDirProbeInterval:            250 * time.Microsecond,
+			HealthyProbeLatencyThreshold:       time.Millisecond,
+			HealthyInterval:                    3 * time.Millisecond,
+			UnhealthySamplingInterval:          250 * time.Microsecond,
+			UnhealthyOperationLatencyThreshold: func() (time.Duration, bool) { return 5 * time.Millisecond, true },
+		},
+		FailoverWriteAndSyncLatency: prometheus.NewHistogram(prometheus.HistogramOpts{}),
+		WriteWALSyncOffsets:         func() bool { return false },
+	}, nil /* initial  logs */))
+	defer func() {
+		priHang.Release()
+		secHang.Release()
+		require.NoError(t, m.Close())
+	}()
+
+	priHang.Rearm()
+	secHang.Rearm()
+	w, err := m.Create(NumWAL(1), 1)
+	require.NoError(t, err)
+	var wg sync.WaitGroup
+	var syncErr error
+	wg.Add(1)
+	_, err = w.WriteRecord([]byte("hello world"), SyncOptions{Done: &wg, Err: &syncErr}, nil)
+	require.NoError(t, err)
+	// The sync completes even though the primary and secondary are stalled.
+	// Since the secondaries are probed while the primary is in use, the writer
+	// usually fails over straight to the additional secondary.
+	wg.Wait()
+	require.NoError(t, syncErr)
+	require.GreaterOrEqual(t, m.Stats().Failover.DirSwitchCount, int64(1))
+	ls, err := memFS.List("sec2")
+	require.NoError(t, err)
+	require.True(t, slices.ContainsFunc(ls, func(name string) bool {
+		// Log segments after the first are named 000001-<index>.log.
+		return strings.HasPrefix(name, "000001-")
+	}), "no log segment in sec2: %v", ls)
+
+	priHang.Release()
+	secHang.Release()
+	_, err = w.Close()
+	require.NoError(t, err)
+}
+
 // TODO(sumeer): test wrap around of history in dirProber.
 
 // TODO(sumeer): the failover datadriven test cases are not easy to write,
@@ -6,6 +6,7 @@ package wal
 
 import (
 	"io"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -287,7 +288,7 @@ func (q *recordQueue) pop(index uint32, err error) (numSyncsPopped int) {
 			}
 		}
 	}
-	if addLatencySample {
+	if addLatencySample && q.failoverWriteAndSyncLatency != nil {
 		if maxLatency < 0 {
 			maxLatency = 0
 		}
@@ -458,7 +459,7 @@ type failoverWriterOpts struct {
 
 	// WAL file operation latency histograms
 	primaryDir               Dir
-	secondaryDir             Dir
+	secondaryDirs            []Dir
 	primaryFileOpHistogram   record.WALFileOpHistogram
 	secondaryFileOpHistogram record.WALFileOpHistogram
 
@@ -498,7 +499,7 @@ func simpleLogCreator(
 	filename := dir.FS.PathJoin(dir.Dirname, makeLogFilename(wn, li))
 	// Create file.
 	r.writeStart()
-	f, err = dir.FS.Create(filename, "pebble-wal")
+	f, err = dir.FS.Create(filename, vfs.WriteCategoryWAL)
 	r.writeEnd(err)
 	return f, 0, err
 }
@@ -656,12 +657,12 @@ func (ww *failoverWriter) switchToNewDir(dir dirAndFileHandle) error {
 		// happens when n as a whole is obsolete).
 
 		// Determine which directory we're using and select the appropriate
-		// histogram. dir.Dir is always either the primary or secondary directory.
+		// histogram. dir.Dir is always either the primary or a secondary directory.
 		var histogram record.WALFileOpHistogram
 		if dir.Dir == ww.opts.primaryDir {
 			histogram = ww.opts.primaryFileOpHistogram
 		} else {
-			if dir.Dir != ww.opts.secondaryDir {
+			if !slices.Contains(ww.opts.secondaryDirs, dir.Dir) {
 				panic(errors.AssertionFailedf(
 					"dir %q matches neither primary nor secondary", dir.Dir.Dirname))
 			}
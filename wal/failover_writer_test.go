@@ -42,11 +42,11 @@ func TestFailoverWriter(t *testing.T) {
 
 	datadriven.Walk(t, "testdata/failover_writer", func(t *testing.T, path string) {
 		memFS := vfs.NewCrashableMem()
-		dirs := [numDirIndices]dirAndFileHandle{
+		dirs := [2]dirAndFileHandle{
 			{Dir: Dir{Dirname: "pri"}},
 			{Dir: Dir{Dirname: "sec"}},
 		}
-		var testDirs [numDirIndices]dirAndFileHandle
+		var testDirs [2]dirAndFileHandle
 		for i, dir := range dirs {
 			require.NoError(t, memFS.MkdirAll(dir.Dirname, 0755))
 			f, err := memFS.OpenDir("")
@@ -55,7 +55,7 @@ func TestFailoverWriter(t *testing.T) {
 			require.NoError(t, f.Close())
 			testDirs[i].Dir = dir.Dir
 		}
-		setDirsFunc := func(t *testing.T, fs vfs.FS, dirs *[numDirIndices]dirAndFileHandle) {
+		setDirsFunc := func(t *testing.T, fs vfs.FS, dirs *[2]dirAndFileHandle) {
 			for i := range *dirs {
 				f := (*dirs)[i].File
 				if f != nil {
@@ -282,7 +282,7 @@ func TestFailoverWriter(t *testing.T) {
 					w, err = newFailoverWriter(failoverWriterOpts{
 						wn:                          wn,
 						primaryDir:                  testDirs[primaryDirIndex].Dir,
-						secondaryDir:                testDirs[secondaryDirIndex].Dir,
+						secondaryDirs:               []Dir{testDirs[secondaryDirIndex].Dir},
 						timeSource:                  defaultTime{},
 						logCreator:                  testLogCreator,
 						preallocateSize:             func() int { return 0 },
@@ -627,7 +627,7 @@ func TestConcurrentWritersWithManyRecords(t *testing.T) {
 	}
 	const numLogWriters = 4
 	memFS := vfs.NewCrashableMem()
-	dirs := [numDirIndices]dirAndFileHandle{{Dir: Dir{Dirname: "pri"}}, {Dir: Dir{Dirname: "sec"}}}
+	dirs := [2]dirAndFileHandle{{Dir: Dir{Dirname: "pri"}}, {Dir: Dir{Dirname: "sec"}}}
 	for _, dir := range dirs {
 		require.NoError(t, memFS.MkdirAll(dir.Dirname, 0755))
 		f, err := memFS.OpenDir("")
@@ -652,7 +652,7 @@ func TestConcurrentWritersWithManyRecords(t *testing.T) {
 	ww, err := newFailoverWriter(failoverWriterOpts{
 		wn:                          0,
 		primaryDir:                  dirs[primaryDirIndex].Dir,
-		secondaryDir:                dirs[secondaryDirIndex].Dir,
+		secondaryDirs:               []Dir{dirs[secondaryDirIndex].Dir},
 		timeSource:                  defaultTime{},
 		logCreator:                  simpleLogCreator,
 		preallocateSize:             func() int { return 0 },
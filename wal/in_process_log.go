@@ -0,0 +1,155 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package wal
+
+import (
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble/internal/base"
+	"github.com/cockroachdb/pebble/record"
+)
+
+// InProcessLog is an ExternalLog that holds the logs in memory. It stands in
+// for a real ExternalLog in tests. Like a real ExternalLog, it confirms the
+// durability of appended records asynchronously.
+//
+// The logs outlive the DB using the InProcessLog, so an InProcessLog can be
+// passed to successive Opens of a DB to exercise recovery.
+type InProcessLog struct {
+	mu struct {
+		sync.Mutex
+		logs map[NumWAL][][]byte
+	}
+}
+
+var _ ExternalLog = (*InProcessLog)(nil)
+
+// NewInProcessLog returns a new, empty InProcessLog.
+func NewInProcessLog() *InProcessLog {
+	l := &InProcessLog{}
+	l.mu.logs = make(map[NumWAL][][]byte)
+	return l
+}
+
+// Create implements ExternalLog.
+func (l *InProcessLog) Create(wn NumWAL) (ExternalLogWriter, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.mu.logs[wn]; ok {
+		return nil, errors.Errorf("wal: external WAL %s already exists", wn)
+	}
+	l.mu.logs[wn] = nil
+	w := &inProcessLogWriter{
+		l:     l,
+		wn:    wn,
+		dones: make(chan func(error), record.SyncConcurrency),
+	}
+	w.wg.Add(1)
+	go w.confirmLoop()
+	return w, nil
+}
+
+// List implements ExternalLog.
+func (l *InProcessLog) List() ([]NumWAL, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	nums := make([]NumWAL, 0, len(l.mu.logs))
+	for wn := range l.mu.logs {
+		nums = append(nums, wn)
+	}
+	slices.Sort(nums)
+	return nums, nil
+}
+
+// OpenForRead implements ExternalLog.
+func (l *InProcessLog) OpenForRead(wn NumWAL) (ExternalLogReader, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	recs, ok := l.mu.logs[wn]
+	if !ok {
+		return nil, errors.Errorf("wal: external WAL %s does not exist", wn)
+	}
+	return &inProcessLogReader{recs: slices.Clip(recs)}, nil
+}
+
+// Truncate implements ExternalLog.
+func (l *InProcessLog) Truncate(minUnflushedNum NumWAL) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for wn := range l.mu.logs {
+		if wn < minUnflushedNum {
+			delete(l.mu.logs, wn)
+		}
+	}
+	return nil
+}
+
+// NumRecords returns the number of records in the log numbered wn, or zero if
+// there is no such log.
+func (l *InProcessLog) NumRecords(wn NumWAL) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.mu.logs[wn])
+}
+
+// inProcessLogWriter implements ExternalLogWriter for an InProcessLog.
+type inProcessLogWriter struct {
+	l  *InProcessLog
+	wn NumWAL
+	// dones holds the done functions of appended records, which are called by
+	// confirmLoop in the order of the Append calls.
+	dones chan func(error)
+	wg    sync.WaitGroup
+}
+
+// Append implements ExternalLogWriter.
+func (w *inProcessLogWriter) Append(rec []byte, _ base.SeqNum, done func(error)) error {
+	w.l.mu.Lock()
+	w.l.mu.logs[w.wn] = append(w.l.mu.logs[w.wn], slices.Clone(rec))
+	w.l.mu.Unlock()
+	if done != nil {
+		w.dones <- done
+	}
+	return nil
+}
+
+// confirmLoop confirms the durability of appended records until the writer is
+// closed.
+func (w *inProcessLogWriter) confirmLoop() {
+	defer w.wg.Done()
+	for done := range w.dones {
+		done(nil)
+	}
+}
+
+// Close implements ExternalLogWriter.
+func (w *inProcessLogWriter) Close() error {
+	close(w.dones)
+	w.wg.Wait()
+	return nil
+}
+
+// inProcessLogReader implements ExternalLogReader for an InProcessLog.
+type inProcessLogReader struct {
+	recs [][]byte
+}
+
+// Next implements ExternalLogReader.
+func (r *inProcessLogReader) Next() ([]byte, error) {
+	if len(r.recs) == 0 {
+		return nil, io.EOF
+	}
+	rec := r.recs[0]
+	r.recs = r.recs[1:]
+	return rec, nil
+}
+
+// Close implements ExternalLogReader.
+func (r *inProcessLogReader) Close() error {
+	return nil
+}
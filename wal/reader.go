@@ -25,11 +25,16 @@ type LogicalLog struct {
 	// make up the single logical WAL file. segments is ordered by increasing
 	// logIndex.
 	segments []segment
+	// external is set if the WAL is held by an ExternalLog, in which case
+	// there are no segments.
+	external ExternalLog
 }
 
 // A segment represents an individual physical file that makes up a contiguous
 // segment of a logical WAL. If a failover occurred during a WAL's lifetime, a
-// WAL may be composed of multiple segments.
+// WAL may be composed of multiple segments. If the WAL was replicated, there
+// are multiple segments with the same logNameIndex in different dirs, each a
+// copy of the same records.
 type segment struct {
 	logNameIndex LogNameIndex
 	dir          Dir
@@ -72,6 +77,9 @@ func (ll LogicalLog) PhysicalSize() (uint64, error) {
 
 // OpenForRead a logical WAL for reading.
 func (ll LogicalLog) OpenForRead() Reader {
+	if ll.external != nil {
+		return newExternalReader(ll.external, ll.Num)
+	}
 	return newVirtualWALReader(ll)
 }
 
@@ -83,6 +91,9 @@ func (ll LogicalLog) String() string {
 // SafeFormat implements redact.SafeFormatter.
 func (ll LogicalLog) SafeFormat(w redact.SafePrinter, _ rune) {
 	w.Printf("%s: {", base.DiskFileNum(ll.Num).String())
+	if ll.external != nil {
+		w.SafeString("external")
+	}
 	for i := range ll.segments {
 		if i > 0 {
 			w.SafeString(", ")
@@ -119,24 +130,40 @@ func appendDeletableLogs(dst []DeletableLog, ll LogicalLog) ([]DeletableLog, err
 // ordered list of WALs in increasing NumWAL order.
 func Scan(dirs ...Dir) (Logs, error) {
 	var fa FileAccumulator
+	return fa.scan(dirs)
+}
+
+// ScanWithReplica is like Scan, but also finds the log files in the replica
+// dir of a replicated WAL (see Options.Replica). A log file in the replica dir
+// may have the same name as a log file in one of the other dirs, in which case
+// it is a replica of that file.
+func ScanWithReplica(replica Dir, dirs ...Dir) (Logs, error) {
+	fa := FileAccumulator{replica: replica}
+	return fa.scan(append(slices.Clip(dirs), replica))
+}
+
+func (a *FileAccumulator) scan(dirs []Dir) (Logs, error) {
 	for _, d := range dirs {
 		ls, err := d.FS.List(d.Dirname)
 		if err != nil {
 			return nil, errors.Wrapf(err, "reading %q", d.Dirname)
 		}
 		for _, name := range ls {
-			_, err := fa.maybeAccumulate(d.FS, d.Dirname, name)
+			_, err := a.maybeAccumulate(d.FS, d.Dirname, name)
 			if err != nil {
 				return nil, err
 			}
 		}
 	}
-	return fa.wals, nil
+	return a.wals, nil
 }
 
 // FileAccumulator parses and accumulates log files.
 type FileAccumulator struct {
 	wals []LogicalLog
+	// replica, if set, is the replica dir of a replicated WAL (see
+	// ScanWithReplica).
+	replica Dir
 }
 
 // MaybeAccumulate parses the provided path's filename. If the filename
@@ -171,14 +198,17 @@ func (a *FileAccumulator) maybeAccumulate(
 	if !found {
 		a.wals = slices.Insert(a.wals, i, LogicalLog{Num: dfn, segments: make([]segment, 0, 1)})
 	}
-	// Ensure we haven't seen this log index yet, and find where it
-	// slots within this log's segments.
+	// Ensure we haven't seen this log index yet (unless one of the files is in
+	// the replica dir), and find where it slots within this log's segments,
+	// after any replicas.
 	j, found := slices.BinarySearchFunc(a.wals[i].segments, li, func(s segment, li LogNameIndex) int {
 		return cmp.Compare(s.logNameIndex, li)
 	})
-	if found {
-		return false, errors.Errorf("wal: duplicate logIndex=%s for WAL %s in %s and %s",
-			li, dfn, dirname, a.wals[i].segments[j].dir.Dirname)
+	for ; found && j < len(a.wals[i].segments) && a.wals[i].segments[j].logNameIndex == li; j++ {
+		if d := a.wals[i].segments[j].dir; a.isReplica(d.FS, d.Dirname) == a.isReplica(fs, dirname) {
+			return false, errors.Errorf("wal: duplicate logIndex=%s for WAL %s in %s and %s",
+				li, dfn, dirname, d.Dirname)
+		}
 	}
 	a.wals[i].segments = slices.Insert(a.wals[i].segments, j, segment{logNameIndex: li, dir: Dir{
 		FS:      fs,
@@ -187,6 +217,11 @@ func (a *FileAccumulator) maybeAccumulate(
 	return true, nil
 }
 
+// isReplica returns true if the given dir is the replica dir.
+func (a *FileAccumulator) isReplica(fs vfs.FS, dirname string) bool {
+	return a.replica != (Dir{}) && a.replica.FS == fs && a.replica.Dirname == dirname
+}
+
 // Logs holds a collection of WAL files, in increasing order of NumWAL.
 type Logs []LogicalLog
 
@@ -225,6 +260,11 @@ type virtualWALReader struct {
 	currReader *record.Reader
 	// off describes the current Offset within the WAL.
 	off Offset
+	// replicaEOF is true if a previous segment that is a replica of the
+	// current segment (i.e. has the same logNameIndex) ended cleanly. An
+	// invalid record at the tail of the last segment is then not surfaced,
+	// since the replica contains the records up to the end of the WAL.
+	replicaEOF bool
 	// lastSeqNum is the sequence number of the batch contained within the last
 	// record returned to the user. A virtual WAL may be split across a sequence
 	// of several physical WAL files. The tail of one physical WAL may be
@@ -267,6 +307,7 @@ func (r *virtualWALReader) nextRecord() (io.Reader, batchrepr.Header, Offset, er
 		rec, err := r.currReader.Next()
 		if errors.Is(err, io.EOF) {
 			// This file is exhausted; continue to the next.
+			r.replicaEOF = true
 			err := r.nextFile()
 			if err != nil {
 				return nil, batchrepr.Header{}, r.off, err
@@ -288,12 +329,14 @@ func (r *virtualWALReader) nextRecord() (io.Reader, batchrepr.Header, Offset, er
 		// because the tail of a WAL may be only partially written or otherwise
 		// unclean because of WAL recycling and the inability to write the EOF
 		// trailer record. If this isn't the last file, we silently ignore the
-		// invalid record at the tail and proceed to the next file. If it is
-		// the last file, bubble the error up and let the client decide what to
-		// do with it. If the virtual WAL is the most recent WAL, Open may also
-		// decide to ignore it because it's consistent with an incomplete
-		// in-flight write at the time of process exit/crash. See #453.
-		if record.IsInvalidRecord(err) && r.currIndex < len(r.segments)-1 {
+		// invalid record at the tail and proceed to the next file. The same
+		// applies if a replica of this file ended cleanly, since then the
+		// replica has all the records. Otherwise, if it is the last file,
+		// bubble the error up and let the client decide what to do with it. If
+		// the virtual WAL is the most recent WAL, Open may also decide to
+		// ignore it because it's consistent with an incomplete in-flight write
+		// at the time of process exit/crash. See #453.
+		if record.IsInvalidRecord(err) && (r.currIndex < len(r.segments)-1 || r.replicaEOF) {
 			if err := r.nextFile(); err != nil {
 				return nil, batchrepr.Header{}, r.off, err
 			}
@@ -369,6 +412,9 @@ func (r *virtualWALReader) nextFile() error {
 	if r.currIndex >= len(r.segments) {
 		return io.EOF
 	}
+	if r.currIndex == 0 || r.segments[r.currIndex].logNameIndex != r.segments[r.currIndex-1].logNameIndex {
+		r.replicaEOF = false
+	}
 
 	fs, path := r.LogicalLog.SegmentLocation(r.currIndex)
 	r.off.PreviousFilesBytes += r.off.Physical
@@ -402,7 +448,15 @@ func Copy(
 	}
 	w := record.NewLogWriter(dstFile, base.DiskFileNum(ll.Num), cfg)
 
-	r := newVirtualWALReader(ll)
+	var r interface {
+		nextRecord() (io.Reader, batchrepr.Header, Offset, error)
+		Close() error
+	}
+	if ll.external != nil {
+		r = newExternalReader(ll.external, ll.Num)
+	} else {
+		r = newVirtualWALReader(ll)
+	}
 	defer func() {
 		err = errors.CombineErrors(err, r.Close())
 		// Ensure w is closed (w == nil is the case where it was already closed).
@@ -44,17 +44,26 @@ func TestList(t *testing.T) {
 		switch td.Cmd {
 		case "list":
 			var dirs []Dir
+			var replica Dir
 			for _, arg := range td.CmdArgs {
 				var dirname string
 				if len(arg.Vals) > 1 {
 					dirname = arg.Vals[1]
 				}
-				dirs = append(dirs, Dir{
+				d := Dir{
 					FS:      getFS(arg.Vals[0]),
 					Dirname: dirname,
-				})
+				}
+				if arg.Key == "replica" {
+					replica = d
+				} else {
+					dirs = append(dirs, d)
+				}
 			}
 			logs, err := Scan(dirs...)
+			if replica != (Dir{}) {
+				logs, err = ScanWithReplica(replica, dirs...)
+			}
 			if err != nil {
 				return err.Error()
 			}
@@ -122,11 +131,16 @@ func TestReader(t *testing.T) {
 			var logNum uint64
 			var index int64
 			var recycleFilename string
+			var dirname string
 			td.ScanArgs(t, "logNum", &logNum)
 			td.MaybeScanArgs(t, "logNameIndex", &index)
 			td.MaybeScanArgs(t, "recycleFilename", &recycleFilename)
+			td.MaybeScanArgs(t, "dirname", &dirname)
 
-			filename := makeLogFilename(NumWAL(logNum), LogNameIndex(index))
+			filename := fs.PathJoin(dirname, makeLogFilename(NumWAL(logNum), LogNameIndex(index)))
+			if dirname != "" {
+				require.NoError(t, fs.MkdirAll(dirname, os.ModePerm))
+			}
 			var f vfs.File
 			var err error
 			if recycleFilename != "" {
@@ -138,7 +152,7 @@ func TestReader(t *testing.T) {
 				require.NoError(t, err)
 				fmt.Fprintf(&buf, "created %q\n", filename)
 			}
-			dir, err := fs.OpenDir("")
+			dir, err := fs.OpenDir(dirname)
 			require.NoError(t, err)
 			require.NoError(t, dir.Sync())
 			require.NoError(t, dir.Close())
@@ -250,10 +264,15 @@ func TestReader(t *testing.T) {
 			var logNum uint64
 			var forceLogNameIndexes []uint64
 			dirname := ""
+			var replicaDirname string
 			td.ScanArgs(t, "logNum", &logNum)
 			td.MaybeScanArgs(t, "forceLogNameIndexes", &forceLogNameIndexes)
 			td.MaybeScanArgs(t, "dirname", &dirname)
+			td.MaybeScanArgs(t, "replicaDirname", &replicaDirname)
 			logs, err := Scan(Dir{Dirname: dirname, FS: fs})
+			if replicaDirname != "" {
+				logs, err = ScanWithReplica(Dir{Dirname: replicaDirname, FS: fs}, Dir{Dirname: dirname, FS: fs})
+			}
 			require.NoError(t, err)
 			log, ok := logs.Get(NumWAL(logNum))
 			if !ok {
@@ -0,0 +1,501 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package wal
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/cockroachdb/crlib/crtime"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble/internal/base"
+	"github.com/cockroachdb/pebble/record"
+	"github.com/cockroachdb/pebble/vfs"
+)
+
+const (
+	replicaPrimaryIndex = 0
+	replicaIndex        = 1
+	numReplicas         = 2
+)
+
+// replicatedManager implements Manager by writing every record to a log file
+// in both the primary dir and the replica dir. A sync is acknowledged once
+// the record has been synced in Options.ReplicationQuorum of the dirs.
+//
+// The two log files of a WAL have the same name. When reading, the copies are
+// merged using the sequence numbers of the batches they contain (see
+// FileAccumulator and virtualWALReader), so a record that was synced in only
+// one of the dirs is recovered.
+//
+// Log files are not recycled, since a recycled file in one dir may be
+// replaced by a new file in the other, and keeping the two copies of a WAL
+// equivalent is simpler without recycling.
+type replicatedManager struct {
+	o          Options
+	quorum     int
+	dirs       [numReplicas]Dir
+	dirHandles [numReplicas]vfs.File
+	// recycler is never used for recycling, and is only present to implement
+	// RecyclerForTesting.
+	recycler LogRecycler
+	// initialObsolete holds the set of DeletableLogs that formed the logs
+	// passed into Init. The initialObsolete logs are all obsolete. Once
+	// returned via Manager.Obsolete, initialObsolete is cleared.
+	initialObsolete []DeletableLog
+
+	// External synchronization is relied on when accessing w in Manager.Create,
+	// Writer.{WriteRecord,Close}.
+	w *replicatedWriter
+
+	mu struct {
+		sync.Mutex
+		// The queue of WALs, containing both flushed and unflushed WALs. The
+		// flushed logs are a prefix, the unflushed logs a suffix. If w != nil,
+		// the last entry here is that active WAL.
+		queue []replicatedLog
+	}
+}
+
+// replicatedLog describes the log files of a WAL written by the
+// replicatedManager.
+type replicatedLog struct {
+	num NumWAL
+	// created is true for each dir in which the log file was created.
+	created [numReplicas]bool
+	// fileSize is the size of each of the log files.
+	fileSize uint64
+}
+
+var _ Manager = &replicatedManager{}
+
+// init implements Manager.
+func (m *replicatedManager) init(o Options, initial Logs) error {
+	quorum := o.ReplicationQuorum
+	if quorum == 0 {
+		quorum = numReplicas
+	}
+	if quorum < 1 || quorum > numReplicas {
+		return errors.Newf("wal: invalid replication quorum %d", o.ReplicationQuorum)
+	}
+	*m = replicatedManager{
+		o:      o,
+		quorum: quorum,
+		dirs:   [numReplicas]Dir{o.Primary, o.Replica},
+	}
+	m.recycler.Init(0)
+	for i, dir := range m.dirs {
+		openStart := crtime.NowMono()
+		f, err := dir.FS.OpenDir(dir.Dirname)
+		if err != nil {
+			return firstError(err, m.closeDirHandles())
+		}
+		if h := m.histogram(i); h != nil {
+			h.Observe(float64(openStart.Elapsed()))
+		}
+		m.dirHandles[i] = f
+	}
+	for _, ll := range initial {
+		var err error
+		m.initialObsolete, err = appendDeletableLogs(m.initialObsolete, ll)
+		if err != nil {
+			return firstError(err, m.closeDirHandles())
+		}
+	}
+	return nil
+}
+
+func (m *replicatedManager) histogram(i int) record.WALFileOpHistogram {
+	if i == replicaPrimaryIndex {
+		return m.o.PrimaryFileOpHistogram
+	}
+	return m.o.SecondaryFileOpHistogram
+}
+
+func (m *replicatedManager) closeDirHandles() error {
+	var err error
+	for i := range m.dirHandles {
+		if m.dirHandles[i] != nil {
+			err = firstError(err, m.dirHandles[i].Close())
+			m.dirHandles[i] = nil
+		}
+	}
+	return err
+}
+
+// List implements Manager.
+func (m *replicatedManager) List() Logs {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	wals := make(Logs, len(m.mu.queue))
+	for i, rl := range m.mu.queue {
+		wals[i] = LogicalLog{Num: rl.num}
+		for j := range rl.created {
+			if rl.created[j] {
+				wals[i].segments = append(wals[i].segments, segment{dir: m.dirs[j]})
+			}
+		}
+	}
+	return wals
+}
+
+// Obsolete implements Manager.
+func (m *replicatedManager) Obsolete(
+	minUnflushedNum NumWAL, noRecycle bool,
+) (toDelete []DeletableLog, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.initialObsolete = slices.DeleteFunc(m.initialObsolete, func(dl DeletableLog) bool {
+		if dl.NumWAL >= minUnflushedNum {
+			return false
+		}
+		toDelete = append(toDelete, dl)
+		return true
+	})
+
+	i := 0
+	for ; i < len(m.mu.queue); i++ {
+		rl := m.mu.queue[i]
+		if rl.num >= minUnflushedNum {
+			break
+		}
+		for j, dir := range m.dirs {
+			if !rl.created[j] {
+				continue
+			}
+			toDelete = append(toDelete, DeletableLog{
+				FS:             dir.FS,
+				Path:           dir.FS.PathJoin(dir.Dirname, makeLogFilename(rl.num, 0)),
+				NumWAL:         rl.num,
+				ApproxFileSize: rl.fileSize,
+			})
+		}
+	}
+	m.mu.queue = m.mu.queue[i:]
+	return toDelete, nil
+}
+
+// Create implements Manager.
+func (m *replicatedManager) Create(wn NumWAL, jobID int) (Writer, error) {
+	var files [numReplicas]vfs.File
+	var errs [numReplicas]error
+	numCreated := 0
+	for i := range m.dirs {
+		files[i], errs[i] = m.createFile(i, wn, jobID)
+		if errs[i] == nil {
+			numCreated++
+		}
+	}
+	if numCreated < m.quorum {
+		for i := range files {
+			if files[i] != nil {
+				_ = files[i].Close()
+			}
+		}
+		return nil, firstError(errs[replicaPrimaryIndex], errs[replicaIndex])
+	}
+	rl := replicatedLog{num: wn}
+	w := &replicatedWriter{m: m}
+	w.q.init(nil /* failoverWriteAndSyncLatency */)
+	w.mu.failedIndex = record.NoSyncIndex
+	for i := range files {
+		w.mu.synced[i] = record.NoSyncIndex
+		if files[i] == nil {
+			w.failLocked(i, errs[i])
+			continue
+		}
+		rl.created[i] = true
+		w.writers[i] = record.NewLogWriter(files[i], base.DiskFileNum(wn), record.LogWriterConfig{
+			WALMinSyncInterval: m.o.MinSyncInterval,
+			WALFileOpHistogram: m.histogram(i),
+			ExternalSyncQueueCallback: func(doneSync record.PendingSyncIndex, err error) {
+				w.doneSyncCallback(i, doneSync, err)
+			},
+			WriteWALSyncOffsets: m.o.WriteWALSyncOffsets,
+		})
+	}
+	m.w = w
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mu.queue = append(m.mu.queue, rl)
+	return w, nil
+}
+
+// createFile creates the log file for wn in the i-th dir.
+func (m *replicatedManager) createFile(i int, wn NumWAL, jobID int) (vfs.File, error) {
+	dir := m.dirs[i]
+	path := dir.FS.PathJoin(dir.Dirname, makeLogFilename(wn, 0))
+	createStart := crtime.NowMono()
+	f, err := dir.FS.Create(path, vfs.WriteCategoryWAL)
+	if h := m.histogram(i); h != nil {
+		h.Observe(float64(createStart.Elapsed()))
+	}
+	if err == nil {
+		if err = m.dirHandles[i].Sync(); err != nil {
+			err = firstError(err, f.Close())
+		}
+	}
+	if m.o.EventListener != nil {
+		m.o.EventListener.LogCreated(CreateInfo{
+			JobID:       jobID,
+			Path:        path,
+			IsSecondary: i == replicaIndex,
+			Num:         wn,
+			Err:         err,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return vfs.NewSyncingFile(f, vfs.SyncingFileOptions{
+		NoSyncOnClose:   m.o.NoSyncOnClose,
+		BytesPerSync:    m.o.BytesPerSync,
+		PreallocateSize: m.o.PreallocateSize(),
+	}), nil
+}
+
+// ElevateWriteStallThresholdForFailover implements Manager.
+func (m *replicatedManager) ElevateWriteStallThresholdForFailover() bool {
+	return false
+}
+
+// Stats implements Manager.
+func (m *replicatedManager) Stats() Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var stats Stats
+	for _, rl := range m.mu.queue {
+		for j := range rl.created {
+			if rl.created[j] {
+				stats.LiveFileCount++
+				stats.LiveFileSize += rl.fileSize
+			}
+		}
+	}
+	for _, dl := range m.initialObsolete {
+		stats.ObsoleteFileCount++
+		stats.ObsoleteFileSize += dl.ApproxFileSize
+	}
+	return stats
+}
+
+// Close implements Manager.
+func (m *replicatedManager) Close() error {
+	var err error
+	if m.w != nil {
+		_, err = m.w.Close()
+	}
+	return firstError(err, m.closeDirHandles())
+}
+
+// Opts implements Manager.
+func (m *replicatedManager) Opts() Options {
+	return m.o
+}
+
+// RecyclerForTesting implements Manager.
+func (m *replicatedManager) RecyclerForTesting() *LogRecycler {
+	return &m.recycler
+}
+
+// replicatedWriter implements Writer by writing every record to the
+// record.LogWriter of each dir. The records are queued in a recordQueue until
+// they are synced in a quorum of the dirs, at which point the syncs are
+// acknowledged.
+//
+// A LogWriter that encounters an error is not used again. If the remaining
+// LogWriters cannot form a quorum, the syncs of all the records that have not
+// been acknowledged fail with the error, and WriteRecord returns the error.
+type replicatedWriter struct {
+	m *replicatedManager
+	// writers contains nil for a dir in which the log file could not be
+	// created.
+	writers [numReplicas]*record.LogWriter
+	q       recordQueue
+
+	// Only accessed in WriteRecord and Close, which are externally
+	// synchronized.
+	psiForWriteRecordBacking record.PendingSyncIndex
+	logicalOffset            int64
+	// writeFailed is true for a LogWriter that returned an error from a
+	// write, and is no longer written to.
+	writeFailed [numReplicas]bool
+
+	mu struct {
+		sync.Mutex
+		// synced is the highest index synced by each LogWriter, or
+		// record.NoSyncIndex.
+		synced [numReplicas]int64
+		// errs is the error encountered by each LogWriter.
+		errs [numReplicas]error
+		// failedIndex is the highest index reported with an error.
+		failedIndex int64
+	}
+}
+
+var _ Writer = &replicatedWriter{}
+
+// doneSyncCallback is the record.ExternalSyncQueueCallback of the i-th
+// LogWriter.
+func (w *replicatedWriter) doneSyncCallback(i int, doneSync record.PendingSyncIndex, err error) {
+	w.mu.Lock()
+	if err != nil {
+		w.failLocked(i, err)
+		w.mu.failedIndex = max(w.mu.failedIndex, doneSync.Index)
+	} else {
+		w.mu.synced[i] = max(w.mu.synced[i], doneSync.Index)
+	}
+	index, err := w.ackIndexLocked()
+	w.mu.Unlock()
+	if index == record.NoSyncIndex {
+		return
+	}
+	// NB: harmless after Close returns since numSyncsPopped will be 0.
+	numSyncsPopped := w.q.pop(uint32(index), err)
+	if w.m.o.QueueSemChan != nil {
+		for range numSyncsPopped {
+			<-w.m.o.QueueSemChan
+		}
+	}
+}
+
+// failLocked records that the i-th LogWriter encountered err.
+func (w *replicatedWriter) failLocked(i int, err error) {
+	if w.mu.errs[i] != nil {
+		return
+	}
+	w.mu.errs[i] = err
+	if w.m.o.Logger != nil {
+		w.m.o.Logger.Errorf("pebble: WAL replica in %s failed: %v", errors.Safe(w.m.dirs[i].Dirname), err)
+	}
+}
+
+// ackIndexLocked returns the highest index that can be acknowledged, and the
+// error to acknowledge it with.
+func (w *replicatedWriter) ackIndexLocked() (int64, error) {
+	numHealthy := 0
+	index := int64(record.NoSyncIndex)
+	minIndex := int64(-1)
+	for i := range w.mu.synced {
+		if w.mu.errs[i] != nil {
+			continue
+		}
+		if numHealthy == 0 || w.mu.synced[i] < minIndex {
+			minIndex = w.mu.synced[i]
+		}
+		numHealthy++
+		index = max(index, w.mu.synced[i])
+	}
+	if numHealthy < w.m.quorum {
+		// A quorum is no longer possible, so fail all the reported indices.
+		index = max(w.mu.failedIndex, w.mu.synced[replicaPrimaryIndex], w.mu.synced[replicaIndex])
+		return index, firstError(w.mu.errs[replicaPrimaryIndex], w.mu.errs[replicaIndex])
+	}
+	if w.m.quorum == numReplicas {
+		// All the healthy LogWriters must have synced.
+		index = minIndex
+	}
+	return index, nil
+}
+
+// WriteRecord implements Writer.
+func (w *replicatedWriter) WriteRecord(
+	p []byte, opts SyncOptions, _ RefCount,
+) (logicalOffset int64, err error) {
+	var writeStart crtime.Mono
+	if opts.Done != nil {
+		writeStart = crtime.NowMono()
+	}
+	// The records are not retained by the queue, since they are never
+	// replayed.
+	recordIndex, _, _ := w.q.push(nil, opts, nil, writeStart, 0, nil)
+	w.psiForWriteRecordBacking = record.PendingSyncIndex{Index: record.NoSyncIndex}
+	if opts.Done != nil {
+		w.psiForWriteRecordBacking.Index = int64(recordIndex)
+	}
+	numWritten := 0
+	for i, lw := range w.writers {
+		if lw == nil || w.writeFailed[i] {
+			continue
+		}
+		size, writeErr := lw.SyncRecordGeneralized(p, &w.psiForWriteRecordBacking)
+		if writeErr != nil {
+			w.writeFailed[i] = true
+			w.mu.Lock()
+			w.failLocked(i, writeErr)
+			w.mu.Unlock()
+			err = firstError(err, writeErr)
+			continue
+		}
+		numWritten++
+		w.logicalOffset = max(w.logicalOffset, size)
+	}
+	if numWritten >= w.m.quorum {
+		err = nil
+	} else if err == nil {
+		w.mu.Lock()
+		err = firstError(w.mu.errs[replicaPrimaryIndex], w.mu.errs[replicaIndex])
+		w.mu.Unlock()
+	}
+	return w.logicalOffset, err
+}
+
+// Close implements Writer.
+func (w *replicatedWriter) Close() (logicalOffset int64, err error) {
+	logicalOffset = w.logicalOffset
+	lastQueuedRecord := record.PendingSyncIndex{Index: w.q.getLastIndex()}
+	// Close the LogWriters concurrently, since each writes an EOF trailer and
+	// syncs.
+	var closeErrs [numReplicas]error
+	var wg sync.WaitGroup
+	for i, lw := range w.writers {
+		if lw == nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			closeErrs[i] = lw.CloseWithLastQueuedRecord(lastQueuedRecord)
+		}()
+	}
+	wg.Wait()
+	w.mu.Lock()
+	numClosed := 0
+	for i := range closeErrs {
+		if closeErrs[i] != nil {
+			w.failLocked(i, closeErrs[i])
+		} else if w.writers[i] != nil && w.mu.errs[i] == nil {
+			numClosed++
+		}
+	}
+	if numClosed < w.m.quorum {
+		err = firstError(w.mu.errs[replicaPrimaryIndex], w.mu.errs[replicaIndex])
+	}
+	w.mu.Unlock()
+	// All the syncs have been acknowledged by the callbacks, so this only pops
+	// records that did not request a sync, unless a quorum was not possible.
+	w.q.popAll(err)
+
+	w.m.mu.Lock()
+	defer w.m.mu.Unlock()
+	i := len(w.m.mu.queue) - 1
+	w.m.mu.queue[i].fileSize = max(w.m.mu.queue[i].fileSize, uint64(logicalOffset))
+	w.m.w = nil
+	return logicalOffset, err
+}
+
+// Metrics implements Writer.
+func (w *replicatedWriter) Metrics() record.LogWriterMetrics {
+	var metrics record.LogWriterMetrics
+	for _, lw := range w.writers {
+		if lw != nil {
+			m := lw.Metrics()
+			// Merge never returns an error.
+			_ = metrics.Merge(&m)
+		}
+	}
+	return metrics
+}
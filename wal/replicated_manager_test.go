@@ -0,0 +1,166 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package wal
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble/batchrepr"
+	"github.com/cockroachdb/pebble/internal/base"
+	"github.com/cockroachdb/pebble/internal/testutils"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/cockroachdb/pebble/vfs/errorfs"
+	"github.com/stretchr/testify/require"
+)
+
+func makeReplicatedOptions(t *testing.T, fs vfs.FS, quorum int) Options {
+	return Options{
+		Primary:              Dir{FS: fs, Dirname: "pri"},
+		Replica:              Dir{FS: fs, Dirname: "rep"},
+		ReplicationQuorum:    quorum,
+		Logger:               testutils.Logger{T: t},
+		MaxNumRecyclableLogs: 1,
+		PreallocateSize:      func() int { return 4 },
+		WriteWALSyncOffsets:  func() bool { return false },
+	}
+}
+
+// makeReplicatedTestBatch returns a batch containing a single record, with the
+// given sequence number.
+func makeReplicatedTestBatch(seqNum int) []byte {
+	repr := make([]byte, batchrepr.HeaderLen, batchrepr.HeaderLen+16)
+	batchrepr.SetSeqNum(repr, base.SeqNum(seqNum))
+	batchrepr.SetCount(repr, 1)
+	return fmt.Appendf(repr, "record %d", seqNum)
+}
+
+func TestReplicatedManager(t *testing.T) {
+	memFS := vfs.NewMem()
+	for _, dir := range []string{"pri", "rep"} {
+		require.NoError(t, memFS.MkdirAll(dir, os.ModePerm))
+	}
+	opts := makeReplicatedOptions(t, memFS, 0 /* quorum */)
+	m, err := Init(opts, nil /* initial logs */)
+	require.NoError(t, err)
+
+	// Write some records to two WALs.
+	for wn := NumWAL(1); wn <= 2; wn++ {
+		w, err := m.Create(wn, int(wn))
+		require.NoError(t, err)
+		var wg sync.WaitGroup
+		var syncErr error
+		for i := range 3 {
+			wg.Add(1)
+			seqNum := 3*int(wn-1) + i + 1
+			_, err = w.WriteRecord(makeReplicatedTestBatch(seqNum), SyncOptions{Done: &wg, Err: &syncErr}, nil)
+			require.NoError(t, err)
+		}
+		wg.Wait()
+		require.NoError(t, syncErr)
+		_, err = w.Close()
+		require.NoError(t, err)
+	}
+
+	// Both WALs have a log file in both dirs.
+	for _, ll := range m.List() {
+		require.Equal(t, 2, ll.NumSegments())
+	}
+	for _, dir := range []string{"pri", "rep"} {
+		ls, err := memFS.List(dir)
+		require.NoError(t, err)
+		slices.Sort(ls)
+		require.Equal(t, []string{makeLogFilename(1, 0), makeLogFilename(2, 0)}, ls)
+	}
+	require.Equal(t, 4, m.Stats().LiveFileCount)
+
+	// Reading a WAL returns each record once, even though it is present in
+	// both dirs.
+	logs, err := ScanWithReplica(opts.Replica, opts.Primary)
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	r := logs[0].OpenForRead()
+	for i := range 3 {
+		rr, _, err := r.NextRecord()
+		require.NoError(t, err)
+		b, err := io.ReadAll(rr)
+		require.NoError(t, err)
+		require.Equal(t, makeReplicatedTestBatch(i+1), b)
+	}
+	_, _, err = r.NextRecord()
+	require.Equal(t, io.EOF, err)
+	require.NoError(t, r.Close())
+
+	// Both copies of an obsolete WAL are deleted, and none are recycled.
+	toDelete, err := m.Obsolete(2, false /* noRecycle */)
+	require.NoError(t, err)
+	require.Len(t, toDelete, 2)
+	require.Equal(t, "pri/000001.log", toDelete[0].Path)
+	require.Equal(t, "rep/000001.log", toDelete[1].Path)
+	require.Empty(t, m.RecyclerForTesting().LogNumsForTesting())
+	require.NoError(t, m.Close())
+}
+
+func TestReplicatedManager_Quorum(t *testing.T) {
+	t.Run("one", func(t *testing.T) {
+		memFS := vfs.NewMem()
+		for _, dir := range []string{"pri", "rep"} {
+			require.NoError(t, memFS.MkdirAll(dir, os.ModePerm))
+		}
+		repHang := errorfs.NewHang("rep", errorfs.PathMatch("rep/*"))
+		repHang.Release()
+		fs := errorfs.Wrap(memFS, repHang)
+		m, err := Init(makeReplicatedOptions(t, fs, 1 /* quorum */), nil /* initial logs */)
+		require.NoError(t, err)
+		w, err := m.Create(NumWAL(1), 1)
+		require.NoError(t, err)
+
+		// The sync completes even though the replica is stalled.
+		repHang.Rearm()
+		var wg sync.WaitGroup
+		var syncErr error
+		wg.Add(1)
+		_, err = w.WriteRecord(makeReplicatedTestBatch(1), SyncOptions{Done: &wg, Err: &syncErr}, nil)
+		require.NoError(t, err)
+		wg.Wait()
+		require.NoError(t, syncErr)
+
+		repHang.Release()
+		_, err = w.Close()
+		require.NoError(t, err)
+		require.NoError(t, m.Close())
+	})
+
+	t.Run("two", func(t *testing.T) {
+		memFS := vfs.NewMem()
+		for _, dir := range []string{"pri", "rep"} {
+			require.NoError(t, memFS.MkdirAll(dir, os.ModePerm))
+		}
+		repErr := &errorfs.Toggle{Injector: errorfs.ErrInjected.If(errorfs.PathMatch("rep/*"))}
+		fs := errorfs.Wrap(memFS, repErr)
+		m, err := Init(makeReplicatedOptions(t, fs, 2 /* quorum */), nil /* initial logs */)
+		require.NoError(t, err)
+		w, err := m.Create(NumWAL(1), 1)
+		require.NoError(t, err)
+
+		// The sync fails since the replica cannot sync.
+		repErr.On()
+		var wg sync.WaitGroup
+		var syncErr error
+		wg.Add(1)
+		_, _ = w.WriteRecord(makeReplicatedTestBatch(1), SyncOptions{Done: &wg, Err: &syncErr}, nil)
+		wg.Wait()
+		require.True(t, errors.Is(syncErr, errorfs.ErrInjected))
+
+		_, err = w.Close()
+		require.True(t, errors.Is(err, errorfs.ErrInjected))
+		require.NoError(t, m.Close())
+	})
+}
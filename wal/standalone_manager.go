@@ -52,6 +52,9 @@ func (m *StandaloneManager) init(o Options, initial Logs) error {
 	if o.Secondary.FS != nil {
 		return base.AssertionFailedf("cannot create StandaloneManager with a secondary")
 	}
+	if len(o.AdditionalSecondaries) > 0 {
+		return base.AssertionFailedf("cannot create StandaloneManager with additional secondaries")
+	}
 	var err error
 	var walDir vfs.File
 
@@ -154,7 +157,7 @@ func (m *StandaloneManager) Create(wn NumWAL, jobID int) (Writer, error) {
 		recycleLogName := m.o.Primary.FS.PathJoin(m.o.Primary.Dirname, makeLogFilename(NumWAL(recycleLog.FileNum), 0))
 		// measure file reuse operation
 		reuseStart := crtime.NowMono()
-		newLogFile, err = m.o.Primary.FS.ReuseForWrite(recycleLogName, newLogName, "pebble-wal")
+		newLogFile, err = m.o.Primary.FS.ReuseForWrite(recycleLogName, newLogName, vfs.WriteCategoryWAL)
 		if m.o.PrimaryFileOpHistogram != nil {
 			m.o.PrimaryFileOpHistogram.Observe(float64(reuseStart.Elapsed()))
 		}
@@ -162,7 +165,7 @@ func (m *StandaloneManager) Create(wn NumWAL, jobID int) (Writer, error) {
 	} else {
 		// measure file creation operation
 		createStart := crtime.NowMono()
-		newLogFile, err = m.o.Primary.FS.Create(newLogName, "pebble-wal")
+		newLogFile, err = m.o.Primary.FS.Create(newLogName, vfs.WriteCategoryWAL)
 		if m.o.PrimaryFileOpHistogram != nil {
 			m.o.PrimaryFileOpHistogram.Observe(float64(createStart.Elapsed()))
 		}
@@ -12,6 +12,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/pebble/internal/base"
 	"github.com/cockroachdb/pebble/record"
 	"github.com/cockroachdb/pebble/vfs"
@@ -112,6 +113,28 @@ type Options struct {
 	// Secondary is used for failover. Optional. It must already be created and
 	// synced up to the root.
 	Secondary Dir
+	// AdditionalSecondaries are further dirs used for failover, in order of
+	// preference after Secondary, so that writes can proceed even if the
+	// primary and Secondary are unhealthy at the same time. Optional, and
+	// requires Secondary. They must already be created and synced up to the
+	// root.
+	AdditionalSecondaries []Dir
+	// Replica, if set, is a dir to which every record written to the primary
+	// is also written, so that a copy of the WAL survives the loss of either
+	// device. Optional, and mutually exclusive with Secondary. It must already
+	// be created and synced up to the root.
+	Replica Dir
+	// ReplicationQuorum is the number of dirs, 1 or 2, in which a record must
+	// be synced before a sync is acknowledged. It is only used when Replica is
+	// set. With a quorum of 1, writes proceed when either device fails, at the
+	// cost of only a single durable copy of the recently synced records. The
+	// zero value is equivalent to 2.
+	ReplicationQuorum int
+	// External, if set, is the backend to which the records of every WAL are
+	// handed, instead of being written to log files in Primary. Optional, and
+	// mutually exclusive with Secondary and Replica. Primary is still used to
+	// find and delete log files written before External was configured.
+	External ExternalLog
 
 	// MinUnflushedLogNum is the smallest WAL number corresponding to
 	// mutations that have not been flushed to a sstable.
@@ -135,7 +158,8 @@ type Options struct {
 	// MinSyncInterval is documented in Options.WALMinSyncInterval.
 	MinSyncInterval func() time.Duration
 
-	// WAL file operation latency histograms
+	// WAL file operation latency histograms. SecondaryFileOpHistogram is used
+	// for all the secondary dirs, and for the replica.
 	PrimaryFileOpHistogram   record.WALFileOpHistogram
 	SecondaryFileOpHistogram record.WALFileOpHistogram
 	// QueueSemChan is the channel to pop from when popping from queued records
@@ -172,9 +196,20 @@ type Options struct {
 // and to ensure that they won't be recycled.
 func Init(o Options, initial Logs) (Manager, error) {
 	var m Manager
-	if o.Secondary == (Dir{}) {
+	switch {
+	case o.External != nil:
+		if o.Secondary != (Dir{}) || o.Replica != (Dir{}) {
+			return nil, errors.New("wal: cannot use an external log with a secondary or replica")
+		}
+		m = new(externalManager)
+	case o.Replica != (Dir{}):
+		if o.Secondary != (Dir{}) {
+			return nil, errors.New("wal: cannot use both a replica and a secondary")
+		}
+		m = new(replicatedManager)
+	case o.Secondary == (Dir{}):
 		m = new(StandaloneManager)
-	} else {
+	default:
 		m = new(failoverManager)
 	}
 	if err := m.init(o, initial); err != nil {
@@ -183,18 +218,25 @@ func Init(o Options, initial Logs) (Manager, error) {
 	return m, nil
 }
 
-// Dirs returns the primary Dir and the secondary if provided.
+// Dirs returns the primary Dir and the secondaries or the replica if
+// provided.
 func (o *Options) Dirs() []Dir {
+	if o.Replica != (Dir{}) {
+		return []Dir{o.Primary, o.Replica}
+	}
 	if o.Secondary == (Dir{}) {
 		return []Dir{o.Primary}
 	}
-	return []Dir{o.Primary, o.Secondary}
+	return append([]Dir{o.Primary, o.Secondary}, o.AdditionalSecondaries...)
 }
 
 // FailoverOptions are options that are specific to failover mode.
 type FailoverOptions struct {
 	// PrimaryDirProbeInterval is the interval for probing the primary dir, when
-	// the WAL is being written to the secondary, to decide when to fail back.
+	// the WAL is being written to a secondary, to decide when to fail back.
+	// When there are additional secondaries, it is also the interval for
+	// probing the secondaries that are not in use, to choose the dir to fail
+	// over to.
 	PrimaryDirProbeInterval time.Duration
 	// HealthyProbeLatencyThreshold is the latency threshold to declare that the
 	// primary is healthy again.
@@ -269,7 +311,7 @@ type CreateInfo struct {
 	// Path to the file. This includes the NumWAL, and implicitly or explicitly
 	// includes the logNameIndex.
 	Path string
-	// IsSecondary is true if the file was created on the secondary.
+	// IsSecondary is true if the file was created on a secondary.
 	IsSecondary bool
 	// Num is the WAL number.
 	Num NumWAL
@@ -322,7 +364,7 @@ type FailoverStats struct {
 	// using the primary directory.
 	PrimaryWriteDuration time.Duration
 	// SecondaryWriteDuration is the cumulative duration for which WAL writes
-	// are using the secondary directory.
+	// are using a secondary directory.
 	SecondaryWriteDuration time.Duration
 
 	// FailoverWriteAndSyncLatency measures the latency of writing and syncing a
@@ -403,7 +445,9 @@ type SyncOptions struct {
 
 // Writer writes to a virtual WAL. A Writer in standalone mode maps to a
 // single record.LogWriter. In failover mode, it can failover across multiple
-// physical log files.
+// physical log files. In replicated mode, it writes to two physical log files
+// with the same contents. With an ExternalLog, it maps to an
+// ExternalLogWriter.
 type Writer interface {
 	// WriteRecord writes a complete record. The record is asynchronously
 	// persisted to the underlying writer. If SyncOptions.Done != nil, the wait
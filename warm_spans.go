@@ -0,0 +1,115 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package pebble
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble/internal/base"
+	"github.com/cockroachdb/pebble/internal/manifest"
+	"github.com/cockroachdb/pebble/objstorage"
+	"github.com/cockroachdb/pebble/sstable"
+	"github.com/cockroachdb/pebble/sstable/block"
+)
+
+// WarmSpansOptions configures DB.WarmSpans.
+type WarmSpansOptions struct {
+	// PinDuration, if non-zero, protects the warmed data from eviction from the
+	// secondary cache for the given duration.
+	PinDuration time.Duration
+}
+
+// WarmSpans proactively reads the blocks of remote (shared or external) tables
+// that are needed to read the given key ranges into the secondary cache (see
+// Options.SecondaryCacheSizeBytes). For each table overlapping a
+// span, this includes the data blocks that overlap the span as well as the
+// index, filter and metadata blocks of the table.
+//
+// Local tables are ignored, as are tables that appear in the LSM after the
+// method is called. Progress is reported through the WarmSpansBegin,
+// WarmSpansProgress and WarmSpansEnd events.
+//
+// The method returns once all the tables were processed, the context is
+// canceled, or an error is hit.
+func (d *DB) WarmSpans(ctx context.Context, spans []KeyRange, opts WarmSpansOptions) error {
+	if err := d.closed.Load(); err != nil {
+		panic(err)
+	}
+	if d.opts.SecondaryCacheSizeBytes == 0 {
+		return errors.New("pebble: WarmSpans requires a secondary cache")
+	}
+
+	// Grab and reference the current readState. This prevents the underlying
+	// files in the associated version from being deleted.
+	readState := d.loadReadState()
+	defer readState.unref()
+
+	type warmTask struct {
+		span  KeyRange
+		table *manifest.TableMetadata
+		meta  objstorage.ObjectMetadata
+	}
+	var tasks []warmTask
+	for _, span := range spans {
+		bounds := span.UserKeyBounds()
+		for level := range readState.current.Levels {
+			for f := range readState.current.Overlaps(level, bounds).All() {
+				meta, err := d.objProvider.Lookup(base.FileTypeTable, f.TableBacking.DiskFileNum)
+				if err != nil {
+					return err
+				}
+				if meta.IsRemote() {
+					tasks = append(tasks, warmTask{span: span, table: f, meta: meta})
+				}
+			}
+		}
+	}
+
+	info := WarmSpansInfo{
+		JobID:       int(d.newJobID()),
+		Spans:       spans,
+		TablesTotal: len(tasks),
+	}
+	startTime := d.opts.private.timeNow()
+	var pinUntil time.Time
+	if opts.PinDuration > 0 {
+		pinUntil = time.Now().Add(opts.PinDuration)
+	}
+	d.opts.EventListener.WarmSpansBegin(info)
+
+	for _, t := range tasks {
+		if err := ctx.Err(); err != nil {
+			info.Err = err
+			break
+		}
+		err := d.fileCache.withReader(ctx, block.NoReadEnv, t.table, func(r *sstable.Reader, env sstable.ReadEnv) error {
+			ranges, err := r.ByteRangesForSpan(t.span.Start, t.span.End, env, t.table.IterTransforms())
+			if err != nil {
+				return err
+			}
+			for _, br := range ranges {
+				n, err := d.objProvider.PrefetchRemote(ctx, t.meta, int64(br.Offset), int64(br.Length), pinUntil)
+				info.BytesFetched += n
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			info.Err = err
+			break
+		}
+		info.TablesWarmed++
+		info.Duration = d.opts.private.timeNow().Sub(startTime)
+		d.opts.EventListener.WarmSpansProgress(info)
+	}
+	info.Duration = d.opts.private.timeNow().Sub(startTime)
+	info.Done = info.Err == nil
+	d.opts.EventListener.WarmSpansEnd(info)
+	return info.Err
+}
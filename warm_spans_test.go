@@ -0,0 +1,78 @@
+// Copyright 2026 The LevelDB-Go and Pebble Authors. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package pebble
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/pebble/internal/testutils"
+	"github.com/cockroachdb/pebble/objstorage/remote"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarmSpans(t *testing.T) {
+	var events []WarmSpansInfo
+	opts := &Options{
+		FS:                      vfs.NewMem(),
+		FormatMajorVersion:      FormatNewest,
+		Logger:                  testutils.Logger{T: t},
+		SecondaryCacheSizeBytes: 32 << 20,
+		EventListener: &EventListener{
+			WarmSpansBegin:    func(info WarmSpansInfo) { events = append(events, info) },
+			WarmSpansProgress: func(info WarmSpansInfo) { events = append(events, info) },
+			WarmSpansEnd:      func(info WarmSpansInfo) { events = append(events, info) },
+		},
+	}
+	opts.RemoteStorage = remote.MakeSimpleFactory(map[remote.Locator]remote.Storage{
+		remote.MakeLocator(""): remote.NewInMem(),
+	})
+	opts.CreateOnShared = remote.CreateOnSharedAll
+	d, err := Open("", opts)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, d.Close()) }()
+	require.NoError(t, d.SetCreatorID(1))
+
+	// Write two shared tables.
+	for i := 0; i < 1000; i++ {
+		require.NoError(t, d.Set([]byte(fmt.Sprintf("a%04d", i)), []byte(strings.Repeat("x", 100)), nil))
+		require.NoError(t, d.Set([]byte(fmt.Sprintf("b%04d", i)), []byte(strings.Repeat("y", 100)), nil))
+		if i == 500 {
+			require.NoError(t, d.Flush())
+		}
+	}
+	require.NoError(t, d.Flush())
+	require.NoError(t, d.Compact(context.Background(), []byte("a"), []byte("c"), false /* parallelize */))
+
+	spans := []KeyRange{{Start: []byte("a0100"), End: []byte("a0200")}}
+	require.NoError(t, d.WarmSpans(context.Background(), spans, WarmSpansOptions{PinDuration: time.Hour}))
+	require.GreaterOrEqual(t, len(events), 3)
+	begin, end := events[0], events[len(events)-1]
+	require.Zero(t, begin.TablesWarmed)
+	require.Greater(t, begin.TablesTotal, 0)
+	require.True(t, end.Done)
+	require.NoError(t, end.Err)
+	require.Equal(t, end.TablesTotal, end.TablesWarmed)
+	require.Greater(t, end.BytesFetched, int64(0))
+	require.Equal(t, end.BytesFetched, d.Metrics().SecondaryCacheMetrics.PrefetchedBytes)
+
+	// Warming the same span again does not fetch anything.
+	events = nil
+	require.NoError(t, d.WarmSpans(context.Background(), spans, WarmSpansOptions{}))
+	require.True(t, events[len(events)-1].Done)
+	require.Zero(t, events[len(events)-1].BytesFetched)
+
+	// A canceled context stops the operation.
+	events = nil
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = d.WarmSpans(ctx, []KeyRange{{Start: []byte("a"), End: []byte("c")}}, WarmSpansOptions{})
+	require.ErrorIs(t, err, context.Canceled)
+	require.False(t, events[len(events)-1].Done)
+}